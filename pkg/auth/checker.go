@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrMissingToken is returned when a request has no bearer token
+	ErrMissingToken = errors.New("authorization token required")
+	// ErrInvalidToken is returned for tokens that fail verification
+	ErrInvalidToken = errors.New("invalid or expired token")
+	// ErrPermissionDenied is returned when the caller lacks a permission
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// claims mirrors the access token claims issued by auth-service
+type claims struct {
+	jwt.RegisteredClaims
+	UserID      string   `json:"user_id"`
+	Email       string   `json:"email"`
+	Role        string   `json:"role"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+}
+
+// Checker verifies access tokens with the shared signing secret and checks permissions
+type Checker struct {
+	secret []byte
+}
+
+// NewChecker creates a checker for tokens signed with secret
+func NewChecker(secret string) *Checker {
+	return &Checker{secret: []byte(secret)}
+}
+
+// Verify parses an access token and returns its principal.
+// Tokens with an audience (such as two-factor challenges) are not access tokens.
+func (c *Checker) Verify(tokenString string) (*Principal, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &claims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method")
+		}
+		return c.secret, nil
+	})
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	cl, ok := token.Claims.(*claims)
+	if !ok || !token.Valid || len(cl.Audience) > 0 || cl.UserID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	roles := cl.Roles
+	if len(roles) == 0 && cl.Role != "" {
+		roles = []string{cl.Role}
+	}
+
+	return &Principal{
+		UserID:      cl.UserID,
+		Email:       cl.Email,
+		Roles:       roles,
+		Permissions: cl.Permissions,
+	}, nil
+}
+
+// FromRequest verifies the request's bearer token
+func (c *Checker) FromRequest(r *http.Request) (*Principal, error) {
+	header := r.Header.Get("Authorization")
+	tokenString := strings.TrimPrefix(header, "Bearer ")
+	if header == "" || tokenString == header {
+		return nil, ErrMissingToken
+	}
+	return c.Verify(tokenString)
+}
+
+// Require authenticates the request and checks it holds every listed permission.
+// On failure it writes a 401 or 403 JSON error and returns false.
+func (c *Checker) Require(w http.ResponseWriter, r *http.Request, perms ...Permission) (*Principal, bool) {
+	principal, err := c.FromRequest(r)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, err)
+		return nil, false
+	}
+
+	for _, perm := range perms {
+		if !principal.Has(perm) {
+			writeError(w, http.StatusForbidden, ErrPermissionDenied)
+			return nil, false
+		}
+	}
+
+	return principal, true
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	apperrors "github.com/rentalflow/rentalflow/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+// FromContext verifies the bearer token in the incoming gRPC metadata
+func (c *Checker) FromContext(ctx context.Context) (*Principal, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, ErrMissingToken
+	}
+
+	tokenString := strings.TrimPrefix(values[0], "Bearer ")
+	if tokenString == values[0] {
+		return nil, ErrMissingToken
+	}
+	return c.Verify(tokenString)
+}
+
+// RequireContext is Require for gRPC handlers. It returns an Unauthenticated
+// or PermissionDenied status error on failure.
+func (c *Checker) RequireContext(ctx context.Context, perms ...Permission) (*Principal, error) {
+	principal, err := c.FromContext(ctx)
+	if err != nil {
+		return nil, apperrors.ToGRPCError(apperrors.Unauthorized(err.Error()))
+	}
+
+	for _, perm := range perms {
+		if !principal.Has(perm) {
+			return nil, apperrors.ToGRPCError(apperrors.Forbidden(ErrPermissionDenied.Error()))
+		}
+	}
+
+	return principal, nil
+}
@@ -0,0 +1,70 @@
+package auth
+
+// Permission names an action a user may perform. Auth-service maps roles to
+// permissions and embeds the result in access tokens.
+type Permission string
+
+const (
+	PermItemsWrite      Permission = "items:write"
+	PermBookingsConfirm Permission = "bookings:confirm"
+	PermReviewsWrite    Permission = "reviews:write"
+	PermMessagesSend    Permission = "messages:send"
+	PermKYCReview       Permission = "kyc:review"
+	PermRefundsIssue    Permission = "refunds:issue"
+	PermUsersManage     Permission = "users:manage"
+	PermUserDataManage  Permission = "user_data:manage"
+)
+
+// Service permissions are only ever granted to service tokens, never to a
+// role, and guard the endpoints services call on each other.
+const (
+	PermDatesHold         Permission = "service:dates:hold"
+	PermBookingsPayment   Permission = "service:bookings:payment"
+	PermNotificationsSend Permission = "service:notifications:send"
+)
+
+// AllPermissions lists every permission a role can grant
+var AllPermissions = []Permission{
+	PermItemsWrite,
+	PermBookingsConfirm,
+	PermReviewsWrite,
+	PermMessagesSend,
+	PermKYCReview,
+	PermRefundsIssue,
+	PermUsersManage,
+	PermUserDataManage,
+}
+
+// Principal is the authenticated caller described by an access token
+type Principal struct {
+	UserID      string
+	Email       string
+	Roles       []string
+	Permissions []string
+}
+
+// Has checks if the principal holds a permission
+func (p *Principal) Has(perm Permission) bool {
+	for _, granted := range p.Permissions {
+		if granted == string(perm) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole checks if the principal holds a role
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// CanActFor checks if the principal may act on behalf of userID. Requests that
+// name no user act as the caller; acting for someone else requires users:manage.
+func (p *Principal) CanActFor(userID string) bool {
+	return userID == "" || userID == p.UserID || p.Has(PermUsersManage)
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
 )
 
@@ -15,6 +16,7 @@ type Config struct {
 	ServiceName string
 	Environment string
 	LogLevel    string
+	LogFormat   string // console or json
 
 	// gRPC Server
 	GRPCPort int
@@ -28,6 +30,9 @@ type Config struct {
 	// Redis
 	Redis RedisConfig
 
+	// Message broker
+	Messaging MessagingConfig
+
 	// RabbitMQ
 	RabbitMQ RabbitMQConfig
 
@@ -45,6 +50,167 @@ type Config struct {
 
 	// SMTP
 	SMTP SMTPConfig
+
+	// OIDC social login providers
+	OIDC OIDCConfig
+
+	// Tracing
+	Tracing TracingConfig
+
+	// Readiness checks
+	Health HealthConfig
+
+	// HTTP base URLs of the services, for calls made over REST
+	ServiceURLs ServiceURLsConfig
+
+	// Auth service
+	Auth AuthConfig
+
+	// Booking service
+	Booking BookingConfig
+
+	// API gateway
+	Gateway GatewayConfig
+
+	// source is the viper instance the config was read from, kept for the
+	// debug dump and for reloading
+	source *source
+}
+
+// ServiceURLsConfig holds the HTTP base URLs of the services
+type ServiceURLsConfig struct {
+	Auth         string
+	Inventory    string
+	Booking      string
+	Payment      string
+	Notification string
+	Review       string
+}
+
+// AuthConfig holds auth service settings
+type AuthConfig struct {
+	BCryptCost int
+
+	// Links in account emails point at the frontend here
+	AppBaseURL           string
+	EmailVerificationTTL time.Duration
+	PasswordResetTTL     time.Duration
+
+	// Two-factor authentication
+	MFAIssuer       string
+	MFAChallengeTTL time.Duration
+
+	// Social login
+	OIDCStateTTL time.Duration
+
+	Lockout      LockoutConfig
+	DataRequests DataRequestsConfig
+}
+
+// LockoutConfig holds failed-login lockout settings
+type LockoutConfig struct {
+	AccountMaxFailures int
+	IPMaxFailures      int
+	FailureWindow      time.Duration
+	Base               time.Duration
+	Max                time.Duration
+}
+
+// DataRequestsConfig holds personal-data export and account deletion settings
+type DataRequestsConfig struct {
+	PollInterval time.Duration
+	MaxAttempts  int
+	ExportTTL    time.Duration // how long a finished export can be downloaded
+}
+
+// BookingConfig holds booking service settings
+type BookingConfig struct {
+	// Bookings at or above this total require a verified identity
+	HighValueThreshold float64
+}
+
+// GatewayConfig holds API gateway settings
+type GatewayConfig struct {
+	Port           int
+	AllowedOrigins []string
+
+	RateLimit RateLimitConfig
+	Cache     CacheConfig
+	Upstreams UpstreamsConfig
+
+	// Extra attempts for forwarded idempotent requests that fail to connect
+	ProxyRetries int
+
+	// Instance selection (round_robin or least_conn) and health checking
+	LoadBalancing       string
+	HealthCheckInterval time.Duration
+
+	// Consecutive failures that open an upstream's circuit breaker, and how
+	// long it stays open before a trial request
+	BreakerFailureThreshold int
+	BreakerOpenTimeout      time.Duration
+
+	// Deadline for each backend call made by the /api/views aggregation endpoints
+	ViewCallTimeout time.Duration
+}
+
+// RateLimitConfig holds the gateway's quotas in requests per minute. Roles
+// raises the quota for signed-in users by role, e.g. "owner=300,admin=1000",
+// and Routes adds a separate quota for matching requests, e.g.
+// "POST /api/auth/login=10,/api/payments=60".
+type RateLimitConfig struct {
+	Default int
+	Roles   map[string]int
+	Routes  map[string]int
+	// Store is "redis", to share counters between gateway instances, or
+	// "memory" for a single node
+	Store string
+}
+
+// CacheConfig holds the gateway's response cache for public catalog reads.
+// Store is "redis" or "memory", an LRU of Size entries. A zero TTL disables
+// caching for the route.
+type CacheConfig struct {
+	Store          string
+	Size           int
+	ItemsTTL       time.Duration // item listings and search
+	ItemTTL        time.Duration // item detail
+	ItemReviewsTTL time.Duration // an item's reviews
+}
+
+// UpstreamsConfig holds the services the gateway routes to
+type UpstreamsConfig struct {
+	Auth         UpstreamConfig
+	Inventory    UpstreamConfig
+	Booking      UpstreamConfig
+	Payment      UpstreamConfig
+	Notification UpstreamConfig
+	Review       UpstreamConfig
+}
+
+// UpstreamConfig holds how the gateway reaches one service
+type UpstreamConfig struct {
+	// Instance URLs for routes forwarded over HTTP, from service_urls.<name>
+	// as a comma-separated list
+	URLs []string
+	// How long the service may take to start responding to a forwarded
+	// request, and to answer a transcoded gRPC call
+	Timeout time.Duration
+	// Address for routes transcoded from the proto HTTP annotations
+	GRPCAddr string
+}
+
+// HealthConfig holds readiness check settings
+type HealthConfig struct {
+	Timeout  time.Duration // per dependency check
+	CacheTTL time.Duration // how long a check result is reused
+}
+
+// TracingConfig holds OpenTelemetry settings. The OTLP exporter reads its
+// endpoint from the standard OTEL_EXPORTER_OTLP_ENDPOINT variable.
+type TracingConfig struct {
+	Exporter    string // otlp, stdout or none
+	SampleRatio float64
 }
 
 // CloudinaryConfig holds Cloudinary settings
@@ -55,13 +221,18 @@ type CloudinaryConfig struct {
 	UploadPreset string
 }
 
-// ChapaConfig holds Chapa settings
+// ChapaConfig holds Chapa settings. Chapa calls CallbackURL and sends the
+// payer back to ReturnURL, both with the transaction reference added.
+// BaseURL is the Chapa API, which tests point at a fake.
 type ChapaConfig struct {
+	BaseURL       string
 	SecretKey     string
 	PublicKey     string
+	EncryptionKey string
 	WebhookSecret string
 	PaymentLink   string
 	CallbackURL   string
+	ReturnURL     string
 }
 
 // SMTPConfig holds SMTP settings
@@ -74,13 +245,41 @@ type SMTPConfig struct {
 	FromName string
 }
 
+// OIDCConfig holds OpenID Connect relying-party settings
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+}
+
+// OIDCProviderConfig holds settings for a single OpenID Connect provider.
+// Providers are listed in oidc.providers and configured under oidc.<name>.*,
+// e.g. RENTALFLOW_OIDC_PROVIDERS=google and RENTALFLOW_OIDC_GOOGLE_ISSUER_URL.
+type OIDCProviderConfig struct {
+	Name         string
+	DisplayName  string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Database drivers a service can store its data with. DriverMemory keeps
+// the data in the process, for tests and local runs.
+const (
+	DriverMongo    = "mongodb"
+	DriverPostgres = "postgres"
+	DriverMemory   = "memory"
+)
+
 // DatabaseConfig holds database connection settings
 type DatabaseConfig struct {
-	URI      string
-	Database string
+	Driver         string // DriverMongo, DriverPostgres or DriverMemory
+	URI            string
+	Database       string // the MongoDB database; a PostgreSQL URI names its own
+	MigrateOnStart bool   // apply pending schema migrations before serving
 }
 
-// GetURI returns the MongoDB connection string
+// GetURI returns the database connection string
 func (d DatabaseConfig) GetURI() string {
 	return d.URI
 }
@@ -98,6 +297,19 @@ func (r RedisConfig) Addr() string {
 	return fmt.Sprintf("%s:%d", r.Host, r.Port)
 }
 
+// Message brokers the services can exchange events through. BrokerMemory
+// delivers events within the process, for services run in one binary and
+// for tests.
+const (
+	BrokerRabbitMQ = "rabbitmq"
+	BrokerMemory   = "memory"
+)
+
+// MessagingConfig selects the message broker
+type MessagingConfig struct {
+	Driver string // BrokerRabbitMQ or BrokerMemory
+}
+
 // RabbitMQConfig holds RabbitMQ connection settings
 type RabbitMQConfig struct {
 	Host     string
@@ -133,7 +345,12 @@ type ServicesConfig struct {
 	ReviewServiceAddr       string
 }
 
-// Load reads configuration from environment variables and config files
+// Load reads configuration from defaults, the optional config file named by
+// CONFIG_PATH and environment variables, each overriding the one before.
+// Every key can be set as RENTALFLOW_<KEY>, with dots as underscores, or
+// read from the file named by RENTALFLOW_<KEY>_FILE, which suits mounted
+// secrets. Every missing or invalid setting is reported at once in a
+// *ValidationError.
 func Load(serviceName string) (*Config, error) {
 	v := viper.New()
 
@@ -141,9 +358,10 @@ func Load(serviceName string) (*Config, error) {
 	setDefaults(v, serviceName)
 
 	// Read from environment variables
-	v.SetEnvPrefix("RENTALFLOW")
+	v.SetEnvPrefix(envPrefix)
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
+	bindLegacyEnv(v)
 
 	// Try to read config file (optional)
 	configPath := os.Getenv("CONFIG_PATH")
@@ -155,16 +373,33 @@ func Load(serviceName string) (*Config, error) {
 		}
 	}
 
+	bindOIDCEnv(v)
+	problems := readSecretFiles(v)
+
+	config := newConfig(v, serviceName)
+	if problems = append(problems, validate(config, v)...); len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	config.source = newSource(v, config)
+
+	return config, nil
+}
+
+// newConfig builds a Config from the settings in v
+func newConfig(v *viper.Viper, serviceName string) *Config {
 	config := &Config{
 		ServiceName: serviceName,
 		Environment: v.GetString("environment"),
 		LogLevel:    v.GetString("log_level"),
+		LogFormat:   v.GetString("log_format"),
 		GRPCPort:    v.GetInt("grpc_port"),
 		HTTPPort:    v.GetInt("http_port"),
 
 		Database: DatabaseConfig{
-			URI:      v.GetString("database.uri"),
-			Database: v.GetString("database.name"),
+			Driver:         v.GetString("database.driver"),
+			URI:            v.GetString("database.uri"),
+			Database:       v.GetString("database.name"),
+			MigrateOnStart: v.GetBool("database.migrate_on_start"),
 		},
 
 		Redis: RedisConfig{
@@ -174,6 +409,10 @@ func Load(serviceName string) (*Config, error) {
 			DB:       v.GetInt("redis.db"),
 		},
 
+		Messaging: MessagingConfig{
+			Driver: v.GetString("messaging.driver"),
+		},
+
 		RabbitMQ: RabbitMQConfig{
 			Host:     v.GetString("rabbitmq.host"),
 			Port:     v.GetInt("rabbitmq.port"),
@@ -206,11 +445,14 @@ func Load(serviceName string) (*Config, error) {
 		},
 
 		Chapa: ChapaConfig{
+			BaseURL:       v.GetString("chapa.base_url"),
 			SecretKey:     v.GetString("chapa.secret_key"),
 			PublicKey:     v.GetString("chapa.public_key"),
 			WebhookSecret: v.GetString("chapa.webhook_secret"),
+			EncryptionKey: v.GetString("chapa.encryption_key"),
 			PaymentLink:   v.GetString("chapa.payment_link"),
 			CallbackURL:   v.GetString("chapa.callback_url"),
+			ReturnURL:     v.GetString("chapa.return_url"),
 		},
 
 		SMTP: SMTPConfig{
@@ -221,24 +463,206 @@ func Load(serviceName string) (*Config, error) {
 			From:     v.GetString("smtp.from_email"),
 			FromName: v.GetString("smtp.from_name"),
 		},
+
+		OIDC: loadOIDCConfig(v),
+
+		Tracing: TracingConfig{
+			Exporter:    v.GetString("tracing.exporter"),
+			SampleRatio: v.GetFloat64("tracing.sample_ratio"),
+		},
+
+		Health: HealthConfig{
+			Timeout:  v.GetDuration("health.timeout"),
+			CacheTTL: v.GetDuration("health.cache_ttl"),
+		},
+
+		ServiceURLs: ServiceURLsConfig{
+			Auth:         v.GetString("service_urls.auth"),
+			Inventory:    v.GetString("service_urls.inventory"),
+			Booking:      v.GetString("service_urls.booking"),
+			Payment:      v.GetString("service_urls.payment"),
+			Notification: v.GetString("service_urls.notification"),
+			Review:       v.GetString("service_urls.review"),
+		},
+
+		Auth: AuthConfig{
+			BCryptCost:           v.GetInt("auth.bcrypt_cost"),
+			AppBaseURL:           v.GetString("auth.app_base_url"),
+			EmailVerificationTTL: v.GetDuration("auth.email_verification_ttl"),
+			PasswordResetTTL:     v.GetDuration("auth.password_reset_ttl"),
+			MFAIssuer:            v.GetString("auth.mfa_issuer"),
+			MFAChallengeTTL:      v.GetDuration("auth.mfa_challenge_ttl"),
+			OIDCStateTTL:         v.GetDuration("auth.oidc_state_ttl"),
+			Lockout: LockoutConfig{
+				AccountMaxFailures: v.GetInt("auth.lockout.account_max_failures"),
+				IPMaxFailures:      v.GetInt("auth.lockout.ip_max_failures"),
+				FailureWindow:      v.GetDuration("auth.lockout.failure_window"),
+				Base:               v.GetDuration("auth.lockout.base"),
+				Max:                v.GetDuration("auth.lockout.max"),
+			},
+			DataRequests: DataRequestsConfig{
+				PollInterval: v.GetDuration("auth.data_requests.poll_interval"),
+				MaxAttempts:  v.GetInt("auth.data_requests.max_attempts"),
+				ExportTTL:    v.GetDuration("auth.data_requests.export_ttl"),
+			},
+		},
+
+		Booking: BookingConfig{
+			HighValueThreshold: v.GetFloat64("booking.high_value_threshold"),
+		},
+
+		Gateway: loadGatewayConfig(v),
 	}
 
-	return config, nil
+	return config
+}
+
+// loadGatewayConfig reads the gateway section. Upstream instance URLs come
+// from service_urls, which the gateway reads as lists.
+func loadGatewayConfig(v *viper.Viper) GatewayConfig {
+	roles, _ := getQuotas(v, "gateway.rate_limit.roles")
+	routes, _ := getQuotas(v, "gateway.rate_limit.routes")
+	upstream := func(name string) UpstreamConfig {
+		return UpstreamConfig{
+			URLs:     getList(v, "service_urls."+name),
+			Timeout:  v.GetDuration("gateway.upstreams." + name + ".timeout"),
+			GRPCAddr: v.GetString("gateway.upstreams." + name + ".grpc_addr"),
+		}
+	}
+
+	return GatewayConfig{
+		Port:           v.GetInt("gateway.port"),
+		AllowedOrigins: getList(v, "gateway.allowed_origins"),
+		RateLimit: RateLimitConfig{
+			Default: v.GetInt("gateway.rate_limit.default"),
+			Roles:   roles,
+			Routes:  routes,
+			Store:   v.GetString("gateway.rate_limit.store"),
+		},
+		Cache: CacheConfig{
+			Store:          v.GetString("gateway.cache.store"),
+			Size:           v.GetInt("gateway.cache.size"),
+			ItemsTTL:       v.GetDuration("gateway.cache.items_ttl"),
+			ItemTTL:        v.GetDuration("gateway.cache.item_ttl"),
+			ItemReviewsTTL: v.GetDuration("gateway.cache.item_reviews_ttl"),
+		},
+		Upstreams: UpstreamsConfig{
+			Auth:         upstream("auth"),
+			Inventory:    upstream("inventory"),
+			Booking:      upstream("booking"),
+			Payment:      upstream("payment"),
+			Notification: upstream("notification"),
+			Review:       upstream("review"),
+		},
+		ProxyRetries:            v.GetInt("gateway.proxy_retries"),
+		LoadBalancing:           v.GetString("gateway.load_balancing"),
+		HealthCheckInterval:     v.GetDuration("gateway.health_check_interval"),
+		BreakerFailureThreshold: v.GetInt("gateway.breaker_failure_threshold"),
+		BreakerOpenTimeout:      v.GetDuration("gateway.breaker_open_timeout"),
+		ViewCallTimeout:         v.GetDuration("gateway.view_call_timeout"),
+	}
+}
+
+// loadOIDCConfig reads the providers listed in oidc.providers
+func loadOIDCConfig(v *viper.Viper) OIDCConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range getList(v, "oidc.providers") {
+		name = strings.ToLower(name)
+		prefix := "oidc." + name + "."
+		scopes := getList(v, prefix+"scopes")
+		if len(scopes) == 0 {
+			scopes = []string{"openid", "email", "profile"}
+		}
+		displayName := v.GetString(prefix + "display_name")
+		if displayName == "" {
+			displayName = name
+		}
+
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			DisplayName:  displayName,
+			IssuerURL:    v.GetString(prefix + "issuer_url"),
+			ClientID:     v.GetString(prefix + "client_id"),
+			ClientSecret: v.GetString(prefix + "client_secret"),
+			RedirectURL:  v.GetString(prefix + "redirect_url"),
+			Scopes:       scopes,
+		})
+	}
+	return OIDCConfig{Providers: providers}
+}
+
+// oidcProviderKeys are the settings of each OIDC provider
+var oidcProviderKeys = []string{"display_name", "issuer_url", "client_id", "client_secret", "redirect_url", "scopes"}
+
+// bindOIDCEnv makes the keys of each listed provider known to v, so they can
+// be read from files and appear in the debug dump like any other key
+func bindOIDCEnv(v *viper.Viper) {
+	for _, name := range getList(v, "oidc.providers") {
+		for _, key := range oidcProviderKeys {
+			v.BindEnv("oidc." + strings.ToLower(name) + "." + key)
+		}
+	}
+}
+
+// getQuotas reads a map of names to requests per minute, given as a map in
+// the config file or as comma-separated name=limit pairs. Entries without a
+// positive limit are skipped and returned as invalid.
+func getQuotas(v *viper.Viper, key string) (quotas map[string]int, invalid []string) {
+	quotas = make(map[string]int)
+	add := func(name string, value interface{}) {
+		limit, err := cast.ToIntE(value)
+		if err != nil || limit <= 0 {
+			invalid = append(invalid, fmt.Sprintf("%s=%v", name, value))
+			return
+		}
+		quotas[name] = limit
+	}
+
+	if entries, ok := v.Get(key).(map[string]interface{}); ok {
+		for name, value := range entries {
+			add(name, value)
+		}
+		return quotas, invalid
+	}
+	for _, entry := range getList(v, key) {
+		name, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			invalid = append(invalid, entry)
+			continue
+		}
+		add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return quotas, invalid
+}
+
+// getList reads a list key. Env values arrive as a single comma-separated string.
+func getList(v *viper.Viper, key string) []string {
+	var items []string
+	for _, entry := range v.GetStringSlice(key) {
+		for _, item := range strings.Split(entry, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
 }
 
 func setDefaults(v *viper.Viper, serviceName string) {
 	// Environment
 	v.SetDefault("environment", "development")
 	v.SetDefault("log_level", "debug")
+	v.SetDefault("log_format", "console")
 
 	// Ports (will be overridden per service)
 	v.SetDefault("grpc_port", 50051)
 	v.SetDefault("http_port", 8080)
 
 	// Database
-	// Database
+	v.SetDefault("database.driver", DriverMongo)
 	v.SetDefault("database.uri", "mongodb://localhost:27017")
 	v.SetDefault("database.name", serviceName+"_db")
+	v.SetDefault("database.migrate_on_start", true)
 
 	// Redis
 	v.SetDefault("redis.host", "localhost")
@@ -246,6 +670,9 @@ func setDefaults(v *viper.Viper, serviceName string) {
 	v.SetDefault("redis.password", "")
 	v.SetDefault("redis.db", 0)
 
+	// Message broker
+	v.SetDefault("messaging.driver", BrokerRabbitMQ)
+
 	// RabbitMQ
 	v.SetDefault("rabbitmq.host", "localhost")
 	v.SetDefault("rabbitmq.port", 5672)
@@ -264,6 +691,7 @@ func setDefaults(v *viper.Viper, serviceName string) {
 	v.SetDefault("services.inventory", "localhost:50052")
 	v.SetDefault("services.booking", "localhost:50053")
 	v.SetDefault("services.payment", "localhost:50054")
+	v.SetDefault("services.notification", "localhost:8085")
 	v.SetDefault("services.review", "localhost:50056")
 
 	// Cloudinary (Defaults are empty, must be provided by env)
@@ -273,11 +701,14 @@ func setDefaults(v *viper.Viper, serviceName string) {
 	v.SetDefault("cloudinary.upload_preset", "")
 
 	// Chapa
+	v.SetDefault("chapa.base_url", "https://api.chapa.co/v1")
 	v.SetDefault("chapa.secret_key", "")
 	v.SetDefault("chapa.public_key", "")
 	v.SetDefault("chapa.webhook_secret", "")
+	v.SetDefault("chapa.encryption_key", "")
 	v.SetDefault("chapa.payment_link", "")
 	v.SetDefault("chapa.callback_url", "http://localhost:3001/payment/callback")
+	v.SetDefault("chapa.return_url", "http://localhost:3001/payment/callback")
 
 	// SMTP
 	v.SetDefault("smtp.host", "smtp.gmail.com")
@@ -286,4 +717,76 @@ func setDefaults(v *viper.Viper, serviceName string) {
 	v.SetDefault("smtp.password", "")
 	v.SetDefault("smtp.from_email", "")
 	v.SetDefault("smtp.from_name", "RentalFlow")
+
+	// OIDC (no providers unless configured)
+	v.SetDefault("oidc.providers", []string{})
+
+	// Tracing
+	v.SetDefault("tracing.exporter", "none")
+	v.SetDefault("tracing.sample_ratio", 1.0)
+
+	// Readiness checks
+	v.SetDefault("health.timeout", 2*time.Second)
+	v.SetDefault("health.cache_ttl", 5*time.Second)
+
+	// Service HTTP base URLs
+	v.SetDefault("service_urls.auth", "http://localhost:8081")
+	v.SetDefault("service_urls.inventory", "http://localhost:8082")
+	v.SetDefault("service_urls.booking", "http://localhost:8083")
+	v.SetDefault("service_urls.payment", "http://localhost:8084")
+	v.SetDefault("service_urls.notification", "http://localhost:8085")
+	v.SetDefault("service_urls.review", "http://localhost:8086")
+
+	// Auth service
+	v.SetDefault("auth.bcrypt_cost", 12)
+	v.SetDefault("auth.app_base_url", "http://localhost:3000")
+	v.SetDefault("auth.email_verification_ttl", 48*time.Hour)
+	v.SetDefault("auth.password_reset_ttl", time.Hour)
+	v.SetDefault("auth.mfa_issuer", "RentalFlow")
+	v.SetDefault("auth.mfa_challenge_ttl", 5*time.Minute)
+	v.SetDefault("auth.oidc_state_ttl", 10*time.Minute)
+	v.SetDefault("auth.lockout.account_max_failures", 5)
+	v.SetDefault("auth.lockout.ip_max_failures", 20)
+	v.SetDefault("auth.lockout.failure_window", 15*time.Minute)
+	v.SetDefault("auth.lockout.base", time.Minute)
+	v.SetDefault("auth.lockout.max", 24*time.Hour)
+	v.SetDefault("auth.data_requests.poll_interval", 10*time.Second)
+	v.SetDefault("auth.data_requests.max_attempts", 5)
+	v.SetDefault("auth.data_requests.export_ttl", 7*24*time.Hour)
+
+	// Booking service
+	v.SetDefault("booking.high_value_threshold", 10000.0)
+
+	// API gateway
+	v.SetDefault("gateway.port", 8080)
+	v.SetDefault("gateway.allowed_origins", "http://localhost:3000,http://localhost:3001")
+	v.SetDefault("gateway.rate_limit.default", 100)
+	v.SetDefault("gateway.rate_limit.roles", "")
+	v.SetDefault("gateway.rate_limit.routes", "")
+	v.SetDefault("gateway.rate_limit.store", "memory")
+	v.SetDefault("gateway.cache.store", "memory")
+	v.SetDefault("gateway.cache.size", 1000)
+	v.SetDefault("gateway.cache.items_ttl", 30*time.Second)
+	v.SetDefault("gateway.cache.item_ttl", time.Minute)
+	v.SetDefault("gateway.cache.item_reviews_ttl", time.Minute)
+	v.SetDefault("gateway.proxy_retries", 2)
+	v.SetDefault("gateway.load_balancing", "round_robin")
+	v.SetDefault("gateway.health_check_interval", 10*time.Second)
+	v.SetDefault("gateway.breaker_failure_threshold", 5)
+	v.SetDefault("gateway.breaker_open_timeout", 30*time.Second)
+	v.SetDefault("gateway.view_call_timeout", 3*time.Second)
+	for name, upstream := range map[string]struct {
+		grpcAddr string
+		timeout  time.Duration
+	}{
+		"auth":         {"localhost:50051", 10 * time.Second},
+		"inventory":    {"localhost:50052", 10 * time.Second},
+		"booking":      {"localhost:50053", 15 * time.Second},
+		"payment":      {"localhost:50054", 30 * time.Second},
+		"notification": {"localhost:50055", 10 * time.Second},
+		"review":       {"localhost:50056", 10 * time.Second},
+	} {
+		v.SetDefault("gateway.upstreams."+name+".grpc_addr", upstream.grpcAddr)
+		v.SetDefault("gateway.upstreams."+name+".timeout", upstream.timeout)
+	}
 }
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/spf13/viper"
+)
+
+// secretWords mark the keys whose values the debug dump masks
+var secretWords = []string{"secret", "password", "token", "key"}
+
+// urlCredentials matches the password in a connection string, which may
+// hold several hosts and so not parse as a URL
+var urlCredentials = regexp.MustCompile(`(://[^:/@]*:)[^@/]*@`)
+
+// source is the viper instance a Config was loaded from, shared by the
+// copies a reload makes
+type source struct {
+	v *viper.Viper
+
+	mu       sync.Mutex
+	current  *Config
+	settings map[string]interface{} // settings in effect, by key
+}
+
+func newSource(v *viper.Viper, c *Config) *source {
+	return &source{v: v, current: c, settings: flatten(v)}
+}
+
+// flatten returns every setting in v by its dotted key
+func flatten(v *viper.Viper) map[string]interface{} {
+	settings := make(map[string]interface{})
+	for _, key := range v.AllKeys() {
+		settings[key] = v.Get(key)
+	}
+	return settings
+}
+
+// Redacted returns the settings in effect by their dotted keys, with
+// secrets masked and the passwords in connection strings removed
+func (c *Config) Redacted() map[string]interface{} {
+	redacted := make(map[string]interface{})
+	if c.source == nil {
+		return redacted
+	}
+
+	c.source.mu.Lock()
+	defer c.source.mu.Unlock()
+	for key, value := range c.source.settings {
+		redacted[key] = redact(key, value)
+	}
+	return redacted
+}
+
+// redact masks value if key names a secret, or the credentials of any
+// connection strings in it. An unset secret stays empty, so the dump still
+// shows that it is missing.
+func redact(key string, value interface{}) interface{} {
+	name := key[strings.LastIndex(key, ".")+1:]
+	for _, word := range secretWords {
+		if strings.Contains(name, word) {
+			if value == nil || value == "" {
+				return ""
+			}
+			return "[REDACTED]"
+		}
+	}
+
+	switch value := value.(type) {
+	case time.Duration:
+		return value.String()
+	case string:
+		return urlCredentials.ReplaceAllString(value, "${1}xxxxx@")
+	case []string:
+		redacted := make([]string, len(value))
+		for i, item := range value {
+			redacted[i] = urlCredentials.ReplaceAllString(item, "${1}xxxxx@")
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(value))
+		for i, item := range value {
+			redacted[i] = redact("", item)
+		}
+		return redacted
+	}
+	return value
+}
+
+// DebugHandler serves the redacted settings as JSON to callers with the
+// users:manage permission
+func (c *Config) DebugHandler(checker *auth.Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := checker.Require(w, r, auth.PermUsersManage); !ok {
+			return
+		}
+
+		configFile := ""
+		if c.source != nil {
+			configFile = c.source.v.ConfigFileUsed()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"service":     c.ServiceName,
+			"environment": c.Environment,
+			"config_file": configFile,
+			"settings":    c.Redacted(),
+		})
+	})
+}
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// envPrefix starts the environment variable of every key
+const envPrefix = "RENTALFLOW"
+
+// legacyEnv lists the unprefixed variables that set a key before every
+// service read its settings through this package. They are still accepted,
+// so existing deployments keep working, but RENTALFLOW_<KEY> wins when both
+// are set.
+var legacyEnv = map[string][]string{
+	"log_level":             {"LOG_LEVEL"},
+	"log_format":            {"LOG_FORMAT"},
+	"database.uri":          {"MONGODB_URI"},
+	"jwt.secret":            {"JWT_SECRET"},
+	"jwt.access_expires_in": {"JWT_EXPIRY"},
+
+	"redis.host":        {"REDIS_HOST"},
+	"redis.port":        {"REDIS_PORT"},
+	"redis.password":    {"REDIS_PASSWORD"},
+	"redis.db":          {"REDIS_DB"},
+	"rabbitmq.host":     {"RABBITMQ_HOST"},
+	"rabbitmq.port":     {"RABBITMQ_PORT"},
+	"rabbitmq.user":     {"RABBITMQ_USER"},
+	"rabbitmq.password": {"RABBITMQ_PASSWORD"},
+
+	"tracing.exporter":     {"TRACING_EXPORTER"},
+	"tracing.sample_ratio": {"TRACING_SAMPLE_RATIO"},
+
+	"chapa.secret_key":     {"CHAPA_SECRET_KEY"},
+	"chapa.public_key":     {"CHAPA_PUBLIC_KEY"},
+	"chapa.webhook_secret": {"CHAPA_WEBHOOK_SECRET"},
+	"chapa.callback_url":   {"CALLBACK_URL"},
+
+	"smtp.host":       {"SMTP_HOST"},
+	"smtp.port":       {"SMTP_PORT"},
+	"smtp.username":   {"SMTP_USERNAME"},
+	"smtp.password":   {"SMTP_PASSWORD"},
+	"smtp.from_email": {"FROM_EMAIL"},
+	"smtp.from_name":  {"FROM_NAME"},
+
+	"service_urls.auth":         {"AUTH_SERVICE_URL"},
+	"service_urls.inventory":    {"INVENTORY_SERVICE_URL"},
+	"service_urls.booking":      {"BOOKING_SERVICE_URL"},
+	"service_urls.payment":      {"PAYMENT_SERVICE_URL"},
+	"service_urls.notification": {"NOTIFICATION_SERVICE_URL"},
+	"service_urls.review":       {"REVIEW_SERVICE_URL"},
+
+	"auth.app_base_url":            {"APP_BASE_URL"},
+	"auth.mfa_issuer":              {"MFA_ISSUER"},
+	"booking.high_value_threshold": {"HIGH_VALUE_BOOKING_THRESHOLD"},
+
+	"gateway.port":                      {"PORT"},
+	"gateway.allowed_origins":           {"ALLOWED_ORIGINS"},
+	"gateway.rate_limit.default":        {"RATE_LIMIT"},
+	"gateway.rate_limit.roles":          {"RATE_LIMIT_ROLES"},
+	"gateway.rate_limit.routes":         {"RATE_LIMIT_ROUTES"},
+	"gateway.rate_limit.store":          {"RATE_LIMIT_STORE"},
+	"gateway.cache.store":               {"CACHE_STORE"},
+	"gateway.cache.size":                {"CACHE_SIZE"},
+	"gateway.cache.items_ttl":           {"CACHE_ITEMS_TTL"},
+	"gateway.cache.item_ttl":            {"CACHE_ITEM_TTL"},
+	"gateway.cache.item_reviews_ttl":    {"CACHE_ITEM_REVIEWS_TTL"},
+	"gateway.proxy_retries":             {"PROXY_RETRIES"},
+	"gateway.load_balancing":            {"LOAD_BALANCING"},
+	"gateway.health_check_interval":     {"HEALTH_CHECK_INTERVAL"},
+	"gateway.breaker_failure_threshold": {"BREAKER_FAILURE_THRESHOLD"},
+	"gateway.breaker_open_timeout":      {"BREAKER_OPEN_TIMEOUT"},
+	"gateway.view_call_timeout":         {"VIEW_CALL_TIMEOUT"},
+}
+
+func init() {
+	for _, name := range []string{"auth", "inventory", "booking", "payment", "notification", "review"} {
+		prefix := strings.ToUpper(name) + "_SERVICE_"
+		legacyEnv["gateway.upstreams."+name+".timeout"] = []string{prefix + "TIMEOUT"}
+		legacyEnv["gateway.upstreams."+name+".grpc_addr"] = []string{prefix + "GRPC_ADDR"}
+	}
+}
+
+// envName returns the environment variable that sets key
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// bindLegacyEnv binds each key to its prefixed variable followed by its
+// legacy ones
+func bindLegacyEnv(v *viper.Viper) {
+	for key, names := range legacyEnv {
+		v.BindEnv(append([]string{key, envName(key)}, names...)...)
+	}
+}
+
+// readSecretFiles sets each key whose RENTALFLOW_<KEY>_FILE, or legacy
+// <NAME>_FILE, variable names a file to the file's contents, without the
+// trailing newline. It returns a problem for each file that cannot be read.
+func readSecretFiles(v *viper.Viper) []string {
+	var problems []string
+	for _, key := range v.AllKeys() {
+		for _, name := range append([]string{envName(key)}, legacyEnv[key]...) {
+			path := os.Getenv(name + "_FILE")
+			if path == "" {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s (%s_FILE): %v", key, name, err))
+				break
+			}
+			v.Set(key, strings.TrimSpace(string(data)))
+			break
+		}
+	}
+	return problems
+}
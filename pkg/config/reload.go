@@ -0,0 +1,79 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+)
+
+// reloadable reports whether a change to key applies without a restart.
+// These are the settings that are safe to swap in a running service: the
+// log level and the gateway's quotas.
+func reloadable(key string) bool {
+	return key == "log_level" ||
+		(strings.HasPrefix(key, "gateway.rate_limit.") && key != "gateway.rate_limit.store")
+}
+
+// Watch reloads the config file named by CONFIG_PATH whenever it changes and
+// calls apply with a copy of the configuration whose reloadable settings
+// are updated. Changes to other settings are reported and wait for a
+// restart, and a file that no longer validates is ignored. Without a config
+// file there is nothing to watch.
+func (c *Config) Watch(apply func(*Config)) {
+	s := c.source
+	if s == nil || s.v.ConfigFileUsed() == "" {
+		return
+	}
+
+	s.v.OnConfigChange(func(fsnotify.Event) {
+		if next := s.reload(c.ServiceName); next != nil {
+			apply(next)
+		}
+	})
+	s.v.WatchConfig()
+}
+
+// reload rebuilds the configuration after viper has read the changed file
+func (s *source) reload(serviceName string) *Config {
+	fresh := newConfig(s.v, serviceName)
+	if problems := validate(fresh, s.v); len(problems) > 0 {
+		logger.Logger.Warn().Strs("problems", problems).Msg("Ignoring invalid config file change")
+		return nil
+	}
+	settings := flatten(s.v)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []string
+	for key, value := range settings {
+		if reflect.DeepEqual(value, s.settings[key]) {
+			continue
+		}
+		if reloadable(key) {
+			s.settings[key] = value
+		} else {
+			pending = append(pending, key)
+		}
+	}
+	for key := range s.settings {
+		if _, ok := settings[key]; !ok && reloadable(key) {
+			delete(s.settings, key)
+		}
+	}
+	if len(pending) > 0 {
+		sort.Strings(pending)
+		logger.Logger.Warn().Strs("keys", pending).Msg("Config changes take effect after a restart")
+	}
+
+	next := *s.current
+	next.LogLevel = fresh.LogLevel
+	next.Gateway.RateLimit.Default = fresh.Gateway.RateLimit.Default
+	next.Gateway.RateLimit.Roles = fresh.Gateway.RateLimit.Roles
+	next.Gateway.RateLimit.Routes = fresh.Gateway.RateLimit.Routes
+	s.current = &next
+	return &next
+}
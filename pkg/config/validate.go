@@ -0,0 +1,287 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// defaultJWTSecret is the development secret, which production must replace
+const defaultJWTSecret = "your-super-secret-key-change-in-production"
+
+// ValidationError lists every missing or invalid setting Load found
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration (%d problems):\n  - %s",
+		len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// problems collects at most one problem per key
+type problems struct {
+	list []string
+	seen map[string]bool
+}
+
+func (p *problems) add(key, format string, args ...interface{}) {
+	if p.seen[key] {
+		return
+	}
+	p.seen[key] = true
+	p.list = append(p.list, fmt.Sprintf("%s (%s): %s", key, envName(key), fmt.Sprintf(format, args...)))
+}
+
+func (p *problems) required(key, value string) {
+	if strings.TrimSpace(value) == "" {
+		p.add(key, "must be set")
+	}
+}
+
+func (p *problems) port(key string, port int) {
+	if port < 1 || port > 65535 {
+		p.add(key, "must be a port between 1 and 65535")
+	}
+}
+
+func (p *problems) positive(key string, d time.Duration) {
+	if d <= 0 {
+		p.add(key, "must be a positive duration")
+	}
+}
+
+func (p *problems) atLeast(key string, n, min int) {
+	if n < min {
+		p.add(key, "must be at least %d", min)
+	}
+}
+
+func (p *problems) url(key, value string) {
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		p.add(key, "must be an http or https URL, got %q", value)
+	}
+}
+
+func (p *problems) oneOf(key, value string, options ...string) {
+	for _, option := range options {
+		if value == option {
+			return
+		}
+	}
+	p.add(key, "must be one of %s, got %q", strings.Join(options, ", "), value)
+}
+
+// validate returns every problem with c and the settings it was built from
+func validate(c *Config, v *viper.Viper) []string {
+	p := &problems{seen: make(map[string]bool)}
+	checkTypes(p, v, c.ServiceName)
+
+	p.oneOf("environment", c.Environment, "development", "test", "staging", "production")
+	if _, err := zerolog.ParseLevel(c.LogLevel); err != nil {
+		p.add("log_level", "unknown level %q", c.LogLevel)
+	}
+	p.oneOf("log_format", c.LogFormat, "console", "json")
+
+	p.required("jwt.secret", c.JWT.Secret)
+	if c.Environment == "production" && c.JWT.Secret == defaultJWTSecret {
+		p.add("jwt.secret", "must be changed from the development default in production")
+	}
+	p.positive("jwt.access_expires_in", c.JWT.AccessExpiresIn)
+	p.positive("jwt.refresh_expires_in", c.JWT.RefreshExpiresIn)
+
+	p.port("redis.port", c.Redis.Port)
+	p.oneOf("messaging.driver", c.Messaging.Driver, BrokerRabbitMQ, BrokerMemory)
+	if c.Messaging.Driver == BrokerMemory && c.Environment == "production" {
+		p.add("messaging.driver", "must not be memory in production")
+	}
+	p.port("rabbitmq.port", c.RabbitMQ.Port)
+
+	p.oneOf("tracing.exporter", c.Tracing.Exporter, "none", "stdout", "otlp")
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		p.add("tracing.sample_ratio", "must be between 0 and 1")
+	}
+	p.positive("health.timeout", c.Health.Timeout)
+	if c.Health.CacheTTL < 0 {
+		p.add("health.cache_ttl", "must not be negative")
+	}
+
+	if c.ServiceName == "gateway" {
+		validateGateway(p, c, v)
+	} else {
+		validateService(p, c)
+	}
+
+	sort.Strings(p.list)
+	return p.list
+}
+
+// validateService checks the settings of the service named in c
+func validateService(p *problems, c *Config) {
+	p.port("grpc_port", c.GRPCPort)
+	p.port("http_port", c.HTTPPort)
+	p.oneOf("database.driver", c.Database.Driver, DriverMongo, DriverPostgres, DriverMemory)
+	switch c.Database.Driver {
+	case DriverMongo:
+		if !strings.HasPrefix(c.Database.URI, "mongodb://") && !strings.HasPrefix(c.Database.URI, "mongodb+srv://") {
+			p.add("database.uri", "must be a mongodb:// or mongodb+srv:// connection string")
+		}
+		p.required("database.name", c.Database.Database)
+	case DriverPostgres:
+		if !strings.HasPrefix(c.Database.URI, "postgres://") && !strings.HasPrefix(c.Database.URI, "postgresql://") {
+			p.add("database.uri", "must be a postgres:// or postgresql:// connection string")
+		}
+	case DriverMemory:
+		if c.Environment == "production" {
+			p.add("database.driver", "must not be memory in production")
+		}
+	}
+
+	switch c.ServiceName {
+	case "auth":
+		validateAuth(p, c)
+	case "booking":
+		p.url("service_urls.auth", c.ServiceURLs.Auth)
+		if c.Booking.HighValueThreshold < 0 {
+			p.add("booking.high_value_threshold", "must not be negative")
+		}
+	case "inventory":
+		p.url("service_urls.auth", c.ServiceURLs.Auth)
+	case "payment":
+		if c.Environment == "production" {
+			p.required("chapa.secret_key", c.Chapa.SecretKey)
+		}
+		p.url("chapa.base_url", c.Chapa.BaseURL)
+		p.url("chapa.callback_url", c.Chapa.CallbackURL)
+		p.url("chapa.return_url", c.Chapa.ReturnURL)
+	case "notification":
+		// Links in account emails are built from it
+		p.url("auth.app_base_url", c.Auth.AppBaseURL)
+		p.required("smtp.host", c.SMTP.Host)
+		p.port("smtp.port", c.SMTP.Port)
+		if c.Environment == "production" {
+			p.required("smtp.username", c.SMTP.Username)
+			p.required("smtp.password", c.SMTP.Password)
+			p.required("smtp.from_email", c.SMTP.From)
+		}
+	}
+}
+
+func validateAuth(p *problems, c *Config) {
+	if c.Auth.BCryptCost < 4 || c.Auth.BCryptCost > 31 {
+		p.add("auth.bcrypt_cost", "must be between 4 and 31")
+	}
+	p.positive("auth.email_verification_ttl", c.Auth.EmailVerificationTTL)
+	p.positive("auth.password_reset_ttl", c.Auth.PasswordResetTTL)
+	p.required("auth.mfa_issuer", c.Auth.MFAIssuer)
+	p.positive("auth.mfa_challenge_ttl", c.Auth.MFAChallengeTTL)
+	p.positive("auth.oidc_state_ttl", c.Auth.OIDCStateTTL)
+
+	p.atLeast("auth.lockout.account_max_failures", c.Auth.Lockout.AccountMaxFailures, 1)
+	p.atLeast("auth.lockout.ip_max_failures", c.Auth.Lockout.IPMaxFailures, 1)
+	p.positive("auth.lockout.failure_window", c.Auth.Lockout.FailureWindow)
+	p.positive("auth.lockout.base", c.Auth.Lockout.Base)
+	p.positive("auth.lockout.max", c.Auth.Lockout.Max)
+
+	p.positive("auth.data_requests.poll_interval", c.Auth.DataRequests.PollInterval)
+	p.atLeast("auth.data_requests.max_attempts", c.Auth.DataRequests.MaxAttempts, 1)
+	p.positive("auth.data_requests.export_ttl", c.Auth.DataRequests.ExportTTL)
+
+	// Personal-data requests reach these services over HTTP
+	p.url("service_urls.inventory", c.ServiceURLs.Inventory)
+	p.url("service_urls.booking", c.ServiceURLs.Booking)
+	p.url("service_urls.payment", c.ServiceURLs.Payment)
+	p.url("service_urls.review", c.ServiceURLs.Review)
+
+	for _, provider := range c.OIDC.Providers {
+		prefix := "oidc." + provider.Name + "."
+		p.url(prefix+"issuer_url", provider.IssuerURL)
+		p.required(prefix+"client_id", provider.ClientID)
+		p.url(prefix+"redirect_url", provider.RedirectURL)
+	}
+}
+
+func validateGateway(p *problems, c *Config, v *viper.Viper) {
+	g := c.Gateway
+	p.port("gateway.port", g.Port)
+
+	p.atLeast("gateway.rate_limit.default", g.RateLimit.Default, 1)
+	for _, key := range []string{"gateway.rate_limit.roles", "gateway.rate_limit.routes"} {
+		if _, invalid := getQuotas(v, key); len(invalid) > 0 {
+			p.add(key, "entries need a positive limit, got %s", strings.Join(invalid, ", "))
+		}
+	}
+	p.oneOf("gateway.rate_limit.store", g.RateLimit.Store, "memory", "redis")
+
+	p.oneOf("gateway.cache.store", g.Cache.Store, "memory", "redis")
+	p.atLeast("gateway.cache.size", g.Cache.Size, 1)
+	for key, ttl := range map[string]time.Duration{
+		"gateway.cache.items_ttl":        g.Cache.ItemsTTL,
+		"gateway.cache.item_ttl":         g.Cache.ItemTTL,
+		"gateway.cache.item_reviews_ttl": g.Cache.ItemReviewsTTL,
+	} {
+		if ttl < 0 {
+			p.add(key, "must not be negative")
+		}
+	}
+
+	for name, upstream := range map[string]UpstreamConfig{
+		"auth":         g.Upstreams.Auth,
+		"inventory":    g.Upstreams.Inventory,
+		"booking":      g.Upstreams.Booking,
+		"payment":      g.Upstreams.Payment,
+		"notification": g.Upstreams.Notification,
+		"review":       g.Upstreams.Review,
+	} {
+		if len(upstream.URLs) == 0 {
+			p.add("service_urls."+name, "must list at least one instance")
+		}
+		for _, instance := range upstream.URLs {
+			p.url("service_urls."+name, instance)
+		}
+		p.positive("gateway.upstreams."+name+".timeout", upstream.Timeout)
+		p.required("gateway.upstreams."+name+".grpc_addr", upstream.GRPCAddr)
+	}
+
+	p.atLeast("gateway.proxy_retries", g.ProxyRetries, 0)
+	p.oneOf("gateway.load_balancing", g.LoadBalancing, "round_robin", "least_conn")
+	p.positive("gateway.health_check_interval", g.HealthCheckInterval)
+	p.atLeast("gateway.breaker_failure_threshold", g.BreakerFailureThreshold, 1)
+	p.positive("gateway.breaker_open_timeout", g.BreakerOpenTimeout)
+	p.positive("gateway.view_call_timeout", g.ViewCallTimeout)
+}
+
+// checkTypes reports values that do not parse as the type of their key's
+// default. viper reads such values as zero, which would otherwise hide the
+// mistake.
+func checkTypes(p *problems, v *viper.Viper, serviceName string) {
+	defaults := viper.New()
+	setDefaults(defaults, serviceName)
+
+	for _, key := range defaults.AllKeys() {
+		value := v.Get(key)
+		var err error
+		var kind string
+		switch defaults.Get(key).(type) {
+		case int:
+			_, err = cast.ToIntE(value)
+			kind = "an integer"
+		case float64:
+			_, err = cast.ToFloat64E(value)
+			kind = "a number"
+		case time.Duration:
+			_, err = cast.ToDurationE(value)
+			kind = "a duration such as 30s or 5m"
+		}
+		if err != nil {
+			p.add(key, "must be %s, got %q", kind, cast.ToString(value))
+		}
+	}
+}
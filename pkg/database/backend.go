@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+
+	"github.com/rentalflow/rentalflow/pkg/config"
+)
+
+// Backend is the database a service stores its data in, MongoDB,
+// PostgreSQL or memory as its config selects. Exactly one of Mongo,
+// Postgres and Memory is set.
+type Backend struct {
+	Mongo    *Client
+	Postgres *Postgres
+	// Memory is set for the in-memory driver. Each repository then keeps
+	// its own data, which lasts as long as the process.
+	Memory bool
+}
+
+// Open connects to the database the config selects
+func Open(cfg config.DatabaseConfig) (*Backend, error) {
+	if cfg.Driver == config.DriverMemory {
+		return &Backend{Memory: true}, nil
+	}
+	if cfg.Driver == config.DriverPostgres {
+		db, err := NewPostgres(cfg.GetURI())
+		if err != nil {
+			return nil, err
+		}
+		return &Backend{Postgres: db}, nil
+	}
+
+	client, err := New(cfg.GetURI(), cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	return &Backend{Mongo: client}, nil
+}
+
+// Driver names the database, as in the database.driver setting
+func (b *Backend) Driver() string {
+	if b.Memory {
+		return config.DriverMemory
+	}
+	if b.Postgres != nil {
+		return config.DriverPostgres
+	}
+	return config.DriverMongo
+}
+
+// Close closes the connection
+func (b *Backend) Close(ctx context.Context) error {
+	if b.Memory {
+		return nil
+	}
+	if b.Postgres != nil {
+		b.Postgres.Close()
+		return nil
+	}
+	return b.Mongo.Close(ctx)
+}
+
+// Health checks if the database is healthy
+func (b *Backend) Health(ctx context.Context) error {
+	if b.Memory {
+		return nil
+	}
+	if b.Postgres != nil {
+		return b.Postgres.Health(ctx)
+	}
+	return b.Mongo.Health(ctx)
+}
+
+// WithTransaction runs fn in a transaction of the database. See
+// Client.WithTransaction and Postgres.WithTransaction. In memory fn simply
+// runs, as against a standalone MongoDB server.
+func (b *Backend) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if b.Memory {
+		return fn(ctx)
+	}
+	if b.Postgres != nil {
+		return b.Postgres.WithTransaction(ctx, fn)
+	}
+	return b.Mongo.WithTransaction(ctx, fn)
+}
+
+// Migrator returns the migrator of the database: mongo lists the service's
+// MongoDB migrations and postgres its SQL ones
+func (b *Backend) Migrator(mongo []Migration, postgres []SQLMigration) SchemaMigrator {
+	if b.Memory {
+		return memoryMigrator{}
+	}
+	if b.Postgres != nil {
+		return NewPostgresMigrator(b.Postgres, postgres)
+	}
+	return NewMigrator(b.Mongo.DB, mongo)
+}
@@ -0,0 +1,98 @@
+// Package databasetest opens throwaway databases for repository tests. Each
+// call creates a database with a random name, migrates it and drops it when
+// the test ends. Tests are skipped unless TEST_MONGODB_URI or
+// TEST_POSTGRES_URI names a server they may create databases on.
+package databasetest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// Mongo returns a migrated MongoDB database for the test
+func Mongo(t testing.TB, migrations []database.Migration) *database.Client {
+	t.Helper()
+	uri := os.Getenv("TEST_MONGODB_URI")
+	if uri == "" {
+		t.Skip("TEST_MONGODB_URI is not set")
+	}
+
+	client, err := database.New(uri, databaseName(t))
+	if err != nil {
+		t.Fatalf("connect to mongodb: %v", err)
+	}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		client.DB.Drop(ctx)
+		client.Close(ctx)
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := database.NewMigrator(client.DB, migrations).Up(ctx); err != nil {
+		t.Fatalf("migrate mongodb: %v", err)
+	}
+	return client
+}
+
+// Postgres returns a migrated PostgreSQL database for the test
+func Postgres(t testing.TB, migrations []database.SQLMigration) *database.Postgres {
+	t.Helper()
+	uri := os.Getenv("TEST_POSTGRES_URI")
+	if uri == "" {
+		t.Skip("TEST_POSTGRES_URI is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	admin, err := database.NewPostgres(uri)
+	if err != nil {
+		t.Fatalf("connect to postgres: %v", err)
+	}
+	name := databaseName(t)
+	if _, err := admin.Pool.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		admin.Close()
+		t.Fatalf("create database: %v", err)
+	}
+
+	config, err := pgxpool.ParseConfig(uri)
+	if err != nil {
+		t.Fatalf("parse TEST_POSTGRES_URI: %v", err)
+	}
+	config.ConnConfig.Database = name
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("connect to %s: %v", name, err)
+	}
+	db := &database.Postgres{Pool: pool}
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		db.Close()
+		admin.Pool.Exec(ctx, "DROP DATABASE IF EXISTS "+name+" WITH (FORCE)")
+		admin.Close()
+	})
+
+	if _, err := database.NewPostgresMigrator(db, migrations).Up(ctx); err != nil {
+		t.Fatalf("migrate postgres: %v", err)
+	}
+	return db
+}
+
+// databaseName returns a fresh database name, valid for both servers
+func databaseName(t testing.TB) string {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		t.Fatalf("generate database name: %v", err)
+	}
+	return "test_" + hex.EncodeToString(suffix)
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Clone returns a copy of doc that shares no memory with it, made by
+// encoding it as MongoDB would store it. In-memory repositories keep and
+// hand out clones, so callers can no more change stored data in place than
+// they could in a database, and times keep the millisecond precision a
+// database gives them. doc must be a document the repositories store.
+func Clone[T any](doc *T) *T {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		panic(fmt.Sprintf("database: failed to clone %T: %v", doc, err))
+	}
+	clone := new(T)
+	if err := bson.Unmarshal(data, clone); err != nil {
+		panic(fmt.Sprintf("database: failed to clone %T: %v", doc, err))
+	}
+	return clone
+}
+
+// Page returns the page of items at offset, at most limit long, as a
+// database skips and limits the results of a query
+func Page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// memoryMigrator is the migrator of the in-memory driver, whose
+// repositories need no schema
+type memoryMigrator struct{}
+
+// Up applies nothing
+func (memoryMigrator) Up(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+// Status lists no migrations
+func (memoryMigrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	return nil, nil
+}
+
+// Run carries out a migrate command
+func (m memoryMigrator) Run(ctx context.Context, command string, out io.Writer) error {
+	return runCommand(ctx, m, command, out)
+}
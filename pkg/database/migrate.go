@@ -0,0 +1,257 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationsCollection is the collection, or with PostgreSQL the table,
+// that records the migrations applied to a database
+const MigrationsCollection = "schema_migrations"
+
+// Migration is one versioned step of a service's schema: indexes to build
+// or data to rewrite. Steps run in version order and are recorded once
+// applied, so each runs once per database. They must still be idempotent,
+// since a step interrupted before it is recorded runs again in full, as
+// may a step two replicas start at the same time.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+// CreateIndexes returns a migration step that builds indexes on a
+// collection. Building an index that already exists with the same options
+// does nothing.
+func CreateIndexes(collection string, indexes ...mongo.IndexModel) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		if _, err := db.Collection(collection).Indexes().CreateMany(ctx, indexes); err != nil {
+			return fmt.Errorf("failed to create indexes on %s: %w", collection, err)
+		}
+		return nil
+	}
+}
+
+// BackfillVersion returns a migration step that sets version 1 on the
+// documents of a collection stored before its updates were versioned
+func BackfillVersion(collection string) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).UpdateMany(ctx,
+			bson.M{"version": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"version": 1}},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to backfill versions of %s: %w", collection, err)
+		}
+		return nil
+	}
+}
+
+// Steps combines migration steps into one, run in order
+func Steps(steps ...func(context.Context, *mongo.Database) error) func(context.Context, *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		for _, step := range steps {
+			if err := step(ctx, db); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// appliedMigration is the record of a migration in MigrationsCollection
+type appliedMigration struct {
+	Version     int           `bson:"_id"`
+	Description string        `bson:"description"`
+	AppliedAt   time.Time     `bson:"applied_at"`
+	Duration    time.Duration `bson:"duration"`
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Version     int
+	Description string
+	AppliedAt   *time.Time // nil while pending
+}
+
+// Migrator applies a service's migrations to its MongoDB database
+type Migrator struct {
+	db         *mongo.Database
+	coll       *mongo.Collection
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for the given migrations, which may be
+// listed in any order
+func NewMigrator(db *mongo.Database, migrations []Migration) *Migrator {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	return &Migrator{
+		db:         db,
+		coll:       db.Collection(MigrationsCollection),
+		migrations: sorted,
+	}
+}
+
+// Up applies every pending migration in version order and returns how many
+// it applied. It stops at the first that fails, leaving later ones pending.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	if err := m.check(); err != nil {
+		return 0, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		start := time.Now()
+		if err := migration.Up(ctx, m.db); err != nil {
+			return count, fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+		}
+		record := appliedMigration{
+			Version:     migration.Version,
+			Description: migration.Description,
+			AppliedAt:   time.Now(),
+			Duration:    time.Since(start),
+		}
+		// Another replica may have recorded the same step meanwhile
+		_, err := m.coll.UpdateOne(ctx,
+			bson.M{"_id": record.Version},
+			bson.M{"$setOnInsert": record},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			return count, fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+		}
+		count++
+
+		logger.Ctx(ctx).Info().
+			Int("version", migration.Version).
+			Str("description", migration.Description).
+			Dur("duration", record.Duration).
+			Msg("Applied migration")
+	}
+	return count, nil
+}
+
+// Status reports each migration and when it was applied, in version order
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{
+			Version:     migration.Version,
+			Description: migration.Description,
+		}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Run carries out a migrate command: "up" (the default) applies pending
+// migrations and "status" lists them. Output is written to out.
+func (m *Migrator) Run(ctx context.Context, command string, out io.Writer) error {
+	return runCommand(ctx, m, command, out)
+}
+
+// SchemaMigrator applies and lists a service's migrations, whichever
+// database it stores its data in
+type SchemaMigrator interface {
+	Up(ctx context.Context) (int, error)
+	Status(ctx context.Context) ([]MigrationStatus, error)
+	Run(ctx context.Context, command string, out io.Writer) error
+}
+
+// runCommand carries out a migrate command with a migrator
+func runCommand(ctx context.Context, m SchemaMigrator, command string, out io.Writer) error {
+	switch command {
+	case "", "up":
+		count, err := m.Up(ctx)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			fmt.Fprintln(out, "No pending migrations")
+		} else {
+			fmt.Fprintf(out, "Applied %d migration(s)\n", count)
+		}
+		return nil
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tAPPLIED\tDESCRIPTION")
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, applied, status.Description)
+		}
+		return w.Flush()
+
+	default:
+		return fmt.Errorf("unknown command %q, expected up or status", command)
+	}
+}
+
+// check rejects migrations that share a version or lack a step
+func (m *Migrator) check() error {
+	for i, migration := range m.migrations {
+		if migration.Up == nil {
+			return fmt.Errorf("migration %d has no step", migration.Version)
+		}
+		if i > 0 && m.migrations[i-1].Version == migration.Version {
+			return fmt.Errorf("migration version %d is declared twice", migration.Version)
+		}
+	}
+	return nil
+}
+
+// applied returns the recorded migrations by version
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedMigration, error) {
+	cursor, err := m.coll.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", MigrationsCollection, err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []appliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", MigrationsCollection, err)
+	}
+
+	applied := make(map[int]appliedMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
@@ -0,0 +1,219 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+)
+
+// migrationLockKey is the advisory lock that lets one replica at a time
+// apply PostgreSQL migrations
+const migrationLockKey = 7265746
+
+// SQLMigration is one versioned step of a service's PostgreSQL schema. It
+// runs in a transaction together with its record in MigrationsCollection,
+// so it is applied fully or not at all.
+type SQLMigration struct {
+	Version     int
+	Description string
+	SQL         string
+}
+
+// LoadSQLMigrations reads the migrations in the .sql files of a directory,
+// each named after its version and description, as in
+// 0001_create_bookings.sql
+func LoadSQLMigrations(fsys fs.FS, dir string) ([]SQLMigration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []SQLMigration
+	versions := make(map[int]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || path.Ext(name) != ".sql" {
+			continue
+		}
+
+		prefix, description, _ := strings.Cut(strings.TrimSuffix(name, ".sql"), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("migration %s does not start with a version", name)
+		}
+		if other, ok := versions[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, name, version)
+		}
+		versions[version] = name
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, SQLMigration{
+			Version:     version,
+			Description: strings.ReplaceAll(description, "_", " "),
+			SQL:         string(data),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// MustLoadSQLMigrations is like LoadSQLMigrations but panics if the files
+// cannot be read. It is meant for migrations embedded in a service binary.
+func MustLoadSQLMigrations(fsys fs.FS, dir string) []SQLMigration {
+	migrations, err := LoadSQLMigrations(fsys, dir)
+	if err != nil {
+		panic(fmt.Sprintf("database: %v", err))
+	}
+	return migrations
+}
+
+// PostgresMigrator applies a service's migrations to its PostgreSQL database
+type PostgresMigrator struct {
+	db         *Postgres
+	migrations []SQLMigration
+}
+
+// NewPostgresMigrator creates a migrator for the given migrations, which
+// may be listed in any order
+func NewPostgresMigrator(db *Postgres, migrations []SQLMigration) *PostgresMigrator {
+	sorted := append([]SQLMigration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+	return &PostgresMigrator{db: db, migrations: sorted}
+}
+
+// Up applies every pending migration in version order and returns how many
+// it applied. It stops at the first that fails, leaving later ones pending.
+func (m *PostgresMigrator) Up(ctx context.Context) (int, error) {
+	if err := m.createTable(ctx); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		var duration time.Duration
+		applied := false
+
+		err := pgx.BeginFunc(ctx, m.db.Pool, func(tx pgx.Tx) error {
+			// Replicas starting together wait here and find the step recorded
+			if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockKey); err != nil {
+				return err
+			}
+			var exists bool
+			err := tx.QueryRow(ctx,
+				`SELECT EXISTS (SELECT 1 FROM `+MigrationsCollection+` WHERE version = $1)`,
+				migration.Version,
+			).Scan(&exists)
+			if err != nil || exists {
+				return err
+			}
+
+			start := time.Now()
+			if _, err := tx.Exec(ctx, migration.SQL); err != nil {
+				return fmt.Errorf("migration %d (%s) failed: %w", migration.Version, migration.Description, err)
+			}
+			duration = time.Since(start)
+
+			_, err = tx.Exec(ctx,
+				`INSERT INTO `+MigrationsCollection+` (version, description, applied_at, duration_ms) VALUES ($1, $2, $3, $4)`,
+				migration.Version, migration.Description, time.Now(), duration.Milliseconds(),
+			)
+			applied = err == nil
+			return err
+		})
+		if err != nil {
+			return count, err
+		}
+		if !applied {
+			continue
+		}
+		count++
+
+		logger.Ctx(ctx).Info().
+			Int("version", migration.Version).
+			Str("description", migration.Description).
+			Dur("duration", duration).
+			Msg("Applied migration")
+	}
+	return count, nil
+}
+
+// Status reports each migration and when it was applied, in version order
+func (m *PostgresMigrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.createTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := m.db.Pool.Query(ctx, `SELECT version, applied_at FROM `+MigrationsCollection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", MigrationsCollection, err)
+	}
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to read %s: %w", MigrationsCollection, err)
+		}
+		applied[version] = appliedAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", MigrationsCollection, err)
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{
+			Version:     migration.Version,
+			Description: migration.Description,
+		}
+		if appliedAt, ok := applied[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Run carries out a migrate command: "up" (the default) applies pending
+// migrations and "status" lists them. Output is written to out.
+func (m *PostgresMigrator) Run(ctx context.Context, command string, out io.Writer) error {
+	return runCommand(ctx, m, command, out)
+}
+
+// createTable creates the table migrations are recorded in, holding the
+// migration lock so replicas starting together do not race to create it
+func (m *PostgresMigrator) createTable(ctx context.Context) error {
+	return pgx.BeginFunc(ctx, m.db.Pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockKey); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS `+MigrationsCollection+` (
+			version     integer PRIMARY KEY,
+			description text NOT NULL,
+			applied_at  timestamptz NOT NULL,
+			duration_ms bigint NOT NULL
+		)`)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", MigrationsCollection, err)
+		}
+		return nil
+	})
+}
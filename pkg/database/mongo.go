@@ -5,6 +5,10 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/metrics"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -14,6 +18,8 @@ import (
 type Client struct {
 	Client *mongo.Client
 	DB     *mongo.Database
+
+	transactions bool // the server supports multi-document transactions
 }
 
 // New creates a new MongoDB connection
@@ -21,8 +27,11 @@ func New(uri string, dbName string) (*Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	clientOptions := options.Client().ApplyURI(uri)
-	
+	clientOptions := options.Client().ApplyURI(uri).SetMonitor(chainMonitors(
+		tracing.MongoMonitor(),
+		metrics.MongoMonitor(),
+	))
+
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to mongodb: %w", err)
@@ -33,9 +42,18 @@ func New(uri string, dbName string) (*Client, error) {
 		return nil, fmt.Errorf("failed to ping mongodb: %w", err)
 	}
 
+	transactions, err := supportsTransactions(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query mongodb topology: %w", err)
+	}
+	if !transactions {
+		logger.Logger.Warn().Msg("MongoDB is a standalone server, multi-document writes run without transactions")
+	}
+
 	return &Client{
-		Client: client,
-		DB:     client.Database(dbName),
+		Client:       client,
+		DB:           client.Database(dbName),
+		transactions: transactions,
 	}, nil
 }
 
@@ -48,3 +66,24 @@ func (c *Client) Close(ctx context.Context) error {
 func (c *Client) Health(ctx context.Context) error {
 	return c.Client.Ping(ctx, readpref.Primary())
 }
+
+// chainMonitors passes each command event to every monitor in turn
+func chainMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			for _, m := range monitors {
+				m.Started(ctx, evt)
+			}
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			for _, m := range monitors {
+				m.Succeeded(ctx, evt)
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			for _, m := range monitors {
+				m.Failed(ctx, evt)
+			}
+		},
+	}
+}
@@ -0,0 +1,120 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgreSQL error codes the repositories act on
+const (
+	pgUniqueViolation      = "23505"
+	pgExclusionViolation   = "23P01"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// maxTransactionAttempts bounds how often a PostgreSQL transaction that
+// lost a serialization race is run again
+const maxTransactionAttempts = 3
+
+// Postgres holds the PostgreSQL connection pool
+type Postgres struct {
+	Pool *pgxpool.Pool
+}
+
+// Querier runs SQL statements. The pool and a transaction are both one.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// NewPostgres creates a new PostgreSQL connection pool
+func NewPostgres(uri string) (*Postgres, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to postgres: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping postgres: %w", err)
+	}
+
+	return &Postgres{Pool: pool}, nil
+}
+
+// Close closes the connection pool
+func (p *Postgres) Close() {
+	p.Pool.Close()
+}
+
+// Health checks if the database is healthy
+func (p *Postgres) Health(ctx context.Context) error {
+	return p.Pool.Ping(ctx)
+}
+
+// Conn returns the transaction ctx belongs to, or the pool outside of one.
+// Repositories run every statement on it so they join the caller's
+// transaction.
+func (p *Postgres) Conn(ctx context.Context) Querier {
+	if tx, ok := ctx.Value(transactionKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return p.Pool
+}
+
+// WithTransaction runs fn in a transaction and commits it if fn returns nil.
+// As with the MongoDB client, fn runs again from the start when the
+// transaction loses a serialization race or a deadlock, so it must not have
+// side effects outside the database. A call made within a transaction joins
+// it rather than starting another.
+func (p *Postgres) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if inTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := pgx.BeginFunc(ctx, p.Pool, func(tx pgx.Tx) error {
+			return fn(context.WithValue(ctx, transactionKey{}, tx))
+		})
+		if isPgError(err, pgSerializationFailure, pgDeadlockDetected) && attempt < maxTransactionAttempts {
+			continue
+		}
+		return err
+	}
+}
+
+// IsUniqueViolation reports whether err is a PostgreSQL unique constraint
+// violation
+func IsUniqueViolation(err error) bool {
+	return isPgError(err, pgUniqueViolation)
+}
+
+// IsExclusionViolation reports whether err is a PostgreSQL exclusion
+// constraint violation, such as two overlapping ranges
+func IsExclusionViolation(err error) bool {
+	return isPgError(err, pgExclusionViolation)
+}
+
+// isPgError reports whether err is a PostgreSQL error with one of codes
+func isPgError(err error, codes ...string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	for _, code := range codes {
+		if pgErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
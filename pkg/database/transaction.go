@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transactor runs a function in a transaction. Services take one so their
+// multi-document writes commit or fail together.
+type Transactor interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// WithTransaction runs fn in a transaction and commits it if fn returns nil.
+// Repositories join the transaction by passing the context fn receives to
+// the driver, as they already do with the context of any call.
+//
+// fn runs again from the start when the transaction fails with a
+// TransientTransactionError, such as a write conflict with a concurrent
+// transaction, so it must not have side effects outside the database:
+// publish events and send notifications after WithTransaction returns.
+//
+// A call made within a transaction joins it rather than starting another.
+// Against a standalone server, which has no transactions, fn simply runs.
+func (c *Client) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !c.transactions || inTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	session, err := c.Client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(context.WithValue(sessCtx, transactionKey{}, true))
+	})
+	return err
+}
+
+// transactionKey marks the context of a running transaction. With
+// PostgreSQL its value is the transaction itself.
+type transactionKey struct{}
+
+// inTransaction reports whether ctx belongs to a running transaction
+func inTransaction(ctx context.Context) bool {
+	return ctx.Value(transactionKey{}) != nil
+}
+
+// supportsTransactions reports whether the server is a replica set member
+// or a mongos router, the deployments that support transactions
+func supportsTransactions(ctx context.Context, client *mongo.Client) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return false, err
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"errors"
 	"fmt"
 
 	"google.golang.org/grpc/codes"
@@ -19,6 +20,8 @@ const (
 	ErrorTypeInternal       ErrorType = "INTERNAL"
 	ErrorTypeBadRequest     ErrorType = "BAD_REQUEST"
 	ErrorTypeServiceUnavail ErrorType = "SERVICE_UNAVAILABLE"
+	ErrorTypePrecondition   ErrorType = "FAILED_PRECONDITION"
+	ErrorTypeRateLimited    ErrorType = "RATE_LIMITED"
 )
 
 // AppError represents an application error
@@ -55,6 +58,10 @@ func (e *AppError) GRPCStatus() *status.Status {
 		code = codes.AlreadyExists
 	case ErrorTypeServiceUnavail:
 		code = codes.Unavailable
+	case ErrorTypePrecondition:
+		code = codes.FailedPrecondition
+	case ErrorTypeRateLimited:
+		code = codes.ResourceExhausted
 	}
 	return status.New(code, e.Message)
 }
@@ -127,15 +134,47 @@ func ServiceUnavailable(service string, err error) *AppError {
 	}
 }
 
-// ToGRPCError converts any error to a gRPC status error
+// FailedPrecondition creates an error for requests that conflict with the
+// resource's current state
+func FailedPrecondition(message string) *AppError {
+	return &AppError{
+		Type:    ErrorTypePrecondition,
+		Message: message,
+	}
+}
+
+// RateLimited creates a too-many-requests error
+func RateLimited(message string) *AppError {
+	return &AppError{
+		Type:    ErrorTypeRateLimited,
+		Message: message,
+	}
+}
+
+// Wrap classifies a domain error, keeping its text as the message
+func Wrap(errType ErrorType, err error) *AppError {
+	return &AppError{
+		Type:    errType,
+		Message: err.Error(),
+		Err:     err,
+	}
+}
+
+// ToGRPCError converts any error to a gRPC status error. Status errors, such
+// as those returned by a downstream gRPC call, are passed through unchanged.
 func ToGRPCError(err error) error {
 	if err == nil {
 		return nil
 	}
 
-	if appErr, ok := err.(*AppError); ok {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
 		return appErr.GRPCStatus().Err()
 	}
 
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
 	return status.Error(codes.Internal, "internal server error")
 }
@@ -0,0 +1,76 @@
+// Package etag carries document versions as ETags so clients can make
+// conditional updates with If-Match. Over HTTP they are the ETag and
+// If-Match headers; over gRPC they are "etag" and "if-match" metadata,
+// which the gateway maps to and from those headers.
+package etag
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataKey carries an ETag in gRPC response metadata
+const MetadataKey = "etag"
+
+// If-Match metadata, as sent by gRPC clients and as the gateway's
+// transcoder forwards the If-Match header
+const (
+	metadataIfMatch = "if-match"
+	gatewayIfMatch  = "grpcgateway-if-match"
+)
+
+// Format returns the ETag of a version
+func Format(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// Set writes the ETag header of a version to an HTTP response
+func Set(w http.ResponseWriter, version int64) {
+	w.Header().Set("ETag", Format(version))
+}
+
+// IfMatch returns the version an HTTP request's If-Match header requires,
+// or 0 when it requires none. See Expected.
+func IfMatch(r *http.Request) int64 {
+	return Expected(r.Header.Get("If-Match"))
+}
+
+// Expected parses an If-Match value into the version it requires. An
+// absent value or "*" requires none and gives 0. A value that is not the
+// ETag of any version gives -1, which no document matches.
+func Expected(ifMatch string) int64 {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "" || ifMatch == "*" {
+		return 0
+	}
+	version, err := strconv.ParseInt(strings.Trim(ifMatch, `"`), 10, 64)
+	if err != nil || version < 1 {
+		return -1
+	}
+	return version
+}
+
+// FromContext returns the version the If-Match of an incoming gRPC call
+// requires, or 0 when it requires none
+func FromContext(ctx context.Context) int64 {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0
+	}
+	for _, key := range []string{metadataIfMatch, gatewayIfMatch} {
+		if values := md.Get(key); len(values) > 0 {
+			return Expected(values[0])
+		}
+	}
+	return 0
+}
+
+// SetHeader sends the ETag of a version with a gRPC response
+func SetHeader(ctx context.Context, version int64) {
+	grpc.SetHeader(ctx, metadata.Pairs(MetadataKey, Format(version)))
+}
@@ -0,0 +1,180 @@
+// Package health runs readiness checks against a service's dependencies and
+// reports the result of each one.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Check reports whether a dependency is usable. It should give up when ctx
+// is done.
+type Check func(ctx context.Context) error
+
+// Overall readiness of a service
+const (
+	StatusReady    = "ready"
+	StatusDegraded = "degraded"
+	StatusNotReady = "not_ready"
+)
+
+// Result of a single dependency check
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// Result is the outcome of one dependency's check
+type Result struct {
+	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the readiness of a service and each of its dependencies
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Registry holds a service's dependency checks. Each check runs with a
+// timeout, and its result is reused for the cache TTL so frequent probes do
+// not put load on the dependencies.
+type Registry struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu     sync.Mutex
+	checks []*check
+}
+
+type check struct {
+	name     string
+	run      Check
+	critical bool
+
+	mu     sync.Mutex
+	result Result
+}
+
+// NewRegistry creates a registry whose checks time out after timeout and
+// are cached for cacheTTL
+func NewRegistry(timeout, cacheTTL time.Duration) *Registry {
+	return &Registry{timeout: timeout, cacheTTL: cacheTTL}
+}
+
+// Register adds a dependency the service cannot serve without. While it is
+// down the service is not ready.
+func (r *Registry) Register(name string, run Check) {
+	r.add(&check{name: name, run: run, critical: true})
+}
+
+// RegisterOptional adds a dependency the service can partly work without.
+// While it is down the service is degraded but still ready.
+func (r *Registry) RegisterOptional(name string, run Check) {
+	r.add(&check{name: name, run: run})
+}
+
+func (r *Registry) add(c *check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, c)
+}
+
+// Check runs every check concurrently, or reuses its cached result, and
+// reports the service's readiness
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.Lock()
+	checks := append([]*check(nil), r.checks...)
+	r.mu.Unlock()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			results[i] = r.result(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusReady, Checks: make(map[string]Result, len(checks))}
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+		if results[i].Status == StatusUp {
+			continue
+		}
+		if c.critical {
+			report.Status = StatusNotReady
+		} else if report.Status == StatusReady {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// result returns the cached result of c, running it again once it is older
+// than the cache TTL. Callers that arrive while c runs wait for its result
+// rather than starting another run.
+func (r *Registry) result(ctx context.Context, c *check) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.result.CheckedAt.IsZero() && time.Since(c.result.CheckedAt) < r.cacheTTL {
+		return c.result
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.run(checkCtx)
+	if err == nil && checkCtx.Err() != nil {
+		err = checkCtx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = errors.New("check timed out after " + r.timeout.String())
+	}
+
+	result := Result{
+		Status:    StatusUp,
+		Critical:  c.critical,
+		Duration:  time.Since(start).Round(time.Millisecond).String(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	// A caller that went away says nothing about the dependency
+	if ctx.Err() == nil {
+		c.result = result
+	}
+	return result
+}
+
+// Handler serves the readiness report as JSON, with 503 Service Unavailable
+// when a critical dependency is down
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := r.Check(req.Context())
+
+		statusCode := http.StatusOK
+		if report.Status == StatusNotReady {
+			statusCode = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(statusCode)
+		json.NewEncoder(w).Encode(report)
+	})
+}
@@ -1,17 +1,23 @@
 package logger
 
 import (
+	"context"
+	"io"
 	"os"
 	"time"
 
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Logger is the global logger instance
 var Logger zerolog.Logger
 
-// Init initializes the logger with the given service name and log level
-func Init(serviceName, level string) {
+// Init initializes the logger with the given service name, log level and
+// format: "json", one object per line for log aggregation, or "console"
+// for reading in a terminal. Personal data and credentials are redacted
+// from every line.
+func Init(serviceName, level, format string) {
 	// Parse log level
 	logLevel, err := zerolog.ParseLevel(level)
 	if err != nil {
@@ -22,17 +28,54 @@ func Init(serviceName, level string) {
 	zerolog.SetGlobalLevel(logLevel)
 	zerolog.TimeFieldFormat = time.RFC3339
 
-	// Create logger with console writer for development
-	output := zerolog.ConsoleWriter{
-		Out:        os.Stdout,
-		TimeFormat: "15:04:05",
+	var output io.Writer = os.Stdout
+	if format != "json" {
+		// Console writer for development
+		output = zerolog.ConsoleWriter{
+			Out:        os.Stdout,
+			TimeFormat: "15:04:05",
+		}
 	}
 
-	Logger = zerolog.New(output).
+	Logger = zerolog.New(redactWriter{out: output}).
 		With().
 		Timestamp().
 		Str("service", serviceName).
-		Logger()
+		Logger().
+		Hook(ContextHook{})
+}
+
+// SetLevel changes the minimum level logged, e.g. after a config reload.
+// An unknown level is ignored.
+func SetLevel(level string) {
+	if logLevel, err := zerolog.ParseLevel(level); err == nil {
+		zerolog.SetGlobalLevel(logLevel)
+	}
+}
+
+// ContextHook adds the request ID and the trace and span IDs of the
+// event's context to each log line, so the lines for one user action can
+// be joined across services and matched with its trace
+type ContextHook struct{}
+
+func (ContextHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	ctx := e.GetCtx()
+	if id := RequestID(ctx); id != "" {
+		e.Str("request_id", id)
+	}
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return
+	}
+	e.Str("trace_id", spanContext.TraceID().String()).
+		Str("span_id", spanContext.SpanID().String())
+}
+
+// Ctx returns the global logger bound to ctx, so its lines carry the
+// request ID and trace of the current request
+func Ctx(ctx context.Context) *zerolog.Logger {
+	l := Logger.With().Ctx(ctx).Logger()
+	return &l
 }
 
 // NewLogger creates a new logger with additional context
@@ -0,0 +1,96 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/rentalflow/rentalflow/pkg/config"
+)
+
+// Handler handles the JSON body of a message. Its context carries the trace
+// and request ID of the publisher.
+type Handler func(ctx context.Context, body []byte) error
+
+// Publisher publishes events to topics. A topic, such as booking_events,
+// carries one kind of event; the key of each message, such as
+// booking.created, says what happened and is what subscribers select on.
+type Publisher interface {
+	// Publish publishes body, encoded as JSON, to topic under key
+	Publish(ctx context.Context, topic, key string, body interface{}) error
+}
+
+// Subscriber delivers the events of a topic to handlers.
+//
+// Keys are words separated by dots. A pattern matches keys word by word,
+// where "*" stands for exactly one word and "#" for any number; "#" should
+// only end a pattern, so that every backend can express it.
+//
+// Subscribers that name the same group share its messages, each message
+// going to one of them, and the group keeps messages while none is running.
+// A subscriber with no group gets its own copy of every message for as
+// long as it runs.
+type Subscriber interface {
+	// Subscribe delivers the messages of topic with keys matching pattern
+	// to handler until the broker is closed
+	Subscribe(topic, pattern, group string, handler Handler) error
+}
+
+// Broker is the message broker the services exchange events through.
+// MessageBroker maps topics to RabbitMQ topic exchanges and groups to
+// queues, and Bus delivers messages within the process. A NATS JetStream
+// backend would map a topic to a stream, a key to the subject topic.key
+// and a group to a durable consumer.
+type Broker interface {
+	Publisher
+	Subscriber
+
+	// Health reports whether the broker can be used
+	Health(ctx context.Context) error
+
+	// Close stops delivering messages and disconnects
+	Close()
+}
+
+// Open connects to the broker the config selects
+func Open(cfg *config.Config) (Broker, error) {
+	if cfg.Messaging.Driver == config.BrokerMemory {
+		return NewBus(), nil
+	}
+	return NewMessageBroker(cfg.RabbitMQ.URL())
+}
+
+// Health returns the health check of broker. A nil broker, left by a failed
+// connection at startup, is reported as down.
+func Health(broker Broker) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if broker == nil {
+			return errors.New("not connected to the message broker")
+		}
+		return broker.Health(ctx)
+	}
+}
+
+// MatchKey reports whether key matches a subscription pattern
+func MatchKey(pattern, key string) bool {
+	return matchWords(strings.Split(pattern, "."), strings.Split(key, "."))
+}
+
+func matchWords(pattern, words []string) bool {
+	if len(pattern) == 0 {
+		return len(words) == 0
+	}
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(words); i++ {
+			if matchWords(pattern[1:], words[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(words) > 0 && matchWords(pattern[1:], words[1:])
+	default:
+		return len(words) > 0 && pattern[0] == words[0] && matchWords(pattern[1:], words[1:])
+	}
+}
@@ -0,0 +1,252 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rentalflow/rentalflow/pkg/logger"
+)
+
+func TestMatchKey(t *testing.T) {
+	tests := []struct {
+		pattern string
+		key     string
+		want    bool
+	}{
+		{"booking.created", "booking.created", true},
+		{"booking.created", "booking.cancelled", false},
+		{"booking.created", "booking.created.late", false},
+		{"booking.*", "booking.created", true},
+		{"booking.*", "booking", false},
+		{"booking.*", "booking.created.late", false},
+		{"*.created", "item.created", true},
+		{"*.created", "created", false},
+		{"booking.#", "booking", true},
+		{"booking.#", "booking.created", true},
+		{"booking.#", "booking.created.late", true},
+		{"booking.#", "item.created", false},
+		{"#", "", true},
+		{"#", "item.created", true},
+		{"*.*.#", "booking.created.late", true},
+		{"*.*.#", "booking", false},
+	}
+	for _, tt := range tests {
+		if got := MatchKey(tt.pattern, tt.key); got != tt.want {
+			t.Errorf("MatchKey(%q, %q) = %v, want %v", tt.pattern, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestBus(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("RoutesByTopicAndPattern", func(t *testing.T) {
+		bus := NewBus()
+		defer bus.Close()
+
+		tests := []struct {
+			topic   string
+			pattern string
+			want    []string
+		}{
+			{"booking_events", "booking.#", []string{"booking.created", "booking.cancelled"}},
+			{"booking_events", "booking.created", []string{"booking.created"}},
+			{"booking_events", "*.cancelled", []string{"booking.cancelled"}},
+			{"inventory_events", "#", []string{"item.created"}},
+		}
+		inboxes := make([]*inbox, len(tests))
+		for i, tt := range tests {
+			inboxes[i] = newInbox()
+			if err := bus.Subscribe(tt.topic, tt.pattern, "", inboxes[i].handle); err != nil {
+				t.Fatalf("Subscribe(%q, %q): %v", tt.topic, tt.pattern, err)
+			}
+		}
+
+		mustPublish(t, bus, "booking_events", "booking.created")
+		mustPublish(t, bus, "booking_events", "booking.cancelled")
+		mustPublish(t, bus, "inventory_events", "item.created")
+
+		for i, tt := range tests {
+			if got := inboxes[i].wait(t, len(tt.want)); !equal(got, tt.want) {
+				t.Errorf("%s %s received %v, want %v", tt.topic, tt.pattern, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("GroupSharesMessages", func(t *testing.T) {
+		bus := NewBus()
+		defer bus.Close()
+
+		shared := newInbox()
+		for i := 0; i < 3; i++ {
+			if err := bus.Subscribe("booking_events", "booking.#", "notifications", shared.handle); err != nil {
+				t.Fatalf("Subscribe: %v", err)
+			}
+		}
+		own := []*inbox{newInbox(), newInbox()}
+		for _, in := range own {
+			if err := bus.Subscribe("booking_events", "booking.#", "", in.handle); err != nil {
+				t.Fatalf("Subscribe: %v", err)
+			}
+		}
+
+		for i := 0; i < 10; i++ {
+			mustPublish(t, bus, "booking_events", "booking.created")
+		}
+
+		// The group handles each message once; every subscriber without a
+		// group gets its own copy
+		if got := shared.wait(t, 10); len(got) != 10 {
+			t.Errorf("group received %d messages, want 10", len(got))
+		}
+		for i, in := range own {
+			if got := in.wait(t, 10); len(got) != 10 {
+				t.Errorf("subscriber %d received %d messages, want 10", i, len(got))
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		if got := shared.count(); got != 10 {
+			t.Errorf("group received %d messages in the end, want 10", got)
+		}
+	})
+
+	t.Run("MatchesEachQueueOnce", func(t *testing.T) {
+		bus := NewBus()
+		defer bus.Close()
+
+		in := newInbox()
+		bus.Subscribe("booking_events", "booking.#", "notifications", in.handle)
+		bus.Subscribe("booking_events", "booking.created", "notifications", in.handle)
+
+		mustPublish(t, bus, "booking_events", "booking.created")
+		in.wait(t, 1)
+		time.Sleep(50 * time.Millisecond)
+		if got := in.count(); got != 1 {
+			t.Errorf("group received %d copies of a message matching two of its patterns, want 1", got)
+		}
+	})
+
+	t.Run("CarriesRequestID", func(t *testing.T) {
+		bus := NewBus()
+		defer bus.Close()
+
+		ids := make(chan string, 1)
+		bus.Subscribe("booking_events", "#", "", func(ctx context.Context, body []byte) error {
+			ids <- logger.RequestID(ctx)
+			return nil
+		})
+
+		if err := bus.Publish(logger.WithRequestID(ctx, "req-1"), "booking_events", "booking.created", nil); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+		select {
+		case id := <-ids:
+			if id != "req-1" {
+				t.Errorf("handler saw request ID %q, want req-1", id)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("message was not delivered")
+		}
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		bus := NewBus()
+		if err := bus.Health(ctx); err != nil {
+			t.Errorf("Health of an open bus = %v", err)
+		}
+		bus.Close()
+		bus.Close()
+
+		if err := bus.Health(ctx); err == nil {
+			t.Error("Health of a closed bus = nil, want an error")
+		}
+		if err := bus.Publish(ctx, "booking_events", "booking.created", nil); err == nil {
+			t.Error("Publish on a closed bus = nil, want an error")
+		}
+		if err := bus.Subscribe("booking_events", "#", "", newInbox().handle); err == nil {
+			t.Error("Subscribe on a closed bus = nil, want an error")
+		}
+	})
+}
+
+func TestHealthOfMissingBroker(t *testing.T) {
+	if err := Health(nil)(context.Background()); err == nil {
+		t.Error("Health(nil) = nil, want an error")
+	}
+}
+
+// inbox collects the keys of the messages delivered to its handler
+type inbox struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func newInbox() *inbox {
+	return &inbox{}
+}
+
+func (in *inbox) handle(ctx context.Context, body []byte) error {
+	var msg struct {
+		Key string `json:"key"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return err
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	in.keys = append(in.keys, msg.Key)
+	return nil
+}
+
+func (in *inbox) count() int {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return len(in.keys)
+}
+
+// wait returns the keys received once there are n, or what arrived within
+// a second
+func (in *inbox) wait(t *testing.T, n int) []string {
+	t.Helper()
+
+	deadline := time.Now().Add(time.Second)
+	for in.count() < n && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return append([]string(nil), in.keys...)
+}
+
+// mustPublish publishes a message whose body names its key
+func mustPublish(t *testing.T, bus *Bus, topic, key string) {
+	t.Helper()
+
+	if err := bus.Publish(context.Background(), topic, key, map[string]string{"key": key}); err != nil {
+		t.Fatalf("Publish(%q, %q): %v", topic, key, err)
+	}
+}
+
+// equal reports whether two lists of keys hold the same keys, in any order
+func equal(got, want []string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	seen := make(map[string]int)
+	for _, key := range got {
+		seen[key]++
+	}
+	for _, key := range want {
+		seen[key]--
+		if seen[key] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,211 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Bus is a Broker that delivers messages within the process, for running
+// the services in one binary and for tests. Subscribers receive messages
+// asynchronously with the trace and request ID of the publisher, as they
+// would from RabbitMQ. Nothing outlives the process.
+type Bus struct {
+	mu            sync.Mutex
+	groups        map[string]*busQueue
+	subscriptions []busSubscription
+	closed        bool
+}
+
+// busSubscription routes the messages of a topic matching pattern to queue
+type busSubscription struct {
+	topic   string
+	pattern string
+	queue   *busQueue
+}
+
+// busMessage is a message waiting in a queue
+type busMessage struct {
+	topic   string
+	key     string
+	body    []byte
+	headers propagation.MapCarrier
+}
+
+// NewBus creates a bus with no subscribers
+func NewBus() *Bus {
+	return &Bus{groups: make(map[string]*busQueue)}
+}
+
+// Publish delivers a message to every group and subscriber whose pattern
+// matches its key. Messages nobody subscribes to are dropped.
+func (b *Bus) Publish(ctx context.Context, topic, key string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	msg := busMessage{topic: topic, key: key, body: data, headers: propagation.MapCarrier{}}
+	otel.GetTextMapPropagator().Inject(ctx, msg.headers)
+	if id := logger.RequestID(ctx); id != "" {
+		msg.headers[logger.RequestIDHeader] = id
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return errors.New("message bus is closed")
+	}
+	delivered := make(map[*busQueue]bool)
+	for _, sub := range b.subscriptions {
+		if sub.topic == topic && !delivered[sub.queue] && MatchKey(sub.pattern, key) {
+			sub.queue.put(msg)
+			delivered[sub.queue] = true
+		}
+	}
+	return nil
+}
+
+// Subscribe starts delivering the matching messages of topic to handler
+func (b *Bus) Subscribe(topic, pattern, group string, handler Handler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return errors.New("message bus is closed")
+	}
+	q := newBusQueue()
+	if group != "" {
+		if existing, ok := b.groups[group]; ok {
+			q = existing
+		} else {
+			b.groups[group] = q
+		}
+	}
+	b.subscriptions = append(b.subscriptions, busSubscription{topic: topic, pattern: pattern, queue: q})
+
+	name := group
+	if name == "" {
+		name = topic
+	}
+	go func() {
+		for {
+			msg, ok := q.get()
+			if !ok {
+				return
+			}
+			deliver(name, msg, handler)
+		}
+	}()
+	return nil
+}
+
+// deliver runs handler on a message in the trace and request of its publisher
+func deliver(name string, msg busMessage, handler Handler) {
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), msg.headers)
+	requestID := msg.headers[logger.RequestIDHeader]
+	if requestID == "" {
+		requestID = logger.NewRequestID()
+	}
+	ctx = logger.WithRequestID(ctx, requestID)
+	ctx, span := tracing.Tracer().Start(ctx, name+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "in-process"),
+			attribute.String("messaging.source.name", name),
+			attribute.String("messaging.destination.name", msg.topic),
+		),
+	)
+	defer span.End()
+
+	if err := handler(ctx, msg.body); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		logger.Ctx(ctx).Error().Err(err).
+			Str("topic", msg.topic).
+			Str("key", msg.key).
+			Msg("Failed to handle message")
+	}
+}
+
+// Health reports whether the bus is still open
+func (b *Bus) Health(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return errors.New("message bus is closed")
+	}
+	return nil
+}
+
+// Close stops every subscriber. Messages not yet delivered are dropped.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for _, sub := range b.subscriptions {
+		sub.queue.close()
+	}
+}
+
+// busQueue holds the messages of a group or subscriber until it takes them
+type busQueue struct {
+	mu      sync.Mutex
+	ready   *sync.Cond
+	pending []busMessage
+	closed  bool
+}
+
+func newBusQueue() *busQueue {
+	q := &busQueue{}
+	q.ready = sync.NewCond(&q.mu)
+	return q
+}
+
+func (q *busQueue) put(msg busMessage) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending = append(q.pending, msg)
+	q.ready.Signal()
+}
+
+// get waits for the next message. It returns false once the queue is closed.
+func (q *busQueue) get() (busMessage, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.pending) == 0 && !q.closed {
+		q.ready.Wait()
+	}
+	if q.closed {
+		return busMessage{}, false
+	}
+	msg := q.pending[0]
+	q.pending = q.pending[1:]
+	return msg, true
+}
+
+func (q *busQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	q.ready.Broadcast()
+}
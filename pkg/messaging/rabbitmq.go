@@ -3,15 +3,30 @@ package messaging
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/metrics"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// MessageBroker defines the wrapper for RabbitMQ operations
+// MessageBroker is a Broker over RabbitMQ. Each topic is a topic exchange,
+// each group a durable queue named after it, and each subscriber without a
+// group gets a temporary queue.
 type MessageBroker struct {
 	conn    *amqp.Connection
 	channel *amqp.Channel
+
+	mu     sync.Mutex
+	topics map[string]bool // exchanges declared so far
 }
 
 // NewMessageBroker creates a new RabbitMQ message broker
@@ -30,29 +45,84 @@ func NewMessageBroker(url string) (*MessageBroker, error) {
 	return &MessageBroker{
 		conn:    conn,
 		channel: ch,
+		topics:  make(map[string]bool),
 	}, nil
 }
 
-// Publish publishes a message to an exchange with a routing key
-func (b *MessageBroker) Publish(ctx context.Context, exchange, routingKey string, body interface{}) error {
+// Publish publishes a message to the exchange of topic with key as its
+// routing key. The trace and request ID in ctx travel in the message
+// headers so consumers can continue them.
+func (b *MessageBroker) Publish(ctx context.Context, topic, key string, body interface{}) error {
+	if err := b.declareTopic(topic); err != nil {
+		return err
+	}
+	ctx, span := tracing.Tracer().Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", topic),
+			attribute.String("messaging.rabbitmq.destination.routing_key", key),
+		),
+	)
+	defer span.End()
+
 	data, err := json.Marshal(body)
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	return b.channel.PublishWithContext(ctx,
-		exchange,   // exchange
-		routingKey, // routing key
-		false,      // mandatory
-		false,      // immediate
+	headers := amqp.Table{}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(headers))
+	if id := logger.RequestID(ctx); id != "" {
+		headers[logger.RequestIDHeader] = id
+	}
+
+	err = b.channel.PublishWithContext(ctx,
+		topic, // exchange
+		key,   // routing key
+		false, // mandatory
+		false, // immediate
 		amqp.Publishing{
 			ContentType: "application/json",
+			Headers:     headers,
+			Timestamp:   time.Now(),
 			Body:        data,
 		})
+	metrics.ObservePublish(topic, key, err)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// Subscribe binds the queue of group, or a temporary queue when group is
+// empty, to the exchange of topic with pattern and consumes it
+func (b *MessageBroker) Subscribe(topic, pattern, group string, handler Handler) error {
+	if err := b.declareTopic(topic); err != nil {
+		return err
+	}
+
+	var q amqp.Queue
+	var err error
+	if group == "" {
+		q, err = b.DeclareTemporaryQueue()
+	} else {
+		q, err = b.DeclareQueue(group)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to declare queue: %w", err)
+	}
+	if err := b.BindQueue(q.Name, pattern, topic); err != nil {
+		return fmt.Errorf("failed to bind queue: %w", err)
+	}
+	return b.Consume(q.Name, handler)
 }
 
-// Subscribe registers a consumer for a specific queue
-func (b *MessageBroker) Subscribe(queueName string, handler func([]byte) error) error {
+// Consume registers a consumer for a specific queue. The handler's context
+// carries the trace and request ID of the publisher.
+func (b *MessageBroker) Consume(queueName string, handler Handler) error {
 	msgs, err := b.channel.Consume(
 		queueName, // queue
 		"",        // consumer
@@ -68,16 +138,52 @@ func (b *MessageBroker) Subscribe(queueName string, handler func([]byte) error)
 
 	go func() {
 		for d := range msgs {
-			if err := handler(d.Body); err != nil {
-				// In a real app, we'd use a logger here
-				fmt.Printf("Error handling message from %s: %v\n", queueName, err)
+			ctx := otel.GetTextMapPropagator().Extract(context.Background(), headerCarrier(d.Headers))
+			requestID, _ := d.Headers[logger.RequestIDHeader].(string)
+			if requestID == "" {
+				requestID = logger.NewRequestID()
+			}
+			ctx = logger.WithRequestID(ctx, requestID)
+			ctx, span := tracing.Tracer().Start(ctx, queueName+" process",
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(
+					attribute.String("messaging.system", "rabbitmq"),
+					attribute.String("messaging.source.name", queueName),
+					attribute.String("messaging.rabbitmq.destination.routing_key", d.RoutingKey),
+				),
+			)
+			err := handler(ctx, d.Body)
+			metrics.ObserveConsume(queueName, d.Timestamp, err)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				logger.Ctx(ctx).Error().Err(err).
+					Str("queue", queueName).
+					Str("routing_key", d.RoutingKey).
+					Msg("Failed to handle message")
 			}
+			span.End()
 		}
 	}()
 
 	return nil
 }
 
+// declareTopic declares the topic exchange of topic, once per broker
+func (b *MessageBroker) declareTopic(topic string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.topics[topic] {
+		return nil
+	}
+	if err := b.DeclareExchange(topic, amqp.ExchangeTopic); err != nil {
+		return fmt.Errorf("failed to declare exchange %s: %w", topic, err)
+	}
+	b.topics[topic] = true
+	return nil
+}
+
 // DeclareQueue ensures a queue exists
 func (b *MessageBroker) DeclareQueue(name string) (amqp.Queue, error) {
 	return b.channel.QueueDeclare(
@@ -90,6 +196,19 @@ func (b *MessageBroker) DeclareQueue(name string) (amqp.Queue, error) {
 	)
 }
 
+// DeclareTemporaryQueue declares a server-named queue that is deleted with
+// the connection, for consumers that each need their own copy of every event
+func (b *MessageBroker) DeclareTemporaryQueue() (amqp.Queue, error) {
+	return b.channel.QueueDeclare(
+		"",    // name
+		false, // durable
+		true,  // delete when unused
+		true,  // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+}
+
 // DeclareExchange ensures an exchange exists
 func (b *MessageBroker) DeclareExchange(name, kind string) error {
 	return b.channel.ExchangeDeclare(
@@ -123,3 +242,38 @@ func (b *MessageBroker) Close() {
 		b.conn.Close()
 	}
 }
+
+// Health reports whether the broker connection and channel are open. A nil
+// broker, left by a failed connection at startup, is reported as down.
+func (b *MessageBroker) Health(ctx context.Context) error {
+	if b == nil {
+		return errors.New("not connected to rabbitmq")
+	}
+	if b.conn.IsClosed() {
+		return errors.New("rabbitmq connection is closed")
+	}
+	if b.channel.IsClosed() {
+		return errors.New("rabbitmq channel is closed")
+	}
+	return nil
+}
+
+// headerCarrier lets the trace propagator read and write message headers
+type headerCarrier amqp.Table
+
+func (c headerCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	bookingsCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bookings_created_total",
+		Help: "Bookings created.",
+	})
+
+	bookingsCancelled = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "bookings_cancelled_total",
+		Help: "Bookings cancelled.",
+	})
+
+	payments = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "payments_total",
+		Help: "Payments that reached a final state, by status. The success rate is the share with status \"completed\".",
+	}, []string{"status"})
+
+	notifications = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notifications_total",
+		Help: "Notifications sent, by channel and outcome.",
+	}, []string{"channel", "outcome"})
+)
+
+// BookingCreated counts a new booking
+func BookingCreated() {
+	bookingsCreated.Inc()
+}
+
+// BookingCancelled counts a cancelled booking
+func BookingCancelled() {
+	bookingsCancelled.Inc()
+}
+
+// PaymentCompleted counts a payment that succeeded
+func PaymentCompleted() {
+	payments.WithLabelValues("completed").Inc()
+}
+
+// PaymentFailed counts a payment that failed
+func PaymentFailed() {
+	payments.WithLabelValues("failed").Inc()
+}
+
+// NotificationSent counts a notification delivered on channel, or one that
+// could not be delivered when err is set
+func NotificationSent(channel string, err error) {
+	notifications.WithLabelValues(channel, outcome(err)).Inc()
+}
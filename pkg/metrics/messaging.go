@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	messagesPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "amqp_messages_published_total",
+		Help: "Messages published to RabbitMQ, by exchange, routing key and outcome.",
+	}, []string{"exchange", "routing_key", "outcome"})
+
+	messagesConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "amqp_messages_consumed_total",
+		Help: "Messages consumed from RabbitMQ, by queue and outcome.",
+	}, []string{"queue", "outcome"})
+
+	messageAge = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "amqp_message_age_seconds",
+		Help:    "Time between a message being published and consumed, by queue.",
+		Buckets: []float64{1, 2, 5, 10, 30, 60, 120, 300, 600, 1800},
+	}, []string{"queue"})
+)
+
+// ObservePublish records a message published to exchange
+func ObservePublish(exchange, routingKey string, err error) {
+	messagesPublished.WithLabelValues(exchange, routingKey, outcome(err)).Inc()
+}
+
+// ObserveConsume records a message handled from queue. publishedAt is the
+// message timestamp and is ignored when zero.
+func ObserveConsume(queue string, publishedAt time.Time, err error) {
+	messagesConsumed.WithLabelValues(queue, outcome(err)).Inc()
+	if !publishedAt.IsZero() {
+		messageAge.WithLabelValues(queue).Observe(time.Since(publishedAt).Seconds())
+	}
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
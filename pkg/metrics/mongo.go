@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+var mongoDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "mongodb_command_duration_seconds",
+	Help:    "Time taken by MongoDB commands, by command, collection and outcome.",
+	Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"command", "collection", "outcome"})
+
+// MongoMonitor returns a command monitor that records the latency of each
+// MongoDB command
+func MongoMonitor() *event.CommandMonitor {
+	var collections sync.Map // request ID -> collection name
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			collection := ""
+			// The first element of a command names the collection it acts on
+			if elem, err := evt.Command.IndexErr(0); err == nil {
+				collection, _ = elem.Value().StringValueOK()
+			}
+			collections.Store(evt.RequestID, collection)
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			collection, _ := collections.LoadAndDelete(evt.RequestID)
+			name, _ := collection.(string)
+			mongoDuration.WithLabelValues(evt.CommandName, name, "success").Observe(evt.Duration.Seconds())
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			collection, _ := collections.LoadAndDelete(evt.RequestID)
+			name, _ := collection.(string)
+			mongoDuration.WithLabelValues(evt.CommandName, name, "failure").Observe(evt.Duration.Seconds())
+		},
+	}
+}
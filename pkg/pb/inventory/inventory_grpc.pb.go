@@ -0,0 +1,561 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: inventory/inventory.proto
+
+package inventory
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	InventoryService_ListItems_FullMethodName            = "/inventory.InventoryService/ListItems"
+	InventoryService_GetItem_FullMethodName              = "/inventory.InventoryService/GetItem"
+	InventoryService_SearchItems_FullMethodName          = "/inventory.InventoryService/SearchItems"
+	InventoryService_CreateItem_FullMethodName           = "/inventory.InventoryService/CreateItem"
+	InventoryService_UpdateItem_FullMethodName           = "/inventory.InventoryService/UpdateItem"
+	InventoryService_DeleteItem_FullMethodName           = "/inventory.InventoryService/DeleteItem"
+	InventoryService_GetOwnerItems_FullMethodName        = "/inventory.InventoryService/GetOwnerItems"
+	InventoryService_GetAvailability_FullMethodName      = "/inventory.InventoryService/GetAvailability"
+	InventoryService_UpdateAvailability_FullMethodName   = "/inventory.InventoryService/UpdateAvailability"
+	InventoryService_BlockDates_FullMethodName           = "/inventory.InventoryService/BlockDates"
+	InventoryService_UnblockDates_FullMethodName         = "/inventory.InventoryService/UnblockDates"
+	InventoryService_CreateMaintenanceLog_FullMethodName = "/inventory.InventoryService/CreateMaintenanceLog"
+	InventoryService_GetMaintenanceLogs_FullMethodName   = "/inventory.InventoryService/GetMaintenanceLogs"
+)
+
+// InventoryServiceClient is the client API for InventoryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InventoryServiceClient interface {
+	// Public endpoints
+	ListItems(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error)
+	GetItem(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*RentalItem, error)
+	SearchItems(ctx context.Context, in *SearchItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error)
+	// Owner endpoints
+	CreateItem(ctx context.Context, in *CreateItemRequest, opts ...grpc.CallOption) (*RentalItem, error)
+	UpdateItem(ctx context.Context, in *UpdateItemRequest, opts ...grpc.CallOption) (*RentalItem, error)
+	DeleteItem(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error)
+	GetOwnerItems(ctx context.Context, in *GetOwnerItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error)
+	// Availability management
+	GetAvailability(ctx context.Context, in *GetAvailabilityRequest, opts ...grpc.CallOption) (*AvailabilityResponse, error)
+	UpdateAvailability(ctx context.Context, in *UpdateAvailabilityRequest, opts ...grpc.CallOption) (*AvailabilityResponse, error)
+	BlockDates(ctx context.Context, in *BlockDatesRequest, opts ...grpc.CallOption) (*BlockDatesResponse, error)
+	UnblockDates(ctx context.Context, in *UnblockDatesRequest, opts ...grpc.CallOption) (*UnblockDatesResponse, error)
+	// Maintenance
+	CreateMaintenanceLog(ctx context.Context, in *CreateMaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceLog, error)
+	GetMaintenanceLogs(ctx context.Context, in *GetMaintenanceLogsRequest, opts ...grpc.CallOption) (*MaintenanceLogsResponse, error)
+}
+
+type inventoryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInventoryServiceClient(cc grpc.ClientConnInterface) InventoryServiceClient {
+	return &inventoryServiceClient{cc}
+}
+
+func (c *inventoryServiceClient) ListItems(ctx context.Context, in *ListItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error) {
+	out := new(ListItemsResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ListItems_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) GetItem(ctx context.Context, in *GetItemRequest, opts ...grpc.CallOption) (*RentalItem, error) {
+	out := new(RentalItem)
+	err := c.cc.Invoke(ctx, InventoryService_GetItem_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) SearchItems(ctx context.Context, in *SearchItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error) {
+	out := new(ListItemsResponse)
+	err := c.cc.Invoke(ctx, InventoryService_SearchItems_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) CreateItem(ctx context.Context, in *CreateItemRequest, opts ...grpc.CallOption) (*RentalItem, error) {
+	out := new(RentalItem)
+	err := c.cc.Invoke(ctx, InventoryService_CreateItem_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) UpdateItem(ctx context.Context, in *UpdateItemRequest, opts ...grpc.CallOption) (*RentalItem, error) {
+	out := new(RentalItem)
+	err := c.cc.Invoke(ctx, InventoryService_UpdateItem_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) DeleteItem(ctx context.Context, in *DeleteItemRequest, opts ...grpc.CallOption) (*DeleteItemResponse, error) {
+	out := new(DeleteItemResponse)
+	err := c.cc.Invoke(ctx, InventoryService_DeleteItem_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) GetOwnerItems(ctx context.Context, in *GetOwnerItemsRequest, opts ...grpc.CallOption) (*ListItemsResponse, error) {
+	out := new(ListItemsResponse)
+	err := c.cc.Invoke(ctx, InventoryService_GetOwnerItems_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) GetAvailability(ctx context.Context, in *GetAvailabilityRequest, opts ...grpc.CallOption) (*AvailabilityResponse, error) {
+	out := new(AvailabilityResponse)
+	err := c.cc.Invoke(ctx, InventoryService_GetAvailability_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) UpdateAvailability(ctx context.Context, in *UpdateAvailabilityRequest, opts ...grpc.CallOption) (*AvailabilityResponse, error) {
+	out := new(AvailabilityResponse)
+	err := c.cc.Invoke(ctx, InventoryService_UpdateAvailability_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) BlockDates(ctx context.Context, in *BlockDatesRequest, opts ...grpc.CallOption) (*BlockDatesResponse, error) {
+	out := new(BlockDatesResponse)
+	err := c.cc.Invoke(ctx, InventoryService_BlockDates_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) UnblockDates(ctx context.Context, in *UnblockDatesRequest, opts ...grpc.CallOption) (*UnblockDatesResponse, error) {
+	out := new(UnblockDatesResponse)
+	err := c.cc.Invoke(ctx, InventoryService_UnblockDates_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) CreateMaintenanceLog(ctx context.Context, in *CreateMaintenanceRequest, opts ...grpc.CallOption) (*MaintenanceLog, error) {
+	out := new(MaintenanceLog)
+	err := c.cc.Invoke(ctx, InventoryService_CreateMaintenanceLog_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) GetMaintenanceLogs(ctx context.Context, in *GetMaintenanceLogsRequest, opts ...grpc.CallOption) (*MaintenanceLogsResponse, error) {
+	out := new(MaintenanceLogsResponse)
+	err := c.cc.Invoke(ctx, InventoryService_GetMaintenanceLogs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InventoryServiceServer is the server API for InventoryService service.
+// All implementations must embed UnimplementedInventoryServiceServer
+// for forward compatibility
+type InventoryServiceServer interface {
+	// Public endpoints
+	ListItems(context.Context, *ListItemsRequest) (*ListItemsResponse, error)
+	GetItem(context.Context, *GetItemRequest) (*RentalItem, error)
+	SearchItems(context.Context, *SearchItemsRequest) (*ListItemsResponse, error)
+	// Owner endpoints
+	CreateItem(context.Context, *CreateItemRequest) (*RentalItem, error)
+	UpdateItem(context.Context, *UpdateItemRequest) (*RentalItem, error)
+	DeleteItem(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error)
+	GetOwnerItems(context.Context, *GetOwnerItemsRequest) (*ListItemsResponse, error)
+	// Availability management
+	GetAvailability(context.Context, *GetAvailabilityRequest) (*AvailabilityResponse, error)
+	UpdateAvailability(context.Context, *UpdateAvailabilityRequest) (*AvailabilityResponse, error)
+	BlockDates(context.Context, *BlockDatesRequest) (*BlockDatesResponse, error)
+	UnblockDates(context.Context, *UnblockDatesRequest) (*UnblockDatesResponse, error)
+	// Maintenance
+	CreateMaintenanceLog(context.Context, *CreateMaintenanceRequest) (*MaintenanceLog, error)
+	GetMaintenanceLogs(context.Context, *GetMaintenanceLogsRequest) (*MaintenanceLogsResponse, error)
+	mustEmbedUnimplementedInventoryServiceServer()
+}
+
+// UnimplementedInventoryServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedInventoryServiceServer struct {
+}
+
+func (UnimplementedInventoryServiceServer) ListItems(context.Context, *ListItemsRequest) (*ListItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListItems not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetItem(context.Context, *GetItemRequest) (*RentalItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetItem not implemented")
+}
+func (UnimplementedInventoryServiceServer) SearchItems(context.Context, *SearchItemsRequest) (*ListItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchItems not implemented")
+}
+func (UnimplementedInventoryServiceServer) CreateItem(context.Context, *CreateItemRequest) (*RentalItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateItem not implemented")
+}
+func (UnimplementedInventoryServiceServer) UpdateItem(context.Context, *UpdateItemRequest) (*RentalItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateItem not implemented")
+}
+func (UnimplementedInventoryServiceServer) DeleteItem(context.Context, *DeleteItemRequest) (*DeleteItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteItem not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetOwnerItems(context.Context, *GetOwnerItemsRequest) (*ListItemsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOwnerItems not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetAvailability(context.Context, *GetAvailabilityRequest) (*AvailabilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAvailability not implemented")
+}
+func (UnimplementedInventoryServiceServer) UpdateAvailability(context.Context, *UpdateAvailabilityRequest) (*AvailabilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAvailability not implemented")
+}
+func (UnimplementedInventoryServiceServer) BlockDates(context.Context, *BlockDatesRequest) (*BlockDatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockDates not implemented")
+}
+func (UnimplementedInventoryServiceServer) UnblockDates(context.Context, *UnblockDatesRequest) (*UnblockDatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnblockDates not implemented")
+}
+func (UnimplementedInventoryServiceServer) CreateMaintenanceLog(context.Context, *CreateMaintenanceRequest) (*MaintenanceLog, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMaintenanceLog not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetMaintenanceLogs(context.Context, *GetMaintenanceLogsRequest) (*MaintenanceLogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMaintenanceLogs not implemented")
+}
+func (UnimplementedInventoryServiceServer) mustEmbedUnimplementedInventoryServiceServer() {}
+
+// UnsafeInventoryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InventoryServiceServer will
+// result in compilation errors.
+type UnsafeInventoryServiceServer interface {
+	mustEmbedUnimplementedInventoryServiceServer()
+}
+
+func RegisterInventoryServiceServer(s grpc.ServiceRegistrar, srv InventoryServiceServer) {
+	s.RegisterService(&InventoryService_ServiceDesc, srv)
+}
+
+func _InventoryService_ListItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ListItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ListItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ListItems(ctx, req.(*ListItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_GetItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetItem(ctx, req.(*GetItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_SearchItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).SearchItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_SearchItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).SearchItems(ctx, req.(*SearchItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_CreateItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).CreateItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_CreateItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).CreateItem(ctx, req.(*CreateItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_UpdateItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).UpdateItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_UpdateItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).UpdateItem(ctx, req.(*UpdateItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_DeleteItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).DeleteItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_DeleteItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).DeleteItem(ctx, req.(*DeleteItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_GetOwnerItems_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOwnerItemsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetOwnerItems(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetOwnerItems_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetOwnerItems(ctx, req.(*GetOwnerItemsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_GetAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetAvailability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetAvailability(ctx, req.(*GetAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_UpdateAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).UpdateAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_UpdateAvailability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).UpdateAvailability(ctx, req.(*UpdateAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_BlockDates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockDatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).BlockDates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_BlockDates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).BlockDates(ctx, req.(*BlockDatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_UnblockDates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnblockDatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).UnblockDates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_UnblockDates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).UnblockDates(ctx, req.(*UnblockDatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_CreateMaintenanceLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMaintenanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).CreateMaintenanceLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_CreateMaintenanceLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).CreateMaintenanceLog(ctx, req.(*CreateMaintenanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_GetMaintenanceLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMaintenanceLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetMaintenanceLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetMaintenanceLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetMaintenanceLogs(ctx, req.(*GetMaintenanceLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InventoryService_ServiceDesc is the grpc.ServiceDesc for InventoryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InventoryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "inventory.InventoryService",
+	HandlerType: (*InventoryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListItems",
+			Handler:    _InventoryService_ListItems_Handler,
+		},
+		{
+			MethodName: "GetItem",
+			Handler:    _InventoryService_GetItem_Handler,
+		},
+		{
+			MethodName: "SearchItems",
+			Handler:    _InventoryService_SearchItems_Handler,
+		},
+		{
+			MethodName: "CreateItem",
+			Handler:    _InventoryService_CreateItem_Handler,
+		},
+		{
+			MethodName: "UpdateItem",
+			Handler:    _InventoryService_UpdateItem_Handler,
+		},
+		{
+			MethodName: "DeleteItem",
+			Handler:    _InventoryService_DeleteItem_Handler,
+		},
+		{
+			MethodName: "GetOwnerItems",
+			Handler:    _InventoryService_GetOwnerItems_Handler,
+		},
+		{
+			MethodName: "GetAvailability",
+			Handler:    _InventoryService_GetAvailability_Handler,
+		},
+		{
+			MethodName: "UpdateAvailability",
+			Handler:    _InventoryService_UpdateAvailability_Handler,
+		},
+		{
+			MethodName: "BlockDates",
+			Handler:    _InventoryService_BlockDates_Handler,
+		},
+		{
+			MethodName: "UnblockDates",
+			Handler:    _InventoryService_UnblockDates_Handler,
+		},
+		{
+			MethodName: "CreateMaintenanceLog",
+			Handler:    _InventoryService_CreateMaintenanceLog_Handler,
+		},
+		{
+			MethodName: "GetMaintenanceLogs",
+			Handler:    _InventoryService_GetMaintenanceLogs_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "inventory/inventory.proto",
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// untracedCommands are connection housekeeping rather than queries
+var untracedCommands = map[string]bool{
+	"hello":        true,
+	"isMaster":     true,
+	"ping":         true,
+	"saslStart":    true,
+	"saslContinue": true,
+	"endSessions":  true,
+}
+
+// MongoMonitor returns a command monitor that records a span for each
+// MongoDB command, as a child of the span in the command's context
+func MongoMonitor() *event.CommandMonitor {
+	var spans sync.Map // request ID -> trace.Span
+
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, evt *event.CommandStartedEvent) {
+			if untracedCommands[evt.CommandName] {
+				return
+			}
+
+			attrs := []attribute.KeyValue{
+				attribute.String("db.system", "mongodb"),
+				attribute.String("db.name", evt.DatabaseName),
+				attribute.String("db.operation", evt.CommandName),
+			}
+			// The first element of a command names the collection it acts on
+			if elem, err := evt.Command.IndexErr(0); err == nil {
+				if collection, ok := elem.Value().StringValueOK(); ok {
+					attrs = append(attrs, attribute.String("db.mongodb.collection", collection))
+				}
+			}
+
+			_, span := Tracer().Start(ctx, "mongodb."+evt.CommandName,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attrs...),
+			)
+			spans.Store(evt.RequestID, span)
+		},
+		Succeeded: func(ctx context.Context, evt *event.CommandSucceededEvent) {
+			if span, ok := spans.LoadAndDelete(evt.RequestID); ok {
+				span.(trace.Span).End()
+			}
+		},
+		Failed: func(ctx context.Context, evt *event.CommandFailedEvent) {
+			if value, ok := spans.LoadAndDelete(evt.RequestID); ok {
+				span := value.(trace.Span)
+				span.RecordError(errors.New(evt.Failure))
+				span.SetStatus(codes.Error, evt.Failure)
+				span.End()
+			}
+		},
+	}
+}
@@ -0,0 +1,121 @@
+// Package tracing sets up OpenTelemetry and instruments the transports the
+// services share: HTTP servers and clients, gRPC, MongoDB and RabbitMQ.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+)
+
+// instrumentationName names the tracer used for spans this package creates
+const instrumentationName = "github.com/rentalflow/rentalflow/pkg/tracing"
+
+// Init installs the global tracer provider and W3C trace context propagation.
+// With the "none" exporter spans are not recorded, but incoming trace context
+// is still passed on. The returned function flushes pending spans.
+func Init(serviceName string, cfg config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case "", "none":
+		return func(context.Context) error { return nil }, nil
+	case "stdout":
+		exporter, err = stdouttrace.New()
+	case "otlp":
+		exporter, err = otlptracehttp.New(context.Background())
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer for spans created by this package
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Handler traces incoming HTTP requests, continuing the caller's trace
+func Handler(h http.Handler, operation string) http.Handler {
+	return otelhttp.NewHandler(h, operation,
+		otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+	)
+}
+
+// Transport traces outgoing HTTP requests and passes the trace on in their
+// headers. A nil base uses http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
+
+// ServerOption traces incoming gRPC calls
+func ServerOption() grpc.ServerOption {
+	return grpc.StatsHandler(otelgrpc.NewServerHandler())
+}
+
+// DialOption traces outgoing gRPC calls
+func DialOption() grpc.DialOption {
+	return grpc.WithStatsHandler(taggedOnly{otelgrpc.NewClientHandler()})
+}
+
+// taggedOnly passes on only the stats of calls it has tagged. gRPC opens the
+// streams of client-side health checks without tagging them, which the
+// otelgrpc handler does not survive.
+type taggedOnly struct {
+	stats.Handler
+}
+
+type taggedKey struct{}
+
+func (h taggedOnly) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(h.Handler.TagRPC(ctx, info), taggedKey{}, true)
+}
+
+func (h taggedOnly) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if ctx.Value(taggedKey{}) != nil {
+		h.Handler.HandleRPC(ctx, s)
+	}
+}
@@ -0,0 +1,124 @@
+// Package userdata serves the internal endpoints auth-service calls on every
+// other service to export and erase a user's data
+package userdata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+)
+
+// Statuses an erasure reports: the service removed or anonymized the data,
+// or keeps it, such as financial records it must retain
+const (
+	StatusErased   = "erased"
+	StatusRetained = "retained"
+)
+
+// Func exports or erases the data a service stores about a user and
+// summarizes it
+type Func func(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error)
+
+// Handler serves GET /internal/users/data and POST /internal/users/erase.
+// Both require a token holding user_data:manage.
+type Handler struct {
+	checker    *auth.Checker
+	export     Func
+	erase      Func
+	status     string
+	writeError func(w http.ResponseWriter, err error)
+}
+
+// NewHandler creates a handler for a service's export and erase functions.
+// status is what a completed erasure reports. writeError writes the errors
+// of export and erase; when nil they are answered with 500.
+func NewHandler(checker *auth.Checker, export, erase Func, status string, writeError func(w http.ResponseWriter, err error)) *Handler {
+	if writeError == nil {
+		writeError = func(w http.ResponseWriter, err error) {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+	return &Handler{
+		checker:    checker,
+		export:     export,
+		erase:      erase,
+		status:     status,
+		writeError: writeError,
+	}
+}
+
+// Register adds the endpoints to mux
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/internal/users/data", h.Export)
+	mux.HandleFunc("/internal/users/erase", h.Erase)
+}
+
+// Export returns the data the service stores about a user. It is called by
+// auth-service when building a personal-data export.
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.checker.Require(w, r, auth.PermUserDataManage); !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(r.URL.Query().Get("user_id"))
+	if err != nil {
+		http.Error(w, "Invalid user_id format", http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.export(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(data)
+}
+
+// Erase erases or retains a user's data during an account deletion. It is
+// called by auth-service and is safe to repeat.
+func (h *Handler) Erase(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.checker.Require(w, r, auth.PermUserDataManage); !ok {
+		return
+	}
+
+	var req struct {
+		UserID string `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		http.Error(w, "Invalid user_id format", http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.erase(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  h.status,
+		"summary": summary,
+	})
+}
@@ -21,15 +21,32 @@ if ! command -v protoc &> /dev/null; then
     exit 1
 fi
 
+# Plugin versions must match the protobuf and grpc modules in go.mod; newer
+# protoc-gen-go-grpc releases emit code that needs a newer grpc runtime
+PROTOC_GEN_GO_VERSION="v1.32.0"
+PROTOC_GEN_GO_GRPC_VERSION="v1.3.0"
+GRPC_GATEWAY_VERSION="v2.18.0"
+DOCS_DIR="./docs"
+
 # Check if Go plugins are installed
 if ! command -v protoc-gen-go &> /dev/null; then
     echo -e "${YELLOW}Installing protoc-gen-go...${NC}"
-    go install google.golang.org/protobuf/cmd/protoc-gen-go@latest
+    go install google.golang.org/protobuf/cmd/protoc-gen-go@$PROTOC_GEN_GO_VERSION
 fi
 
 if ! command -v protoc-gen-go-grpc &> /dev/null; then
     echo -e "${YELLOW}Installing protoc-gen-go-grpc...${NC}"
-    go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@latest
+    go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@$PROTOC_GEN_GO_GRPC_VERSION
+fi
+
+if ! command -v protoc-gen-grpc-gateway &> /dev/null; then
+    echo -e "${YELLOW}Installing protoc-gen-grpc-gateway...${NC}"
+    go install github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-grpc-gateway@$GRPC_GATEWAY_VERSION
+fi
+
+if ! command -v protoc-gen-openapiv2 &> /dev/null; then
+    echo -e "${YELLOW}Installing protoc-gen-openapiv2...${NC}"
+    go install github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2@$GRPC_GATEWAY_VERSION
 fi
 
 # Create output directory
@@ -52,10 +69,22 @@ for service in "${SERVICES[@]}"; do
             --go_opt=paths=source_relative \
             --go-grpc_out="$OUT_DIR" \
             --go-grpc_opt=paths=source_relative \
+            --grpc-gateway_out="$OUT_DIR" \
+            --grpc-gateway_opt=paths=source_relative \
             "$PROTO_PATH"/*.proto
     else
         echo -e "${YELLOW}Skipping $service (no .proto files found)${NC}"
     fi
 done
 
+# The gateway's REST contract is generated from the same HTTP annotations
+echo -e "${GREEN}Generating OpenAPI document...${NC}"
+protoc \
+    --proto_path="$PROTO_DIR" \
+    --openapiv2_out="$DOCS_DIR" \
+    --openapiv2_opt=allow_merge=true,merge_file_name=rentalflow,output_format=yaml \
+    --openapiv2_opt=json_names_for_fields=false,disable_default_errors=true \
+    --openapiv2_opt=openapi_configuration="$PROTO_DIR/openapi_config.yaml" \
+    $(for service in "${SERVICES[@]}"; do echo "$PROTO_DIR/$service/$service.proto"; done)
+
 echo -e "${GREEN}Proto generation complete!${NC}"
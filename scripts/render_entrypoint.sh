@@ -55,6 +55,23 @@ export RENTALFLOW_RABBITMQ_USER=rentalflow
 export RENTALFLOW_RABBITMQ_PASSWORD=devpassword
 export RENTALFLOW_RABBITMQ_VHOST=/
 
+# Service-to-service addresses (auth emails via notification; booking and inventory check users in auth;
+# auth collects and erases personal data in every service; booking holds dates in inventory and
+# payment reports to booking over gRPC)
+export RENTALFLOW_SERVICES_NOTIFICATION=localhost:$NOTIFICATION_PORT
+export RENTALFLOW_SERVICES_INVENTORY=localhost:50052
+export RENTALFLOW_SERVICES_BOOKING=localhost:50053
+export AUTH_SERVICE_URL="http://localhost:$AUTH_PORT"
+export INVENTORY_SERVICE_URL="http://localhost:$INVENTORY_PORT"
+export BOOKING_SERVICE_URL="http://localhost:$BOOKING_PORT"
+export PAYMENT_SERVICE_URL="http://localhost:$PAYMENT_PORT"
+export REVIEW_SERVICE_URL="http://localhost:$REVIEW_PORT"
+
+# Every service verifies access tokens and their permissions with the same secret
+if [ -n "$JWT_SECRET" ]; then
+    export RENTALFLOW_JWT_SECRET="$JWT_SECRET"
+fi
+
 
 echo "Starting RentalFlow Microservices..."
 
@@ -116,12 +133,25 @@ sleep 5
 echo "Starting API Gateway on :$PORT..."
 export PORT=$PORT
 export AUTH_SERVICE_URL="http://localhost:$AUTH_PORT"
+
+# Every service verifies access tokens and their permissions with the same secret
+if [ -n "$JWT_SECRET" ]; then
+    export RENTALFLOW_JWT_SECRET="$JWT_SECRET"
+fi
 export INVENTORY_SERVICE_URL="http://localhost:$INVENTORY_PORT"
 export BOOKING_SERVICE_URL="http://localhost:$BOOKING_PORT"
 export PAYMENT_SERVICE_URL="http://localhost:$PAYMENT_PORT"
 export REVIEW_SERVICE_URL="http://localhost:$REVIEW_PORT"
 export NOTIFICATION_SERVICE_URL="http://localhost:$NOTIFICATION_PORT"
 
+# Routes annotated in the protos are transcoded to the services' gRPC ports
+export AUTH_SERVICE_GRPC_ADDR="localhost:50051"
+export INVENTORY_SERVICE_GRPC_ADDR="localhost:50052"
+export BOOKING_SERVICE_GRPC_ADDR="localhost:50053"
+export PAYMENT_SERVICE_GRPC_ADDR="localhost:50054"
+export REVIEW_SERVICE_GRPC_ADDR="localhost:50055"
+export NOTIFICATION_SERVICE_GRPC_ADDR="localhost:50056"
+
 ./api-gateway &
 PID_GATEWAY=$!
 
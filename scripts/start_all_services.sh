@@ -23,10 +23,11 @@ start_service() {
     local name=$1
     local dir=$2
     local port=$3
+    local grpc_port=$4
     
-    log "Starting $name on port $port..."
+    log "Starting $name on port $port (gRPC $grpc_port)..."
     cd $dir
-    go run ./cmd/server > "${name}.log" 2>&1 &
+    RENTALFLOW_HTTP_PORT=$port RENTALFLOW_GRPC_PORT=$grpc_port go run ./cmd/server > "${name}.log" 2>&1 &
     echo $! > "${name}.pid"
     cd ../..
 }
@@ -36,12 +37,12 @@ rm -f services/*/*.pid
 rm -f services/*/*.log
 
 # Start Services
-start_service "auth-service" "services/auth-service" 8081
-start_service "inventory-service" "services/inventory-service" 8082
-start_service "booking-service" "services/booking-service" 8083
-start_service "payment-service" "services/payment-service" 8084
-start_service "notification-service" "services/notification-service" 8085
-start_service "review-service" "services/review-service" 8086
+start_service "auth-service" "services/auth-service" 8081 50051
+start_service "inventory-service" "services/inventory-service" 8082 50052
+start_service "booking-service" "services/booking-service" 8083 50053
+start_service "payment-service" "services/payment-service" 8084 50054
+start_service "notification-service" "services/notification-service" 8085 50055
+start_service "review-service" "services/review-service" 8086 50056
 
 # Start Gateway last
 log "Starting API Gateway on port 8080..."
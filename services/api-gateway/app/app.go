@@ -0,0 +1,197 @@
+// Package app assembles the API gateway, so that cmd/server and the system
+// tests run the same wiring
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/redis/go-redis/v9"
+	"github.com/rentalflow/api-gateway/internal/cache"
+	"github.com/rentalflow/api-gateway/internal/handlers"
+	"github.com/rentalflow/api-gateway/internal/middleware"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/messaging"
+	"github.com/rentalflow/rentalflow/pkg/metrics"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+	"github.com/rs/zerolog/log"
+)
+
+// App is an API gateway ready to serve
+type App struct {
+	redisClient *redis.Client
+	broker      messaging.Broker
+	ownsBroker  bool
+	gateway     *handlers.Gateway
+	rateLimiter *middleware.RateLimiter
+	server      *http.Server
+}
+
+// New connects to the services and builds the gateway. Item events that
+// invalidate cached reads come from broker; if it is nil the gateway opens
+// the broker the config selects, and cached items only expire when it can't.
+func New(cfg *config.Config, broker messaging.Broker) (*App, error) {
+	a := &App{broker: broker}
+
+	checker := auth.NewChecker(cfg.JWT.Secret)
+
+	// Redis backs the rate limiter and response cache when either is shared
+	// between gateway instances
+	if cfg.Gateway.RateLimit.Store == "redis" || cfg.Gateway.Cache.Store == "redis" {
+		redisClient, err := newRedisClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		a.redisClient = redisClient
+	}
+
+	responseCache := cache.New(newCacheStore(cfg, a.redisClient), checker)
+
+	// Item events invalidate cached catalog reads; without a broker entries
+	// only expire
+	if a.broker == nil {
+		broker, err := messaging.Open(cfg)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to connect to the message broker, cached items will only expire")
+		} else {
+			log.Info().Str("driver", cfg.Messaging.Driver).Msg("Connected to the message broker")
+			a.broker = broker
+			a.ownsBroker = true
+		}
+	}
+	if a.broker != nil {
+		if err := responseCache.ListenForItemEvents(a.broker); err != nil {
+			log.Warn().Err(err).Msg("Failed to subscribe to item events, cached items will only expire")
+		}
+		if err := responseCache.ListenForReviewEvents(a.broker); err != nil {
+			log.Warn().Err(err).Msg("Failed to subscribe to review events, cached reviews will only expire")
+		}
+	}
+
+	// Create gateway with microservice clients
+	gateway, err := handlers.NewGateway(cfg, responseCache)
+	if err != nil {
+		a.close()
+		return nil, fmt.Errorf("failed to create gateway: %w", err)
+	}
+	a.gateway = gateway
+
+	// Setup router
+	r := mux.NewRouter()
+
+	// Apply global middleware
+	r.Use(middleware.Metrics)
+	// r.Use(middleware.CORS) - Moving to wrap router to handle OPTIONS correctly
+
+	a.rateLimiter = middleware.NewRateLimiter(newRateLimitStore(cfg, a.redisClient), checker, rateLimitConfig(cfg))
+
+	// Register routes
+	gateway.RegisterRoutes(r)
+
+	// Apply rate limiting to all routes
+	r.Use(a.rateLimiter.Limit)
+
+	// Metrics are scraped outside the router, so scrapes are neither rate
+	// limited nor counted as API traffic
+	root := http.NewServeMux()
+	root.Handle("/metrics", metrics.Handler())
+	root.Handle("/debug/config", cfg.DebugHandler(checker))
+	root.Handle("/", middleware.CORS(cfg.Gateway.AllowedOrigins)(r))
+
+	a.server = &http.Server{
+		Handler:      tracing.Handler(middleware.RequestID(logger.Handler(root)), "api-gateway"),
+		WriteTimeout: 30 * time.Second,
+		ReadTimeout:  30 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	return a, nil
+}
+
+// Reload applies the settings that can change without a restart
+func (a *App) Reload(next *config.Config) {
+	a.rateLimiter.SetConfig(rateLimitConfig(next))
+}
+
+// Start serves the API on lis until Shutdown
+func (a *App) Start(lis net.Listener) {
+	go func() {
+		log.Info().Str("addr", lis.Addr().String()).Msg("API Gateway listening")
+		if err := a.server.Serve(lis); err != nil && err != http.ErrServerClosed {
+			log.Error().Err(err).Msg("Server failed")
+		}
+	}()
+}
+
+// Shutdown stops the server and closes the connections the gateway opened
+func (a *App) Shutdown(ctx context.Context) {
+	if err := a.server.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Server shutdown failed")
+	}
+	a.close()
+}
+
+// close releases the clients and connections opened so far
+func (a *App) close() {
+	if a.gateway != nil {
+		a.gateway.Close()
+	}
+	if a.ownsBroker {
+		a.broker.Close()
+	}
+	if a.redisClient != nil {
+		a.redisClient.Close()
+	}
+}
+
+// newRedisClient connects to Redis, failing at startup rather than on the
+// first request
+func newRedisClient(cfg *config.Config) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Addr(),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis at %s: %w", cfg.Redis.Addr(), err)
+	}
+
+	log.Info().Str("addr", cfg.Redis.Addr()).Msg("Connected to Redis")
+	return client, nil
+}
+
+// newRateLimitStore shares counters through Redis when the gateway runs as
+// several instances, or keeps them in memory for a single node
+func newRateLimitStore(cfg *config.Config, redisClient *redis.Client) middleware.RateLimitStore {
+	if cfg.Gateway.RateLimit.Store == "redis" {
+		return middleware.NewRedisStore(redisClient)
+	}
+	return middleware.NewMemoryStore()
+}
+
+// newCacheStore shares cached responses through Redis, or keeps an LRU in
+// memory for a single node
+func newCacheStore(cfg *config.Config, redisClient *redis.Client) cache.Store {
+	if cfg.Gateway.Cache.Store == "redis" {
+		return cache.NewRedisStore(redisClient)
+	}
+	return cache.NewMemoryStore(cfg.Gateway.Cache.Size)
+}
+
+// rateLimitConfig returns the quotas the rate limiter enforces
+func rateLimitConfig(cfg *config.Config) middleware.RateLimitConfig {
+	return middleware.RateLimitConfig{
+		Default: cfg.Gateway.RateLimit.Default,
+		Roles:   cfg.Gateway.RateLimit.Roles,
+		Routes:  cfg.Gateway.RateLimit.Routes,
+	}
+}
@@ -1,71 +1,58 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"net/http"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/gorilla/mux"
-	"github.com/rentalflow/api-gateway/config"
-	"github.com/rentalflow/api-gateway/internal/handlers"
-	"github.com/rentalflow/api-gateway/internal/middleware"
-	"github.com/rs/zerolog"
+	"github.com/rentalflow/api-gateway/app"
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
 	"github.com/rs/zerolog/log"
 )
 
 func main() {
-	// Configure logger
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
-
-	log.Info().Msg("Starting API Gateway...")
-
 	// Load configuration
-	cfg := config.Load()
-
-	// Create gateway with microservice clients
-	gateway := handlers.NewGateway(cfg)
-
-	// Setup router
-	r := mux.NewRouter()
+	cfg, err := config.Load("gateway")
+	if err != nil {
+		fmt.Printf("Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
 
-	// Apply global middleware
-	r.Use(middleware.Logger)
-	// r.Use(middleware.CORS) - Moving to wrap router to handle OPTIONS correctly
+	// Configure logger
+	logger.Init("api-gateway", cfg.LogLevel, cfg.LogFormat)
+	log.Logger = logger.Logger
 
-	// Create auth middleware
-	authMiddleware := middleware.NewAuthMiddleware(cfg.JWTSecret)
-	rateLimiter := middleware.NewRateLimiter(cfg.RateLimit)
+	log.Info().Msg("Starting API Gateway...")
 
-	// Register routes
-	gateway.RegisterRoutes(r)
+	shutdownTracing, err := tracing.Init("api-gateway", cfg.Tracing)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize tracing")
+	}
+	defer shutdownTracing(context.Background())
 
-	// Apply rate limiting to all routes
-	r.Use(rateLimiter.Limit)
+	a, err := app.New(cfg, nil)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to start API Gateway")
+	}
 
-	// Protected routes example (can be expanded)
-	// r.Use(authMiddleware.Authenticate) // Uncomment to require auth for all routes
+	// The log level and quotas can change in the config file without a restart
+	cfg.Watch(func(next *config.Config) {
+		logger.SetLevel(next.LogLevel)
+		a.Reload(next)
+		log.Info().Str("log_level", next.LogLevel).Int("rate_limit", next.Gateway.RateLimit.Default).Msg("Configuration reloaded")
+	})
 
-	// Create HTTP server
-	addr := fmt.Sprintf(":%d", cfg.Port)
-	srv := &http.Server{
-		Addr:         addr,
-		Handler:      middleware.CORS(cfg.AllowedOrigins)(r),
-		WriteTimeout: 30 * time.Second,
-		ReadTimeout:  30 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.Gateway.Port))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Server failed to start")
 	}
-
-	// Start server in goroutine
-	go func() {
-		log.Info().Str("addr", addr).Msg("API Gateway listening")
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatal().Err(err).Msg("Server failed to start")
-		}
-	}()
+	a.Start(lis)
 
 	// Graceful shutdown
 	quit := make(chan os.Signal, 1)
@@ -73,7 +60,10 @@ func main() {
 	<-quit
 
 	log.Info().Msg("Shutting down API Gateway...")
-	log.Info().Msg("Gateway stopped")
 
-	_ = authMiddleware // Use the variable to avoid unused warning
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	a.Shutdown(ctx)
+
+	log.Info().Msg("Gateway stopped")
 }
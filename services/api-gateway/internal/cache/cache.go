@@ -0,0 +1,222 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+)
+
+// Tags that item events invalidate. CatalogTag covers every item, ItemsTag
+// the item listings and ItemTag a single item. Review events invalidate
+// ReviewsTag, every item's reviews, or the ItemTag of the reviewed item.
+const (
+	CatalogTag = "catalog"
+	ItemsTag   = "items"
+	ReviewsTag = "reviews"
+)
+
+// ItemTag is the tag of a single item's cached responses
+func ItemTag(itemID string) string {
+	return "item:" + itemID
+}
+
+// generationTTL outlives any route TTL, so an expired generation can only
+// bring back entries that have expired too
+const generationTTL = 24 * time.Hour
+
+// Cache serves GET responses from a Store. Entries are keyed by path, query
+// and the caller's roles, and carry an ETag so clients can revalidate with
+// If-None-Match. Each entry depends on a set of tags; invalidating a tag
+// moves it to a new generation, which changes the key of every entry that
+// depends on it.
+type Cache struct {
+	store   Store
+	checker *auth.Checker
+}
+
+// entry is a cached response
+type entry struct {
+	ContentType string `json:"content_type"`
+	ETag        string `json:"etag"`
+	Body        []byte `json:"body"`
+}
+
+func New(store Store, checker *auth.Checker) *Cache {
+	return &Cache{store: store, checker: checker}
+}
+
+// Handler caches successful GET responses from next for ttl. tags lists the
+// tags a request's response depends on and may be nil. A zero ttl disables
+// caching for the route.
+func (c *Cache) Handler(ttl time.Duration, tags func(r *http.Request) []string, next http.Handler) http.Handler {
+	if ttl <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var requestTags []string
+		if tags != nil {
+			requestTags = tags(r)
+		}
+		key, err := c.key(r, requestTags)
+		if err != nil {
+			logger.Ctx(r.Context()).Warn().Err(err).Msg("Response cache unavailable")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if data, ok, err := c.store.Get(r.Context(), key); err != nil {
+			logger.Ctx(r.Context()).Warn().Err(err).Msg("Response cache unavailable")
+		} else if ok {
+			var cached entry
+			if err := json.Unmarshal(data, &cached); err == nil {
+				writeEntry(w, r, &cached, "HIT")
+				return
+			}
+		}
+
+		rec := &recorder{header: make(http.Header), status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		if rec.status != http.StatusOK {
+			rec.writeTo(w)
+			return
+		}
+
+		// Keep the version ETag of a single document, which clients send
+		// back in If-Match, and fingerprint other responses
+		tag := rec.header.Get("ETag")
+		if tag == "" {
+			sum := sha256.Sum256(rec.body.Bytes())
+			tag = `"` + hex.EncodeToString(sum[:16]) + `"`
+		}
+		fresh := &entry{
+			ContentType: rec.header.Get("Content-Type"),
+			ETag:        tag,
+			Body:        rec.body.Bytes(),
+		}
+		if data, err := json.Marshal(fresh); err == nil {
+			if err := c.store.Set(r.Context(), key, data, ttl); err != nil {
+				logger.Ctx(r.Context()).Warn().Err(err).Msg("Failed to store cached response")
+			}
+		}
+
+		for k, v := range rec.header {
+			w.Header()[k] = v
+		}
+		writeEntry(w, r, fresh, "MISS")
+	})
+}
+
+// Invalidate drops every entry that depends on one of the tags
+func (c *Cache) Invalidate(ctx context.Context, tags ...string) error {
+	generation := []byte(strconv.FormatInt(time.Now().UnixNano(), 10))
+	for _, tag := range tags {
+		if err := c.store.Set(ctx, "gen:"+tag, generation, generationTTL); err != nil {
+			return fmt.Errorf("failed to invalidate %s: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// key builds the entry key from the request and the current generation of
+// each tag
+func (c *Cache) key(r *http.Request, tags []string) (string, error) {
+	var b strings.Builder
+	for _, tag := range tags {
+		generation, ok, err := c.store.Get(r.Context(), "gen:"+tag)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			generation = []byte("0")
+		}
+		fmt.Fprintf(&b, "%s=%s;", tag, generation)
+	}
+	// Encode sorts the parameters, so their order does not split the cache
+	fmt.Fprintf(&b, "%s|%s?%s", c.roles(r), r.URL.Path, r.URL.Query().Encode())
+
+	sum := sha256.Sum256([]byte(b.String()))
+	return "entry:" + hex.EncodeToString(sum[:]), nil
+}
+
+// roles names the caller's roles, or "anonymous" without a valid token
+func (c *Cache) roles(r *http.Request) string {
+	principal, err := c.checker.FromRequest(r)
+	if err != nil || len(principal.Roles) == 0 {
+		return "anonymous"
+	}
+	roles := append([]string(nil), principal.Roles...)
+	sort.Strings(roles)
+	return strings.Join(roles, ",")
+}
+
+func writeEntry(w http.ResponseWriter, r *http.Request, e *entry, status string) {
+	w.Header().Set("ETag", e.ETag)
+	w.Header().Set("X-Cache", status)
+	if matchesETag(r.Header.Get("If-None-Match"), e.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if e.ContentType != "" {
+		w.Header().Set("Content-Type", e.ContentType)
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(e.Body)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(e.Body)
+}
+
+// matchesETag reports whether an If-None-Match header lists etag
+func matchesETag(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// recorder buffers a response so it can be cached before it is sent
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (r *recorder) writeTo(w http.ResponseWriter) {
+	for k, v := range r.header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(r.status)
+	w.Write(r.body.Bytes())
+}
@@ -0,0 +1,131 @@
+package cache_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rentalflow/api-gateway/internal/cache"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+)
+
+const secret = "test-secret"
+
+// accessToken signs an access token for a user with roles
+func accessToken(t *testing.T, userID string, roles ...string) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"roles":   roles,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+// newHandler caches an upstream that counts the requests reaching it
+func newHandler(t *testing.T) (*cache.Cache, http.Handler, *int) {
+	t.Helper()
+
+	c := cache.New(cache.NewMemoryStore(100), auth.NewChecker(secret))
+	calls := 0
+	upstream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[]}`))
+	})
+	tags := func(r *http.Request) []string { return []string{cache.ItemsTag} }
+	return c, c.Handler(time.Minute, tags, upstream), &calls
+}
+
+func get(handler http.Handler, target, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCacheKey(t *testing.T) {
+	renter := accessToken(t, "u1", "renter")
+
+	tests := []struct {
+		name        string
+		firstTarget string
+		firstToken  string
+		target      string
+		token       string
+		wantCache   string
+	}{
+		{"SameRole", "/api/items", renter, "/api/items", renter, "HIT"},
+		{"OtherUserSameRole", "/api/items", renter, "/api/items", accessToken(t, "u2", "renter"), "HIT"},
+		{"RoleOrder", "/api/items", accessToken(t, "u1", "owner", "renter"), "/api/items", accessToken(t, "u2", "renter", "owner"), "HIT"},
+		{"QueryOrder", "/api/items?a=1&b=2", "", "/api/items?b=2&a=1", "", "HIT"},
+		{"InvalidTokenIsAnonymous", "/api/items", "", "/api/items", "not-a-token", "HIT"},
+		{"AnonymousThenRole", "/api/items", "", "/api/items", renter, "MISS"},
+		{"RoleThenAnonymous", "/api/items", renter, "/api/items", "", "MISS"},
+		{"OtherRole", "/api/items", renter, "/api/items", accessToken(t, "u3", "admin"), "MISS"},
+		{"ExtraRole", "/api/items", renter, "/api/items", accessToken(t, "u1", "renter", "admin"), "MISS"},
+		{"OtherQuery", "/api/items?page=1", "", "/api/items?page=2", "", "MISS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, handler, calls := newHandler(t)
+
+			if rec := get(handler, tt.firstTarget, tt.firstToken); rec.Header().Get("X-Cache") != "MISS" {
+				t.Fatalf("first request: X-Cache = %q, want MISS", rec.Header().Get("X-Cache"))
+			}
+			rec := get(handler, tt.target, tt.token)
+			if got := rec.Header().Get("X-Cache"); got != tt.wantCache {
+				t.Errorf("X-Cache = %q, want %q", got, tt.wantCache)
+			}
+			if wantCalls := map[string]int{"HIT": 1, "MISS": 2}[tt.wantCache]; *calls != wantCalls {
+				t.Errorf("upstream saw %d requests, want %d", *calls, wantCalls)
+			}
+		})
+	}
+}
+
+func TestCacheRevalidates(t *testing.T) {
+	_, handler, _ := newHandler(t)
+
+	etag := get(handler, "/api/items", "").Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("response has no ETag")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/items", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("revalidation: %d with %d bytes, want %d and no body", rec.Code, rec.Body.Len(), http.StatusNotModified)
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	c, handler, calls := newHandler(t)
+
+	get(handler, "/api/items", "")
+	if err := c.Invalidate(context.Background(), cache.ReviewsTag); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	if got := get(handler, "/api/items", "").Header().Get("X-Cache"); got != "HIT" {
+		t.Errorf("after invalidating another tag: X-Cache = %q, want HIT", got)
+	}
+
+	if err := c.Invalidate(context.Background(), cache.ItemsTag); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	if got := get(handler, "/api/items", "").Header().Get("X-Cache"); got != "MISS" || *calls != 2 {
+		t.Errorf("after invalidating the tag: X-Cache = %q with %d upstream requests, want MISS and 2", got, *calls)
+	}
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rentalflow/rentalflow/pkg/messaging"
+)
+
+// Topics where inventory-service publishes item changes and review-service
+// review changes
+const (
+	itemEventsTopic   = "inventory_events"
+	reviewEventsTopic = "review_events"
+)
+
+// itemEvent is the part of an inventory event the cache needs. Item events
+// carry the item; item.erased carries only the owner, whose items all change.
+type itemEvent struct {
+	ID string `json:"id"`
+}
+
+// ListenForItemEvents invalidates cached catalog reads as inventory-service
+// reports item changes. It subscribes without a group, so every gateway
+// instance with an in-memory store sees every event.
+func (c *Cache) ListenForItemEvents(subscriber messaging.Subscriber) error {
+	return subscriber.Subscribe(itemEventsTopic, "item.#", "", func(ctx context.Context, body []byte) error {
+		var event itemEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return fmt.Errorf("invalid item event: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		if event.ID == "" {
+			return c.Invalidate(ctx, CatalogTag)
+		}
+		return c.Invalidate(ctx, ItemsTag, ItemTag(event.ID))
+	})
+}
+
+// reviewEvent is the part of a review event the cache needs. Review events
+// carry the review; review.erased carries only the user, whose reviews of
+// any item may change.
+type reviewEvent struct {
+	TargetItemID string `json:"target_item_id"`
+	UserID       string `json:"user_id"`
+}
+
+// ListenForReviewEvents invalidates an item's cached reviews as
+// review-service reports review changes. Reviews of users are not cached,
+// so their events change nothing. Like ListenForItemEvents it subscribes
+// without a group.
+func (c *Cache) ListenForReviewEvents(subscriber messaging.Subscriber) error {
+	return subscriber.Subscribe(reviewEventsTopic, "review.#", "", func(ctx context.Context, body []byte) error {
+		var event reviewEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return fmt.Errorf("invalid review event: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		switch {
+		case event.TargetItemID != "":
+			return c.Invalidate(ctx, ItemTag(event.TargetItemID))
+		case event.UserID != "":
+			return c.Invalidate(ctx, ReviewsTag)
+		default:
+			return nil
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package clients_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rentalflow/api-gateway/internal/clients"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBreaker(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeout   time.Duration
+		record    []string
+		wantState clients.BreakerState
+		wantAllow bool
+	}{
+		{"Closed", time.Hour, nil, clients.BreakerClosed, true},
+		{"BelowThreshold", time.Hour, []string{"failure", "failure"}, clients.BreakerClosed, true},
+		{"SuccessResetsCount", time.Hour, []string{"failure", "failure", "success", "failure"}, clients.BreakerClosed, true},
+		{"Opens", time.Hour, []string{"failure", "failure", "failure"}, clients.BreakerOpen, false},
+		{"TrialAfterTimeout", 0, []string{"failure", "failure", "failure"}, clients.BreakerOpen, true},
+		{"TrialSucceeds", 0, []string{"failure", "failure", "failure", "allow", "success"}, clients.BreakerClosed, true},
+		{"TrialFails", 0, []string{"failure", "failure", "failure", "allow", "failure"}, clients.BreakerOpen, true},
+		{"OneTrialAtATime", 0, []string{"failure", "failure", "failure", "allow"}, clients.BreakerHalfOpen, false},
+		{"TrialReleased", 0, []string{"failure", "failure", "failure", "allow", "release"}, clients.BreakerHalfOpen, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := clients.NewBreaker(3, tt.timeout)
+			for _, r := range tt.record {
+				switch r {
+				case "allow":
+					if !b.Allow() {
+						t.Fatalf("trial request was not allowed")
+					}
+				case "success":
+					b.Success()
+				case "failure":
+					b.Failure()
+				case "release":
+					b.Release()
+				}
+			}
+
+			if got := b.Status().State; got != tt.wantState {
+				t.Errorf("state = %s, want %s", got, tt.wantState)
+			}
+			if got := b.Allow(); got != tt.wantAllow {
+				t.Errorf("Allow = %v, want %v", got, tt.wantAllow)
+			}
+		})
+	}
+}
+
+func TestBreakerInterceptor(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      codes.Code
+		wantState clients.BreakerState
+	}{
+		{"OK", codes.OK, clients.BreakerClosed},
+		{"NotFound", codes.NotFound, clients.BreakerClosed},
+		{"Unavailable", codes.Unavailable, clients.BreakerOpen},
+		{"DeadlineExceeded", codes.DeadlineExceeded, clients.BreakerOpen},
+		// A call its caller gave up on says nothing about the upstream, so
+		// the breaker waits for another trial
+		{"Canceled", codes.Canceled, clients.BreakerHalfOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy, err := clients.NewProxy("test", []string{"http://127.0.0.1:1"}, clients.ProxyConfig{FailureThreshold: 1})
+			if err != nil {
+				t.Fatalf("NewProxy: %v", err)
+			}
+			interceptor := proxy.BreakerInterceptor()
+			invoke := func(code codes.Code) error {
+				return interceptor(context.Background(), "/test.Test/Call", nil, nil, nil,
+					func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+						return status.Error(code, "")
+					})
+			}
+
+			// Open the breaker so the call under test is its trial
+			invoke(codes.Unavailable)
+			if got := status.Code(invoke(tt.code)); got != tt.code {
+				t.Fatalf("trial call returned %s, want %s", got, tt.code)
+			}
+			if got := proxy.Status().Breaker.State; got != tt.wantState {
+				t.Errorf("state = %s, want %s", got, tt.wantState)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package clients
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	// Registers the client side of the gRPC health protocol used by
+	// GRPCServiceConfig
+	_ "google.golang.org/grpc/health"
+)
+
+// GRPCServiceConfig spreads the calls on a gRPC connection over every
+// address its target resolves to, and takes addresses whose health service
+// does not report SERVING out of rotation until it does
+const GRPCServiceConfig = `{
+	"loadBalancingConfig": [{"round_robin": {}}],
+	"healthCheckConfig": {"serviceName": ""}
+}`
+
+// BreakerInterceptor makes unary gRPC calls to the upstream go through the
+// same circuit breaker as the requests the proxy forwards over HTTP. Calls
+// the upstream could not serve in time count as failures, and calls their
+// caller cancelled count as neither.
+func (p *Proxy) BreakerInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if !p.breaker.Allow() {
+			return status.Error(codes.Unavailable, p.name+" service unavailable")
+		}
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		switch status.Code(err) {
+		case codes.Unavailable, codes.DeadlineExceeded:
+			p.breaker.Failure()
+		case codes.Canceled:
+			p.breaker.Release()
+		default:
+			p.breaker.Success()
+		}
+		return err
+	}
+}
+
+// TimeoutInterceptor bounds each unary gRPC call to the upstream by timeout,
+// as the proxy bounds the requests it forwards. A caller's earlier deadline
+// still applies, and a zero timeout leaves calls unbounded.
+func TimeoutInterceptor(timeout time.Duration) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if timeout <= 0 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -1,85 +0,0 @@
-package clients
-
-import (
-	"bytes"
-	"encoding/json"
-	"io"
-	"net/http"
-	"time"
-)
-
-type HTTPClient struct {
-	baseURL string
-	client  *http.Client
-}
-
-func NewHTTPClient(baseURL string) *HTTPClient {
-	return &HTTPClient{
-		baseURL: baseURL,
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-	}
-}
-
-func (c *HTTPClient) Forward(method, path string, body io.Reader, headers map[string]string) (*http.Response, error) {
-	url := c.baseURL + path
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return nil, err
-	}
-
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
-
-	return c.client.Do(req)
-}
-
-func (c *HTTPClient) Get(path string) (*http.Response, error) {
-	return c.Forward("GET", path, nil, nil)
-}
-
-func (c *HTTPClient) Post(path string, data interface{}) (*http.Response, error) {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
-	}
-
-	headers := map[string]string{"Content-Type": "application/json"}
-	return c.Forward("POST", path, bytes.NewBuffer(jsonData), headers)
-}
-
-func (c *HTTPClient) Put(path string, data interface{}) (*http.Response, error) {
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		return nil, err
-	}
-
-	headers := map[string]string{"Content-Type": "application/json"}
-	return c.Forward("PUT", path, bytes.NewBuffer(jsonData), headers)
-}
-
-func (c *HTTPClient) Delete(path string) (*http.Response, error) {
-	return c.Forward("DELETE", path, nil, nil)
-}
-
-func ForwardResponse(w http.ResponseWriter, resp *http.Response) error {
-	defer resp.Body.Close()
-
-	for key, values := range resp.Header {
-		for _, value := range values {
-			w.Header().Add(key, value)
-		}
-	}
-
-	w.WriteHeader(resp.StatusCode)
-	_, err := io.Copy(w, resp.Body)
-	return err
-}
-
-func WriteError(w http.ResponseWriter, statusCode int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
-}
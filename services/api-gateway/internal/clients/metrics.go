@@ -0,0 +1,39 @@
+package clients
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var upstreamDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "gateway_upstream_request_duration_seconds",
+	Help:    "Time upstream services took to respond to the gateway, by upstream, instance, protocol and outcome.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"upstream", "instance", "protocol", "outcome"})
+
+// observeHTTP records one forwarded HTTP attempt. The outcome is the status
+// code, or "error" when no response came back.
+func observeHTTP(upstream, instance string, statusCode int, err error, duration time.Duration) {
+	outcome := "error"
+	if err == nil {
+		outcome = strconv.Itoa(statusCode)
+	}
+	upstreamDuration.WithLabelValues(upstream, instance, "http", outcome).Observe(duration.Seconds())
+}
+
+// UpstreamInterceptor records the latency of unary gRPC calls to upstream,
+// labelled with the status code of each call
+func UpstreamInterceptor(upstream, addr string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		upstreamDuration.WithLabelValues(upstream, addr, "grpc", status.Code(err).String()).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
@@ -0,0 +1,172 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Load balancing strategies for picking an upstream instance
+const (
+	RoundRobin       = "round_robin"
+	LeastConnections = "least_conn"
+)
+
+// healthCheckTimeout bounds each /health and /ready probe
+const healthCheckTimeout = 2 * time.Second
+
+// instance is one address of an upstream service
+type instance struct {
+	url    *url.URL
+	active atomic.Int64
+
+	mu          sync.Mutex
+	healthy     bool
+	lastChecked time.Time
+	lastError   string
+}
+
+// InstanceStatus is a snapshot of an instance for the admin endpoint
+type InstanceStatus struct {
+	URL            string     `json:"url"`
+	Healthy        bool       `json:"healthy"`
+	ActiveRequests int64      `json:"active_requests"`
+	LastCheckedAt  *time.Time `json:"last_checked_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+}
+
+func newInstance(rawURL string) (*instance, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("%q is not an absolute URL", rawURL)
+	}
+	// Instances are assumed healthy until the first check says otherwise
+	return &instance{url: u, healthy: true}, nil
+}
+
+func (i *instance) isHealthy() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.healthy
+}
+
+// setHealth records the result of a health check or a failed request
+func (i *instance) setHealth(err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.healthy = err == nil
+	i.lastChecked = time.Now()
+	i.lastError = ""
+	if err != nil {
+		i.lastError = err.Error()
+	}
+}
+
+func (i *instance) status() InstanceStatus {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	status := InstanceStatus{
+		URL:            i.url.String(),
+		Healthy:        i.healthy,
+		ActiveRequests: i.active.Load(),
+		LastError:      i.lastError,
+	}
+	if !i.lastChecked.IsZero() {
+		lastChecked := i.lastChecked
+		status.LastCheckedAt = &lastChecked
+	}
+	return status
+}
+
+// pick chooses a healthy instance other than exclude, or nil if there is none
+func (p *Proxy) pick(exclude *instance) *instance {
+	candidates := make([]*instance, 0, len(p.instances))
+	for _, inst := range p.instances {
+		if inst != exclude && inst.isHealthy() {
+			candidates = append(candidates, inst)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if p.strategy == LeastConnections {
+		best := candidates[0]
+		for _, inst := range candidates[1:] {
+			if inst.active.Load() < best.active.Load() {
+				best = inst
+			}
+		}
+		return best
+	}
+
+	n := p.cursor.Add(1)
+	return candidates[(n-1)%uint64(len(candidates))]
+}
+
+// StartHealthChecks probes every instance's /health and /ready endpoints
+// every interval until ctx is done. Unhealthy instances are taken out of
+// rotation until a probe succeeds again.
+func (p *Proxy) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			p.checkHealth(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (p *Proxy) checkHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, inst := range p.instances {
+		wg.Add(1)
+		go func(inst *instance) {
+			defer wg.Done()
+			inst.setHealth(p.probe(ctx, inst))
+		}(inst)
+	}
+	wg.Wait()
+}
+
+func (p *Proxy) probe(ctx context.Context, inst *instance) error {
+	for _, path := range []string{"/health", "/ready"} {
+		probeCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		req, err := http.NewRequestWithContext(probeCtx, http.MethodGet, inst.url.JoinPath(path).String(), nil)
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		resp, err := p.healthClient.Do(req)
+		cancel()
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		// Not every service has a readiness endpoint
+		if path == "/ready" && resp.StatusCode == http.StatusNotFound {
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s returned %d", path, resp.StatusCode)
+		}
+	}
+	return nil
+}
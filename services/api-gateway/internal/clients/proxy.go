@@ -0,0 +1,268 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"sync/atomic"
+	"time"
+
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+)
+
+// Proxy streams requests to the instances of an upstream service and its
+// responses back to the caller. Headers, including repeated ones, and the
+// query string are passed through, and upstream responses are relayed
+// unchanged.
+type Proxy struct {
+	name         string
+	instances    []*instance
+	strategy     string
+	cursor       atomic.Uint64
+	breaker      *Breaker
+	proxy        *httputil.ReverseProxy
+	healthClient *http.Client
+}
+
+// ProxyConfig tunes how a Proxy talks to its upstream
+type ProxyConfig struct {
+	// Timeout bounds how long an instance may take to send response headers;
+	// the body is streamed without a deadline
+	Timeout time.Duration
+	// Retries is how many more times an idempotent request that failed
+	// before a response is sent
+	Retries int
+	// Strategy is RoundRobin or LeastConnections
+	Strategy string
+	// FailureThreshold consecutive failures open the breaker for OpenTimeout
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+// UpstreamStatus is a snapshot of a proxy for the admin endpoint
+type UpstreamStatus struct {
+	Name      string           `json:"name"`
+	Strategy  string           `json:"strategy"`
+	Breaker   BreakerStatus    `json:"breaker"`
+	Instances []InstanceStatus `json:"instances"`
+}
+
+// attempt tracks the instance serving a request and whether it failed
+type attempt struct {
+	inst   *instance
+	failed bool
+}
+
+type attemptKey struct{}
+
+// NewProxy creates a proxy to the service instances at baseURLs
+func NewProxy(name string, baseURLs []string, cfg ProxyConfig) (*Proxy, error) {
+	if len(baseURLs) == 0 {
+		return nil, fmt.Errorf("no %s service URLs configured", name)
+	}
+	if cfg.Strategy == "" {
+		cfg.Strategy = RoundRobin
+	}
+	if cfg.Strategy != RoundRobin && cfg.Strategy != LeastConnections {
+		return nil, fmt.Errorf("unknown load balancing strategy %q", cfg.Strategy)
+	}
+
+	p := &Proxy{
+		name:         name,
+		strategy:     cfg.Strategy,
+		breaker:      NewBreaker(cfg.FailureThreshold, cfg.OpenTimeout),
+		healthClient: &http.Client{Timeout: healthCheckTimeout},
+	}
+	for _, baseURL := range baseURLs {
+		inst, err := newInstance(baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s service URL: %w", name, err)
+		}
+		p.instances = append(p.instances, inst)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = cfg.Timeout
+
+	p.proxy = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(attemptFrom(r.In.Context()).inst.url)
+			// Client-supplied X-Forwarded-* values are dropped before Rewrite
+			// runs, so the address set here is the caller's own
+			r.SetXForwarded()
+			r.Out.Header.Set("X-Real-Ip", r.Out.Header.Get("X-Forwarded-For"))
+		},
+		Transport: &retryTransport{
+			proxy:   p,
+			next:    tracing.Transport(transport),
+			retries: cfg.Retries,
+			backoff: 100 * time.Millisecond,
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			// The body is passed through as is, but a gateway error from the
+			// instance still counts against the breaker
+			switch resp.StatusCode {
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				attemptFrom(resp.Request.Context()).failed = true
+			}
+			return nil
+		},
+		ErrorHandler: p.writeError,
+	}
+
+	return p, nil
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !p.breaker.Allow() {
+		WriteError(w, http.StatusServiceUnavailable, p.name+" service unavailable")
+		return
+	}
+
+	inst := p.pick(nil)
+	if inst == nil {
+		p.breaker.Failure()
+		WriteError(w, http.StatusServiceUnavailable, p.name+" service unavailable")
+		return
+	}
+
+	a := &attempt{inst: inst}
+	inst.active.Add(1)
+	defer func() { a.inst.active.Add(-1) }()
+
+	p.proxy.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), attemptKey{}, a)))
+
+	if a.failed {
+		p.breaker.Failure()
+	} else {
+		p.breaker.Success()
+	}
+}
+
+// Status returns a snapshot of the breaker and instances
+func (p *Proxy) Status() UpstreamStatus {
+	status := UpstreamStatus{
+		Name:      p.name,
+		Strategy:  p.strategy,
+		Breaker:   p.breaker.Status(),
+		Instances: make([]InstanceStatus, len(p.instances)),
+	}
+	for i, inst := range p.instances {
+		status.Instances[i] = inst.status()
+	}
+	return status
+}
+
+// Health reports why the upstream cannot take requests: its breaker is open
+// or none of its instances passed the last health check
+func (p *Proxy) Health(ctx context.Context) error {
+	if p.breaker.Status().State == BreakerOpen {
+		return errors.New("circuit breaker is open")
+	}
+	for _, inst := range p.instances {
+		if inst.isHealthy() {
+			return nil
+		}
+	}
+	return fmt.Errorf("none of %d instances is healthy", len(p.instances))
+}
+
+// writeError reports a request that got no response from the upstream
+func (p *Proxy) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, context.Canceled) {
+		// The caller went away; there is no one to answer
+		return
+	}
+	attemptFrom(r.Context()).failed = true
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		WriteError(w, http.StatusGatewayTimeout, p.name+" service timed out")
+		return
+	}
+	WriteError(w, http.StatusBadGateway, p.name+" service unavailable")
+}
+
+func attemptFrom(ctx context.Context) *attempt {
+	return ctx.Value(attemptKey{}).(*attempt)
+}
+
+// retryTransport retries idempotent requests that failed without a response,
+// on another healthy instance when there is one. Requests with a body are
+// sent once, since the body has already been streamed to the first attempt,
+// and timeouts are not retried so a slow upstream does not hold the caller
+// for several timeouts.
+type retryTransport struct {
+	proxy   *Proxy
+	next    http.RoundTripper
+	retries int
+	backoff time.Duration
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.roundTrip(req)
+	for n := 1; err != nil && n <= t.retries && canRetry(req, err); n++ {
+		a := attemptFrom(req.Context())
+		next := t.proxy.pick(a.inst)
+		if next == nil {
+			next = a.inst
+		}
+
+		select {
+		case <-req.Context().Done():
+			return nil, err
+		case <-time.After(time.Duration(n) * t.backoff):
+		}
+
+		a.inst.active.Add(-1)
+		next.active.Add(1)
+		a.inst = next
+
+		req = req.Clone(req.Context())
+		req.URL.Scheme = next.url.Scheme
+		req.URL.Host = next.url.Host
+		resp, err = t.roundTrip(req)
+	}
+	return resp, err
+}
+
+// roundTrip sends a single attempt and records how long the instance took
+// to respond
+func (t *retryTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	observeHTTP(t.proxy.name, req.URL.Host, statusCode, err, time.Since(start))
+	return resp, err
+}
+
+func canRetry(req *http.Request, err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// WriteError writes an error in the {"error": "..."} shape the services use
+func WriteError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
@@ -1,30 +1,171 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/rentalflow/api-gateway/config"
+	"github.com/rentalflow/api-gateway/internal/cache"
 	"github.com/rentalflow/api-gateway/internal/clients"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"github.com/rentalflow/rentalflow/pkg/health"
+	authpb "github.com/rentalflow/rentalflow/pkg/pb/auth"
+	bookingpb "github.com/rentalflow/rentalflow/pkg/pb/booking"
+	inventorypb "github.com/rentalflow/rentalflow/pkg/pb/inventory"
+	notificationpb "github.com/rentalflow/rentalflow/pkg/pb/notification"
+	paymentpb "github.com/rentalflow/rentalflow/pkg/pb/payment"
+	reviewpb "github.com/rentalflow/rentalflow/pkg/pb/review"
 )
 
 type Gateway struct {
-	authClient         *clients.HTTPClient
-	inventoryClient    *clients.HTTPClient
-	bookingClient      *clients.HTTPClient
-	paymentClient      *clients.HTTPClient
-	notificationClient *clients.HTTPClient
-	reviewClient       *clients.HTTPClient
+	authProxy         *clients.Proxy
+	inventoryProxy    *clients.Proxy
+	bookingProxy      *clients.Proxy
+	paymentProxy      *clients.Proxy
+	notificationProxy *clients.Proxy
+	reviewProxy       *clients.Proxy
+
+	// REST routes annotated in the protos are transcoded to gRPC; the rest
+	// fall back to the proxies above
+	auth         *transcoder
+	inventory    *transcoder
+	booking      *transcoder
+	payment      *transcoder
+	notification *transcoder
+	review       *transcoder
+
+	views *views
+
+	cache       *cache.Cache
+	cacheConfig config.CacheConfig
+
+	checker          *auth.Checker
+	stopHealthChecks context.CancelFunc
+
+	// upstreams backs /health with the state of every upstream
+	upstreams *health.Registry
 }
 
-func NewGateway(cfg *config.Config) *Gateway {
-	return &Gateway{
-		authClient:         clients.NewHTTPClient(cfg.AuthServiceURL),
-		inventoryClient:    clients.NewHTTPClient(cfg.InventoryServiceURL),
-		bookingClient:      clients.NewHTTPClient(cfg.BookingServiceURL),
-		paymentClient:      clients.NewHTTPClient(cfg.PaymentServiceURL),
-		notificationClient: clients.NewHTTPClient(cfg.NotificationServiceURL),
-		reviewClient:       clients.NewHTTPClient(cfg.ReviewServiceURL),
+func NewGateway(cfg *config.Config, responseCache *cache.Cache) (*Gateway, error) {
+	g := &Gateway{
+		cache:       responseCache,
+		cacheConfig: cfg.Gateway.Cache,
+		checker:     auth.NewChecker(cfg.JWT.Secret),
+	}
+	upstreams := cfg.Gateway.Upstreams
+
+	proxyConfig := func(timeout time.Duration) clients.ProxyConfig {
+		return clients.ProxyConfig{
+			Timeout:          timeout,
+			Retries:          cfg.Gateway.ProxyRetries,
+			Strategy:         cfg.Gateway.LoadBalancing,
+			FailureThreshold: cfg.Gateway.BreakerFailureThreshold,
+			OpenTimeout:      cfg.Gateway.BreakerOpenTimeout,
+		}
+	}
+
+	var err error
+	if g.authProxy, err = clients.NewProxy("Auth", upstreams.Auth.URLs, proxyConfig(upstreams.Auth.Timeout)); err != nil {
+		return nil, err
+	}
+	if g.inventoryProxy, err = clients.NewProxy("Inventory", upstreams.Inventory.URLs, proxyConfig(upstreams.Inventory.Timeout)); err != nil {
+		return nil, err
+	}
+	if g.bookingProxy, err = clients.NewProxy("Booking", upstreams.Booking.URLs, proxyConfig(upstreams.Booking.Timeout)); err != nil {
+		return nil, err
+	}
+	if g.paymentProxy, err = clients.NewProxy("Payment", upstreams.Payment.URLs, proxyConfig(upstreams.Payment.Timeout)); err != nil {
+		return nil, err
+	}
+	if g.notificationProxy, err = clients.NewProxy("Notification", upstreams.Notification.URLs, proxyConfig(upstreams.Notification.Timeout)); err != nil {
+		return nil, err
+	}
+	if g.reviewProxy, err = clients.NewProxy("Review", upstreams.Review.URLs, proxyConfig(upstreams.Review.Timeout)); err != nil {
+		return nil, err
+	}
+
+	if g.auth, err = newTranscoder("Auth", upstreams.Auth, authpb.RegisterAuthServiceHandler, g.authProxy); err != nil {
+		return nil, err
+	}
+	if g.inventory, err = newTranscoder("Inventory", upstreams.Inventory, inventorypb.RegisterInventoryServiceHandler, g.inventoryProxy); err != nil {
+		g.Close()
+		return nil, err
+	}
+	if g.booking, err = newTranscoder("Booking", upstreams.Booking, bookingpb.RegisterBookingServiceHandler, g.bookingProxy); err != nil {
+		g.Close()
+		return nil, err
+	}
+	if g.payment, err = newTranscoder("Payment", upstreams.Payment, paymentpb.RegisterPaymentServiceHandler, g.paymentProxy); err != nil {
+		g.Close()
+		return nil, err
+	}
+	if g.notification, err = newTranscoder("Notification", upstreams.Notification, notificationpb.RegisterNotificationServiceHandler, g.notificationProxy); err != nil {
+		g.Close()
+		return nil, err
+	}
+	if g.review, err = newTranscoder("Review", upstreams.Review, reviewpb.RegisterReviewServiceHandler, g.reviewProxy); err != nil {
+		g.Close()
+		return nil, err
+	}
+
+	g.views = &views{
+		auth:         authpb.NewAuthServiceClient(g.auth.conn),
+		inventory:    inventorypb.NewInventoryServiceClient(g.inventory.conn),
+		booking:      bookingpb.NewBookingServiceClient(g.booking.conn),
+		payment:      paymentpb.NewPaymentServiceClient(g.payment.conn),
+		notification: notificationpb.NewNotificationServiceClient(g.notification.conn),
+		review:       reviewpb.NewReviewServiceClient(g.review.conn),
+		callTimeout:  cfg.Gateway.ViewCallTimeout,
+	}
+
+	// Upstream checks read state the proxies and connections already keep,
+	// so they are cheap enough to run on every request
+	g.upstreams = health.NewRegistry(time.Second, 0)
+	for _, u := range []struct {
+		name  string
+		proxy *clients.Proxy
+		grpc  *transcoder
+	}{
+		{"auth", g.authProxy, g.auth},
+		{"inventory", g.inventoryProxy, g.inventory},
+		{"booking", g.bookingProxy, g.booking},
+		{"payment", g.paymentProxy, g.payment},
+		{"notification", g.notificationProxy, g.notification},
+		{"review", g.reviewProxy, g.review},
+	} {
+		g.upstreams.Register(u.name, func(ctx context.Context) error {
+			if err := u.proxy.Health(ctx); err != nil {
+				return err
+			}
+			return u.grpc.Health(ctx)
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	g.stopHealthChecks = cancel
+	for _, p := range g.proxies() {
+		p.StartHealthChecks(ctx, cfg.Gateway.HealthCheckInterval)
+	}
+
+	return g, nil
+}
+
+func (g *Gateway) proxies() []*clients.Proxy {
+	return []*clients.Proxy{g.authProxy, g.inventoryProxy, g.bookingProxy, g.paymentProxy, g.notificationProxy, g.reviewProxy}
+}
+
+// Close stops the health checks and closes the gRPC connections to the services
+func (g *Gateway) Close() {
+	if g.stopHealthChecks != nil {
+		g.stopHealthChecks()
+	}
+	for _, t := range []*transcoder{g.auth, g.inventory, g.booking, g.payment, g.notification, g.review} {
+		if t != nil {
+			t.Close()
+		}
 	}
 }
 
@@ -33,120 +174,111 @@ func (g *Gateway) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/health", g.Health).Methods("GET")
 	r.HandleFunc("/api/health", g.Health).Methods("GET")
 
+	// Gateway admin
+	r.HandleFunc("/api/admin/upstreams", g.Upstreams).Methods("GET")
+
+	// Aggregated views for the web app
+	r.HandleFunc("/api/views/booking/{id}", g.views.Booking).Methods("GET")
+	r.HandleFunc("/api/views/item/{id}", g.views.Item).Methods("GET")
+
 	// Auth service routes (public)
-	r.HandleFunc("/api/auth/register", g.forwardToAuth).Methods("POST")
-	r.HandleFunc("/api/auth/login", g.forwardToAuth).Methods("POST")
-	r.HandleFunc("/api/auth/validate", g.forwardToAuth).Methods("POST")
-	r.HandleFunc("/api/auth/logout", g.forwardToAuth).Methods("POST")
-	r.HandleFunc("/api/auth/profile", g.forwardToAuth).Methods("GET", "PUT")
-	r.HandleFunc("/api/auth/avatar", g.forwardToAuth).Methods("POST")
-	r.HandleFunc("/api/auth/change-password", g.forwardToAuth).Methods("POST")
-	r.HandleFunc("/api/users", g.forwardToAuth).Methods("GET")
-
-	// Inventory service routes
-	r.PathPrefix("/api/inventory").HandlerFunc(g.forwardToInventory)
-	r.PathPrefix("/api/items").HandlerFunc(g.forwardToInventory)
+	r.Handle("/api/auth/register", g.auth).Methods("POST")
+	r.Handle("/api/auth/login", g.auth).Methods("POST")
+	r.Handle("/api/auth/validate", g.auth).Methods("POST")
+	r.Handle("/api/auth/logout", g.auth).Methods("POST")
+	r.Handle("/api/auth/profile", g.auth).Methods("GET", "PUT")
+	r.Handle("/api/auth/avatar", g.auth).Methods("POST")
+	r.Handle("/api/auth/change-password", g.auth).Methods("POST")
+	r.Handle("/api/auth/verify-email", g.auth).Methods("POST")
+	r.Handle("/api/auth/verify-email/resend", g.auth).Methods("POST")
+	r.Handle("/api/auth/forgot-password", g.auth).Methods("POST")
+	r.Handle("/api/auth/reset-password", g.auth).Methods("POST")
+	r.Handle("/api/auth/login/2fa", g.auth).Methods("POST")
+	r.Handle("/api/auth/oidc/providers", g.auth).Methods("GET")
+	r.Handle("/api/auth/oidc/authorize", g.auth).Methods("POST")
+	r.Handle("/api/auth/oidc/callback", g.auth).Methods("GET", "POST")
+	r.Handle("/api/auth/2fa/enroll", g.auth).Methods("POST")
+	r.Handle("/api/auth/2fa/confirm", g.auth).Methods("POST")
+	r.Handle("/api/auth/2fa/disable", g.auth).Methods("POST")
+	r.Handle("/api/auth/2fa/recovery-codes", g.auth).Methods("POST")
+	r.Handle("/api/auth/admin/2fa-policy", g.auth).Methods("GET", "PUT")
+	r.Handle("/api/auth/admin/locked-accounts", g.auth).Methods("GET")
+	r.Handle("/api/auth/admin/unlock", g.auth).Methods("POST")
+	r.Handle("/api/auth/admin/audit-log", g.auth).Methods("GET")
+	r.Handle("/api/auth/roles", g.auth).Methods("POST")
+	r.Handle("/api/auth/admin/users/roles", g.auth).Methods("PUT")
+	r.Handle("/api/auth/kyc", g.auth).Methods("GET", "POST")
+	r.Handle("/api/auth/kyc/cases", g.auth).Methods("GET")
+	r.Handle("/api/auth/admin/kyc/cases", g.auth).Methods("GET")
+	r.Handle("/api/auth/admin/kyc/approve", g.auth).Methods("POST")
+	r.Handle("/api/auth/admin/kyc/reject", g.auth).Methods("POST")
+	r.Handle("/api/auth/privacy/export", g.auth).Methods("POST")
+	r.Handle("/api/auth/privacy/export/download", g.auth).Methods("GET")
+	r.Handle("/api/auth/privacy/delete", g.auth).Methods("POST")
+	r.Handle("/api/auth/privacy/requests", g.auth).Methods("GET")
+	r.Handle("/api/auth/admin/privacy/requests", g.auth).Methods("GET")
+	r.Handle("/api/users", g.auth).Methods("GET")
+
+	// Inventory service routes. GET /api/items?id= predates the REST
+	// bindings and would otherwise be served by ListItems.
+	r.Path("/api/items").Methods("GET").Queries("id", "{id}").Handler(g.inventoryProxy)
+	// Public catalog reads are served from the response cache
+	r.Handle("/api/items", g.cache.Handler(g.cacheConfig.ItemsTTL, itemsTags, g.inventory)).Methods("GET")
+	r.Handle("/api/items/search", g.cache.Handler(g.cacheConfig.ItemsTTL, itemsTags, g.inventory)).Methods("GET")
+	r.Handle("/api/items/{id:[0-9a-fA-F-]{36}}", g.cache.Handler(g.cacheConfig.ItemTTL, itemTags, g.inventory)).Methods("GET")
+	r.PathPrefix("/api/inventory").Handler(g.inventory)
+	r.PathPrefix("/api/items").Handler(g.inventory)
 
 	// Booking service routes
-	r.PathPrefix("/api/bookings").HandlerFunc(g.forwardToBooking)
+	r.PathPrefix("/api/bookings").Handler(g.booking)
 
 	// Payment service routes
-	r.PathPrefix("/api/payments").HandlerFunc(g.forwardToPayment)
+	r.PathPrefix("/api/payments").Handler(g.payment)
 
 	// Notification service routes
-	r.PathPrefix("/api/notifications").HandlerFunc(g.forwardToNotification)
-	r.PathPrefix("/api/messages").HandlerFunc(g.forwardToNotification)
+	r.PathPrefix("/api/notifications").Handler(g.notification)
+	r.PathPrefix("/api/messages").Handler(g.notification)
 
 	// Review service routes
-	r.PathPrefix("/api/reviews").HandlerFunc(g.forwardToReview)
+	r.Handle("/api/reviews/item", g.cache.Handler(g.cacheConfig.ItemReviewsTTL, itemReviewsTags, g.review)).Methods("GET")
+	r.PathPrefix("/api/reviews").Handler(g.review)
 }
 
-func (g *Gateway) Health(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"healthy","service":"api-gateway"}`))
+// itemsTags makes cached item listings depend on every item
+func itemsTags(r *http.Request) []string {
+	return []string{cache.CatalogTag, cache.ItemsTag}
 }
 
-func (g *Gateway) forwardToAuth(w http.ResponseWriter, r *http.Request) {
-	resp, err := g.authClient.Forward(r.Method, r.URL.Path, r.Body, getHeaders(r))
-	if err != nil {
-		clients.WriteError(w, http.StatusBadGateway, "Auth service unavailable")
-		return
-	}
-	clients.ForwardResponse(w, resp)
+// itemTags makes a cached item depend on that item
+func itemTags(r *http.Request) []string {
+	return []string{cache.CatalogTag, cache.ItemTag(mux.Vars(r)["id"])}
 }
 
-func (g *Gateway) forwardToInventory(w http.ResponseWriter, r *http.Request) {
-	path := r.URL.Path
-	if r.URL.RawQuery != "" {
-		path += "?" + r.URL.RawQuery
-	}
-	resp, err := g.inventoryClient.Forward(r.Method, path, r.Body, getHeaders(r))
-	if err != nil {
-		clients.WriteError(w, http.StatusBadGateway, "Inventory service unavailable")
-		return
-	}
-	clients.ForwardResponse(w, resp)
+// itemReviewsTags makes an item's cached reviews depend on the item, whose
+// tag review events for it invalidate, and on every review
+func itemReviewsTags(r *http.Request) []string {
+	return []string{cache.ReviewsTag, cache.ItemTag(r.URL.Query().Get("item_id"))}
 }
 
-func (g *Gateway) forwardToBooking(w http.ResponseWriter, r *http.Request) {
-	path := r.URL.Path
-	if r.URL.RawQuery != "" {
-		path += "?" + r.URL.RawQuery
-	}
-	resp, err := g.bookingClient.Forward(r.Method, path, r.Body, getHeaders(r))
-	if err != nil {
-		clients.WriteError(w, http.StatusBadGateway, "Booking service unavailable")
-		return
-	}
-	clients.ForwardResponse(w, resp)
+// Health reports each upstream, with 503 Service Unavailable while any of
+// them cannot take requests
+func (g *Gateway) Health(w http.ResponseWriter, r *http.Request) {
+	g.upstreams.Handler().ServeHTTP(w, r)
 }
 
-func (g *Gateway) forwardToPayment(w http.ResponseWriter, r *http.Request) {
-	path := r.URL.Path
-	if r.URL.RawQuery != "" {
-		path += "?" + r.URL.RawQuery
-	}
-	resp, err := g.paymentClient.Forward(r.Method, path, r.Body, getHeaders(r))
-	if err != nil {
-		clients.WriteError(w, http.StatusBadGateway, "Payment service unavailable")
+// Upstreams reports the health of every upstream instance and the state of
+// each upstream's circuit breaker
+func (g *Gateway) Upstreams(w http.ResponseWriter, r *http.Request) {
+	if _, ok := g.checker.Require(w, r, auth.PermUsersManage); !ok {
 		return
 	}
-	clients.ForwardResponse(w, resp)
-}
 
-func (g *Gateway) forwardToNotification(w http.ResponseWriter, r *http.Request) {
-	path := r.URL.Path
-	if r.URL.RawQuery != "" {
-		path += "?" + r.URL.RawQuery
+	proxies := g.proxies()
+	upstreams := make([]clients.UpstreamStatus, len(proxies))
+	for i, p := range proxies {
+		upstreams[i] = p.Status()
 	}
-	resp, err := g.notificationClient.Forward(r.Method, path, r.Body, getHeaders(r))
-	if err != nil {
-		clients.WriteError(w, http.StatusBadGateway, "Notification service unavailable")
-		return
-	}
-	clients.ForwardResponse(w, resp)
-}
 
-func (g *Gateway) forwardToReview(w http.ResponseWriter, r *http.Request) {
-	path := r.URL.Path
-	if r.URL.RawQuery != "" {
-		path += "?" + r.URL.RawQuery
-	}
-	resp, err := g.reviewClient.Forward(r.Method, path, r.Body, getHeaders(r))
-	if err != nil {
-		clients.WriteError(w, http.StatusBadGateway, "Review service unavailable")
-		return
-	}
-	clients.ForwardResponse(w, resp)
-}
-
-func getHeaders(r *http.Request) map[string]string {
-	headers := make(map[string]string)
-	for key, values := range r.Header {
-		if len(values) > 0 {
-			headers[key] = values[0]
-		}
-	}
-	return headers
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"upstreams": upstreams})
 }
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rentalflow/api-gateway/internal/clients"
+	"github.com/rentalflow/api-gateway/internal/middleware"
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"github.com/rentalflow/rentalflow/pkg/etag"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// registerFunc binds a service's REST routes to its gRPC client connection.
+// The generated Register*ServiceHandler functions have this signature.
+type registerFunc func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error
+
+// protoJSON keeps the snake_case field names and zero values the HTTP
+// handlers return
+var protoJSON = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// transcoder serves the REST routes declared by a service's google.api.http
+// annotations by calling its gRPC methods. Requests that match no annotated
+// route are passed to the service's proxy over HTTP.
+type transcoder struct {
+	mux  *runtime.ServeMux
+	conn *grpc.ClientConn
+}
+
+// newTranscoder dials a service's gRPC address and registers its REST routes.
+// The connection is established lazily on the first call. name labels the
+// service in the upstream latency metrics. Calls go through the circuit
+// breaker of proxy, the service's HTTP upstream, are bounded by the
+// upstream's timeout and are spread over every instance the address
+// resolves to in DNS.
+func newTranscoder(name string, upstream config.UpstreamConfig, register registerFunc, proxy *clients.Proxy) (*transcoder, error) {
+	addr := upstream.GRPCAddr
+	target := addr
+	if !strings.Contains(target, ":///") {
+		target = "dns:///" + target
+	}
+
+	conn, err := grpc.Dial(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(clients.GRPCServiceConfig),
+		grpc.WithChainUnaryInterceptor(
+			proxy.BreakerInterceptor(),
+			clients.TimeoutInterceptor(upstream.Timeout),
+			clients.UpstreamInterceptor(name, addr),
+		),
+		tracing.DialOption(),
+		logger.DialOption(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protoJSON,
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+		runtime.WithErrorHandler(writeStatusError),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeader),
+		runtime.WithRoutingErrorHandler(func(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, httpStatus int) {
+			if httpStatus == http.StatusNotFound || httpStatus == http.StatusMethodNotAllowed {
+				proxy.ServeHTTP(w, r)
+				return
+			}
+			runtime.DefaultRoutingErrorHandler(ctx, mux, m, w, r, httpStatus)
+		}),
+	)
+
+	if err := register(context.Background(), mux, conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to register routes for %s: %w", addr, err)
+	}
+
+	return &transcoder{mux: mux, conn: conn}, nil
+}
+
+// outgoingHeader returns the ETag a service sends as metadata in the ETag
+// header and other metadata under the default Grpc-Metadata- prefix
+func outgoingHeader(key string) (string, bool) {
+	if key == etag.MetadataKey {
+		return "ETag", true
+	}
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+func (t *transcoder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The services read the caller's address from the first X-Forwarded-For
+	// entry, so drop any client-supplied value before it is passed on
+	r.Header.Set("X-Forwarded-For", middleware.ClientIP(r))
+	t.mux.ServeHTTP(w, r)
+}
+
+// Health reports an error while the gRPC connection is failing. An idle
+// connection is fine, since it connects on the next call.
+func (t *transcoder) Health(ctx context.Context) error {
+	if t.conn.GetState() == connectivity.TransientFailure {
+		return errors.New("grpc connection is failing")
+	}
+	return nil
+}
+
+// Close closes the gRPC connection
+func (t *transcoder) Close() error {
+	return t.conn.Close()
+}
+
+// writeStatusError writes a gRPC error in the {"error": "..."} shape the
+// services use over HTTP
+func writeStatusError(ctx context.Context, mux *runtime.ServeMux, m runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	st := status.Convert(err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(runtime.HTTPStatusFromCode(st.Code()))
+	json.NewEncoder(w).Encode(map[string]string{"error": st.Message()})
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rentalflow/api-gateway/internal/clients"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	authpb "github.com/rentalflow/rentalflow/pkg/pb/auth"
+	bookingpb "github.com/rentalflow/rentalflow/pkg/pb/booking"
+	inventorypb "github.com/rentalflow/rentalflow/pkg/pb/inventory"
+	notificationpb "github.com/rentalflow/rentalflow/pkg/pb/notification"
+	paymentpb "github.com/rentalflow/rentalflow/pkg/pb/payment"
+	reviewpb "github.com/rentalflow/rentalflow/pkg/pb/review"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// availabilityWindow is how far ahead the item view reports availability
+const availabilityWindow = 90 * 24 * time.Hour
+
+// views serves the /api/views endpoints, which gather everything a page of
+// the web app needs in one call. The main resource must load; the other
+// parts are fetched concurrently, and any that fail are left out and named
+// in "missing" instead of failing the whole view.
+type views struct {
+	auth         authpb.AuthServiceClient
+	inventory    inventorypb.InventoryServiceClient
+	booking      bookingpb.BookingServiceClient
+	payment      paymentpb.PaymentServiceClient
+	notification notificationpb.NotificationServiceClient
+	review       reviewpb.ReviewServiceClient
+
+	// callTimeout is the deadline for each backend call
+	callTimeout time.Duration
+}
+
+// viewPart fetches one section of a view
+type viewPart struct {
+	name  string
+	fetch func(ctx context.Context) (interface{}, error)
+}
+
+// publicProfile is the part of a user shown to the other side of a booking
+type publicProfile struct {
+	ID               string `json:"id"`
+	FirstName        string `json:"first_name"`
+	LastName         string `json:"last_name"`
+	IdentityVerified bool   `json:"identity_verified"`
+}
+
+// Booking returns a booking with its item, both parties' profiles, payments,
+// messages and the item's reviews
+func (v *views) Booking(w http.ResponseWriter, r *http.Request) {
+	ctx := outgoingContext(r)
+
+	callCtx, cancel := context.WithTimeout(ctx, v.callTimeout)
+	booking, err := v.booking.GetBooking(callCtx, &bookingpb.GetBookingRequest{BookingId: mux.Vars(r)["id"]})
+	cancel()
+	if err != nil {
+		writeViewError(w, err)
+		return
+	}
+
+	view := map[string]interface{}{"booking": protoValue(booking)}
+	missing := v.fetch(ctx, view, []viewPart{
+		{"item", func(ctx context.Context) (interface{}, error) {
+			return v.inventory.GetItem(ctx, &inventorypb.GetItemRequest{ItemId: booking.RentalItemId})
+		}},
+		{"owner", v.profile(booking.OwnerId)},
+		{"renter", v.profile(booking.RenterId)},
+		{"payments", func(ctx context.Context) (interface{}, error) {
+			resp, err := v.payment.GetBookingPayments(ctx, &paymentpb.GetBookingPaymentsRequest{BookingId: booking.Id})
+			if err != nil {
+				return nil, err
+			}
+			return protoList(resp.Payments), nil
+		}},
+		{"messages", func(ctx context.Context) (interface{}, error) {
+			resp, err := v.notification.GetBookingMessages(ctx, &notificationpb.GetBookingMessagesRequest{BookingId: booking.Id})
+			if err != nil {
+				return nil, err
+			}
+			return protoList(resp.Messages), nil
+		}},
+		{"reviews", v.itemReviews(booking.RentalItemId)},
+	})
+
+	writeView(w, view, missing)
+}
+
+// Item returns an item with its owner's profile, reviews and availability
+// over the next 90 days
+func (v *views) Item(w http.ResponseWriter, r *http.Request) {
+	ctx := outgoingContext(r)
+
+	callCtx, cancel := context.WithTimeout(ctx, v.callTimeout)
+	item, err := v.inventory.GetItem(callCtx, &inventorypb.GetItemRequest{ItemId: mux.Vars(r)["id"]})
+	cancel()
+	if err != nil {
+		writeViewError(w, err)
+		return
+	}
+
+	view := map[string]interface{}{"item": protoValue(item)}
+	missing := v.fetch(ctx, view, []viewPart{
+		{"owner", v.profile(item.OwnerId)},
+		{"reviews", v.itemReviews(item.Id)},
+		{"availability", func(ctx context.Context) (interface{}, error) {
+			now := time.Now().UTC()
+			resp, err := v.inventory.GetAvailability(ctx, &inventorypb.GetAvailabilityRequest{
+				ItemId:    item.Id,
+				StartDate: now.Format("2006-01-02"),
+				EndDate:   now.Add(availabilityWindow).Format("2006-01-02"),
+			})
+			if err != nil {
+				return nil, err
+			}
+			return protoList(resp.Slots), nil
+		}},
+	})
+
+	writeView(w, view, missing)
+}
+
+func (v *views) profile(userID string) func(ctx context.Context) (interface{}, error) {
+	return func(ctx context.Context) (interface{}, error) {
+		user, err := v.auth.GetUserById(ctx, &authpb.GetUserByIdRequest{UserId: userID})
+		if err != nil {
+			return nil, err
+		}
+		return publicProfile{
+			ID:               user.Id,
+			FirstName:        user.FirstName,
+			LastName:         user.LastName,
+			IdentityVerified: user.IdentityVerified,
+		}, nil
+	}
+}
+
+func (v *views) itemReviews(itemID string) func(ctx context.Context) (interface{}, error) {
+	return func(ctx context.Context) (interface{}, error) {
+		resp, err := v.review.GetItemReviews(ctx, &reviewpb.GetItemReviewsRequest{ItemId: itemID})
+		if err != nil {
+			return nil, err
+		}
+		return protoList(resp.Reviews), nil
+	}
+}
+
+// fetch runs the parts concurrently, each with its own deadline, and adds
+// the ones that succeed to view. It returns the names of the parts that
+// failed, in the order given.
+func (v *views) fetch(ctx context.Context, view map[string]interface{}, parts []viewPart) []string {
+	results := make([]interface{}, len(parts))
+	errs := make([]error, len(parts))
+
+	var wg sync.WaitGroup
+	for i, part := range parts {
+		wg.Add(1)
+		go func(i int, part viewPart) {
+			defer wg.Done()
+			callCtx, cancel := context.WithTimeout(ctx, v.callTimeout)
+			defer cancel()
+			results[i], errs[i] = part.fetch(callCtx)
+		}(i, part)
+	}
+	wg.Wait()
+
+	missing := []string{}
+	for i, part := range parts {
+		if errs[i] != nil {
+			logger.Ctx(ctx).Warn().Err(errs[i]).Str("part", part.name).Msg("View part unavailable")
+			missing = append(missing, part.name)
+			continue
+		}
+		if m, ok := results[i].(proto.Message); ok {
+			results[i] = protoValue(m)
+		}
+		view[part.name] = results[i]
+	}
+	return missing
+}
+
+// outgoingContext passes the caller's bearer token on to the services, which
+// authorize each call themselves
+func outgoingContext(r *http.Request) context.Context {
+	ctx := r.Context()
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", authorization)
+	}
+	return ctx
+}
+
+// protoValue renders a message the way the transcoded routes do
+func protoValue(m proto.Message) json.RawMessage {
+	b, err := protoJSON.Marshal(m)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return b
+}
+
+func protoList[T proto.Message](items []T) []json.RawMessage {
+	list := make([]json.RawMessage, len(items))
+	for i, item := range items {
+		list[i] = protoValue(item)
+	}
+	return list
+}
+
+func writeView(w http.ResponseWriter, view map[string]interface{}, missing []string) {
+	view["missing"] = missing
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(view)
+}
+
+// writeViewError reports a view whose main resource could not be loaded
+func writeViewError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	clients.WriteError(w, runtime.HTTPStatusFromCode(st.Code()), st.Message())
+}
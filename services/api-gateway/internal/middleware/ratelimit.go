@@ -1,58 +1,174 @@
 package middleware
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
-	"sync"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
-	"golang.org/x/time/rate"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/logger"
 )
 
+// rateLimitWindow is the period every quota covers
+const rateLimitWindow = time.Minute
+
+// RateLimitConfig sets the quotas a RateLimiter enforces, in requests per minute
+type RateLimitConfig struct {
+	// Default applies to anonymous callers and to users whose roles have no
+	// quota of their own
+	Default int
+	// Roles maps a role to its quota; a user with several roles gets the largest
+	Roles map[string]int
+	// Routes maps "METHOD /path" or "/path" to a quota for requests under that
+	// path, counted separately from the caller's overall quota
+	Routes map[string]int
+}
+
+// routeQuota is a parsed entry of RateLimitConfig.Routes
+type routeQuota struct {
+	name   string
+	method string
+	prefix string
+	limit  int
+}
+
+// RateLimiter limits requests per caller. Signed-in callers are counted by
+// user ID and anonymous ones by IP address. Counters live in a
+// RateLimitStore so they can be shared between gateway instances.
 type RateLimiter struct {
-	limiters map[string]*rate.Limiter
-	mu       sync.RWMutex
-	limit    rate.Limit
-	burst    int
+	store   RateLimitStore
+	checker *auth.Checker
+	quotas  atomic.Pointer[quotas]
 }
 
-func NewRateLimiter(requestsPerMinute int) *RateLimiter {
-	return &RateLimiter{
-		limiters: make(map[string]*rate.Limiter),
-		limit:    rate.Every(time.Minute / time.Duration(requestsPerMinute)),
-		burst:    requestsPerMinute,
-	}
+// quotas is a RateLimitConfig with its routes parsed. It is replaced as a
+// whole when the config changes, so a request sees one version throughout.
+type quotas struct {
+	cfg    RateLimitConfig
+	routes []routeQuota
 }
 
-func (rl *RateLimiter) getLimiter(key string) *rate.Limiter {
-	rl.mu.RLock()
-	limiter, exists := rl.limiters[key]
-	rl.mu.RUnlock()
+func NewRateLimiter(store RateLimitStore, checker *auth.Checker, cfg RateLimitConfig) *RateLimiter {
+	rl := &RateLimiter{store: store, checker: checker}
+	rl.SetConfig(cfg)
+	return rl
+}
 
-	if !exists {
-		rl.mu.Lock()
-		limiter = rate.NewLimiter(rl.limit, rl.burst)
-		rl.limiters[key] = limiter
-		rl.mu.Unlock()
+// SetConfig replaces the quotas. Counters carry over, so a caller's requests
+// in the current window count against the new quota.
+func (rl *RateLimiter) SetConfig(cfg RateLimitConfig) {
+	q := &quotas{cfg: cfg}
+	for name, limit := range cfg.Routes {
+		route := routeQuota{name: name, prefix: name, limit: limit}
+		if method, prefix, ok := strings.Cut(name, " "); ok {
+			route.method = strings.ToUpper(method)
+			route.prefix = strings.TrimSpace(prefix)
+		}
+		q.routes = append(q.routes, route)
 	}
+	// The most specific route wins
+	sort.Slice(q.routes, func(i, j int) bool {
+		if len(q.routes[i].prefix) != len(q.routes[j].prefix) {
+			return len(q.routes[i].prefix) > len(q.routes[j].prefix)
+		}
+		return q.routes[i].method > q.routes[j].method
+	})
+	rl.quotas.Store(q)
+}
 
-	return limiter
+// quotaUsage is the state of one quota after counting a request
+type quotaUsage struct {
+	limit     int
+	remaining int64
+	resetAt   time.Time
 }
 
 func (rl *RateLimiter) Limit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		key := r.RemoteAddr
+		q := rl.quotas.Load()
+		key, limit := rl.caller(r, q)
+		keys := map[string]int{key: limit}
+		if route := q.route(r); route != nil {
+			keys["route:"+route.name+":"+key] = route.limit
+		}
+
+		// Count the request against every quota that applies and report the
+		// one closest to running out
+		var tightest *quotaUsage
+		for k, l := range keys {
+			count, resetAt, err := rl.store.Hit(r.Context(), k, rateLimitWindow)
+			if err != nil {
+				// Fail open: an unreachable store should not take the API down
+				logger.Ctx(r.Context()).Warn().Err(err).Msg("Rate limit store unavailable")
+				continue
+			}
+			usage := &quotaUsage{limit: l, remaining: int64(l) - count, resetAt: resetAt}
+			if tightest == nil || usage.remaining < tightest.remaining {
+				tightest = usage
+			}
+		}
+		if tightest == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
 
-		// Use user ID if authenticated
-		if userID, ok := r.Context().Value(UserIDKey).(string); ok {
-			key = userID
+		reset := int(math.Ceil(time.Until(tightest.resetAt).Seconds()))
+		if reset < 0 {
+			reset = 0
 		}
+		w.Header().Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", tightest.limit, int(rateLimitWindow.Seconds())))
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(tightest.limit))
+		w.Header().Set("RateLimit-Remaining", strconv.FormatInt(max(tightest.remaining, 0), 10))
+		w.Header().Set("RateLimit-Reset", strconv.Itoa(reset))
 
-		limiter := rl.getLimiter(key)
-		if !limiter.Allow() {
-			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+		if tightest.remaining < 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(reset))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]string{"error": "rate limit exceeded"})
 			return
 		}
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// caller returns the counter key and overall quota for the request. The
+// token is verified here since the services, not the gateway, authenticate
+// requests; an invalid token is counted as anonymous.
+func (rl *RateLimiter) caller(r *http.Request, q *quotas) (string, int) {
+	principal, err := rl.checker.FromRequest(r)
+	if err != nil {
+		return "ip:" + ClientIP(r), q.cfg.Default
+	}
+
+	limit := 0
+	for _, role := range principal.Roles {
+		if roleLimit, ok := q.cfg.Roles[role]; ok && roleLimit > limit {
+			limit = roleLimit
+		}
+	}
+	if limit == 0 {
+		limit = q.cfg.Default
+	}
+	return "user:" + principal.UserID, limit
+}
+
+// route returns the route quota matching the request, if any
+func (q *quotas) route(r *http.Request) *routeQuota {
+	for i, route := range q.routes {
+		if route.method != "" && route.method != r.Method {
+			continue
+		}
+		if r.URL.Path == route.prefix || strings.HasPrefix(r.URL.Path, strings.TrimSuffix(route.prefix, "/")+"/") {
+			return &q.routes[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package middleware_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rentalflow/api-gateway/internal/middleware"
+)
+
+func TestRedisStoreHit(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(mr *miniredis.Miniredis)
+		wantCount int64
+	}{
+		{"FirstHit", nil, 1},
+		{"SameWindow", func(mr *miniredis.Miniredis) {
+			mr.Set("ratelimit:user:1", "2")
+			mr.SetTTL("ratelimit:user:1", 30*time.Second)
+		}, 3},
+		{"WindowEnded", func(mr *miniredis.Miniredis) {
+			mr.Set("ratelimit:user:1", "2")
+			mr.SetTTL("ratelimit:user:1", 30*time.Second)
+			mr.FastForward(time.Minute)
+		}, 1},
+		// A counter that lost its expiry gets one again rather than
+		// limiting the caller forever
+		{"NoExpiry", func(mr *miniredis.Miniredis) {
+			mr.Set("ratelimit:user:1", "2")
+		}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr := miniredis.RunT(t)
+			if tt.setup != nil {
+				tt.setup(mr)
+			}
+			store := middleware.NewRedisStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+
+			count, resetAt, err := store.Hit(context.Background(), "user:1", time.Minute)
+			if err != nil {
+				t.Fatalf("Hit: %v", err)
+			}
+			if count != tt.wantCount {
+				t.Errorf("count = %d, want %d", count, tt.wantCount)
+			}
+			if until := time.Until(resetAt); until <= 0 || until > time.Minute {
+				t.Errorf("window ends in %v, want within a minute", until)
+			}
+			if ttl := mr.TTL("ratelimit:user:1"); ttl <= 0 || ttl > time.Minute {
+				t.Errorf("key TTL = %v, want within a minute", ttl)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreHit(t *testing.T) {
+	store := middleware.NewMemoryStore()
+	ctx := context.Background()
+
+	for want := int64(1); want <= 3; want++ {
+		count, _, err := store.Hit(ctx, "user:1", time.Minute)
+		if err != nil || count != want {
+			t.Fatalf("hit %d: count = %d, %v", want, count, err)
+		}
+	}
+	if count, _, _ := store.Hit(ctx, "user:2", time.Minute); count != 1 {
+		t.Errorf("another key counted %d, want 1", count)
+	}
+
+	// A new window starts once the old one ends
+	store.Hit(ctx, "user:3", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if count, _, _ := store.Hit(ctx, "user:3", time.Millisecond); count != 1 {
+		t.Errorf("count after the window ended = %d, want 1", count)
+	}
+}
@@ -0,0 +1,121 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rentalflow/api-gateway/internal/middleware"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+)
+
+const secret = "test-secret"
+
+// accessToken signs an access token for a user with roles
+func accessToken(t *testing.T, userID string, roles ...string) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"roles":   roles,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+// failingStore is a rate limit store that cannot be reached
+type failingStore struct{}
+
+func (failingStore) Hit(ctx context.Context, key string, window time.Duration) (int64, time.Time, error) {
+	return 0, time.Time{}, errors.New("connection refused")
+}
+
+func TestRateLimiter(t *testing.T) {
+	cfg := middleware.RateLimitConfig{
+		Default: 2,
+		Roles:   map[string]int{"admin": 4},
+		Routes:  map[string]int{"POST /api/auth/login": 1},
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		token    string
+		allowed  int
+		wantHead string
+	}{
+		{"Anonymous", http.MethodGet, "/api/items", "", 2, "2"},
+		{"DefaultForRole", http.MethodGet, "/api/items", accessToken(t, "u1", "renter"), 2, "2"},
+		{"RoleQuota", http.MethodGet, "/api/items", accessToken(t, "u2", "renter", "admin"), 4, "4"},
+		{"RouteQuota", http.MethodPost, "/api/auth/login", "", 1, "1"},
+		{"OtherMethod", http.MethodGet, "/api/auth/login", "", 2, "2"},
+		{"InvalidToken", http.MethodGet, "/api/items", "not-a-token", 2, "2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := middleware.NewRateLimiter(middleware.NewMemoryStore(), auth.NewChecker(secret), cfg)
+			handler := limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			for i := 0; i <= tt.allowed; i++ {
+				req := httptest.NewRequest(tt.method, tt.path, nil)
+				if tt.token != "" {
+					req.Header.Set("Authorization", "Bearer "+tt.token)
+				}
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+
+				if got := rec.Header().Get("RateLimit-Limit"); got != tt.wantHead {
+					t.Errorf("request %d: RateLimit-Limit = %q, want %q", i+1, got, tt.wantHead)
+				}
+				if i < tt.allowed {
+					if rec.Code != http.StatusOK {
+						t.Fatalf("request %d: %d, want %d", i+1, rec.Code, http.StatusOK)
+					}
+					continue
+				}
+				if rec.Code != http.StatusTooManyRequests {
+					t.Fatalf("request %d: %d, want %d", i+1, rec.Code, http.StatusTooManyRequests)
+				}
+				if rec.Header().Get("Retry-After") == "" || rec.Header().Get("RateLimit-Remaining") != "0" {
+					t.Errorf("limited response headers: %v", rec.Header())
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterCountsUsersSeparately(t *testing.T) {
+	limiter := middleware.NewRateLimiter(middleware.NewMemoryStore(), auth.NewChecker(secret), middleware.RateLimitConfig{Default: 1})
+	handler := limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, userID := range []string{"u1", "u2"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/items", nil)
+		req.Header.Set("Authorization", "Bearer "+accessToken(t, userID))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("first request of %s: %d, want %d", userID, rec.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestRateLimiterFailsOpen(t *testing.T) {
+	limiter := middleware.NewRateLimiter(failingStore{}, auth.NewChecker(secret), middleware.RateLimitConfig{Default: 1})
+	handler := limiter.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/items", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d with the store down: %d, want %d", i+1, rec.Code, http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/rentalflow/rentalflow/pkg/logger"
+)
+
+// RequestID starts a new request ID for every request reaching the
+// gateway, replacing any the client sent, so each user action gets an ID
+// of its own. The services continue it from the X-Request-ID header.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Set(logger.RequestIDHeader, logger.NewRequestID())
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,255 @@
+// Package app assembles the auth service, so that cmd/server and the
+// system tests run the same wiring
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rentalflow/auth-service/internal/datasubject"
+	"github.com/rentalflow/auth-service/internal/handler"
+	"github.com/rentalflow/auth-service/internal/migrations"
+	"github.com/rentalflow/auth-service/internal/notifier"
+	"github.com/rentalflow/auth-service/internal/oidc"
+	"github.com/rentalflow/auth-service/internal/repository"
+	"github.com/rentalflow/auth-service/internal/service"
+	"github.com/rentalflow/auth-service/internal/token"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/rentalflow/pkg/health"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/metrics"
+	pb "github.com/rentalflow/rentalflow/pkg/pb/auth"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// App is an auth service ready to serve
+type App struct {
+	log                zerolog.Logger
+	db                 *database.Backend
+	authService        *service.AuthService
+	dataRequestService *service.DataRequestService
+	stopWorker         context.CancelFunc
+	grpcServer         *grpc.Server
+	httpServer         *http.Server
+}
+
+// New connects to the database and builds the service
+func New(cfg *config.Config) (*App, error) {
+	// Built here rather than at package init, after main has set up logging
+	log := logger.NewLogger("app")
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	a := &App{log: log, db: db}
+
+	log.Info().Str("driver", db.Driver()).Str("uri", cfg.Database.GetURI()).Msg("Connected to database")
+
+	// Apply pending index and data migrations. Deployments that run the
+	// migrate command as a release step turn this off.
+	if cfg.Database.MigrateOnStart {
+		migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), 5*time.Minute)
+		_, err := db.Migrator(migrations.All, migrations.Postgres).Up(migrateCtx)
+		cancelMigrate()
+		if err != nil {
+			a.close()
+			return nil, fmt.Errorf("failed to apply migrations: %w", err)
+		}
+	}
+
+	// Initialize repositories
+	userRepo := repository.NewUserRepository(db)
+	docRepo := repository.NewDocumentRepository(db)
+	tokenRepo := repository.NewActionTokenRepository(db)
+	settingsRepo := repository.NewSettingsRepository(db)
+	throttleRepo := repository.NewLoginThrottleRepository(db)
+	auditRepo := repository.NewAuditLogRepository(db)
+	oidcStateRepo := repository.NewOIDCStateRepository(db)
+	identityRepo := repository.NewExternalIdentityRepository(db)
+	kycRepo := repository.NewKYCCaseRepository(db)
+	dataRequestRepo := repository.NewDataRequestRepository(db)
+	archiveRepo := repository.NewDataExportArchiveRepository(db)
+
+	// Initialize services
+	jwtService := token.NewJWTService(
+		cfg.JWT.Secret,
+		cfg.JWT.AccessExpiresIn,
+		cfg.JWT.RefreshExpiresIn,
+		cfg.JWT.Issuer,
+	)
+	passService := token.NewPasswordService(cfg.Auth.BCryptCost)
+	// Verifies tokens for the config dump and mints the tokens this service
+	// calls the others with
+	checker := auth.NewChecker(cfg.JWT.Secret)
+	notifierClient := notifier.NewClient(cfg.Services.NotificationServiceAddr, checker)
+	oidcProviders := oidc.NewRegistry(cfg.OIDC.Providers)
+	for _, p := range cfg.OIDC.Providers {
+		log.Info().Str("provider", p.Name).Str("issuer", p.IssuerURL).Msg("OIDC provider configured")
+	}
+	authService := service.NewAuthService(
+		userRepo,
+		docRepo,
+		tokenRepo,
+		settingsRepo,
+		throttleRepo,
+		auditRepo,
+		oidcStateRepo,
+		identityRepo,
+		kycRepo,
+		db,
+		jwtService,
+		passService,
+		notifierClient,
+		oidcProviders,
+		service.AccountTokenConfig{
+			EmailVerificationTTL: cfg.Auth.EmailVerificationTTL,
+			PasswordResetTTL:     cfg.Auth.PasswordResetTTL,
+		},
+		service.MFAConfig{
+			Issuer:       cfg.Auth.MFAIssuer,
+			ChallengeTTL: cfg.Auth.MFAChallengeTTL,
+		},
+		service.LockoutConfig{
+			AccountMaxFailures: cfg.Auth.Lockout.AccountMaxFailures,
+			IPMaxFailures:      cfg.Auth.Lockout.IPMaxFailures,
+			FailureWindow:      cfg.Auth.Lockout.FailureWindow,
+			BaseLockout:        cfg.Auth.Lockout.Base,
+			MaxLockout:         cfg.Auth.Lockout.Max,
+		},
+		service.OIDCConfig{
+			StateTTL: cfg.Auth.OIDCStateTTL,
+		},
+	)
+
+	// Personal-data requests call the other services with a short-lived service token
+	serviceToken := func() (string, error) {
+		return checker.ServiceToken("auth-service", auth.PermUserDataManage)
+	}
+	dataServiceURLs := map[string]string{
+		"inventory":    cfg.ServiceURLs.Inventory,
+		"booking":      cfg.ServiceURLs.Booking,
+		"payment":      cfg.ServiceURLs.Payment,
+		"notification": cfg.Services.NotificationServiceAddr,
+		"review":       cfg.ServiceURLs.Review,
+	}
+	dataSources := make(map[string]service.DataSource, len(dataServiceURLs))
+	for name, addr := range dataServiceURLs {
+		dataSources[name] = datasubject.NewClient(name, addr, serviceToken)
+	}
+	dataRequestService := service.NewDataRequestService(
+		userRepo,
+		docRepo,
+		tokenRepo,
+		throttleRepo,
+		auditRepo,
+		identityRepo,
+		kycRepo,
+		dataRequestRepo,
+		archiveRepo,
+		passService,
+		dataSources,
+		service.DataRequestConfig{
+			PollInterval:  cfg.Auth.DataRequests.PollInterval,
+			LeaseDuration: 5 * time.Minute,
+			RetryBackoff:  time.Minute,
+			MaxAttempts:   cfg.Auth.DataRequests.MaxAttempts,
+			ArchiveTTL:    cfg.Auth.DataRequests.ExportTTL,
+		},
+	)
+
+	a.authService = authService
+	a.dataRequestService = dataRequestService
+
+	// Initialize gRPC handler
+	authHandler := handler.NewAuthHandler(authService, checker)
+
+	// Initialize HTTP handler for REST API testing
+	httpHandler := handler.NewHTTPHandler(authService, dataRequestService)
+
+	// Create gRPC server
+	a.grpcServer = grpc.NewServer(tracing.ServerOption(), metrics.ServerOption(), logger.ServerOption())
+
+	// Register services
+	pb.RegisterAuthServiceServer(a.grpcServer, authHandler)
+
+	// Register health check
+	healthServer := grpchealth.NewServer()
+	grpc_health_v1.RegisterHealthServer(a.grpcServer, healthServer)
+	healthServer.SetServingStatus("auth.AuthService", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	// Enable reflection for development
+	if cfg.Environment == "development" {
+		reflection.Register(a.grpcServer)
+	}
+
+	// Dependency checks behind /ready
+	checks := health.NewRegistry(cfg.Health.Timeout, cfg.Health.CacheTTL)
+	checks.Register(db.Driver(), db.Health)
+
+	mux := http.NewServeMux()
+	httpHandler.RegisterRoutes(mux)
+	mux.Handle("/ready", checks.Handler())
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/debug/config", cfg.DebugHandler(checker))
+
+	a.httpServer = &http.Server{
+		Handler: tracing.Handler(logger.Handler(metrics.Instrument(mux)), cfg.ServiceName),
+	}
+
+	return a, nil
+}
+
+// CreateAdmin creates an administrator account, which the API cannot
+func (a *App) CreateAdmin(ctx context.Context, email, password, firstName, lastName string) error {
+	_, err := a.authService.CreateAdmin(ctx, email, password, firstName, lastName)
+	return err
+}
+
+// Start serves gRPC on grpcLis and the HTTP API on httpLis, and works
+// through personal-data requests, until Shutdown
+func (a *App) Start(grpcLis, httpLis net.Listener) {
+	workerCtx, stopWorker := context.WithCancel(context.Background())
+	a.stopWorker = stopWorker
+	go a.dataRequestService.Run(workerCtx)
+
+	go func() {
+		a.log.Info().Str("addr", grpcLis.Addr().String()).Msg("gRPC server listening")
+		if err := a.grpcServer.Serve(grpcLis); err != nil {
+			a.log.Error().Err(err).Msg("gRPC server failed")
+		}
+	}()
+
+	go func() {
+		a.log.Info().Str("addr", httpLis.Addr().String()).Msg("HTTP API server listening")
+		if err := a.httpServer.Serve(httpLis); err != nil && err != http.ErrServerClosed {
+			a.log.Error().Err(err).Msg("HTTP server failed")
+		}
+	}()
+}
+
+// Shutdown stops the worker and the servers and closes the database
+func (a *App) Shutdown(ctx context.Context) {
+	if a.stopWorker != nil {
+		a.stopWorker()
+	}
+	a.grpcServer.GracefulStop()
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		a.log.Error().Err(err).Msg("HTTP server shutdown failed")
+	}
+	a.close()
+}
+
+// close releases the database connection
+func (a *App) close() {
+	a.db.Close(context.Background())
+}
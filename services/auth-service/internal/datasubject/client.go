@@ -0,0 +1,136 @@
+package datasubject
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+)
+
+// TokenSource returns a bearer token for calls to internal endpoints
+type TokenSource func() (string, error)
+
+// EraseResult is a service's answer to an erase request. Status is "erased"
+// when personal data was removed or anonymized and "retained" when the
+// service keeps its records, e.g. financial records it must hold by law.
+type EraseResult struct {
+	Status  string                 `json:"status"`
+	Summary map[string]interface{} `json:"summary,omitempty"`
+}
+
+// StatusError is a non-2xx answer from a service
+type StatusError struct {
+	Service    string
+	StatusCode int
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s service error (status %d): %s", e.Service, e.StatusCode, e.Message)
+}
+
+// IsPermanent checks if retrying cannot help: the service refused the request
+// (4xx) rather than failing or being unreachable
+func IsPermanent(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode >= 400 && statusErr.StatusCode < 500
+}
+
+// Client calls a service's internal personal-data endpoints
+type Client struct {
+	service    string
+	baseURL    string
+	tokens     TokenSource
+	httpClient *http.Client
+}
+
+// NewClient creates a client for one service.
+// addr may be a host:port pair or a full http(s) URL.
+func NewClient(service, addr string, tokens TokenSource) *Client {
+	if !strings.HasPrefix(addr, "http://") && !strings.HasPrefix(addr, "https://") {
+		addr = "http://" + addr
+	}
+	return &Client{
+		service: service,
+		baseURL: strings.TrimSuffix(addr, "/"),
+		tokens:  tokens,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: tracing.Transport(logger.Transport(nil)),
+		},
+	}
+}
+
+// Export fetches everything the service stores about a user
+func (c *Client) Export(ctx context.Context, userID uuid.UUID) (json.RawMessage, error) {
+	path := "/internal/users/data?user_id=" + url.QueryEscape(userID.String())
+
+	var data json.RawMessage
+	if err := c.do(ctx, http.MethodGet, path, nil, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Erase asks the service to erase or anonymize a user's personal data
+func (c *Client) Erase(ctx context.Context, userID uuid.UUID) (*EraseResult, error) {
+	body := map[string]string{"user_id": userID.String()}
+
+	var result EraseResult
+	if err := c.do(ctx, http.MethodPost, "/internal/users/erase", body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// do sends an authenticated request and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	token, err := c.tokens()
+	if err != nil {
+		return fmt.Errorf("failed to issue service token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s service: %w", c.service, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &StatusError{Service: c.service, StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s service response: %w", c.service, err)
+	}
+	return nil
+}
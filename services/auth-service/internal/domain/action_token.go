@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenPurpose identifies what a single-use account token can be redeemed for
+type TokenPurpose string
+
+const (
+	PurposeEmailVerification TokenPurpose = "email_verification"
+	PurposePasswordReset     TokenPurpose = "password_reset"
+)
+
+// ActionToken is a single-use, expiring token emailed to a user.
+// Only the SHA-256 hash of the token is stored.
+type ActionToken struct {
+	ID        uuid.UUID    `json:"id" bson:"_id"`
+	UserID    uuid.UUID    `json:"user_id" bson:"user_id"`
+	Purpose   TokenPurpose `json:"purpose" bson:"purpose"`
+	TokenHash string       `json:"-" bson:"token_hash"`
+	ExpiresAt time.Time    `json:"expires_at" bson:"expires_at"`
+	UsedAt    *time.Time   `json:"used_at,omitempty" bson:"used_at,omitempty"`
+	CreatedAt time.Time    `json:"created_at" bson:"created_at"`
+}
+
+// NewActionToken creates a new action token that expires after ttl
+func NewActionToken(userID uuid.UUID, purpose TokenPurpose, tokenHash string, ttl time.Duration) *ActionToken {
+	now := time.Now()
+	return &ActionToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+}
+
+// IsUsable checks if the token has not been used and has not expired
+func (t *ActionToken) IsUsable() bool {
+	return t.UsedAt == nil && t.ExpiresAt.After(time.Now())
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DataRequestType is the kind of data-subject request
+type DataRequestType string
+
+const (
+	DataRequestExport   DataRequestType = "export"
+	DataRequestDeletion DataRequestType = "deletion"
+)
+
+// DataRequestStatus tracks a data-subject request through the worker
+type DataRequestStatus string
+
+const (
+	DataRequestPending   DataRequestStatus = "pending"
+	DataRequestRunning   DataRequestStatus = "running"
+	DataRequestCompleted DataRequestStatus = "completed"
+	DataRequestFailed    DataRequestStatus = "failed"
+)
+
+// DataStepStatus tracks one service's part of a request
+type DataStepStatus string
+
+const (
+	DataStepPending  DataStepStatus = "pending"
+	DataStepDone     DataStepStatus = "done"
+	DataStepRetained DataStepStatus = "retained"
+	DataStepFailed   DataStepStatus = "failed"
+)
+
+// DataSubjectServices lists the services holding user data. Deletion runs in
+// this order: booking refuses while the user has open bookings, so nothing is
+// erased before that check, and auth goes last so the user keeps access to the
+// request status until everything else is done.
+var DataSubjectServices = []string{"booking", "payment", "inventory", "review", "notification", "auth"}
+
+// DataRequestStep is one service's part of a request
+type DataRequestStep struct {
+	Service     string                 `json:"service" bson:"service"`
+	Status      DataStepStatus         `json:"status" bson:"status"`
+	Summary     map[string]interface{} `json:"summary,omitempty" bson:"summary,omitempty"`
+	Error       string                 `json:"error,omitempty" bson:"error,omitempty"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// DataRequest is an asynchronous personal-data export or account deletion
+type DataRequest struct {
+	ID            uuid.UUID         `json:"id" bson:"_id"`
+	UserID        uuid.UUID         `json:"user_id" bson:"user_id"`
+	Type          DataRequestType   `json:"type" bson:"type"`
+	Status        DataRequestStatus `json:"status" bson:"status"`
+	Steps         []DataRequestStep `json:"steps" bson:"steps"`
+	Attempts      int               `json:"attempts" bson:"attempts"`
+	Error         string            `json:"error,omitempty" bson:"error,omitempty"`
+	NextAttemptAt time.Time         `json:"-" bson:"next_attempt_at"`
+	LeaseUntil    *time.Time        `json:"-" bson:"lease_until,omitempty"`
+	ArchiveReady  bool              `json:"archive_ready" bson:"archive_ready"`
+	CreatedAt     time.Time         `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at" bson:"updated_at"`
+	CompletedAt   *time.Time        `json:"completed_at,omitempty" bson:"completed_at,omitempty"`
+}
+
+// NewDataRequest queues a request with one pending step per service
+func NewDataRequest(userID uuid.UUID, requestType DataRequestType) *DataRequest {
+	now := time.Now()
+	steps := make([]DataRequestStep, len(DataSubjectServices))
+	for i, svc := range DataSubjectServices {
+		steps[i] = DataRequestStep{Service: svc, Status: DataStepPending}
+	}
+	return &DataRequest{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Type:          requestType,
+		Status:        DataRequestPending,
+		Steps:         steps,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// IsActive checks if the request is still queued or in progress
+func (r *DataRequest) IsActive() bool {
+	return r.Status == DataRequestPending || r.Status == DataRequestRunning
+}
+
+// CompleteStep records a finished step
+func (r *DataRequest) CompleteStep(i int, status DataStepStatus, summary map[string]interface{}) {
+	now := time.Now()
+	r.Steps[i].Status = status
+	r.Steps[i].Summary = summary
+	r.Steps[i].Error = ""
+	r.Steps[i].CompletedAt = &now
+	r.UpdatedAt = now
+}
+
+// FailStep records a step error
+func (r *DataRequest) FailStep(i int, err error) {
+	r.Steps[i].Status = DataStepFailed
+	r.Steps[i].Error = err.Error()
+	r.UpdatedAt = time.Now()
+}
+
+// Complete marks the whole request as done
+func (r *DataRequest) Complete() {
+	now := time.Now()
+	r.Status = DataRequestCompleted
+	r.Error = ""
+	r.LeaseUntil = nil
+	r.CompletedAt = &now
+	r.UpdatedAt = now
+}
+
+// Retry puts the request back in the queue after a transient failure
+func (r *DataRequest) Retry(err error, at time.Time) {
+	r.Status = DataRequestPending
+	r.Error = err.Error()
+	r.LeaseUntil = nil
+	r.NextAttemptAt = at
+	r.UpdatedAt = time.Now()
+}
+
+// Fail gives up on the request
+func (r *DataRequest) Fail(err error) {
+	r.Status = DataRequestFailed
+	r.Error = err.Error()
+	r.LeaseUntil = nil
+	r.UpdatedAt = time.Now()
+}
+
+// DataExportArchive is the JSON archive produced by an export request
+type DataExportArchive struct {
+	RequestID uuid.UUID `bson:"_id"`
+	UserID    uuid.UUID `bson:"user_id"`
+	Data      []byte    `bson:"data"`
+	CreatedAt time.Time `bson:"created_at"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+// IsExpired checks if the archive can no longer be downloaded
+func (a *DataExportArchive) IsExpired() bool {
+	return time.Now().After(a.ExpiresAt)
+}
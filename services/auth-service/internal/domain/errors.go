@@ -9,6 +9,9 @@ var (
 	ErrUserAlreadyExists  = errors.New("user with this email already exists")
 	ErrInvalidCredentials = errors.New("invalid email or password")
 	ErrInvalidRole        = errors.New("invalid user role")
+	ErrRoleNotSelfService = errors.New("this role cannot be added to your own account")
+	ErrNoRoles            = errors.New("at least one role is required")
+	ErrRemoveOwnAdminRole = errors.New("admins cannot remove their own admin role")
 
 	// Token errors
 	ErrInvalidToken        = errors.New("invalid token")
@@ -16,10 +19,50 @@ var (
 	ErrInvalidRefreshToken = errors.New("invalid refresh token")
 	ErrRefreshTokenExpired = errors.New("refresh token has expired")
 
+	// Account token errors
+	ErrInvalidActionToken = errors.New("invalid or expired token")
+	ErrEmailNotVerified   = errors.New("email address is not verified")
+	ErrAlreadyVerified    = errors.New("email address is already verified")
+
+	// Login protection errors
+	ErrAccountLocked   = errors.New("account is temporarily locked due to too many failed login attempts")
+	ErrTooManyAttempts = errors.New("too many failed login attempts from this address, try again later")
+	ErrLockoutNotFound = errors.New("account is not locked")
+
+	// Social login errors
+	ErrUnknownOIDCProvider      = errors.New("unknown identity provider")
+	ErrInvalidOIDCState         = errors.New("invalid or expired login state")
+	ErrOIDCEmailNotVerified     = errors.New("identity provider did not return a verified email address")
+	ErrOIDCAuthenticationFailed = errors.New("identity provider authentication failed")
+
+	// Two-factor authentication errors
+	ErrInvalidMFACode        = errors.New("invalid two-factor authentication code")
+	ErrInvalidMFAChallenge   = errors.New("invalid or expired two-factor challenge")
+	ErrMFANotEnabled         = errors.New("two-factor authentication is not enabled")
+	ErrMFAAlreadyEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrMFAEnrollmentNotFound = errors.New("no pending two-factor enrollment")
+	ErrMFARequiredByPolicy   = errors.New("two-factor authentication is required for this role")
+
 	// Verification errors
-	ErrUserNotVerified     = errors.New("user is not verified")
-	ErrDocumentNotFound    = errors.New("identity document not found")
-	ErrInvalidDocumentType = errors.New("invalid document type")
+	ErrKYCCaseNotFound         = errors.New("verification case not found")
+	ErrKYCCasePending          = errors.New("a verification case is already awaiting review")
+	ErrKYCCaseAlreadyReviewed  = errors.New("verification case has already been reviewed")
+	ErrIdentityAlreadyVerified = errors.New("identity is already verified")
+	ErrInvalidKYCDecision      = errors.New("decision must be verified or rejected")
+	ErrRejectionReasonRequired = errors.New("a rejection reason is required")
+	ErrNoDocuments             = errors.New("at least one identity document is required")
+	ErrTooManyDocuments        = errors.New("too many documents in one submission")
+	ErrInvalidDocumentURL      = errors.New("document URL must be an https URL")
+	ErrUserNotVerified         = errors.New("user is not verified")
+	ErrDocumentNotFound        = errors.New("identity document not found")
+	ErrInvalidDocumentType     = errors.New("invalid document type")
+
+	// Data-subject request errors
+	ErrDataRequestNotFound   = errors.New("data request not found")
+	ErrDataRequestInProgress = errors.New("a request of this type is already in progress")
+	ErrDataExportNotFound    = errors.New("export archive not found or expired")
+	ErrDataExportNotReady    = errors.New("export archive is not ready yet")
+	ErrDeletionNotConfirmed  = errors.New(`account deletion must be confirmed with "DELETE"`)
 
 	// Authorization errors
 	ErrUnauthorized = errors.New("unauthorized")
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KYCStatus represents the state of an identity verification case
+type KYCStatus string
+
+const (
+	KYCStatusPending  KYCStatus = "pending"
+	KYCStatusApproved KYCStatus = "approved"
+	KYCStatusRejected KYCStatus = "rejected"
+)
+
+// IsValid checks if the status is valid
+func (s KYCStatus) IsValid() bool {
+	switch s {
+	case KYCStatusPending, KYCStatusApproved, KYCStatusRejected:
+		return true
+	}
+	return false
+}
+
+// KYCAction identifies an entry in a case's history
+type KYCAction string
+
+const (
+	KYCActionSubmitted KYCAction = "submitted"
+	KYCActionApproved  KYCAction = "approved"
+	KYCActionRejected  KYCAction = "rejected"
+)
+
+// KYCDocument is a document attached to a verification case.
+// URLs point at private storage and are only shown to the applicant and reviewers.
+type KYCDocument struct {
+	Type string `json:"type" bson:"type"`
+	URL  string `json:"url" bson:"url"`
+}
+
+// KYCHistoryEntry records who did what to a case
+type KYCHistoryEntry struct {
+	Action  KYCAction `json:"action" bson:"action"`
+	ActorID uuid.UUID `json:"actor_id" bson:"actor_id"`
+	Reason  string    `json:"reason,omitempty" bson:"reason,omitempty"`
+	At      time.Time `json:"at" bson:"at"`
+}
+
+// KYCCase is one identity verification submission and its review.
+// A rejected applicant resubmits by opening a new case linked to the previous one.
+type KYCCase struct {
+	ID              uuid.UUID         `json:"id" bson:"_id"`
+	UserID          uuid.UUID         `json:"user_id" bson:"user_id"`
+	UserEmail       string            `json:"user_email" bson:"user_email"`
+	UserRole        UserRole          `json:"user_role" bson:"user_role"`
+	UserRoles       []UserRole        `json:"user_roles" bson:"user_roles,omitempty"`
+	Status          KYCStatus         `json:"status" bson:"status"`
+	Documents       []KYCDocument     `json:"documents" bson:"documents"`
+	Attempt         int               `json:"attempt" bson:"attempt"`
+	PreviousCaseID  *uuid.UUID        `json:"previous_case_id,omitempty" bson:"previous_case_id,omitempty"`
+	ReviewedBy      *uuid.UUID        `json:"reviewed_by,omitempty" bson:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time        `json:"reviewed_at,omitempty" bson:"reviewed_at,omitempty"`
+	RejectionReason string            `json:"rejection_reason,omitempty" bson:"rejection_reason,omitempty"`
+	History         []KYCHistoryEntry `json:"history" bson:"history"`
+	SubmittedAt     time.Time         `json:"submitted_at" bson:"submitted_at"`
+	UpdatedAt       time.Time         `json:"updated_at" bson:"updated_at"`
+}
+
+// NewKYCCase opens a pending case for a user. previous is the last rejected case, if any.
+func NewKYCCase(user *User, documents []KYCDocument, previous *KYCCase) *KYCCase {
+	now := time.Now()
+	c := &KYCCase{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		UserEmail:   user.Email,
+		UserRole:    user.Role,
+		UserRoles:   user.RoleList(),
+		Status:      KYCStatusPending,
+		Documents:   documents,
+		Attempt:     1,
+		History:     []KYCHistoryEntry{{Action: KYCActionSubmitted, ActorID: user.ID, At: now}},
+		SubmittedAt: now,
+		UpdatedAt:   now,
+	}
+	if previous != nil {
+		c.Attempt = previous.Attempt + 1
+		c.PreviousCaseID = &previous.ID
+	}
+	return c
+}
+
+// IsPending checks if the case is awaiting review
+func (c *KYCCase) IsPending() bool {
+	return c.Status == KYCStatusPending
+}
+
+// Approve records a reviewer's approval
+func (c *KYCCase) Approve(reviewerID uuid.UUID, note string) {
+	c.decide(KYCStatusApproved, KYCActionApproved, reviewerID, note)
+}
+
+// Reject records a reviewer's rejection with the reason shown to the applicant
+func (c *KYCCase) Reject(reviewerID uuid.UUID, reason string) {
+	c.decide(KYCStatusRejected, KYCActionRejected, reviewerID, reason)
+	c.RejectionReason = reason
+}
+
+func (c *KYCCase) decide(status KYCStatus, action KYCAction, reviewerID uuid.UUID, reason string) {
+	now := time.Now()
+	c.Status = status
+	c.ReviewedBy = &reviewerID
+	c.ReviewedAt = &now
+	c.UpdatedAt = now
+	c.History = append(c.History, KYCHistoryEntry{Action: action, ActorID: reviewerID, Reason: reason, At: now})
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ThrottleKind identifies what a failed-login counter is keyed on
+type ThrottleKind string
+
+const (
+	ThrottleAccount ThrottleKind = "account"
+	ThrottleIP      ThrottleKind = "ip"
+)
+
+// LoginThrottle tracks consecutive failed logins for an account or client IP
+type LoginThrottle struct {
+	Key           string       `json:"key" bson:"_id"`
+	Kind          ThrottleKind `json:"kind" bson:"kind"`
+	Subject       string       `json:"subject" bson:"subject"`
+	UserID        *uuid.UUID   `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Failures      int          `json:"failures" bson:"failures"`
+	LockoutCount  int          `json:"lockout_count" bson:"lockout_count"`
+	LockedUntil   *time.Time   `json:"locked_until,omitempty" bson:"locked_until,omitempty"`
+	LastFailureAt time.Time    `json:"last_failure_at" bson:"last_failure_at"`
+	UpdatedAt     time.Time    `json:"updated_at" bson:"updated_at"`
+}
+
+// ThrottleKey builds the storage key for a counter
+func ThrottleKey(kind ThrottleKind, subject string) string {
+	return string(kind) + ":" + subject
+}
+
+// IsLocked checks if the counter is in an active lockout
+func (t *LoginThrottle) IsLocked(now time.Time) bool {
+	return t.LockedUntil != nil && t.LockedUntil.After(now)
+}
+
+// AuditEventType identifies an entry in the auth audit log
+type AuditEventType string
+
+const (
+	AuditLoginSuccess    AuditEventType = "login_success"
+	AuditLoginFailure    AuditEventType = "login_failure"
+	AuditLoginBlocked    AuditEventType = "login_blocked"
+	AuditMFAFailure      AuditEventType = "mfa_failure"
+	AuditAccountLocked   AuditEventType = "account_locked"
+	AuditIPLocked        AuditEventType = "ip_locked"
+	AuditAccountUnlocked AuditEventType = "account_unlocked"
+	AuditIdentityLinked  AuditEventType = "identity_linked"
+	AuditKYCSubmitted    AuditEventType = "kyc_submitted"
+	AuditKYCApproved     AuditEventType = "kyc_approved"
+	AuditKYCRejected     AuditEventType = "kyc_rejected"
+	AuditRoleAdded       AuditEventType = "role_added"
+	AuditRolesChanged    AuditEventType = "roles_changed"
+	AuditDataRequested   AuditEventType = "data_requested"
+	AuditAccountDeleted  AuditEventType = "account_deleted"
+)
+
+// AuthAuditEvent is an entry in the auth audit log
+type AuthAuditEvent struct {
+	ID        uuid.UUID      `json:"id" bson:"_id"`
+	Type      AuditEventType `json:"type" bson:"type"`
+	UserID    *uuid.UUID     `json:"user_id,omitempty" bson:"user_id,omitempty"`
+	Email     string         `json:"email,omitempty" bson:"email,omitempty"`
+	IPAddress string         `json:"ip_address,omitempty" bson:"ip_address,omitempty"`
+	UserAgent string         `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+	Reason    string         `json:"reason,omitempty" bson:"reason,omitempty"`
+	ActorID   *uuid.UUID     `json:"actor_id,omitempty" bson:"actor_id,omitempty"`
+	CreatedAt time.Time      `json:"created_at" bson:"created_at"`
+}
+
+// NewAuthAuditEvent creates a new audit log entry
+func NewAuthAuditEvent(eventType AuditEventType, userID *uuid.UUID, email, ipAddress, userAgent, reason string) *AuthAuditEvent {
+	return &AuthAuditEvent{
+		ID:        uuid.New(),
+		Type:      eventType,
+		UserID:    userID,
+		Email:     email,
+		IPAddress: ipAddress,
+		UserAgent: userAgent,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MFAPolicy controls which roles must use two-factor authentication
+type MFAPolicy struct {
+	RequiredRoles []UserRole `json:"required_roles" bson:"required_roles"`
+	UpdatedBy     *uuid.UUID `json:"updated_by,omitempty" bson:"updated_by,omitempty"`
+	UpdatedAt     time.Time  `json:"updated_at" bson:"updated_at"`
+}
+
+// RequiresMFA checks if the policy requires two-factor authentication for a role
+func (p *MFAPolicy) RequiresMFA(role UserRole) bool {
+	for _, r := range p.RequiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiresMFAForUser checks if the policy requires two-factor authentication for any of the user's roles
+func (p *MFAPolicy) RequiresMFAForUser(u *User) bool {
+	for _, role := range u.RoleList() {
+		if p.RequiresMFA(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// StartMFAEnrollment stores a secret awaiting confirmation
+func (u *User) StartMFAEnrollment(secret string) {
+	u.MFAPendingSecret = secret
+	u.UpdatedAt = time.Now()
+}
+
+// EnableMFA activates the pending secret with a fresh set of recovery code hashes
+func (u *User) EnableMFA(recoveryCodeHashes []string) {
+	now := time.Now()
+	u.MFAEnabled = true
+	u.MFAEnabledAt = &now
+	u.MFASecret = u.MFAPendingSecret
+	u.MFAPendingSecret = ""
+	u.MFARecoveryCodes = recoveryCodeHashes
+	u.UpdatedAt = now
+}
+
+// DisableMFA removes the user's second factor
+func (u *User) DisableMFA() {
+	u.MFAEnabled = false
+	u.MFAEnabledAt = nil
+	u.MFASecret = ""
+	u.MFAPendingSecret = ""
+	u.MFARecoveryCodes = nil
+	u.MFALastUsedStep = 0
+	u.UpdatedAt = time.Now()
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OIDCLoginState is the server side of an in-flight social login.
+// Only the SHA-256 hash of the state parameter is stored; the PKCE
+// verifier and nonce never leave the service.
+type OIDCLoginState struct {
+	StateHash    string    `json:"-" bson:"_id"`
+	Provider     string    `json:"provider" bson:"provider"`
+	Nonce        string    `json:"-" bson:"nonce"`
+	CodeVerifier string    `json:"-" bson:"code_verifier"`
+	ExpiresAt    time.Time `json:"expires_at" bson:"expires_at"`
+	CreatedAt    time.Time `json:"created_at" bson:"created_at"`
+}
+
+// NewOIDCLoginState creates a login state that expires after ttl
+func NewOIDCLoginState(stateHash, provider, nonce, codeVerifier string, ttl time.Duration) *OIDCLoginState {
+	now := time.Now()
+	return &OIDCLoginState{
+		StateHash:    stateHash,
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    now.Add(ttl),
+		CreatedAt:    now,
+	}
+}
+
+// IsExpired checks if the login state has expired
+func (s *OIDCLoginState) IsExpired() bool {
+	return !s.ExpiresAt.After(time.Now())
+}
+
+// ExternalIdentity links a user to an account at an OIDC provider
+type ExternalIdentity struct {
+	ID          uuid.UUID `json:"id" bson:"_id"`
+	UserID      uuid.UUID `json:"user_id" bson:"user_id"`
+	Provider    string    `json:"provider" bson:"provider"`
+	Subject     string    `json:"subject" bson:"subject"`
+	Email       string    `json:"email" bson:"email"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+	LastLoginAt time.Time `json:"last_login_at" bson:"last_login_at"`
+}
+
+// NewExternalIdentity creates a new link between a user and a provider subject
+func NewExternalIdentity(userID uuid.UUID, provider, subject, email string) *ExternalIdentity {
+	now := time.Now()
+	return &ExternalIdentity{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Provider:    provider,
+		Subject:     subject,
+		Email:       email,
+		CreatedAt:   now,
+		LastLoginAt: now,
+	}
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "github.com/rentalflow/rentalflow/pkg/auth"
+
+// RolePermissions maps each role to the permissions it grants. A user's
+// permissions are the union over all roles they hold.
+var RolePermissions = map[UserRole][]auth.Permission{
+	RoleRenter: {
+		auth.PermReviewsWrite,
+		auth.PermMessagesSend,
+	},
+	RoleOwner: {
+		auth.PermItemsWrite,
+		auth.PermBookingsConfirm,
+		auth.PermReviewsWrite,
+		auth.PermMessagesSend,
+	},
+	RoleAdmin: auth.AllPermissions,
+}
+
+// PermissionsFor returns the de-duplicated permissions granted by roles, in AllPermissions order
+func PermissionsFor(roles []UserRole) []string {
+	seen := make(map[auth.Permission]bool)
+	perms := []string{}
+	for _, perm := range auth.AllPermissions {
+		for _, role := range roles {
+			if !seen[perm] && grants(role, perm) {
+				seen[perm] = true
+				perms = append(perms, string(perm))
+			}
+		}
+	}
+	return perms
+}
+
+// grants checks if a role grants a permission
+func grants(role UserRole, perm auth.Permission) bool {
+	for _, p := range RolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rentalflow/rentalflow/pkg/auth"
 )
 
 // UserRole represents the role of a user
@@ -37,6 +38,8 @@ const (
 type User struct {
 	ID                    uuid.UUID          `json:"id" bson:"_id"`
 	Email                 string             `json:"email" bson:"email"`
+	EmailVerified         bool               `json:"email_verified" bson:"email_verified"`
+	EmailVerifiedAt       *time.Time         `json:"email_verified_at,omitempty" bson:"email_verified_at,omitempty"`
 	PasswordHash          string             `json:"-" bson:"password_hash"`
 	FirstName             string             `json:"first_name" bson:"first_name"`
 	LastName              string             `json:"last_name" bson:"last_name"`
@@ -44,10 +47,18 @@ type User struct {
 	Bio                   string             `json:"bio" bson:"bio"`
 	AvatarURL             string             `json:"avatar_url" bson:"avatar_url"`
 	Role                  UserRole           `json:"role" bson:"role"`
+	Roles                 []UserRole         `json:"roles" bson:"roles,omitempty"`
 	IdentityVerified      bool               `json:"identity_verified" bson:"identity_verified"`
 	VerificationStatus    VerificationStatus `json:"verification_status" bson:"verification_status"`
 	RefreshTokenHash      string             `json:"-" bson:"refresh_token_hash"`
 	RefreshTokenExpiresAt *time.Time         `json:"-" bson:"refresh_token_expires_at"`
+	MFAEnabled            bool               `json:"mfa_enabled" bson:"mfa_enabled"`
+	MFAEnabledAt          *time.Time         `json:"mfa_enabled_at,omitempty" bson:"mfa_enabled_at,omitempty"`
+	MFASecret             string             `json:"-" bson:"mfa_secret,omitempty"`
+	MFAPendingSecret      string             `json:"-" bson:"mfa_pending_secret,omitempty"`
+	MFARecoveryCodes      []string           `json:"-" bson:"mfa_recovery_codes,omitempty"`
+	MFALastUsedStep       int64              `json:"-" bson:"mfa_last_used_step"`
+	DeletedAt             *time.Time         `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
 	CreatedAt             time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt             time.Time          `json:"updated_at" bson:"updated_at"`
 }
@@ -63,6 +74,7 @@ func NewUser(email, passwordHash, firstName, lastName, phone string, role UserRo
 		LastName:           lastName,
 		Phone:              phone,
 		Role:               role,
+		Roles:              []UserRole{role},
 		IdentityVerified:   false,
 		VerificationStatus: VerificationPending,
 		CreatedAt:          now,
@@ -75,24 +87,111 @@ func (u *User) FullName() string {
 	return u.FirstName + " " + u.LastName
 }
 
+// RoleList returns every role the user holds. Accounts created before users
+// could hold several roles only have the primary role set.
+func (u *User) RoleList() []UserRole {
+	if len(u.Roles) == 0 {
+		return []UserRole{u.Role}
+	}
+	return u.Roles
+}
+
+// HasRole checks if the user holds a role
+func (u *User) HasRole(role UserRole) bool {
+	for _, r := range u.RoleList() {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// SetRoles replaces the user's roles. The primary role becomes the most
+// privileged one so clients that only read "role" keep working.
+func (u *User) SetRoles(roles []UserRole) {
+	u.Roles = roles
+	u.Role = RoleRenter
+	for _, r := range roles {
+		if r == RoleAdmin || (r == RoleOwner && u.Role != RoleAdmin) {
+			u.Role = r
+		}
+	}
+	u.UpdatedAt = time.Now()
+}
+
+// AddRole grants an additional role; it is a no-op if the user already holds it
+func (u *User) AddRole(role UserRole) {
+	if u.HasRole(role) {
+		return
+	}
+	u.SetRoles(append(append([]UserRole{}, u.RoleList()...), role))
+}
+
+// Permissions returns the permissions granted by all of the user's roles
+func (u *User) Permissions() []string {
+	return PermissionsFor(u.RoleList())
+}
+
+// HasPermission checks if any of the user's roles grants a permission
+func (u *User) HasPermission(perm auth.Permission) bool {
+	for _, role := range u.RoleList() {
+		if grants(role, perm) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsAdmin checks if the user is an admin
 func (u *User) IsAdmin() bool {
-	return u.Role == RoleAdmin
+	return u.HasRole(RoleAdmin)
 }
 
 // IsOwner checks if the user is an owner
 func (u *User) IsOwner() bool {
-	return u.Role == RoleOwner
+	return u.HasRole(RoleOwner)
 }
 
 // IsRenter checks if the user is a renter
 func (u *User) IsRenter() bool {
-	return u.Role == RoleRenter
+	return u.HasRole(RoleRenter)
 }
 
 // CanManageItem checks if the user can manage rental items
 func (u *User) CanManageItem() bool {
-	return u.Role == RoleOwner || u.Role == RoleAdmin
+	return u.HasPermission(auth.PermItemsWrite)
+}
+
+// IsDeleted checks if the account was erased
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// Anonymize erases the user's personal data and credentials. The record is
+// kept as a tombstone so IDs referenced by bookings and payments still resolve.
+func (u *User) Anonymize(unusablePasswordHash string) {
+	now := time.Now()
+	u.Email = "deleted-" + u.ID.String() + "@users.invalid"
+	u.EmailVerified = false
+	u.EmailVerifiedAt = nil
+	u.PasswordHash = unusablePasswordHash
+	u.FirstName = "Deleted"
+	u.LastName = "User"
+	u.Phone = ""
+	u.Bio = ""
+	u.AvatarURL = ""
+	u.ClearRefreshToken()
+	u.DisableMFA()
+	u.DeletedAt = &now
+	u.UpdatedAt = now
+}
+
+// MarkEmailVerified marks the user's email address as verified
+func (u *User) MarkEmailVerified() {
+	now := time.Now()
+	u.EmailVerified = true
+	u.EmailVerifiedAt = &now
+	u.UpdatedAt = now
 }
 
 // HasValidRefreshToken checks if the user has a valid refresh token
@@ -119,11 +218,11 @@ func (u *User) ClearRefreshToken() {
 
 // IdentityDocument represents an identity document
 type IdentityDocument struct {
-	ID           uuid.UUID
-	UserID       uuid.UUID
-	DocumentType string
-	DocumentURL  string
-	UploadedAt   time.Time
+	ID           uuid.UUID `json:"id" bson:"_id"`
+	UserID       uuid.UUID `json:"user_id" bson:"user_id"`
+	DocumentType string    `json:"document_type" bson:"document_type"`
+	DocumentURL  string    `json:"document_url" bson:"document_url"`
+	UploadedAt   time.Time `json:"uploaded_at" bson:"uploaded_at"`
 }
 
 // DocumentType constants
@@ -2,30 +2,40 @@ package handler
 
 import (
 	"context"
+	"net"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/rentalflow/auth-service/internal/domain"
 	"github.com/rentalflow/auth-service/internal/service"
+	"github.com/rentalflow/auth-service/internal/token"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	apperrors "github.com/rentalflow/rentalflow/pkg/errors"
+	pb "github.com/rentalflow/rentalflow/pkg/pb/auth"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // AuthHandler implements the gRPC AuthService
 type AuthHandler struct {
-	UnimplementedAuthServiceServer
+	pb.UnimplementedAuthServiceServer
 	authService *service.AuthService
+	checker     *auth.Checker
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+func NewAuthHandler(authService *service.AuthService, checker *auth.Checker) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
+		checker:     checker,
 	}
 }
 
 // Register registers a new user
-func (h *AuthHandler) Register(ctx context.Context, req *RegisterRequest) (*AuthResponse, error) {
+func (h *AuthHandler) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.AuthResponse, error) {
 	// Validate required fields
 	if req.Email == "" || req.Password == "" || req.FirstName == "" || req.LastName == "" {
 		return nil, status.Error(codes.InvalidArgument, "email, password, first_name, and last_name are required")
@@ -41,35 +51,39 @@ func (h *AuthHandler) Register(ctx context.Context, req *RegisterRequest) (*Auth
 		return nil, toGRPCError(err)
 	}
 
-	return &AuthResponse{
-		User:         toProtoUser(result.User),
-		AccessToken:  result.AccessToken,
-		RefreshToken: result.RefreshToken,
-		ExpiresIn:    result.ExpiresIn,
-	}, nil
+	return toProtoAuthResponse(result), nil
 }
 
 // Login authenticates a user
-func (h *AuthHandler) Login(ctx context.Context, req *LoginRequest) (*AuthResponse, error) {
+func (h *AuthHandler) Login(ctx context.Context, req *pb.LoginRequest) (*pb.AuthResponse, error) {
 	if req.Email == "" || req.Password == "" {
 		return nil, status.Error(codes.InvalidArgument, "email and password are required")
 	}
 
-	result, err := h.authService.Login(ctx, req.Email, req.Password)
+	result, err := h.authService.Login(ctx, req.Email, req.Password, grpcClientInfo(ctx))
 	if err != nil {
 		return nil, toGRPCError(err)
 	}
 
-	return &AuthResponse{
-		User:         toProtoUser(result.User),
-		AccessToken:  result.AccessToken,
-		RefreshToken: result.RefreshToken,
-		ExpiresIn:    result.ExpiresIn,
-	}, nil
+	return toProtoAuthResponse(result), nil
+}
+
+// VerifyLoginMFA completes a login that requires a second factor
+func (h *AuthHandler) VerifyLoginMFA(ctx context.Context, req *pb.VerifyLoginMFARequest) (*pb.AuthResponse, error) {
+	if req.MfaToken == "" || req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "mfa_token and code are required")
+	}
+
+	result, err := h.authService.CompleteMFALogin(ctx, req.MfaToken, req.Code, grpcClientInfo(ctx))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoAuthResponse(result), nil
 }
 
 // RefreshToken refreshes the access token
-func (h *AuthHandler) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*AuthResponse, error) {
+func (h *AuthHandler) RefreshToken(ctx context.Context, req *pb.RefreshTokenRequest) (*pb.AuthResponse, error) {
 	if req.RefreshToken == "" {
 		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
 	}
@@ -80,51 +94,115 @@ func (h *AuthHandler) RefreshToken(ctx context.Context, req *RefreshTokenRequest
 }
 
 // Logout logs out a user
-func (h *AuthHandler) Logout(ctx context.Context, req *LogoutRequest) (*LogoutResponse, error) {
-	if req.UserId == "" {
-		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+func (h *AuthHandler) Logout(ctx context.Context, req *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	userID, err := uuid.Parse(req.UserId)
+	userID, err := resolveUser(principal, req.UserId)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+		return nil, err
 	}
 
 	if err := h.authService.Logout(ctx, userID); err != nil {
 		return nil, toGRPCError(err)
 	}
 
-	return &LogoutResponse{Success: true}, nil
+	return &pb.LogoutResponse{Success: true}, nil
+}
+
+// VerifyEmail redeems an email verification token
+func (h *AuthHandler) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.User, error) {
+	if req.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	user, err := h.authService.VerifyEmail(ctx, req.Token)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoUser(user), nil
+}
+
+// ResendVerificationEmail issues a new email verification link to the caller
+func (h *AuthHandler) ResendVerificationEmail(ctx context.Context, req *pb.ResendVerificationEmailRequest) (*pb.SuccessResponse, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := resolveUser(principal, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.authService.RequestEmailVerification(ctx, userID); err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.SuccessResponse{Success: true}, nil
+}
+
+// RequestPasswordReset emails a password reset link
+func (h *AuthHandler) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.SuccessResponse, error) {
+	if req.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if err := h.authService.RequestPasswordReset(ctx, req.Email); err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.SuccessResponse{Success: true}, nil
+}
+
+// ResetPassword sets a new password using a reset token
+func (h *AuthHandler) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.SuccessResponse, error) {
+	if req.Token == "" || req.NewPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "token and new_password are required")
+	}
+
+	if err := h.authService.ResetPassword(ctx, req.Token, req.NewPassword); err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.SuccessResponse{Success: true}, nil
 }
 
 // ValidateToken validates an access token (internal service-to-service)
-func (h *AuthHandler) ValidateToken(ctx context.Context, req *ValidateTokenRequest) (*ValidateTokenResponse, error) {
+func (h *AuthHandler) ValidateToken(ctx context.Context, req *pb.ValidateTokenRequest) (*pb.ValidateTokenResponse, error) {
 	if req.Token == "" {
 		return nil, status.Error(codes.InvalidArgument, "token is required")
 	}
 
 	claims, err := h.authService.ValidateToken(ctx, req.Token)
 	if err != nil {
-		return &ValidateTokenResponse{Valid: false}, nil
+		return &pb.ValidateTokenResponse{Valid: false}, nil
 	}
 
-	return &ValidateTokenResponse{
-		Valid:  true,
-		UserId: claims.UserID,
-		Role:   claims.Role,
-		Email:  claims.Email,
+	return &pb.ValidateTokenResponse{
+		Valid:         true,
+		UserId:        claims.UserID,
+		Role:          claims.Role,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Roles:         claims.Roles,
+		Permissions:   claims.Permissions,
 	}, nil
 }
 
 // GetProfile retrieves the user's profile
-func (h *AuthHandler) GetProfile(ctx context.Context, req *GetProfileRequest) (*User, error) {
-	if req.UserId == "" {
-		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+func (h *AuthHandler) GetProfile(ctx context.Context, req *pb.GetProfileRequest) (*pb.User, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	userID, err := uuid.Parse(req.UserId)
+	userID, err := resolveUser(principal, req.UserId)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+		return nil, err
 	}
 
 	user, err := h.authService.GetUserByID(ctx, userID)
@@ -136,14 +214,15 @@ func (h *AuthHandler) GetProfile(ctx context.Context, req *GetProfileRequest) (*
 }
 
 // UpdateProfile updates the user's profile
-func (h *AuthHandler) UpdateProfile(ctx context.Context, req *UpdateProfileRequest) (*User, error) {
-	if req.UserId == "" {
-		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+func (h *AuthHandler) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRequest) (*pb.User, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	userID, err := uuid.Parse(req.UserId)
+	userID, err := resolveUser(principal, req.UserId)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+		return nil, err
 	}
 
 	var firstName, lastName, phone, bio *string
@@ -168,7 +247,7 @@ func (h *AuthHandler) UpdateProfile(ctx context.Context, req *UpdateProfileReque
 }
 
 // GetUserById retrieves a user by ID (internal service-to-service)
-func (h *AuthHandler) GetUserById(ctx context.Context, req *GetUserByIdRequest) (*User, error) {
+func (h *AuthHandler) GetUserById(ctx context.Context, req *pb.GetUserByIdRequest) (*pb.User, error) {
 	if req.UserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
@@ -187,12 +266,25 @@ func (h *AuthHandler) GetUserById(ctx context.Context, req *GetUserByIdRequest)
 }
 
 // GetUserByIdAdmin retrieves a user by ID (admin only)
-func (h *AuthHandler) GetUserByIdAdmin(ctx context.Context, req *GetUserByIdRequest) (*User, error) {
+func (h *AuthHandler) GetUserByIdAdmin(ctx context.Context, req *pb.GetUserByIdRequest) (*pb.User, error) {
+	if _, err := h.checker.RequireContext(ctx, auth.PermUsersManage); err != nil {
+		return nil, err
+	}
+
 	return h.GetUserById(ctx, req)
 }
 
-// VerifyUser updates a user's verification status (admin only)
-func (h *AuthHandler) VerifyUser(ctx context.Context, req *VerifyUserRequest) (*User, error) {
+// VerifyUser updates a user's verification status (admin only). The
+// reviewing admin is the caller; admin_id may only name the caller.
+func (h *AuthHandler) VerifyUser(ctx context.Context, req *pb.VerifyUserRequest) (*pb.User, error) {
+	principal, err := h.checker.RequireContext(ctx, auth.PermKYCReview)
+	if err != nil {
+		return nil, err
+	}
+	if req.AdminId != "" && req.AdminId != principal.UserID {
+		return nil, toGRPCError(domain.ErrForbidden)
+	}
+
 	if req.UserId == "" || req.Status == "" {
 		return nil, status.Error(codes.InvalidArgument, "user_id and status are required")
 	}
@@ -202,8 +294,13 @@ func (h *AuthHandler) VerifyUser(ctx context.Context, req *VerifyUserRequest) (*
 		return nil, status.Error(codes.InvalidArgument, "invalid user_id format")
 	}
 
+	adminID, err := uuid.Parse(principal.UserID)
+	if err != nil {
+		return nil, toGRPCError(domain.ErrForbidden)
+	}
+
 	verificationStatus := domain.VerificationStatus(req.Status)
-	user, err := h.authService.VerifyUser(ctx, userID, verificationStatus)
+	user, err := h.authService.VerifyUser(ctx, adminID, userID, verificationStatus, req.Notes)
 	if err != nil {
 		return nil, toGRPCError(err)
 	}
@@ -212,7 +309,11 @@ func (h *AuthHandler) VerifyUser(ctx context.Context, req *VerifyUserRequest) (*
 }
 
 // ListUsers lists users with pagination (admin only)
-func (h *AuthHandler) ListUsers(ctx context.Context, req *ListUsersRequest) (*ListUsersResponse, error) {
+func (h *AuthHandler) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	if _, err := h.checker.RequireContext(ctx, auth.PermUsersManage); err != nil {
+		return nil, err
+	}
+
 	page := int(req.Page)
 	pageSize := int(req.PageSize)
 
@@ -233,12 +334,12 @@ func (h *AuthHandler) ListUsers(ctx context.Context, req *ListUsersRequest) (*Li
 		return nil, toGRPCError(err)
 	}
 
-	protoUsers := make([]*User, len(users))
+	protoUsers := make([]*pb.User, len(users))
 	for i, user := range users {
 		protoUsers[i] = toProtoUser(user)
 	}
 
-	return &ListUsersResponse{
+	return &pb.ListUsersResponse{
 		Users:    protoUsers,
 		Total:    int32(total),
 		Page:     int32(page),
@@ -247,14 +348,19 @@ func (h *AuthHandler) ListUsers(ctx context.Context, req *ListUsersRequest) (*Li
 }
 
 // UploadIdentityDocument uploads an identity document
-func (h *AuthHandler) UploadIdentityDocument(ctx context.Context, req *UploadDocumentRequest) (*UploadDocumentResponse, error) {
-	if req.UserId == "" || req.DocumentType == "" {
-		return nil, status.Error(codes.InvalidArgument, "user_id and document_type are required")
+func (h *AuthHandler) UploadIdentityDocument(ctx context.Context, req *pb.UploadDocumentRequest) (*pb.UploadDocumentResponse, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	userID, err := uuid.Parse(req.UserId)
+	if req.DocumentType == "" {
+		return nil, status.Error(codes.InvalidArgument, "document_type is required")
+	}
+
+	userID, err := resolveUser(principal, req.UserId)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+		return nil, err
 	}
 
 	// In a real implementation, we would:
@@ -268,21 +374,22 @@ func (h *AuthHandler) UploadIdentityDocument(ctx context.Context, req *UploadDoc
 		return nil, toGRPCError(err)
 	}
 
-	return &UploadDocumentResponse{
+	return &pb.UploadDocumentResponse{
 		DocumentId:  doc.ID.String(),
 		DocumentUrl: doc.DocumentURL,
 	}, nil
 }
 
 // GetVerificationStatus gets the user's verification status
-func (h *AuthHandler) GetVerificationStatus(ctx context.Context, req *GetVerificationStatusRequest) (*VerificationStatusResponse, error) {
-	if req.UserId == "" {
-		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+func (h *AuthHandler) GetVerificationStatus(ctx context.Context, req *pb.GetVerificationStatusRequest) (*pb.VerificationStatusResponse, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	userID, err := uuid.Parse(req.UserId)
+	userID, err := resolveUser(principal, req.UserId)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+		return nil, err
 	}
 
 	verificationStatus, docs, err := h.authService.GetVerificationStatus(ctx, userID)
@@ -290,9 +397,9 @@ func (h *AuthHandler) GetVerificationStatus(ctx context.Context, req *GetVerific
 		return nil, toGRPCError(err)
 	}
 
-	protoDocs := make([]*IdentityDocument, len(docs))
+	protoDocs := make([]*pb.IdentityDocument, len(docs))
 	for i, doc := range docs {
-		protoDocs[i] = &IdentityDocument{
+		protoDocs[i] = &pb.IdentityDocument{
 			Id:           doc.ID.String(),
 			DocumentType: doc.DocumentType,
 			DocumentUrl:  doc.DocumentURL,
@@ -300,7 +407,7 @@ func (h *AuthHandler) GetVerificationStatus(ctx context.Context, req *GetVerific
 		}
 	}
 
-	return &VerificationStatusResponse{
+	return &pb.VerificationStatusResponse{
 		Status:    string(verificationStatus),
 		Documents: protoDocs,
 	}, nil
@@ -308,8 +415,24 @@ func (h *AuthHandler) GetVerificationStatus(ctx context.Context, req *GetVerific
 
 // Helper functions
 
-func toProtoUser(user *domain.User) *User {
-	return &User{
+// resolveUser defaults the user to the caller and checks the caller may act for it
+func resolveUser(principal *auth.Principal, userID string) (uuid.UUID, error) {
+	if !principal.CanActFor(userID) {
+		return uuid.Nil, toGRPCError(domain.ErrForbidden)
+	}
+	if userID == "" {
+		userID = principal.UserID
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+	}
+	return id, nil
+}
+
+func toProtoUser(user *domain.User) *pb.User {
+	return &pb.User{
 		Id:                 user.ID.String(),
 		Email:              user.Email,
 		FirstName:          user.FirstName,
@@ -320,28 +443,77 @@ func toProtoUser(user *domain.User) *User {
 		VerificationStatus: string(user.VerificationStatus),
 		CreatedAt:          timestamppb.New(user.CreatedAt),
 		UpdatedAt:          timestamppb.New(user.UpdatedAt),
+		EmailVerified:      user.EmailVerified,
+		MfaEnabled:         user.MFAEnabled,
+		Roles:              roleNames(user.RoleList()),
+	}
+}
+
+// roleNames converts roles to their wire values
+func roleNames(roles []domain.UserRole) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	return names
+}
+
+// grpcClientInfo identifies the caller from forwarded metadata or the peer address
+func grpcClientInfo(ctx context.Context) service.ClientInfo {
+	var client service.ClientInfo
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("x-forwarded-for"); len(values) > 0 {
+			client.IPAddress = strings.TrimSpace(strings.Split(values[0], ",")[0])
+		}
+		if values := md.Get("user-agent"); len(values) > 0 {
+			client.UserAgent = values[0]
+		}
+	}
+	if client.IPAddress == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+				client.IPAddress = host
+			}
+		}
+	}
+	return client
+}
+
+func toProtoAuthResponse(result *service.AuthResult) *pb.AuthResponse {
+	return &pb.AuthResponse{
+		User:             toProtoUser(result.User),
+		AccessToken:      result.AccessToken,
+		RefreshToken:     result.RefreshToken,
+		ExpiresIn:        result.ExpiresIn,
+		MfaRequired:      result.MFARequired,
+		MfaSetupRequired: result.MFASetupRequired,
+		MfaToken:         result.MFAToken,
 	}
 }
 
+// toGRPCError classifies domain errors and maps them to gRPC status codes
 func toGRPCError(err error) error {
 	switch err {
-	case domain.ErrUserNotFound:
-		return status.Error(codes.NotFound, err.Error())
+	case domain.ErrUserNotFound, domain.ErrLockoutNotFound, domain.ErrKYCCaseNotFound:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeNotFound, err))
 	case domain.ErrUserAlreadyExists:
-		return status.Error(codes.AlreadyExists, err.Error())
-	case domain.ErrInvalidCredentials:
-		return status.Error(codes.Unauthenticated, err.Error())
-	case domain.ErrInvalidToken, domain.ErrExpiredToken:
-		return status.Error(codes.Unauthenticated, err.Error())
-	case domain.ErrInvalidRefreshToken, domain.ErrRefreshTokenExpired:
-		return status.Error(codes.Unauthenticated, err.Error())
-	case domain.ErrUnauthorized:
-		return status.Error(codes.Unauthenticated, err.Error())
-	case domain.ErrForbidden:
-		return status.Error(codes.PermissionDenied, err.Error())
-	case domain.ErrInvalidRole, domain.ErrInvalidDocumentType:
-		return status.Error(codes.InvalidArgument, err.Error())
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeConflict, err))
+	case domain.ErrInvalidCredentials, domain.ErrInvalidToken, domain.ErrExpiredToken,
+		domain.ErrInvalidRefreshToken, domain.ErrRefreshTokenExpired,
+		domain.ErrUnauthorized, domain.ErrInvalidMFACode, domain.ErrInvalidMFAChallenge:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeUnauthorized, err))
+	case domain.ErrForbidden, domain.ErrMFARequiredByPolicy:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeForbidden, err))
+	case domain.ErrAccountLocked, domain.ErrTooManyAttempts:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeRateLimited, err))
+	case domain.ErrInvalidRole, domain.ErrInvalidDocumentType, domain.ErrInvalidActionToken,
+		domain.ErrInvalidKYCDecision, domain.ErrRejectionReasonRequired,
+		token.ErrPasswordTooShort, token.ErrPasswordTooWeak:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeValidation, err))
+	case domain.ErrAlreadyVerified, domain.ErrMFAAlreadyEnabled, domain.ErrMFANotEnabled,
+		domain.ErrMFAEnrollmentNotFound, domain.ErrKYCCaseAlreadyReviewed:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypePrecondition, err))
 	default:
-		return status.Error(codes.Internal, "internal server error")
+		return apperrors.ToGRPCError(err)
 	}
 }
@@ -7,22 +7,23 @@ import (
 	"github.com/google/uuid"
 	"github.com/rentalflow/auth-service/internal/domain"
 	"github.com/rentalflow/auth-service/internal/service"
+	"github.com/rentalflow/auth-service/internal/token"
 )
 
 // HTTPHandler provides REST endpoints for testing
 type HTTPHandler struct {
-	authService *service.AuthService
+	authService  *service.AuthService
+	dataRequests *service.DataRequestService
 }
 
 // NewHTTPHandler creates a new HTTP handler
-func NewHTTPHandler(authService *service.AuthService) *HTTPHandler {
-	return &HTTPHandler{authService: authService}
+func NewHTTPHandler(authService *service.AuthService, dataRequests *service.DataRequestService) *HTTPHandler {
+	return &HTTPHandler{authService: authService, dataRequests: dataRequests}
 }
 
 // RegisterRoutes registers HTTP routes
 func (h *HTTPHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/health", h.Health)
-	mux.HandleFunc("/ready", h.Ready)
 	mux.HandleFunc("/api/auth/register", h.Register)
 	mux.HandleFunc("/api/auth/login", h.Login)
 	mux.HandleFunc("/api/auth/logout", h.Logout)
@@ -30,6 +31,34 @@ func (h *HTTPHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/auth/avatar", h.UpdateAvatar)
 	mux.HandleFunc("/api/auth/change-password", h.ChangePassword)
 	mux.HandleFunc("/api/auth/validate", h.ValidateToken)
+	mux.HandleFunc("/api/auth/verify-email", h.VerifyEmail)
+	mux.HandleFunc("/api/auth/verify-email/resend", h.ResendVerification)
+	mux.HandleFunc("/api/auth/forgot-password", h.ForgotPassword)
+	mux.HandleFunc("/api/auth/reset-password", h.ResetPassword)
+	mux.HandleFunc("/api/auth/login/2fa", h.LoginMFA)
+	mux.HandleFunc("/api/auth/oidc/providers", h.ListOIDCProviders)
+	mux.HandleFunc("/api/auth/oidc/authorize", h.AuthorizeOIDC)
+	mux.HandleFunc("/api/auth/oidc/callback", h.OIDCCallback)
+	mux.HandleFunc("/api/auth/2fa/enroll", h.EnrollMFA)
+	mux.HandleFunc("/api/auth/2fa/confirm", h.ConfirmMFA)
+	mux.HandleFunc("/api/auth/2fa/disable", h.DisableMFA)
+	mux.HandleFunc("/api/auth/2fa/recovery-codes", h.RegenerateRecoveryCodes)
+	mux.HandleFunc("/api/auth/admin/2fa-policy", h.MFAPolicyHandler)
+	mux.HandleFunc("/api/auth/admin/locked-accounts", h.ListLockedAccounts)
+	mux.HandleFunc("/api/auth/admin/unlock", h.UnlockAccount)
+	mux.HandleFunc("/api/auth/admin/audit-log", h.ListAuditEvents)
+	mux.HandleFunc("/api/auth/roles", h.AddRole)
+	mux.HandleFunc("/api/auth/admin/users/roles", h.SetUserRoles)
+	mux.HandleFunc("/api/auth/kyc", h.KYCHandler)
+	mux.HandleFunc("/api/auth/kyc/cases", h.GetKYCCase)
+	mux.HandleFunc("/api/auth/admin/kyc/cases", h.ListKYCCases)
+	mux.HandleFunc("/api/auth/admin/kyc/approve", h.ApproveKYC)
+	mux.HandleFunc("/api/auth/admin/kyc/reject", h.RejectKYC)
+	mux.HandleFunc("/api/auth/privacy/export", h.RequestDataExport)
+	mux.HandleFunc("/api/auth/privacy/export/download", h.DownloadDataExport)
+	mux.HandleFunc("/api/auth/privacy/delete", h.RequestAccountDeletion)
+	mux.HandleFunc("/api/auth/privacy/requests", h.GetDataRequests)
+	mux.HandleFunc("/api/auth/admin/privacy/requests", h.ListDataRequests)
 	mux.HandleFunc("/api/users", h.ListUsers)
 }
 
@@ -40,13 +69,6 @@ func (h *HTTPHandler) Health(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
 }
 
-// Ready check
-func (h *HTTPHandler) Ready(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"status": "ready"})
-}
-
 // RegisterRequest for HTTP API
 type RegisterHTTPRequest struct {
 	Email     string `json:"email"`
@@ -81,20 +103,7 @@ func (h *HTTPHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"user": map[string]interface{}{
-			"id":         result.User.ID.String(),
-			"email":      result.User.Email,
-			"first_name": result.User.FirstName,
-			"last_name":  result.User.LastName,
-			"role":       result.User.Role,
-		},
-		"access_token":  result.AccessToken,
-		"refresh_token": result.RefreshToken,
-		"expires_in":    result.ExpiresIn,
-	})
+	h.writeAuthResult(w, http.StatusCreated, result)
 }
 
 // LoginRequest for HTTP API
@@ -116,20 +125,41 @@ func (h *HTTPHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := h.authService.Login(r.Context(), req.Email, req.Password)
+	result, err := h.authService.Login(r.Context(), req.Email, req.Password, clientInfo(r))
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
+	h.writeAuthResult(w, http.StatusOK, result)
+}
+
+// writeAuthResult writes either issued tokens or a two-factor challenge
+func (h *HTTPHandler) writeAuthResult(w http.ResponseWriter, status int, result *service.AuthResult) {
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if result.MFARequired {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"mfa_required":       true,
+			"mfa_setup_required": result.MFASetupRequired,
+			"mfa_token":          result.MFAToken,
+			"expires_in":         result.ExpiresIn,
+		})
+		return
+	}
+
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"user": map[string]interface{}{
-			"id":         result.User.ID.String(),
-			"email":      result.User.Email,
-			"first_name": result.User.FirstName,
-			"last_name":  result.User.LastName,
-			"role":       result.User.Role,
+			"id":             result.User.ID.String(),
+			"email":          result.User.Email,
+			"first_name":     result.User.FirstName,
+			"last_name":      result.User.LastName,
+			"role":           result.User.Role,
+			"roles":          result.User.RoleList(),
+			"permissions":    result.User.Permissions(),
+			"email_verified": result.User.EmailVerified,
+			"mfa_enabled":    result.User.MFAEnabled,
 		},
 		"access_token":  result.AccessToken,
 		"refresh_token": result.RefreshToken,
@@ -216,6 +246,10 @@ func (h *HTTPHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		"bio":                 user.Bio,
 		"avatar_url":          user.AvatarURL,
 		"role":                user.Role,
+		"roles":               user.RoleList(),
+		"permissions":         user.Permissions(),
+		"email_verified":      user.EmailVerified,
+		"mfa_enabled":         user.MFAEnabled,
 		"identity_verified":   user.IdentityVerified,
 		"verification_status": user.VerificationStatus,
 		"created_at":          user.CreatedAt,
@@ -264,6 +298,7 @@ func (h *HTTPHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
 		"bio":        user.Bio,
 		"avatar_url": user.AvatarURL,
 		"role":       user.Role,
+		"roles":      user.RoleList(),
 	})
 }
 
@@ -374,13 +409,133 @@ func (h *HTTPHandler) ValidateToken(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"valid":   true,
-		"user_id": claims.UserID,
-		"email":   claims.Email,
-		"role":    claims.Role,
+		"valid":          true,
+		"user_id":        claims.UserID,
+		"email":          claims.Email,
+		"role":           claims.Role,
+		"roles":          claims.Roles,
+		"permissions":    claims.Permissions,
+		"email_verified": claims.EmailVerified,
 	})
 }
 
+// VerifyEmail redeems an email verification token
+func (h *HTTPHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Token == "" {
+		http.Error(w, "token is required", http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.authService.VerifyEmail(r.Context(), req.Token)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":             user.ID.String(),
+		"email":          user.Email,
+		"email_verified": user.EmailVerified,
+	})
+}
+
+// ResendVerification issues a new email verification link to the signed-in user
+func (h *HTTPHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// The link goes to the signed-in user, never to a user named in the body
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.RequestEmailVerification(r.Context(), userID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// ForgotPassword emails a password reset link
+func (h *HTTPHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Email == "" {
+		http.Error(w, "email is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authService.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	// Always accept so the endpoint cannot be used to probe for accounts
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// ResetPassword sets a new password using a reset token
+func (h *HTTPHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"new_password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Token == "" || req.NewPassword == "" {
+		http.Error(w, "token and new_password are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.authService.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
 // ListUsers lists all users (admin)
 func (h *HTTPHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -402,6 +557,7 @@ func (h *HTTPHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 			"first_name": u.FirstName,
 			"last_name":  u.LastName,
 			"role":       u.Role,
+			"roles":      u.RoleList(),
 		}
 	}
 
@@ -420,12 +576,28 @@ func (h *HTTPHandler) handleError(w http.ResponseWriter, err error) {
 		w.WriteHeader(http.StatusNotFound)
 	case domain.ErrUserAlreadyExists:
 		w.WriteHeader(http.StatusConflict)
-	case domain.ErrInvalidCredentials:
+	case domain.ErrInvalidCredentials, domain.ErrUnauthorized, domain.ErrInvalidToken, domain.ErrExpiredToken,
+		domain.ErrInvalidMFACode, domain.ErrInvalidMFAChallenge, domain.ErrOIDCAuthenticationFailed:
 		w.WriteHeader(http.StatusUnauthorized)
-	case domain.ErrForbidden:
+	case domain.ErrForbidden, domain.ErrMFARequiredByPolicy, domain.ErrOIDCEmailNotVerified,
+		domain.ErrRoleNotSelfService, domain.ErrRemoveOwnAdminRole:
 		w.WriteHeader(http.StatusForbidden)
-	case domain.ErrInvalidRole, domain.ErrInvalidDocumentType:
+	case domain.ErrAccountLocked:
+		w.WriteHeader(http.StatusLocked)
+	case domain.ErrTooManyAttempts:
+		w.WriteHeader(http.StatusTooManyRequests)
+	case domain.ErrLockoutNotFound, domain.ErrUnknownOIDCProvider, domain.ErrKYCCaseNotFound,
+		domain.ErrDataRequestNotFound, domain.ErrDataExportNotFound:
+		w.WriteHeader(http.StatusNotFound)
+	case domain.ErrInvalidRole, domain.ErrInvalidDocumentType, domain.ErrInvalidActionToken, domain.ErrInvalidOIDCState,
+		domain.ErrNoDocuments, domain.ErrTooManyDocuments, domain.ErrInvalidDocumentURL, domain.ErrRejectionReasonRequired,
+		domain.ErrNoRoles, domain.ErrDeletionNotConfirmed,
+		token.ErrPasswordTooShort, token.ErrPasswordTooWeak:
 		w.WriteHeader(http.StatusBadRequest)
+	case domain.ErrAlreadyVerified, domain.ErrMFAAlreadyEnabled, domain.ErrMFANotEnabled,
+		domain.ErrMFAEnrollmentNotFound, domain.ErrKYCCasePending, domain.ErrKYCCaseAlreadyReviewed,
+		domain.ErrIdentityAlreadyVerified, domain.ErrDataRequestInProgress, domain.ErrDataExportNotReady:
+		w.WriteHeader(http.StatusConflict)
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
 	}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/repository"
+)
+
+// KYCHandler handles GET (latest case) and POST (submit or resubmit) for the
+// signed-in user's identity verification
+func (h *HTTPHandler) KYCHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		kycCase, err := h.authService.GetLatestKYCCase(r.Context(), userID)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if kycCase == nil {
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": "not_submitted"})
+			return
+		}
+		json.NewEncoder(w).Encode(kycCase)
+	case http.MethodPost:
+		var req struct {
+			Documents []domain.KYCDocument `json:"documents"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		kycCase, err := h.authService.SubmitKYC(r.Context(), userID, req.Documents)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(kycCase)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// GetKYCCase returns a single case to its applicant or an admin
+func (h *HTTPHandler) GetKYCCase(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	caseID, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid id format", http.StatusBadRequest)
+		return
+	}
+
+	actorID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	kycCase, err := h.authService.GetKYCCase(r.Context(), actorID, caseID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(kycCase)
+}
+
+// ListKYCCases lists the verification review queue (admin)
+func (h *HTTPHandler) ListKYCCases(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	adminID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+
+	filters := repository.KYCFilters{DocumentType: query.Get("document_type")}
+
+	// The queue shows pending cases unless a status is asked for
+	kycStatus := domain.KYCStatusPending
+	if s := query.Get("status"); s != "" {
+		kycStatus = domain.KYCStatus(s)
+		if !kycStatus.IsValid() {
+			http.Error(w, "Invalid status", http.StatusBadRequest)
+			return
+		}
+	}
+	filters.Status = &kycStatus
+
+	if userID := query.Get("user_id"); userID != "" {
+		uid, err := uuid.Parse(userID)
+		if err != nil {
+			http.Error(w, "Invalid user_id format", http.StatusBadRequest)
+			return
+		}
+		filters.UserID = &uid
+	}
+	if role := query.Get("role"); role != "" {
+		userRole := domain.UserRole(role)
+		if !userRole.IsValid() {
+			h.handleError(w, domain.ErrInvalidRole)
+			return
+		}
+		filters.Role = &userRole
+	}
+	if from := query.Get("submitted_from"); from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			http.Error(w, "Invalid submitted_from, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		filters.SubmittedFrom = &t
+	}
+	if to := query.Get("submitted_to"); to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			http.Error(w, "Invalid submitted_to, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		// Inclusive of the whole day
+		t = t.AddDate(0, 0, 1)
+		filters.SubmittedTo = &t
+	}
+
+	cases, total, err := h.authService.ListKYCCases(r.Context(), adminID, page, pageSize, filters)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"cases": cases,
+		"total": total,
+	})
+}
+
+// ApproveKYC approves a pending verification case (admin)
+func (h *HTTPHandler) ApproveKYC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		CaseID string `json:"case_id"`
+		Note   string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	caseID, err := uuid.Parse(req.CaseID)
+	if err != nil {
+		http.Error(w, "Invalid case_id format", http.StatusBadRequest)
+		return
+	}
+
+	adminID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	kycCase, err := h.authService.ApproveKYC(r.Context(), adminID, caseID, req.Note)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(kycCase)
+}
+
+// RejectKYC rejects a pending verification case with a reason (admin)
+func (h *HTTPHandler) RejectKYC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		CaseID string `json:"case_id"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	caseID, err := uuid.Parse(req.CaseID)
+	if err != nil {
+		http.Error(w, "Invalid case_id format", http.StatusBadRequest)
+		return
+	}
+
+	adminID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	kycCase, err := h.authService.RejectKYC(r.Context(), adminID, caseID, req.Reason)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(kycCase)
+}
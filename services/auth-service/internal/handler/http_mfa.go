@@ -0,0 +1,288 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/service"
+)
+
+// LoginMFA completes a login with a TOTP or recovery code
+func (h *HTTPHandler) LoginMFA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		MFAToken string `json:"mfa_token"`
+		Code     string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.MFAToken == "" || req.Code == "" {
+		http.Error(w, "mfa_token and code are required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.authService.CompleteMFALogin(r.Context(), req.MFAToken, req.Code, clientInfo(r))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeAuthResult(w, http.StatusOK, result)
+}
+
+// EnrollMFA starts TOTP enrollment for the signed-in user, or for a user
+// holding a setup challenge from login
+func (h *HTTPHandler) EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		MFAToken string `json:"mfa_token"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var (
+		enrollment *service.MFAEnrollment
+		err        error
+	)
+	if req.MFAToken != "" {
+		enrollment, err = h.authService.BeginMFAEnrollmentForChallenge(r.Context(), req.MFAToken)
+	} else {
+		userID, ok := h.authenticatedUserID(w, r)
+		if !ok {
+			return
+		}
+		enrollment, err = h.authService.BeginMFAEnrollment(r.Context(), userID)
+	}
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"secret":           enrollment.Secret,
+		"provisioning_uri": enrollment.ProvisioningURI,
+	})
+}
+
+// ConfirmMFA activates TOTP with a code from the authenticator app.
+// When called with a setup challenge it also completes the pending login.
+func (h *HTTPHandler) ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		MFAToken string `json:"mfa_token"`
+		Code     string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Code == "" {
+		http.Error(w, "code is required", http.StatusBadRequest)
+		return
+	}
+
+	if req.MFAToken != "" {
+		result, recoveryCodes, err := h.authService.ConfirmMFAEnrollmentForChallenge(r.Context(), req.MFAToken, req.Code, clientInfo(r))
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"recovery_codes": recoveryCodes,
+			"user": map[string]interface{}{
+				"id":          result.User.ID.String(),
+				"email":       result.User.Email,
+				"role":        result.User.Role,
+				"mfa_enabled": true,
+			},
+			"access_token":  result.AccessToken,
+			"refresh_token": result.RefreshToken,
+			"expires_in":    result.ExpiresIn,
+		})
+		return
+	}
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	recoveryCodes, err := h.authService.ConfirmMFAEnrollment(r.Context(), userID, req.Code)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mfa_enabled":    true,
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+// DisableMFA turns off TOTP for the signed-in user
+func (h *HTTPHandler) DisableMFA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Password == "" || req.Code == "" {
+		http.Error(w, "password and code are required", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.DisableMFA(r.Context(), userID, req.Password, req.Code); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// RegenerateRecoveryCodes issues a new set of recovery codes for the signed-in user
+func (h *HTTPHandler) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Code == "" {
+		http.Error(w, "code is required", http.StatusBadRequest)
+		return
+	}
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	recoveryCodes, err := h.authService.RegenerateRecoveryCodes(r.Context(), userID, req.Code)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"recovery_codes": recoveryCodes})
+}
+
+// MFAPolicyHandler handles GET and PUT for the two-factor role policy (admin)
+func (h *HTTPHandler) MFAPolicyHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		user, err := h.authService.GetUserByID(r.Context(), userID)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+		if !user.IsAdmin() {
+			h.handleError(w, domain.ErrForbidden)
+			return
+		}
+
+		policy, err := h.authService.GetMFAPolicy(r.Context())
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(policy)
+	case http.MethodPut:
+		var req struct {
+			RequiredRoles []domain.UserRole `json:"required_roles"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		policy, err := h.authService.UpdateMFAPolicy(r.Context(), userID, req.RequiredRoles)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(policy)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// authenticatedUserID resolves the caller from the bearer access token
+func (h *HTTPHandler) authenticatedUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	tokenString := h.extractToken(r)
+	if tokenString == "" {
+		h.handleError(w, domain.ErrUnauthorized)
+		return uuid.Nil, false
+	}
+
+	claims, err := h.authService.ValidateToken(r.Context(), tokenString)
+	if err != nil {
+		h.handleError(w, err)
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		h.handleError(w, domain.ErrInvalidToken)
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
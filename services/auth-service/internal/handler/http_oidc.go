@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/rentalflow/auth-service/internal/domain"
+)
+
+// ListOIDCProviders lists the configured social login providers
+func (h *HTTPHandler) ListOIDCProviders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	providers := h.authService.ListOIDCProviders()
+	response := make([]map[string]string, 0, len(providers))
+	for _, p := range providers {
+		response = append(response, map[string]string{
+			"name":         p.Name,
+			"display_name": p.DisplayName,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"providers": response})
+}
+
+// AuthorizeOIDC starts a social login and returns the provider URL to redirect the browser to
+func (h *HTTPHandler) AuthorizeOIDC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Provider string `json:"provider"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Provider == "" {
+		http.Error(w, "provider is required", http.StatusBadRequest)
+		return
+	}
+
+	authorization, err := h.authService.StartOIDCLogin(r.Context(), req.Provider)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"authorization_url": authorization.AuthorizationURL,
+		"state":             authorization.State,
+		"expires_in":        authorization.ExpiresIn,
+	})
+}
+
+// OIDCCallback completes a social login with the state and code the provider
+// redirected back with. The frontend callback page POSTs them as JSON; a GET
+// with the provider's query string is also accepted for local testing.
+func (h *HTTPHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		State string `json:"state"`
+		Code  string `json:"code"`
+		Error string `json:"error"`
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		req.State = query.Get("state")
+		req.Code = query.Get("code")
+		req.Error = query.Get("error")
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// The user declined or the provider failed before issuing a code
+	if req.Error != "" {
+		h.handleError(w, domain.ErrOIDCAuthenticationFailed)
+		return
+	}
+
+	if req.State == "" || req.Code == "" {
+		http.Error(w, "state and code are required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.authService.CompleteOIDCLogin(r.Context(), req.State, req.Code, clientInfo(r))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeAuthResult(w, http.StatusOK, result)
+}
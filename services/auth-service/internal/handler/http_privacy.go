@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/repository"
+)
+
+// RequestDataExport queues an export of the signed-in user's data
+func (h *HTTPHandler) RequestDataExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	req, err := h.dataRequests.RequestExport(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(req)
+}
+
+// RequestAccountDeletion queues the deletion of the signed-in user's account
+func (h *HTTPHandler) RequestAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Confirm string `json:"confirm"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	req, err := h.dataRequests.RequestDeletion(r.Context(), userID, body.Confirm)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(req)
+}
+
+// GetDataRequests returns one request by ?id= or lists the user's requests
+func (h *HTTPHandler) GetDataRequests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if idStr := r.URL.Query().Get("id"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			http.Error(w, "Invalid id format", http.StatusBadRequest)
+			return
+		}
+
+		req, err := h.dataRequests.GetDataRequest(r.Context(), userID, id)
+		if err != nil {
+			h.handleError(w, err)
+			return
+		}
+		json.NewEncoder(w).Encode(req)
+		return
+	}
+
+	requests, err := h.dataRequests.ListUserDataRequests(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"requests": requests,
+	})
+}
+
+// DownloadDataExport returns the JSON archive of a completed export
+func (h *HTTPHandler) DownloadDataExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(r.URL.Query().Get("id"))
+	if err != nil {
+		http.Error(w, "Invalid id format", http.StatusBadRequest)
+		return
+	}
+
+	data, err := h.dataRequests.DownloadExport(r.Context(), userID, id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="rentalflow-export-`+id.String()+`.json"`)
+	w.Write(data)
+}
+
+// ListDataRequests lists export and deletion requests across users (admin)
+func (h *HTTPHandler) ListDataRequests(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	adminID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+
+	var filters repository.DataRequestFilters
+	if userID := query.Get("user_id"); userID != "" {
+		uid, err := uuid.Parse(userID)
+		if err != nil {
+			http.Error(w, "Invalid user_id format", http.StatusBadRequest)
+			return
+		}
+		filters.UserID = &uid
+	}
+	if t := query.Get("type"); t != "" {
+		requestType := domain.DataRequestType(t)
+		filters.Type = &requestType
+	}
+	if s := query.Get("status"); s != "" {
+		status := domain.DataRequestStatus(s)
+		filters.Status = &status
+	}
+
+	requests, total, err := h.dataRequests.ListDataRequests(r.Context(), adminID, page, pageSize, filters)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"requests": requests,
+		"total":    total,
+	})
+}
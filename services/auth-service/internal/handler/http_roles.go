@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+)
+
+// AddRole adds a renter or owner role to the signed-in user's account and
+// returns fresh tokens carrying the new permissions
+func (h *HTTPHandler) AddRole(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	userID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.authService.AddRole(r.Context(), userID, domain.UserRole(req.Role))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.writeAuthResult(w, http.StatusOK, result)
+}
+
+// SetUserRoles replaces a user's roles (admin)
+func (h *HTTPHandler) SetUserRoles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		UserID string            `json:"user_id"`
+		Roles  []domain.UserRole `json:"roles"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		http.Error(w, "Invalid user_id format", http.StatusBadRequest)
+		return
+	}
+
+	adminID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.authService.SetUserRoles(r.Context(), adminID, userID, req.Roles)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":          user.ID.String(),
+		"email":       user.Email,
+		"role":        user.Role,
+		"roles":       user.RoleList(),
+		"permissions": user.Permissions(),
+	})
+}
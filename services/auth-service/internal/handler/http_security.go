@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/repository"
+	"github.com/rentalflow/auth-service/internal/service"
+)
+
+// ListLockedAccounts lists accounts in an active lockout (admin)
+func (h *HTTPHandler) ListLockedAccounts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	adminID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+
+	accounts, total, err := h.authService.ListLockedAccounts(r.Context(), adminID, page, pageSize)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	accountList := make([]map[string]interface{}, len(accounts))
+	for i, a := range accounts {
+		accountList[i] = map[string]interface{}{
+			"email":         a.Email,
+			"user_id":       a.UserID,
+			"locked_until":  a.LockedUntil,
+			"lockout_count": a.LockoutCount,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"accounts": accountList,
+		"total":    total,
+	})
+}
+
+// UnlockAccount lifts a lockout (admin)
+func (h *HTTPHandler) UnlockAccount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Email == "" {
+		http.Error(w, "email is required", http.StatusBadRequest)
+		return
+	}
+
+	adminID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.authService.UnlockAccount(r.Context(), adminID, req.Email); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+// ListAuditEvents lists auth audit log entries (admin)
+func (h *HTTPHandler) ListAuditEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	adminID, ok := h.authenticatedUserID(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+
+	filters := repository.AuditFilters{Email: query.Get("email")}
+	if userID := query.Get("user_id"); userID != "" {
+		uid, err := uuid.Parse(userID)
+		if err != nil {
+			http.Error(w, "Invalid user_id format", http.StatusBadRequest)
+			return
+		}
+		filters.UserID = &uid
+	}
+	if eventType := query.Get("type"); eventType != "" {
+		t := domain.AuditEventType(eventType)
+		filters.Type = &t
+	}
+
+	events, total, err := h.authService.ListAuditEvents(r.Context(), adminID, page, pageSize, filters)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"events": events,
+		"total":  total,
+	})
+}
+
+// clientInfo identifies the caller. The service sits behind the API gateway,
+// which overwrites X-Real-IP, so that header is preferred over the socket address.
+func clientInfo(r *http.Request) service.ClientInfo {
+	ip := r.Header.Get("X-Real-IP")
+	if ip == "" {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			ip = strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+	if ip == "" {
+		if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+			ip = host
+		} else {
+			ip = r.RemoteAddr
+		}
+	}
+
+	return service.ClientInfo{
+		IPAddress: ip,
+		UserAgent: r.UserAgent(),
+	}
+}
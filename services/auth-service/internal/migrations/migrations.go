@@ -0,0 +1,122 @@
+// Package migrations declares the auth service's indexes and data
+// migrations, and its schema on PostgreSQL in the .sql files of postgres/.
+// Released versions must never change: add a new one instead.
+package migrations
+
+import (
+	"context"
+	"embed"
+
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// All lists the auth service's migrations
+var All = []database.Migration{
+	{
+		Version:     1,
+		Description: "Index users by unique email, role and creation time",
+		Up: database.CreateIndexes("users",
+			mongo.IndexModel{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+			mongo.IndexModel{Keys: bson.D{{Key: "roles", Value: 1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "created_at", Value: -1}}},
+		),
+	},
+	{
+		Version:     2,
+		Description: "Index tokens, sign-in state, audit log and login throttles, expiring OIDC states",
+		Up: database.Steps(
+			database.CreateIndexes("action_tokens",
+				mongo.IndexModel{Keys: bson.D{{Key: "token_hash", Value: 1}, {Key: "purpose", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "purpose", Value: 1}}},
+			),
+			database.CreateIndexes("oidc_states",
+				mongo.IndexModel{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+			),
+			database.CreateIndexes("external_identities",
+				mongo.IndexModel{Keys: bson.D{{Key: "provider", Value: 1}, {Key: "subject", Value: 1}}, Options: options.Index().SetUnique(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}}},
+			),
+			database.CreateIndexes("auth_audit_log",
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "email", Value: 1}, {Key: "created_at", Value: -1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "type", Value: 1}, {Key: "created_at", Value: -1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "created_at", Value: -1}}},
+			),
+			database.CreateIndexes("login_throttles",
+				mongo.IndexModel{Keys: bson.D{{Key: "kind", Value: 1}, {Key: "locked_until", Value: -1}}},
+			),
+		),
+	},
+	{
+		Version:     3,
+		Description: "Index KYC cases, identity documents and data subject requests",
+		Up: database.Steps(
+			database.CreateIndexes("kyc_cases",
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "submitted_at", Value: -1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "submitted_at", Value: 1}}},
+			),
+			database.CreateIndexes("identity_documents",
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}}},
+			),
+			database.CreateIndexes("data_requests",
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "type", Value: 1}, {Key: "status", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "created_at", Value: -1}}},
+			),
+			database.CreateIndexes("data_export_archives",
+				mongo.IndexModel{Keys: bson.D{{Key: "expires_at", Value: 1}}},
+			),
+		),
+	},
+	{
+		Version:     4,
+		Description: "Backfill roles on accounts and KYC cases created before users could hold several",
+		Up:          backfillRoles,
+	},
+	{
+		Version:     5,
+		Description: "Treat accounts created before email verification as verified",
+		Up:          grandfatherEmailVerified,
+	},
+}
+
+//go:embed postgres/*.sql
+var postgresFiles embed.FS
+
+// Postgres lists the auth service's PostgreSQL migrations
+var Postgres = database.MustLoadSQLMigrations(postgresFiles, "postgres")
+
+// backfillRoles copies the primary role into the roles list where it is
+// missing, so filtering on roles alone finds older accounts
+func backfillRoles(ctx context.Context, db *mongo.Database) error {
+	backfills := []struct {
+		collection, role, roles string
+	}{
+		{"users", "role", "roles"},
+		{"kyc_cases", "user_role", "user_roles"},
+	}
+	for _, b := range backfills {
+		filter := bson.M{
+			b.roles: bson.M{"$in": bson.A{nil, bson.A{}}},
+			b.role:  bson.M{"$nin": bson.A{nil, ""}},
+		}
+		update := mongo.Pipeline{{{Key: "$set", Value: bson.M{b.roles: bson.A{"$" + b.role}}}}}
+		if _, err := db.Collection(b.collection).UpdateMany(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// grandfatherEmailVerified marks accounts stored before email verification
+// existed, which have no email_verified field, as verified, so renters who
+// signed up then can still book
+func grandfatherEmailVerified(ctx context.Context, db *mongo.Database) error {
+	filter := bson.M{"email_verified": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"email_verified": true}}
+	_, err := db.Collection("users").UpdateMany(ctx, filter, update)
+	return err
+}
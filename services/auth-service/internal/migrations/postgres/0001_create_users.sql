@@ -0,0 +1,40 @@
+-- Accounts loaded without email_verified predate email verification and are
+-- grandfathered in as verified; the service always sets it on new accounts.
+CREATE TABLE users (
+    id                       uuid PRIMARY KEY,
+    email                    text NOT NULL,
+    email_verified           boolean NOT NULL DEFAULT true,
+    email_verified_at        timestamptz,
+    password_hash            text NOT NULL DEFAULT '',
+    first_name               text NOT NULL DEFAULT '',
+    last_name                text NOT NULL DEFAULT '',
+    phone                    text NOT NULL DEFAULT '',
+    bio                      text NOT NULL DEFAULT '',
+    avatar_url               text NOT NULL DEFAULT '',
+    role                     text NOT NULL,
+    roles                    text[],
+    identity_verified        boolean NOT NULL DEFAULT false,
+    verification_status      text NOT NULL DEFAULT '',
+    refresh_token_hash       text NOT NULL DEFAULT '',
+    refresh_token_expires_at timestamptz,
+    mfa_enabled              boolean NOT NULL DEFAULT false,
+    mfa_enabled_at           timestamptz,
+    mfa_secret               text NOT NULL DEFAULT '',
+    mfa_pending_secret       text NOT NULL DEFAULT '',
+    mfa_recovery_codes       text[],
+    mfa_last_used_step       bigint NOT NULL DEFAULT 0,
+    deleted_at               timestamptz,
+    created_at               timestamptz NOT NULL,
+    updated_at               timestamptz NOT NULL,
+    CONSTRAINT users_email_key UNIQUE (email)
+);
+
+CREATE INDEX users_roles_idx ON users USING gin (roles);
+CREATE INDEX users_created_at_idx ON users (created_at DESC);
+
+CREATE TABLE auth_settings (
+    id             text PRIMARY KEY,
+    required_roles text[],
+    updated_by     uuid,
+    updated_at     timestamptz NOT NULL
+);
@@ -0,0 +1,68 @@
+CREATE TABLE action_tokens (
+    id         uuid PRIMARY KEY,
+    user_id    uuid NOT NULL,
+    purpose    text NOT NULL,
+    token_hash text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used_at    timestamptz,
+    created_at timestamptz NOT NULL
+);
+
+CREATE INDEX action_tokens_token_hash_purpose_idx ON action_tokens (token_hash, purpose);
+CREATE INDEX action_tokens_user_id_purpose_idx ON action_tokens (user_id, purpose);
+
+-- PostgreSQL has no TTL index: expired states are swept when new ones are created.
+CREATE TABLE oidc_states (
+    state_hash    text PRIMARY KEY,
+    provider      text NOT NULL,
+    nonce         text NOT NULL,
+    code_verifier text NOT NULL,
+    expires_at    timestamptz NOT NULL,
+    created_at    timestamptz NOT NULL
+);
+
+CREATE INDEX oidc_states_expires_at_idx ON oidc_states (expires_at);
+
+CREATE TABLE external_identities (
+    id            uuid PRIMARY KEY,
+    user_id       uuid NOT NULL,
+    provider      text NOT NULL,
+    subject       text NOT NULL,
+    email         text NOT NULL DEFAULT '',
+    created_at    timestamptz NOT NULL,
+    last_login_at timestamptz NOT NULL,
+    CONSTRAINT external_identities_provider_subject_key UNIQUE (provider, subject)
+);
+
+CREATE INDEX external_identities_user_id_idx ON external_identities (user_id);
+
+CREATE TABLE auth_audit_log (
+    id         uuid PRIMARY KEY,
+    type       text NOT NULL,
+    user_id    uuid,
+    email      text NOT NULL DEFAULT '',
+    ip_address text NOT NULL DEFAULT '',
+    user_agent text NOT NULL DEFAULT '',
+    reason     text NOT NULL DEFAULT '',
+    actor_id   uuid,
+    created_at timestamptz NOT NULL
+);
+
+CREATE INDEX auth_audit_log_user_id_created_at_idx ON auth_audit_log (user_id, created_at DESC);
+CREATE INDEX auth_audit_log_email_created_at_idx ON auth_audit_log (email, created_at DESC);
+CREATE INDEX auth_audit_log_type_created_at_idx ON auth_audit_log (type, created_at DESC);
+CREATE INDEX auth_audit_log_created_at_idx ON auth_audit_log (created_at DESC);
+
+CREATE TABLE login_throttles (
+    key             text PRIMARY KEY,
+    kind            text NOT NULL,
+    subject         text NOT NULL,
+    user_id         uuid,
+    failures        integer NOT NULL DEFAULT 0,
+    lockout_count   integer NOT NULL DEFAULT 0,
+    locked_until    timestamptz,
+    last_failure_at timestamptz NOT NULL,
+    updated_at      timestamptz NOT NULL
+);
+
+CREATE INDEX login_throttles_kind_locked_until_idx ON login_throttles (kind, locked_until DESC);
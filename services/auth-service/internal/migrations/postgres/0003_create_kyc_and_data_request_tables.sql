@@ -0,0 +1,60 @@
+CREATE TABLE kyc_cases (
+    id               uuid PRIMARY KEY,
+    user_id          uuid NOT NULL,
+    user_email       text NOT NULL DEFAULT '',
+    user_role        text NOT NULL DEFAULT '',
+    user_roles       text[],
+    status           text NOT NULL,
+    documents        jsonb,
+    attempt          integer NOT NULL DEFAULT 1,
+    previous_case_id uuid,
+    reviewed_by      uuid,
+    reviewed_at      timestamptz,
+    rejection_reason text NOT NULL DEFAULT '',
+    history          jsonb,
+    submitted_at     timestamptz NOT NULL,
+    updated_at       timestamptz NOT NULL
+);
+
+CREATE INDEX kyc_cases_user_id_submitted_at_idx ON kyc_cases (user_id, submitted_at DESC);
+CREATE INDEX kyc_cases_status_submitted_at_idx ON kyc_cases (status, submitted_at);
+
+CREATE TABLE identity_documents (
+    id            uuid PRIMARY KEY,
+    user_id       uuid NOT NULL,
+    document_type text NOT NULL,
+    document_url  text NOT NULL,
+    uploaded_at   timestamptz NOT NULL
+);
+
+CREATE INDEX identity_documents_user_id_idx ON identity_documents (user_id);
+
+CREATE TABLE data_requests (
+    id              uuid PRIMARY KEY,
+    user_id         uuid NOT NULL,
+    type            text NOT NULL,
+    status          text NOT NULL,
+    steps           jsonb,
+    attempts        integer NOT NULL DEFAULT 0,
+    error           text NOT NULL DEFAULT '',
+    next_attempt_at timestamptz NOT NULL,
+    lease_until     timestamptz,
+    archive_ready   boolean NOT NULL DEFAULT false,
+    created_at      timestamptz NOT NULL,
+    updated_at      timestamptz NOT NULL,
+    completed_at    timestamptz
+);
+
+CREATE INDEX data_requests_user_id_type_status_idx ON data_requests (user_id, type, status);
+CREATE INDEX data_requests_status_next_attempt_at_idx ON data_requests (status, next_attempt_at);
+CREATE INDEX data_requests_created_at_idx ON data_requests (created_at DESC);
+
+CREATE TABLE data_export_archives (
+    request_id uuid PRIMARY KEY,
+    user_id    uuid NOT NULL,
+    data       bytea NOT NULL,
+    created_at timestamptz NOT NULL,
+    expires_at timestamptz NOT NULL
+);
+
+CREATE INDEX data_export_archives_expires_at_idx ON data_export_archives (expires_at);
@@ -0,0 +1,104 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+)
+
+// Client sends transactional emails through notification-service
+type Client struct {
+	baseURL    string
+	checker    *auth.Checker
+	httpClient *http.Client
+}
+
+// NewClient creates a new notification client that authenticates with
+// service tokens minted by checker.
+// addr may be a host:port pair or a full http(s) URL.
+func NewClient(addr string, checker *auth.Checker) *Client {
+	if !strings.HasPrefix(addr, "http://") && !strings.HasPrefix(addr, "https://") {
+		addr = "http://" + addr
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(addr, "/"),
+		checker: checker,
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: tracing.Transport(logger.Transport(nil)),
+		},
+	}
+}
+
+// SendEmailVerification sends the email verification link for token.
+// Notification-service builds the link.
+func (c *Client) SendEmailVerification(ctx context.Context, to, token string, data map[string]interface{}) error {
+	return c.sendEmail(ctx, "/api/notifications/email-verification", to, token, data)
+}
+
+// SendPasswordReset sends the password reset link for token.
+// Notification-service builds the link.
+func (c *Client) SendPasswordReset(ctx context.Context, to, token string, data map[string]interface{}) error {
+	return c.sendEmail(ctx, "/api/notifications/password-reset", to, token, data)
+}
+
+// SendAccountLocked warns a user that their account was locked after failed logins
+func (c *Client) SendAccountLocked(ctx context.Context, to string, data map[string]interface{}) error {
+	return c.sendEmail(ctx, "/api/notifications/account-locked", to, "", data)
+}
+
+// SendKYCApproved emails a user that their identity verification was approved
+func (c *Client) SendKYCApproved(ctx context.Context, to string, data map[string]interface{}) error {
+	return c.sendEmail(ctx, "/api/notifications/kyc-approved", to, "", data)
+}
+
+// SendKYCRejected emails a user that their identity verification was rejected
+func (c *Client) SendKYCRejected(ctx context.Context, to string, data map[string]interface{}) error {
+	return c.sendEmail(ctx, "/api/notifications/kyc-rejected", to, "", data)
+}
+
+// sendEmail posts an email request to notification-service
+func (c *Client) sendEmail(ctx context.Context, path, to, token string, data map[string]interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"to":    to,
+		"token": token,
+		"data":  data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal email request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	serviceToken, err := c.checker.ServiceToken("auth-service", auth.PermNotificationsSend)
+	if err != nil {
+		return fmt.Errorf("failed to issue service token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+serviceToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach notification service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("notification service error (status %d): %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
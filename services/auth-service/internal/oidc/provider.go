@@ -0,0 +1,173 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"golang.org/x/oauth2"
+)
+
+// ErrUnknownProvider is returned for provider names that are not configured
+var ErrUnknownProvider = errors.New("unknown identity provider")
+
+// Identity holds the verified claims of an ID token
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Name          string
+}
+
+// ProviderInfo describes a configured provider for clients
+type ProviderInfo struct {
+	Name        string
+	DisplayName string
+}
+
+// Registry holds the configured OIDC providers. Discovery runs lazily on first
+// use so the service can start while a provider is unreachable.
+type Registry struct {
+	configs   map[string]config.OIDCProviderConfig
+	mu        sync.Mutex
+	providers map[string]*provider
+}
+
+type provider struct {
+	oauth    *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+// NewRegistry creates a registry for the given provider configs
+func NewRegistry(configs []config.OIDCProviderConfig) *Registry {
+	r := &Registry{
+		configs:   make(map[string]config.OIDCProviderConfig),
+		providers: make(map[string]*provider),
+	}
+	for _, c := range configs {
+		r.configs[c.Name] = c
+	}
+	return r
+}
+
+// Providers lists the configured providers sorted by name
+func (r *Registry) Providers() []ProviderInfo {
+	infos := make([]ProviderInfo, 0, len(r.configs))
+	for _, c := range r.configs {
+		infos = append(infos, ProviderInfo{Name: c.Name, DisplayName: c.DisplayName})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// AuthCodeURL builds the provider authorization URL with state, nonce and a PKCE S256 challenge
+func (r *Registry) AuthCodeURL(ctx context.Context, name, state, nonce, codeVerifier string) (string, error) {
+	p, err := r.provider(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	return p.oauth.AuthCodeURL(state,
+		gooidc.Nonce(nonce),
+		oauth2.S256ChallengeOption(codeVerifier),
+	), nil
+}
+
+// Exchange redeems an authorization code and verifies the returned ID token
+// against the provider's JWKS, audience, expiry and nonce
+func (r *Registry) Exchange(ctx context.Context, name, code, codeVerifier, nonce string) (*Identity, error) {
+	p, err := r.provider(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, errors.New("token response did not include an id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify id_token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce mismatch")
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified any    `json:"email_verified"`
+		GivenName     string `json:"given_name"`
+		FamilyName    string `json:"family_name"`
+		Name          string `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to decode id_token claims: %w", err)
+	}
+
+	return &Identity{
+		Provider:      name,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: isTrue(claims.EmailVerified),
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+		Name:          claims.Name,
+	}, nil
+}
+
+// provider returns a discovered provider, running discovery on first use
+func (r *Registry) provider(ctx context.Context, name string) (*provider, error) {
+	cfg, ok := r.configs[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p, ok := r.providers[name]; ok {
+		return p, nil
+	}
+
+	discovered, err := gooidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover provider %s: %w", name, err)
+	}
+
+	p := &provider{
+		oauth: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     discovered.Endpoint(),
+			Scopes:       cfg.Scopes,
+		},
+		verifier: discovered.Verifier(&gooidc.Config{ClientID: cfg.ClientID}),
+	}
+	r.providers[name] = p
+	return p, nil
+}
+
+// isTrue accepts email_verified as a boolean or the string "true" (some providers send strings)
+func isTrue(v any) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true"
+	}
+	return false
+}
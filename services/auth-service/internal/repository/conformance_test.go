@@ -0,0 +1,194 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/rentalflow/auth-service/internal/migrations"
+	"github.com/rentalflow/auth-service/internal/repository"
+	"github.com/rentalflow/auth-service/internal/repository/repositorytest"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/rentalflow/pkg/database/databasetest"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestMongoRepositories(t *testing.T) {
+	db := func(t *testing.T) *mongo.Database {
+		return databasetest.Mongo(t, migrations.All).DB
+	}
+
+	t.Run("User", func(t *testing.T) {
+		repositorytest.UserRepository(t, func(t *testing.T) repository.UserRepository {
+			return repository.NewMongoUserRepository(db(t))
+		})
+	})
+	t.Run("Document", func(t *testing.T) {
+		repositorytest.DocumentRepository(t, func(t *testing.T) repository.DocumentRepository {
+			return repository.NewMongoDocumentRepository(db(t))
+		})
+	})
+	t.Run("ActionToken", func(t *testing.T) {
+		repositorytest.ActionTokenRepository(t, func(t *testing.T) repository.ActionTokenRepository {
+			return repository.NewMongoActionTokenRepository(db(t))
+		})
+	})
+	t.Run("Settings", func(t *testing.T) {
+		repositorytest.SettingsRepository(t, func(t *testing.T) repository.SettingsRepository {
+			return repository.NewMongoSettingsRepository(db(t))
+		})
+	})
+	t.Run("LoginThrottle", func(t *testing.T) {
+		repositorytest.LoginThrottleRepository(t, func(t *testing.T) repository.LoginThrottleRepository {
+			return repository.NewMongoLoginThrottleRepository(db(t))
+		})
+	})
+	t.Run("AuditLog", func(t *testing.T) {
+		repositorytest.AuditLogRepository(t, func(t *testing.T) repository.AuditLogRepository {
+			return repository.NewMongoAuditLogRepository(db(t))
+		})
+	})
+	t.Run("OIDCState", func(t *testing.T) {
+		repositorytest.OIDCStateRepository(t, func(t *testing.T) repository.OIDCStateRepository {
+			return repository.NewMongoOIDCStateRepository(db(t))
+		})
+	})
+	t.Run("ExternalIdentity", func(t *testing.T) {
+		repositorytest.ExternalIdentityRepository(t, func(t *testing.T) repository.ExternalIdentityRepository {
+			return repository.NewMongoExternalIdentityRepository(db(t))
+		})
+	})
+	t.Run("KYCCase", func(t *testing.T) {
+		repositorytest.KYCCaseRepository(t, func(t *testing.T) repository.KYCCaseRepository {
+			return repository.NewMongoKYCCaseRepository(db(t))
+		})
+	})
+	t.Run("DataRequest", func(t *testing.T) {
+		repositorytest.DataRequestRepository(t, func(t *testing.T) repository.DataRequestRepository {
+			return repository.NewMongoDataRequestRepository(db(t))
+		})
+	})
+	t.Run("DataExportArchive", func(t *testing.T) {
+		repositorytest.DataExportArchiveRepository(t, func(t *testing.T) repository.DataExportArchiveRepository {
+			return repository.NewMongoDataExportArchiveRepository(db(t))
+		})
+	})
+}
+
+func TestPostgresRepositories(t *testing.T) {
+	db := func(t *testing.T) *database.Postgres {
+		return databasetest.Postgres(t, migrations.Postgres)
+	}
+
+	t.Run("User", func(t *testing.T) {
+		repositorytest.UserRepository(t, func(t *testing.T) repository.UserRepository {
+			return repository.NewPostgresUserRepository(db(t))
+		})
+	})
+	t.Run("Document", func(t *testing.T) {
+		repositorytest.DocumentRepository(t, func(t *testing.T) repository.DocumentRepository {
+			return repository.NewPostgresDocumentRepository(db(t))
+		})
+	})
+	t.Run("ActionToken", func(t *testing.T) {
+		repositorytest.ActionTokenRepository(t, func(t *testing.T) repository.ActionTokenRepository {
+			return repository.NewPostgresActionTokenRepository(db(t))
+		})
+	})
+	t.Run("Settings", func(t *testing.T) {
+		repositorytest.SettingsRepository(t, func(t *testing.T) repository.SettingsRepository {
+			return repository.NewPostgresSettingsRepository(db(t))
+		})
+	})
+	t.Run("LoginThrottle", func(t *testing.T) {
+		repositorytest.LoginThrottleRepository(t, func(t *testing.T) repository.LoginThrottleRepository {
+			return repository.NewPostgresLoginThrottleRepository(db(t))
+		})
+	})
+	t.Run("AuditLog", func(t *testing.T) {
+		repositorytest.AuditLogRepository(t, func(t *testing.T) repository.AuditLogRepository {
+			return repository.NewPostgresAuditLogRepository(db(t))
+		})
+	})
+	t.Run("OIDCState", func(t *testing.T) {
+		repositorytest.OIDCStateRepository(t, func(t *testing.T) repository.OIDCStateRepository {
+			return repository.NewPostgresOIDCStateRepository(db(t))
+		})
+	})
+	t.Run("ExternalIdentity", func(t *testing.T) {
+		repositorytest.ExternalIdentityRepository(t, func(t *testing.T) repository.ExternalIdentityRepository {
+			return repository.NewPostgresExternalIdentityRepository(db(t))
+		})
+	})
+	t.Run("KYCCase", func(t *testing.T) {
+		repositorytest.KYCCaseRepository(t, func(t *testing.T) repository.KYCCaseRepository {
+			return repository.NewPostgresKYCCaseRepository(db(t))
+		})
+	})
+	t.Run("DataRequest", func(t *testing.T) {
+		repositorytest.DataRequestRepository(t, func(t *testing.T) repository.DataRequestRepository {
+			return repository.NewPostgresDataRequestRepository(db(t))
+		})
+	})
+	t.Run("DataExportArchive", func(t *testing.T) {
+		repositorytest.DataExportArchiveRepository(t, func(t *testing.T) repository.DataExportArchiveRepository {
+			return repository.NewPostgresDataExportArchiveRepository(db(t))
+		})
+	})
+}
+
+func TestMemoryRepositories(t *testing.T) {
+	t.Run("User", func(t *testing.T) {
+		repositorytest.UserRepository(t, func(t *testing.T) repository.UserRepository {
+			return repository.NewMemoryUserRepository()
+		})
+	})
+	t.Run("Document", func(t *testing.T) {
+		repositorytest.DocumentRepository(t, func(t *testing.T) repository.DocumentRepository {
+			return repository.NewMemoryDocumentRepository()
+		})
+	})
+	t.Run("ActionToken", func(t *testing.T) {
+		repositorytest.ActionTokenRepository(t, func(t *testing.T) repository.ActionTokenRepository {
+			return repository.NewMemoryActionTokenRepository()
+		})
+	})
+	t.Run("Settings", func(t *testing.T) {
+		repositorytest.SettingsRepository(t, func(t *testing.T) repository.SettingsRepository {
+			return repository.NewMemorySettingsRepository()
+		})
+	})
+	t.Run("LoginThrottle", func(t *testing.T) {
+		repositorytest.LoginThrottleRepository(t, func(t *testing.T) repository.LoginThrottleRepository {
+			return repository.NewMemoryLoginThrottleRepository()
+		})
+	})
+	t.Run("AuditLog", func(t *testing.T) {
+		repositorytest.AuditLogRepository(t, func(t *testing.T) repository.AuditLogRepository {
+			return repository.NewMemoryAuditLogRepository()
+		})
+	})
+	t.Run("OIDCState", func(t *testing.T) {
+		repositorytest.OIDCStateRepository(t, func(t *testing.T) repository.OIDCStateRepository {
+			return repository.NewMemoryOIDCStateRepository()
+		})
+	})
+	t.Run("ExternalIdentity", func(t *testing.T) {
+		repositorytest.ExternalIdentityRepository(t, func(t *testing.T) repository.ExternalIdentityRepository {
+			return repository.NewMemoryExternalIdentityRepository()
+		})
+	})
+	t.Run("KYCCase", func(t *testing.T) {
+		repositorytest.KYCCaseRepository(t, func(t *testing.T) repository.KYCCaseRepository {
+			return repository.NewMemoryKYCCaseRepository()
+		})
+	})
+	t.Run("DataRequest", func(t *testing.T) {
+		repositorytest.DataRequestRepository(t, func(t *testing.T) repository.DataRequestRepository {
+			return repository.NewMemoryDataRequestRepository()
+		})
+	})
+	t.Run("DataExportArchive", func(t *testing.T) {
+		repositorytest.DataExportArchiveRepository(t, func(t *testing.T) repository.DataExportArchiveRepository {
+			return repository.NewMemoryDataExportArchiveRepository()
+		})
+	})
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// MemoryActionTokenRepository implements ActionTokenRepository in memory
+type MemoryActionTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[uuid.UUID]*domain.ActionToken
+}
+
+// NewMemoryActionTokenRepository creates an empty in-memory action token repository
+func NewMemoryActionTokenRepository() *MemoryActionTokenRepository {
+	return &MemoryActionTokenRepository{
+		tokens: make(map[uuid.UUID]*domain.ActionToken),
+	}
+}
+
+// Create stores a new token
+func (r *MemoryActionTokenRepository) Create(ctx context.Context, token *domain.ActionToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.tokens[token.ID] = database.Clone(token)
+	return nil
+}
+
+// GetByHash retrieves a token by its hash and purpose
+func (r *MemoryActionTokenRepository) GetByHash(ctx context.Context, tokenHash string, purpose domain.TokenPurpose) (*domain.ActionToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash && token.Purpose == purpose {
+			return database.Clone(token), nil
+		}
+	}
+	return nil, domain.ErrInvalidActionToken
+}
+
+// MarkUsed marks a token as used if it has not been used yet
+func (r *MemoryActionTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[id]
+	if !ok || token.UsedAt != nil {
+		return domain.ErrInvalidActionToken
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	r.tokens[id] = database.Clone(token)
+	return nil
+}
+
+// InvalidateForUser marks all of a user's unused tokens of a purpose as used
+func (r *MemoryActionTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose domain.TokenPurpose) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, token := range r.tokens {
+		if token.UserID == userID && token.Purpose == purpose && token.UsedAt == nil {
+			token.UsedAt = &now
+			r.tokens[id] = database.Clone(token)
+		}
+	}
+	return nil
+}
+
+// DeleteByUser deletes every token issued to a user
+func (r *MemoryActionTokenRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for id, token := range r.tokens {
+		if token.UserID == userID {
+			delete(r.tokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// MemoryAuditLogRepository implements AuditLogRepository in memory
+type MemoryAuditLogRepository struct {
+	mu     sync.Mutex
+	events map[uuid.UUID]*domain.AuthAuditEvent
+}
+
+// NewMemoryAuditLogRepository creates an empty in-memory audit log repository
+func NewMemoryAuditLogRepository() *MemoryAuditLogRepository {
+	return &MemoryAuditLogRepository{
+		events: make(map[uuid.UUID]*domain.AuthAuditEvent),
+	}
+}
+
+// Create appends an event to the audit log
+func (r *MemoryAuditLogRepository) Create(ctx context.Context, event *domain.AuthAuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events[event.ID] = database.Clone(event)
+	return nil
+}
+
+// List retrieves a paginated list of events, newest first
+func (r *MemoryAuditLogRepository) List(ctx context.Context, offset, limit int, filters AuditFilters) ([]*domain.AuthAuditEvent, int, error) {
+	events := r.find(func(e *domain.AuthAuditEvent) bool {
+		if filters.UserID != nil && (e.UserID == nil || *e.UserID != *filters.UserID) {
+			return false
+		}
+		if filters.Email != "" && e.Email != filters.Email {
+			return false
+		}
+		return filters.Type == nil || e.Type == *filters.Type
+	})
+	return database.Page(events, offset, limit), len(events), nil
+}
+
+// ListByUser retrieves every event about a user, newest first
+func (r *MemoryAuditLogRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.AuthAuditEvent, error) {
+	return r.find(func(e *domain.AuthAuditEvent) bool { return e.UserID != nil && *e.UserID == userID }), nil
+}
+
+// AnonymizeByUser strips the email, IP address and user agent from a user's events
+func (r *MemoryAuditLogRepository) AnonymizeByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	anonymized := 0
+	for id, event := range r.events {
+		if event.UserID == nil || *event.UserID != userID {
+			continue
+		}
+		if event.Email == "" && event.IPAddress == "" && event.UserAgent == "" {
+			continue
+		}
+		event.Email = ""
+		event.IPAddress = ""
+		event.UserAgent = ""
+		r.events[id] = database.Clone(event)
+		anonymized++
+	}
+	return anonymized, nil
+}
+
+// find returns copies of the events that match, newest first
+func (r *MemoryAuditLogRepository) find(match func(*domain.AuthAuditEvent) bool) []*domain.AuthAuditEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := []*domain.AuthAuditEvent{}
+	for _, event := range r.events {
+		if match(event) {
+			events = append(events, database.Clone(event))
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.After(events[j].CreatedAt)
+	})
+	return events
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// MemoryDataRequestRepository implements DataRequestRepository in memory
+type MemoryDataRequestRepository struct {
+	mu       sync.Mutex
+	requests map[uuid.UUID]*domain.DataRequest
+}
+
+// NewMemoryDataRequestRepository creates an empty in-memory data request repository
+func NewMemoryDataRequestRepository() *MemoryDataRequestRepository {
+	return &MemoryDataRequestRepository{
+		requests: make(map[uuid.UUID]*domain.DataRequest),
+	}
+}
+
+// Create queues a new request
+func (r *MemoryDataRequestRepository) Create(ctx context.Context, req *domain.DataRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests[req.ID] = database.Clone(req)
+	return nil
+}
+
+// GetByID retrieves a request by ID
+func (r *MemoryDataRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.DataRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	req, ok := r.requests[id]
+	if !ok {
+		return nil, domain.ErrDataRequestNotFound
+	}
+	return database.Clone(req), nil
+}
+
+// GetActiveByUser retrieves a user's queued or running request of a type (nil if none exists)
+func (r *MemoryDataRequestRepository) GetActiveByUser(ctx context.Context, userID uuid.UUID, requestType domain.DataRequestType) (*domain.DataRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, req := range r.requests {
+		if req.UserID != userID || req.Type != requestType {
+			continue
+		}
+		if req.Status == domain.DataRequestPending || req.Status == domain.DataRequestRunning {
+			return database.Clone(req), nil
+		}
+	}
+	return nil, nil
+}
+
+// List retrieves a paginated list of requests, newest first
+func (r *MemoryDataRequestRepository) List(ctx context.Context, offset, limit int, filters DataRequestFilters) ([]*domain.DataRequest, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	requests := []*domain.DataRequest{}
+	for _, req := range r.requests {
+		if filters.UserID != nil && req.UserID != *filters.UserID {
+			continue
+		}
+		if filters.Type != nil && req.Type != *filters.Type {
+			continue
+		}
+		if filters.Status != nil && req.Status != *filters.Status {
+			continue
+		}
+		requests = append(requests, database.Clone(req))
+	}
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].CreatedAt.After(requests[j].CreatedAt)
+	})
+	return database.Page(requests, offset, limit), len(requests), nil
+}
+
+// ClaimNext leases the oldest request that is due (nil if none exists).
+// Running requests whose lease has expired, e.g. after a crash, are claimed again.
+func (r *MemoryDataRequestRepository) ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.DataRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var next *domain.DataRequest
+	for _, req := range r.requests {
+		due := req.Status == domain.DataRequestPending && !req.NextAttemptAt.After(now) ||
+			req.Status == domain.DataRequestRunning && req.LeaseUntil != nil && req.LeaseUntil.Before(now)
+		if due && (next == nil || req.CreatedAt.Before(next.CreatedAt)) {
+			next = req
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+
+	next.Status = domain.DataRequestRunning
+	next.LeaseUntil = &leaseUntil
+	next.UpdatedAt = now
+	next.Attempts++
+	r.requests[next.ID] = database.Clone(next)
+	return database.Clone(next), nil
+}
+
+// Save stores the progress of a claimed request
+func (r *MemoryDataRequestRepository) Save(ctx context.Context, req *domain.DataRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.requests[req.ID]; ok {
+		r.requests[req.ID] = database.Clone(req)
+	}
+	return nil
+}
+
+// MemoryDataExportArchiveRepository implements DataExportArchiveRepository in memory
+type MemoryDataExportArchiveRepository struct {
+	mu       sync.Mutex
+	archives map[uuid.UUID]*domain.DataExportArchive
+}
+
+// NewMemoryDataExportArchiveRepository creates an empty in-memory export archive repository
+func NewMemoryDataExportArchiveRepository() *MemoryDataExportArchiveRepository {
+	return &MemoryDataExportArchiveRepository{
+		archives: make(map[uuid.UUID]*domain.DataExportArchive),
+	}
+}
+
+// Save stores an archive, replacing any earlier one for the request
+func (r *MemoryDataExportArchiveRepository) Save(ctx context.Context, archive *domain.DataExportArchive) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.archives[archive.RequestID] = database.Clone(archive)
+	return nil
+}
+
+// GetByRequestID retrieves the archive of an export request
+func (r *MemoryDataExportArchiveRepository) GetByRequestID(ctx context.Context, requestID uuid.UUID) (*domain.DataExportArchive, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	archive, ok := r.archives[requestID]
+	if !ok {
+		return nil, domain.ErrDataExportNotFound
+	}
+	return database.Clone(archive), nil
+}
+
+// DeleteExpired deletes archives whose download window has passed
+func (r *MemoryDataExportArchiveRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for id, archive := range r.archives {
+		if archive.ExpiresAt.Before(now) {
+			delete(r.archives, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// MemoryKYCCaseRepository implements KYCCaseRepository in memory
+type MemoryKYCCaseRepository struct {
+	mu    sync.Mutex
+	cases map[uuid.UUID]*domain.KYCCase
+}
+
+// NewMemoryKYCCaseRepository creates an empty in-memory KYC case repository
+func NewMemoryKYCCaseRepository() *MemoryKYCCaseRepository {
+	return &MemoryKYCCaseRepository{
+		cases: make(map[uuid.UUID]*domain.KYCCase),
+	}
+}
+
+// Create stores a new case
+func (r *MemoryKYCCaseRepository) Create(ctx context.Context, c *domain.KYCCase) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cases[c.ID] = database.Clone(c)
+	return nil
+}
+
+// GetByID retrieves a case by ID
+func (r *MemoryKYCCaseRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.KYCCase, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c, ok := r.cases[id]
+	if !ok {
+		return nil, domain.ErrKYCCaseNotFound
+	}
+	return database.Clone(c), nil
+}
+
+// GetLatestByUser retrieves a user's most recent case (nil if none exists)
+func (r *MemoryKYCCaseRepository) GetLatestByUser(ctx context.Context, userID uuid.UUID) (*domain.KYCCase, error) {
+	cases := r.find(func(c *domain.KYCCase) bool { return c.UserID == userID })
+	if len(cases) == 0 {
+		return nil, nil
+	}
+	return cases[len(cases)-1], nil
+}
+
+// List retrieves a paginated list of cases, oldest submission first
+func (r *MemoryKYCCaseRepository) List(ctx context.Context, offset, limit int, filters KYCFilters) ([]*domain.KYCCase, int, error) {
+	cases := r.find(func(c *domain.KYCCase) bool {
+		if filters.Status != nil && c.Status != *filters.Status {
+			return false
+		}
+		if filters.UserID != nil && c.UserID != *filters.UserID {
+			return false
+		}
+		if filters.Role != nil && c.UserRole != *filters.Role && !hasRole(c.UserRoles, *filters.Role) {
+			return false
+		}
+		if filters.DocumentType != "" && !hasDocumentType(c.Documents, filters.DocumentType) {
+			return false
+		}
+		if filters.SubmittedFrom != nil && c.SubmittedAt.Before(*filters.SubmittedFrom) {
+			return false
+		}
+		return filters.SubmittedTo == nil || c.SubmittedAt.Before(*filters.SubmittedTo)
+	})
+	return database.Page(cases, offset, limit), len(cases), nil
+}
+
+// SaveDecision stores a reviewer decision if the case is still pending.
+// Returns ErrKYCCaseAlreadyReviewed if another reviewer decided first.
+func (r *MemoryKYCCaseRepository) SaveDecision(ctx context.Context, c *domain.KYCCase) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.cases[c.ID]
+	if !ok || stored.Status != domain.KYCStatusPending {
+		return domain.ErrKYCCaseAlreadyReviewed
+	}
+	decided := database.Clone(c)
+	stored.Status = decided.Status
+	stored.ReviewedBy = decided.ReviewedBy
+	stored.ReviewedAt = decided.ReviewedAt
+	stored.RejectionReason = decided.RejectionReason
+	stored.History = decided.History
+	stored.UpdatedAt = decided.UpdatedAt
+	return nil
+}
+
+// ListByUser retrieves all of a user's cases, oldest first
+func (r *MemoryKYCCaseRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.KYCCase, error) {
+	return r.find(func(c *domain.KYCCase) bool { return c.UserID == userID }), nil
+}
+
+// AnonymizeByUser strips the email, documents and free-text reasons from a user's cases
+func (r *MemoryKYCCaseRepository) AnonymizeByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	anonymized := 0
+	for id, c := range r.cases {
+		if c.UserID != userID {
+			continue
+		}
+		c.UserEmail = ""
+		c.Documents = []domain.KYCDocument{}
+		c.RejectionReason = ""
+		for i := range c.History {
+			c.History[i].Reason = ""
+		}
+		c.UpdatedAt = now
+		r.cases[id] = database.Clone(c)
+		anonymized++
+	}
+	return anonymized, nil
+}
+
+// find returns copies of the cases that match, oldest submission first
+func (r *MemoryKYCCaseRepository) find(match func(*domain.KYCCase) bool) []*domain.KYCCase {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cases := []*domain.KYCCase{}
+	for _, c := range r.cases {
+		if match(c) {
+			cases = append(cases, database.Clone(c))
+		}
+	}
+	sort.SliceStable(cases, func(i, j int) bool {
+		return cases[i].SubmittedAt.Before(cases[j].SubmittedAt)
+	})
+	return cases
+}
+
+// hasDocumentType reports whether any of docs is of the given type
+func hasDocumentType(docs []domain.KYCDocument, docType string) bool {
+	for _, doc := range docs {
+		if doc.Type == docType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// MemoryLoginThrottleRepository implements LoginThrottleRepository in memory
+type MemoryLoginThrottleRepository struct {
+	mu        sync.Mutex
+	throttles map[string]*domain.LoginThrottle
+}
+
+// NewMemoryLoginThrottleRepository creates an empty in-memory login throttle repository
+func NewMemoryLoginThrottleRepository() *MemoryLoginThrottleRepository {
+	return &MemoryLoginThrottleRepository{
+		throttles: make(map[string]*domain.LoginThrottle),
+	}
+}
+
+// Get retrieves a counter by key (nil if none exists)
+func (r *MemoryLoginThrottleRepository) Get(ctx context.Context, key string) (*domain.LoginThrottle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	throttle, ok := r.throttles[key]
+	if !ok {
+		return nil, nil
+	}
+	return database.Clone(throttle), nil
+}
+
+// RecordFailure counts a failed attempt. Failures older than windowStart
+// restart the count and lockouts older than decayStart are forgotten.
+func (r *MemoryLoginThrottleRepository) RecordFailure(ctx context.Context, kind domain.ThrottleKind, subject string, userID *uuid.UUID, windowStart, decayStart time.Time) (*domain.LoginThrottle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := domain.ThrottleKey(kind, subject)
+	throttle, ok := r.throttles[key]
+	if !ok {
+		throttle = &domain.LoginThrottle{Key: key}
+	}
+	if ok && !throttle.LastFailureAt.Before(windowStart) {
+		throttle.Failures++
+	} else {
+		throttle.Failures = 1
+	}
+	if !ok || throttle.LastFailureAt.Before(decayStart) {
+		throttle.LockoutCount = 0
+	}
+
+	now := time.Now()
+	throttle.Kind = kind
+	throttle.Subject = subject
+	if userID != nil {
+		throttle.UserID = userID
+	}
+	throttle.LastFailureAt = now
+	throttle.UpdatedAt = now
+	r.throttles[key] = database.Clone(throttle)
+	return database.Clone(throttle), nil
+}
+
+// Lock locks a counter until the given time and starts a new count
+func (r *MemoryLoginThrottleRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	throttle, ok := r.throttles[key]
+	if !ok {
+		return nil
+	}
+	throttle.LockedUntil = &until
+	throttle.Failures = 0
+	throttle.LockoutCount++
+	throttle.UpdatedAt = time.Now()
+	r.throttles[key] = database.Clone(throttle)
+	return nil
+}
+
+// Reset deletes a counter
+func (r *MemoryLoginThrottleRepository) Reset(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.throttles, key)
+	return nil
+}
+
+// ListLocked retrieves counters of a kind whose lockout is still active
+func (r *MemoryLoginThrottleRepository) ListLocked(ctx context.Context, kind domain.ThrottleKind, now time.Time, offset, limit int) ([]*domain.LoginThrottle, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	throttles := []*domain.LoginThrottle{}
+	for _, throttle := range r.throttles {
+		if throttle.Kind == kind && throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+			throttles = append(throttles, database.Clone(throttle))
+		}
+	}
+	sort.SliceStable(throttles, func(i, j int) bool {
+		return throttles[i].LockedUntil.After(*throttles[j].LockedUntil)
+	})
+	return database.Page(throttles, offset, limit), len(throttles), nil
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// MemoryOIDCStateRepository implements OIDCStateRepository in memory
+type MemoryOIDCStateRepository struct {
+	mu     sync.Mutex
+	states map[string]*domain.OIDCLoginState
+}
+
+// NewMemoryOIDCStateRepository creates an empty in-memory login state repository
+func NewMemoryOIDCStateRepository() *MemoryOIDCStateRepository {
+	return &MemoryOIDCStateRepository{
+		states: make(map[string]*domain.OIDCLoginState),
+	}
+}
+
+// Create stores a new login state, dropping the ones that have expired
+// as the MongoDB TTL index would
+func (r *MemoryOIDCStateRepository) Create(ctx context.Context, state *domain.OIDCLoginState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for hash, stored := range r.states {
+		if stored.IsExpired() {
+			delete(r.states, hash)
+		}
+	}
+	r.states[state.StateHash] = database.Clone(state)
+	return nil
+}
+
+// Consume retrieves and deletes a login state.
+// Returns ErrInvalidOIDCState if it does not exist or has expired.
+func (r *MemoryOIDCStateRepository) Consume(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state, ok := r.states[stateHash]
+	if !ok {
+		return nil, domain.ErrInvalidOIDCState
+	}
+	delete(r.states, stateHash)
+	if state.IsExpired() {
+		return nil, domain.ErrInvalidOIDCState
+	}
+	return state, nil
+}
+
+// MemoryExternalIdentityRepository implements ExternalIdentityRepository in memory
+type MemoryExternalIdentityRepository struct {
+	mu         sync.Mutex
+	identities map[uuid.UUID]*domain.ExternalIdentity
+}
+
+// NewMemoryExternalIdentityRepository creates an empty in-memory external identity repository
+func NewMemoryExternalIdentityRepository() *MemoryExternalIdentityRepository {
+	return &MemoryExternalIdentityRepository{
+		identities: make(map[uuid.UUID]*domain.ExternalIdentity),
+	}
+}
+
+// Create links a provider subject to a user
+func (r *MemoryExternalIdentityRepository) Create(ctx context.Context, identity *domain.ExternalIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, stored := range r.identities {
+		if stored.Provider == identity.Provider && stored.Subject == identity.Subject {
+			return fmt.Errorf("%s subject %s is already linked", identity.Provider, identity.Subject)
+		}
+	}
+	r.identities[identity.ID] = database.Clone(identity)
+	return nil
+}
+
+// GetByProviderSubject retrieves the link for a provider subject (nil if none exists)
+func (r *MemoryExternalIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.ExternalIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return database.Clone(identity), nil
+		}
+	}
+	return nil, nil
+}
+
+// TouchLogin records a login through the linked identity
+func (r *MemoryExternalIdentityRepository) TouchLogin(ctx context.Context, id uuid.UUID, email string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	identity, ok := r.identities[id]
+	if !ok {
+		return nil
+	}
+	identity.Email = email
+	identity.LastLoginAt = time.Now()
+	r.identities[id] = database.Clone(identity)
+	return nil
+}
+
+// ListByUser retrieves every identity linked to a user
+func (r *MemoryExternalIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.ExternalIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	identities := []*domain.ExternalIdentity{}
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			identities = append(identities, database.Clone(identity))
+		}
+	}
+	sort.SliceStable(identities, func(i, j int) bool {
+		return identities[i].CreatedAt.Before(identities[j].CreatedAt)
+	})
+	return identities, nil
+}
+
+// DeleteByUser unlinks every identity of a user
+func (r *MemoryExternalIdentityRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for id, identity := range r.identities {
+		if identity.UserID == userID {
+			delete(r.identities, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// MemorySettingsRepository implements SettingsRepository in memory
+type MemorySettingsRepository struct {
+	mu        sync.Mutex
+	mfaPolicy *domain.MFAPolicy
+}
+
+// NewMemorySettingsRepository creates an in-memory settings repository
+// with no settings saved
+func NewMemorySettingsRepository() *MemorySettingsRepository {
+	return &MemorySettingsRepository{}
+}
+
+// GetMFAPolicy retrieves the MFA policy (an empty policy if none is saved)
+func (r *MemorySettingsRepository) GetMFAPolicy(ctx context.Context) (*domain.MFAPolicy, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mfaPolicy == nil {
+		return &domain.MFAPolicy{RequiredRoles: []domain.UserRole{}}, nil
+	}
+	return database.Clone(r.mfaPolicy), nil
+}
+
+// SaveMFAPolicy stores the MFA policy
+func (r *MemorySettingsRepository) SaveMFAPolicy(ctx context.Context, policy *domain.MFAPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.mfaPolicy = database.Clone(policy)
+	return nil
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// MemoryUserRepository implements UserRepository in memory, for tests and
+// local runs
+type MemoryUserRepository struct {
+	mu    sync.Mutex
+	users map[uuid.UUID]*domain.User
+}
+
+// NewMemoryUserRepository creates an empty in-memory user repository
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{
+		users: make(map[uuid.UUID]*domain.User),
+	}
+}
+
+// Create creates a new user
+func (r *MemoryUserRepository) Create(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.users {
+		if existing.ID == user.ID || existing.Email == user.Email {
+			return domain.ErrUserAlreadyExists
+		}
+	}
+	r.users[user.ID] = database.Clone(user)
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *MemoryUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	return database.Clone(user), nil
+}
+
+// GetByEmail retrieves a user by email
+func (r *MemoryUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if user.Email == email {
+			return database.Clone(user), nil
+		}
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+// Update updates a user
+func (r *MemoryUserRepository) Update(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	updated := database.Clone(user)
+	updated.Roles = updated.RoleList()
+	updated.CreatedAt = stored.CreatedAt
+	updated.UpdatedAt = time.Now()
+	r.users[user.ID] = updated
+	return nil
+}
+
+// Delete deletes a user
+func (r *MemoryUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id]; !ok {
+		return domain.ErrUserNotFound
+	}
+	delete(r.users, id)
+	return nil
+}
+
+// List retrieves a paginated list of users
+func (r *MemoryUserRepository) List(ctx context.Context, offset, limit int, filters UserFilters) ([]*domain.User, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	users := []*domain.User{}
+	for _, user := range r.users {
+		if filters.Role != nil && user.Role != *filters.Role && !hasRole(user.Roles, *filters.Role) {
+			continue
+		}
+		if filters.VerificationStatus != nil && user.VerificationStatus != *filters.VerificationStatus {
+			continue
+		}
+		users = append(users, database.Clone(user))
+	}
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].CreatedAt.After(users[j].CreatedAt)
+	})
+	return database.Page(users, offset, limit), len(users), nil
+}
+
+// UpdateRefreshToken updates the refresh token for a user
+func (r *MemoryUserRepository) UpdateRefreshToken(ctx context.Context, userID uuid.UUID, hash string, expiresAt *time.Time) error {
+	return r.update(userID, domain.ErrUserNotFound, func(user *domain.User) bool {
+		user.RefreshTokenHash = hash
+		user.RefreshTokenExpiresAt = expiresAt
+		return true
+	})
+}
+
+// ClearRefreshToken clears the refresh token for a user
+func (r *MemoryUserRepository) ClearRefreshToken(ctx context.Context, userID uuid.UUID) error {
+	return r.UpdateRefreshToken(ctx, userID, "", nil)
+}
+
+// RecordMFAStep records a used TOTP time step only if it is newer than the last one
+func (r *MemoryUserRepository) RecordMFAStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	return r.update(userID, domain.ErrInvalidMFACode, func(user *domain.User) bool {
+		if user.MFALastUsedStep >= step {
+			return false
+		}
+		user.MFALastUsedStep = step
+		return true
+	})
+}
+
+// ConsumeRecoveryCode removes a recovery code hash if it is still present
+func (r *MemoryUserRepository) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	return r.update(userID, domain.ErrInvalidMFACode, func(user *domain.User) bool {
+		for i, code := range user.MFARecoveryCodes {
+			if code == codeHash {
+				user.MFARecoveryCodes = append(user.MFARecoveryCodes[:i:i], user.MFARecoveryCodes[i+1:]...)
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// update changes a user in place if change accepts it, and returns
+// notMatched if the user is missing or change refuses
+func (r *MemoryUserRepository) update(userID uuid.UUID, notMatched error, change func(*domain.User) bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[userID]
+	if !ok {
+		return notMatched
+	}
+	user := database.Clone(stored)
+	if !change(user) {
+		return notMatched
+	}
+	user.UpdatedAt = time.Now()
+	r.users[userID] = database.Clone(user)
+	return nil
+}
+
+// hasRole reports whether roles holds role
+func hasRole(roles []domain.UserRole, role domain.UserRole) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// MemoryDocumentRepository implements DocumentRepository in memory
+type MemoryDocumentRepository struct {
+	mu   sync.Mutex
+	docs map[uuid.UUID]*domain.IdentityDocument
+}
+
+// NewMemoryDocumentRepository creates an empty in-memory document repository
+func NewMemoryDocumentRepository() *MemoryDocumentRepository {
+	return &MemoryDocumentRepository{
+		docs: make(map[uuid.UUID]*domain.IdentityDocument),
+	}
+}
+
+// Create creates a new identity document
+func (r *MemoryDocumentRepository) Create(ctx context.Context, doc *domain.IdentityDocument) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.docs[doc.ID] = database.Clone(doc)
+	return nil
+}
+
+// GetByID retrieves a document by ID
+func (r *MemoryDocumentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.IdentityDocument, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	doc, ok := r.docs[id]
+	if !ok {
+		return nil, domain.ErrDocumentNotFound
+	}
+	return database.Clone(doc), nil
+}
+
+// GetByUserID retrieves all documents for a user
+func (r *MemoryDocumentRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.IdentityDocument, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	docs := []*domain.IdentityDocument{}
+	for _, doc := range r.docs {
+		if doc.UserID == userID {
+			docs = append(docs, database.Clone(doc))
+		}
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		return docs[i].UploadedAt.Before(docs[j].UploadedAt)
+	})
+	return docs, nil
+}
+
+// Delete deletes a document
+func (r *MemoryDocumentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.docs[id]; !ok {
+		return domain.ErrDocumentNotFound
+	}
+	delete(r.docs, id)
+	return nil
+}
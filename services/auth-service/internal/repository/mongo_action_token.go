@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoActionTokenRepository implements ActionTokenRepository using MongoDB
+type MongoActionTokenRepository struct {
+	coll *mongo.Collection
+}
+
+// NewMongoActionTokenRepository creates a new MongoDB action token repository
+func NewMongoActionTokenRepository(db *mongo.Database) *MongoActionTokenRepository {
+	return &MongoActionTokenRepository{
+		coll: db.Collection("action_tokens"),
+	}
+}
+
+// Create stores a new action token
+func (r *MongoActionTokenRepository) Create(ctx context.Context, token *domain.ActionToken) error {
+	_, err := r.coll.InsertOne(ctx, token)
+	return err
+}
+
+// GetByHash retrieves a token by its hash and purpose
+func (r *MongoActionTokenRepository) GetByHash(ctx context.Context, tokenHash string, purpose domain.TokenPurpose) (*domain.ActionToken, error) {
+	var token domain.ActionToken
+	err := r.coll.FindOne(ctx, bson.M{"token_hash": tokenHash, "purpose": purpose}).Decode(&token)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrInvalidActionToken
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed atomically marks an unused token as used.
+// Returns ErrInvalidActionToken if the token was already redeemed.
+func (r *MongoActionTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	filter := bson.M{"_id": id, "used_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"used_at": time.Now()}}
+
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrInvalidActionToken
+	}
+	return nil
+}
+
+// InvalidateForUser marks all unused tokens of a purpose for a user as used
+func (r *MongoActionTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose domain.TokenPurpose) error {
+	filter := bson.M{
+		"user_id": userID,
+		"purpose": purpose,
+		"used_at": bson.M{"$exists": false},
+	}
+	update := bson.M{"$set": bson.M{"used_at": time.Now()}}
+
+	_, err := r.coll.UpdateMany(ctx, filter, update)
+	return err
+}
+
+// DeleteByUser removes all tokens issued to a user
+func (r *MongoActionTokenRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	result, err := r.coll.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.DeletedCount), nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoAuditLogRepository implements AuditLogRepository using MongoDB
+type MongoAuditLogRepository struct {
+	coll *mongo.Collection
+}
+
+// NewMongoAuditLogRepository creates a new MongoDB audit log repository
+func NewMongoAuditLogRepository(db *mongo.Database) *MongoAuditLogRepository {
+	return &MongoAuditLogRepository{
+		coll: db.Collection("auth_audit_log"),
+	}
+}
+
+// Create appends an event to the audit log
+func (r *MongoAuditLogRepository) Create(ctx context.Context, event *domain.AuthAuditEvent) error {
+	_, err := r.coll.InsertOne(ctx, event)
+	return err
+}
+
+// List retrieves a paginated list of events, newest first
+func (r *MongoAuditLogRepository) List(ctx context.Context, offset, limit int, filters AuditFilters) ([]*domain.AuthAuditEvent, int, error) {
+	filter := bson.M{}
+
+	if filters.UserID != nil {
+		filter["user_id"] = *filters.UserID
+	}
+
+	if filters.Email != "" {
+		filter["email"] = filters.Email
+	}
+
+	if filters.Type != nil {
+		filter["type"] = *filters.Type
+	}
+
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []*domain.AuthAuditEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, 0, err
+	}
+
+	return events, int(total), nil
+}
+
+// ListByUser retrieves every event about a user, newest first
+func (r *MongoAuditLogRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.AuthAuditEvent, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": -1})
+
+	cursor, err := r.coll.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []*domain.AuthAuditEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// AnonymizeByUser strips the email, IP address and user agent from a user's
+// events. The events themselves are kept as a security record.
+func (r *MongoAuditLogRepository) AnonymizeByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	update := bson.M{"$unset": bson.M{"email": "", "ip_address": "", "user_agent": ""}}
+
+	result, err := r.coll.UpdateMany(ctx, bson.M{"user_id": userID}, update)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
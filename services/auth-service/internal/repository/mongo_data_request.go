@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoDataRequestRepository implements DataRequestRepository using MongoDB
+type MongoDataRequestRepository struct {
+	coll *mongo.Collection
+}
+
+// NewMongoDataRequestRepository creates a new MongoDB data-subject request repository
+func NewMongoDataRequestRepository(db *mongo.Database) *MongoDataRequestRepository {
+	return &MongoDataRequestRepository{
+		coll: db.Collection("data_requests"),
+	}
+}
+
+// Create queues a new request
+func (r *MongoDataRequestRepository) Create(ctx context.Context, req *domain.DataRequest) error {
+	_, err := r.coll.InsertOne(ctx, req)
+	return err
+}
+
+// GetByID retrieves a request by ID
+func (r *MongoDataRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.DataRequest, error) {
+	var req domain.DataRequest
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&req)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrDataRequestNotFound
+		}
+		return nil, err
+	}
+	return &req, nil
+}
+
+// GetActiveByUser retrieves a user's queued or running request of a type (nil if none exists)
+func (r *MongoDataRequestRepository) GetActiveByUser(ctx context.Context, userID uuid.UUID, requestType domain.DataRequestType) (*domain.DataRequest, error) {
+	filter := bson.M{
+		"user_id": userID,
+		"type":    requestType,
+		"status":  bson.M{"$in": bson.A{domain.DataRequestPending, domain.DataRequestRunning}},
+	}
+
+	var req domain.DataRequest
+	err := r.coll.FindOne(ctx, filter).Decode(&req)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &req, nil
+}
+
+// List retrieves a paginated list of requests, newest first
+func (r *MongoDataRequestRepository) List(ctx context.Context, offset, limit int, filters DataRequestFilters) ([]*domain.DataRequest, int, error) {
+	filter := bson.M{}
+
+	if filters.UserID != nil {
+		filter["user_id"] = *filters.UserID
+	}
+
+	if filters.Type != nil {
+		filter["type"] = *filters.Type
+	}
+
+	if filters.Status != nil {
+		filter["status"] = *filters.Status
+	}
+
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"created_at": -1}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var requests []*domain.DataRequest
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, 0, err
+	}
+
+	return requests, int(total), nil
+}
+
+// ClaimNext atomically leases the oldest request that is due (nil if none exists).
+// Running requests whose lease has expired, e.g. after a crash, are claimed again.
+func (r *MongoDataRequestRepository) ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.DataRequest, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": domain.DataRequestPending, "next_attempt_at": bson.M{"$lte": now}},
+			bson.M{"status": domain.DataRequestRunning, "lease_until": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":      domain.DataRequestRunning,
+			"lease_until": leaseUntil,
+			"updated_at":  now,
+		},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"created_at": 1}).
+		SetReturnDocument(options.After)
+
+	var req domain.DataRequest
+	err := r.coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&req)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &req, nil
+}
+
+// Save stores the progress of a claimed request
+func (r *MongoDataRequestRepository) Save(ctx context.Context, req *domain.DataRequest) error {
+	_, err := r.coll.ReplaceOne(ctx, bson.M{"_id": req.ID}, req)
+	return err
+}
+
+// MongoDataExportArchiveRepository implements DataExportArchiveRepository using MongoDB
+type MongoDataExportArchiveRepository struct {
+	coll *mongo.Collection
+}
+
+// NewMongoDataExportArchiveRepository creates a new MongoDB export archive repository
+func NewMongoDataExportArchiveRepository(db *mongo.Database) *MongoDataExportArchiveRepository {
+	return &MongoDataExportArchiveRepository{
+		coll: db.Collection("data_export_archives"),
+	}
+}
+
+// Save stores an archive, replacing any earlier one for the request
+func (r *MongoDataExportArchiveRepository) Save(ctx context.Context, archive *domain.DataExportArchive) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := r.coll.ReplaceOne(ctx, bson.M{"_id": archive.RequestID}, archive, opts)
+	return err
+}
+
+// GetByRequestID retrieves the archive of an export request
+func (r *MongoDataExportArchiveRepository) GetByRequestID(ctx context.Context, requestID uuid.UUID) (*domain.DataExportArchive, error) {
+	var archive domain.DataExportArchive
+	err := r.coll.FindOne(ctx, bson.M{"_id": requestID}).Decode(&archive)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrDataExportNotFound
+		}
+		return nil, err
+	}
+	return &archive, nil
+}
+
+// DeleteExpired removes archives past their download window
+func (r *MongoDataExportArchiveRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	result, err := r.coll.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lt": now}})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.DeletedCount), nil
+}
@@ -34,7 +34,7 @@ func (r *MongoDocumentRepository) GetByID(ctx context.Context, id uuid.UUID) (*d
 	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&doc)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, errors.New("document not found")
+			return nil, domain.ErrDocumentNotFound
 		}
 		return nil, err
 	}
@@ -63,7 +63,7 @@ func (r *MongoDocumentRepository) Delete(ctx context.Context, id uuid.UUID) erro
 		return err
 	}
 	if result.DeletedCount == 0 {
-		return errors.New("document not found")
+		return domain.ErrDocumentNotFound
 	}
 	return nil
 }
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoKYCCaseRepository implements KYCCaseRepository using MongoDB
+type MongoKYCCaseRepository struct {
+	coll *mongo.Collection
+}
+
+// NewMongoKYCCaseRepository creates a new MongoDB verification case repository
+func NewMongoKYCCaseRepository(db *mongo.Database) *MongoKYCCaseRepository {
+	return &MongoKYCCaseRepository{
+		coll: db.Collection("kyc_cases"),
+	}
+}
+
+// Create stores a new case
+func (r *MongoKYCCaseRepository) Create(ctx context.Context, c *domain.KYCCase) error {
+	_, err := r.coll.InsertOne(ctx, c)
+	return err
+}
+
+// GetByID retrieves a case by ID
+func (r *MongoKYCCaseRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.KYCCase, error) {
+	var c domain.KYCCase
+	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&c)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrKYCCaseNotFound
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetLatestByUser retrieves a user's most recent case (nil if none exists)
+func (r *MongoKYCCaseRepository) GetLatestByUser(ctx context.Context, userID uuid.UUID) (*domain.KYCCase, error) {
+	opts := options.FindOne().SetSort(bson.M{"submitted_at": -1})
+
+	var c domain.KYCCase
+	err := r.coll.FindOne(ctx, bson.M{"user_id": userID}, opts).Decode(&c)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &c, nil
+}
+
+// List retrieves a paginated list of cases, oldest submission first so the
+// queue is worked in arrival order
+func (r *MongoKYCCaseRepository) List(ctx context.Context, offset, limit int, filters KYCFilters) ([]*domain.KYCCase, int, error) {
+	filter := bson.M{}
+
+	if filters.Status != nil {
+		filter["status"] = *filters.Status
+	}
+
+	if filters.UserID != nil {
+		filter["user_id"] = *filters.UserID
+	}
+
+	if filters.Role != nil {
+		filter["$or"] = bson.A{
+			bson.M{"user_roles": *filters.Role},
+			bson.M{"user_role": *filters.Role},
+		}
+	}
+
+	if filters.DocumentType != "" {
+		filter["documents.type"] = filters.DocumentType
+	}
+
+	if filters.SubmittedFrom != nil || filters.SubmittedTo != nil {
+		submitted := bson.M{}
+		if filters.SubmittedFrom != nil {
+			submitted["$gte"] = *filters.SubmittedFrom
+		}
+		if filters.SubmittedTo != nil {
+			submitted["$lt"] = *filters.SubmittedTo
+		}
+		filter["submitted_at"] = submitted
+	}
+
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"submitted_at": 1}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var cases []*domain.KYCCase
+	if err := cursor.All(ctx, &cases); err != nil {
+		return nil, 0, err
+	}
+
+	return cases, int(total), nil
+}
+
+// SaveDecision stores a reviewer decision if the case is still pending.
+// Returns ErrKYCCaseAlreadyReviewed if another reviewer decided first.
+func (r *MongoKYCCaseRepository) SaveDecision(ctx context.Context, c *domain.KYCCase) error {
+	filter := bson.M{"_id": c.ID, "status": domain.KYCStatusPending}
+	update := bson.M{
+		"$set": bson.M{
+			"status":           c.Status,
+			"reviewed_by":      c.ReviewedBy,
+			"reviewed_at":      c.ReviewedAt,
+			"rejection_reason": c.RejectionReason,
+			"history":          c.History,
+			"updated_at":       c.UpdatedAt,
+		},
+	}
+
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrKYCCaseAlreadyReviewed
+	}
+	return nil
+}
+
+// ListByUser retrieves all of a user's cases, oldest first
+func (r *MongoKYCCaseRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.KYCCase, error) {
+	opts := options.Find().SetSort(bson.M{"submitted_at": 1})
+
+	cursor, err := r.coll.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var cases []*domain.KYCCase
+	if err := cursor.All(ctx, &cases); err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+// AnonymizeByUser removes documents, email and free-text notes from a user's
+// cases. The outcome and reviewer stay so decisions remain auditable.
+func (r *MongoKYCCaseRepository) AnonymizeByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"user_email":         "",
+			"documents":          []domain.KYCDocument{},
+			"rejection_reason":   "",
+			"history.$[].reason": "",
+			"updated_at":         time.Now(),
+		},
+	}
+
+	result, err := r.coll.UpdateMany(ctx, bson.M{"user_id": userID}, update)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoLoginThrottleRepository implements LoginThrottleRepository using MongoDB
+type MongoLoginThrottleRepository struct {
+	coll *mongo.Collection
+}
+
+// NewMongoLoginThrottleRepository creates a new MongoDB login throttle repository
+func NewMongoLoginThrottleRepository(db *mongo.Database) *MongoLoginThrottleRepository {
+	return &MongoLoginThrottleRepository{
+		coll: db.Collection("login_throttles"),
+	}
+}
+
+// Get retrieves a counter by key
+func (r *MongoLoginThrottleRepository) Get(ctx context.Context, key string) (*domain.LoginThrottle, error) {
+	var throttle domain.LoginThrottle
+	err := r.coll.FindOne(ctx, bson.M{"_id": key}).Decode(&throttle)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// RecordFailure atomically increments a counter using an update pipeline so the
+// window check and increment happen in a single write
+func (r *MongoLoginThrottleRepository) RecordFailure(ctx context.Context, kind domain.ThrottleKind, subject string, userID *uuid.UUID, windowStart, decayStart time.Time) (*domain.LoginThrottle, error) {
+	now := time.Now()
+	withinWindow := bson.M{"$gte": bson.A{"$last_failure_at", windowStart}}
+	withinDecay := bson.M{"$gte": bson.A{"$last_failure_at", decayStart}}
+
+	set := bson.M{
+		"kind":    kind,
+		"subject": subject,
+		"failures": bson.M{"$cond": bson.A{
+			withinWindow,
+			bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$failures", 0}}, 1}},
+			1,
+		}},
+		"lockout_count": bson.M{"$cond": bson.A{
+			withinDecay,
+			bson.M{"$ifNull": bson.A{"$lockout_count", 0}},
+			0,
+		}},
+		"last_failure_at": now,
+		"updated_at":      now,
+	}
+	if userID != nil {
+		set["user_id"] = *userID
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+
+	var throttle domain.LoginThrottle
+	key := domain.ThrottleKey(kind, subject)
+	err := r.coll.FindOneAndUpdate(ctx, bson.M{"_id": key}, mongo.Pipeline{{{Key: "$set", Value: set}}}, opts).Decode(&throttle)
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// Lock starts a lockout and clears the failure count
+func (r *MongoLoginThrottleRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	update := bson.M{
+		"$set": bson.M{
+			"locked_until": until,
+			"failures":     0,
+			"updated_at":   time.Now(),
+		},
+		"$inc": bson.M{"lockout_count": 1},
+	}
+
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": key}, update)
+	return err
+}
+
+// Reset clears failures and lockouts for a counter
+func (r *MongoLoginThrottleRepository) Reset(ctx context.Context, key string) error {
+	_, err := r.coll.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
+
+// ListLocked retrieves counters of a kind whose lockout is still active
+func (r *MongoLoginThrottleRepository) ListLocked(ctx context.Context, kind domain.ThrottleKind, now time.Time, offset, limit int) ([]*domain.LoginThrottle, int, error) {
+	filter := bson.M{
+		"kind":         kind,
+		"locked_until": bson.M{"$gt": now},
+	}
+
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.M{"locked_until": -1}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var throttles []*domain.LoginThrottle
+	if err := cursor.All(ctx, &throttles); err != nil {
+		return nil, 0, err
+	}
+
+	return throttles, int(total), nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// MongoOIDCStateRepository implements OIDCStateRepository using MongoDB
+type MongoOIDCStateRepository struct {
+	coll *mongo.Collection
+}
+
+// NewMongoOIDCStateRepository creates a new MongoDB login state repository
+func NewMongoOIDCStateRepository(db *mongo.Database) *MongoOIDCStateRepository {
+	return &MongoOIDCStateRepository{
+		coll: db.Collection("oidc_states"),
+	}
+}
+
+// Create stores a new login state
+func (r *MongoOIDCStateRepository) Create(ctx context.Context, state *domain.OIDCLoginState) error {
+	_, err := r.coll.InsertOne(ctx, state)
+	return err
+}
+
+// Consume atomically retrieves and deletes a login state.
+// Returns ErrInvalidOIDCState if it does not exist or has expired.
+func (r *MongoOIDCStateRepository) Consume(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error) {
+	var state domain.OIDCLoginState
+	err := r.coll.FindOneAndDelete(ctx, bson.M{"_id": stateHash}).Decode(&state)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrInvalidOIDCState
+		}
+		return nil, err
+	}
+	if state.IsExpired() {
+		return nil, domain.ErrInvalidOIDCState
+	}
+	return &state, nil
+}
+
+// MongoExternalIdentityRepository implements ExternalIdentityRepository using MongoDB
+type MongoExternalIdentityRepository struct {
+	coll *mongo.Collection
+}
+
+// NewMongoExternalIdentityRepository creates a new MongoDB external identity repository
+func NewMongoExternalIdentityRepository(db *mongo.Database) *MongoExternalIdentityRepository {
+	return &MongoExternalIdentityRepository{
+		coll: db.Collection("external_identities"),
+	}
+}
+
+// Create links a provider subject to a user
+func (r *MongoExternalIdentityRepository) Create(ctx context.Context, identity *domain.ExternalIdentity) error {
+	_, err := r.coll.InsertOne(ctx, identity)
+	return err
+}
+
+// GetByProviderSubject retrieves the link for a provider subject (nil if none exists)
+func (r *MongoExternalIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.ExternalIdentity, error) {
+	var identity domain.ExternalIdentity
+	err := r.coll.FindOne(ctx, bson.M{"provider": provider, "subject": subject}).Decode(&identity)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// TouchLogin records a login through the linked identity
+func (r *MongoExternalIdentityRepository) TouchLogin(ctx context.Context, id uuid.UUID, email string) error {
+	update := bson.M{"$set": bson.M{"email": email, "last_login_at": time.Now()}}
+	_, err := r.coll.UpdateByID(ctx, id, update)
+	return err
+}
+
+// ListByUser retrieves all provider accounts linked to a user
+func (r *MongoExternalIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.ExternalIdentity, error) {
+	cursor, err := r.coll.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var identities []*domain.ExternalIdentity
+	if err := cursor.All(ctx, &identities); err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+// DeleteByUser unlinks all provider accounts from a user
+func (r *MongoExternalIdentityRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	result, err := r.coll.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.DeletedCount), nil
+}
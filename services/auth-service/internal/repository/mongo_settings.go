@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rentalflow/auth-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mfaPolicyID is the document ID of the two-factor policy
+const mfaPolicyID = "mfa_policy"
+
+// MongoSettingsRepository implements SettingsRepository using MongoDB
+type MongoSettingsRepository struct {
+	coll *mongo.Collection
+}
+
+// NewMongoSettingsRepository creates a new MongoDB settings repository
+func NewMongoSettingsRepository(db *mongo.Database) *MongoSettingsRepository {
+	return &MongoSettingsRepository{
+		coll: db.Collection("auth_settings"),
+	}
+}
+
+// GetMFAPolicy retrieves the two-factor policy
+func (r *MongoSettingsRepository) GetMFAPolicy(ctx context.Context) (*domain.MFAPolicy, error) {
+	var policy domain.MFAPolicy
+	err := r.coll.FindOne(ctx, bson.M{"_id": mfaPolicyID}).Decode(&policy)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return &domain.MFAPolicy{RequiredRoles: []domain.UserRole{}}, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SaveMFAPolicy stores the two-factor policy
+func (r *MongoSettingsRepository) SaveMFAPolicy(ctx context.Context, policy *domain.MFAPolicy) error {
+	update := bson.M{
+		"$set": bson.M{
+			"required_roles": policy.RequiredRoles,
+			"updated_by":     policy.UpdatedBy,
+			"updated_at":     policy.UpdatedAt,
+		},
+	}
+
+	_, err := r.coll.UpdateOne(ctx, bson.M{"_id": mfaPolicyID}, update, options.Update().SetUpsert(true))
+	return err
+}
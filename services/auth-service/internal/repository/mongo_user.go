@@ -67,15 +67,27 @@ func (r *MongoUserRepository) Update(ctx context.Context, user *domain.User) err
 	update := bson.M{
 		"$set": bson.M{
 			"email":                    user.Email,
+			"email_verified":           user.EmailVerified,
+			"email_verified_at":        user.EmailVerifiedAt,
 			"password_hash":            user.PasswordHash,
 			"first_name":               user.FirstName,
 			"last_name":                user.LastName,
 			"phone":                    user.Phone,
+			"bio":                      user.Bio,
+			"avatar_url":               user.AvatarURL,
 			"role":                     user.Role,
+			"roles":                    user.RoleList(),
 			"identity_verified":        user.IdentityVerified,
 			"verification_status":      user.VerificationStatus,
 			"refresh_token_hash":       user.RefreshTokenHash,
 			"refresh_token_expires_at": user.RefreshTokenExpiresAt,
+			"mfa_enabled":              user.MFAEnabled,
+			"mfa_enabled_at":           user.MFAEnabledAt,
+			"mfa_secret":               user.MFASecret,
+			"mfa_pending_secret":       user.MFAPendingSecret,
+			"mfa_recovery_codes":       user.MFARecoveryCodes,
+			"mfa_last_used_step":       user.MFALastUsedStep,
+			"deleted_at":               user.DeletedAt,
 			"updated_at":               time.Now(),
 		},
 	}
@@ -111,7 +123,11 @@ func (r *MongoUserRepository) List(ctx context.Context, offset, limit int, filte
 	filter := bson.M{}
 
 	if filters.Role != nil {
-		filter["role"] = *filters.Role
+		// Older documents only carry the primary role
+		filter["$or"] = bson.A{
+			bson.M{"roles": *filters.Role},
+			bson.M{"role": *filters.Role},
+		}
 	}
 
 	if filters.VerificationStatus != nil {
@@ -170,3 +186,51 @@ func (r *MongoUserRepository) UpdateRefreshToken(ctx context.Context, userID uui
 func (r *MongoUserRepository) ClearRefreshToken(ctx context.Context, userID uuid.UUID) error {
 	return r.UpdateRefreshToken(ctx, userID, "", nil)
 }
+
+// RecordMFAStep records a used TOTP time step only if it is newer than the last one
+func (r *MongoUserRepository) RecordMFAStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	filter := bson.M{
+		"_id":                userID,
+		"mfa_last_used_step": bson.M{"$lt": step},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"mfa_last_used_step": step,
+			"updated_at":         time.Now(),
+		},
+	}
+
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// ConsumeRecoveryCode removes a recovery code hash if it is still present
+func (r *MongoUserRepository) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	filter := bson.M{
+		"_id":                userID,
+		"mfa_recovery_codes": codeHash,
+	}
+	update := bson.M{
+		"$pull": bson.M{"mfa_recovery_codes": codeHash},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.coll.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
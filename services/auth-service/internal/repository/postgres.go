@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// sqlFilter builds the WHERE condition of a PostgreSQL list query
+type sqlFilter struct {
+	conditions []string
+	args       []interface{}
+}
+
+// add appends a condition in which %d stands for the number of arg. Every
+// %d is the same argument.
+func (f *sqlFilter) add(condition string, arg interface{}) {
+	f.args = append(f.args, arg)
+	n := len(f.args)
+	f.conditions = append(f.conditions, strings.ReplaceAll(condition, "%d", fmt.Sprint(n)))
+}
+
+// where returns the conjunction of the conditions
+func (f *sqlFilter) where() string {
+	if len(f.conditions) == 0 {
+		return "true"
+	}
+	return strings.Join(f.conditions, " AND ")
+}
+
+// findPage returns a page of the rows of table matching filter, in order,
+// and how many rows match in total
+func findPage[T any](ctx context.Context, conn database.Querier, table, columns string, filter *sqlFilter, order string, offset, limit int, scan func(pgx.Row) (T, error)) ([]T, int, error) {
+	where := filter.where()
+
+	var total int
+	if err := conn.QueryRow(ctx, `SELECT count(*) FROM `+table+` WHERE `+where, filter.args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	n := len(filter.args)
+	rows, err := conn.Query(ctx, fmt.Sprintf(`SELECT %s FROM %s WHERE %s ORDER BY %s OFFSET $%d LIMIT $%d`,
+		columns, table, where, order, n+1, n+2),
+		append(filter.args, offset, limit)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	found, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (T, error) {
+		return scan(row)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return found, total, nil
+}
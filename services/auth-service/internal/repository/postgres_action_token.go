@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// actionTokenColumns lists the columns of the action_tokens table in
+// scanActionToken order
+const actionTokenColumns = `id, user_id, purpose, token_hash, expires_at, used_at, created_at`
+
+// PostgresActionTokenRepository implements ActionTokenRepository using PostgreSQL
+type PostgresActionTokenRepository struct {
+	db *database.Postgres
+}
+
+// NewPostgresActionTokenRepository creates a new PostgreSQL action token repository
+func NewPostgresActionTokenRepository(db *database.Postgres) *PostgresActionTokenRepository {
+	return &PostgresActionTokenRepository{db: db}
+}
+
+// Create stores a new action token
+func (r *PostgresActionTokenRepository) Create(ctx context.Context, token *domain.ActionToken) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO action_tokens (`+actionTokenColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		token.ID, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.UsedAt, token.CreatedAt,
+	)
+	return err
+}
+
+// GetByHash retrieves a token by its hash and purpose
+func (r *PostgresActionTokenRepository) GetByHash(ctx context.Context, tokenHash string, purpose domain.TokenPurpose) (*domain.ActionToken, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+actionTokenColumns+` FROM action_tokens
+		WHERE token_hash = $1 AND purpose = $2 LIMIT 1`,
+		tokenHash, purpose,
+	)
+	var token domain.ActionToken
+	err := row.Scan(&token.ID, &token.UserID, &token.Purpose, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvalidActionToken
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed atomically marks an unused token as used.
+// Returns ErrInvalidActionToken if the token was already redeemed.
+func (r *PostgresActionTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE action_tokens SET used_at = $2 WHERE id = $1 AND used_at IS NULL`,
+		id, time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrInvalidActionToken
+	}
+	return nil
+}
+
+// InvalidateForUser marks all unused tokens of a purpose for a user as used
+func (r *PostgresActionTokenRepository) InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose domain.TokenPurpose) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `UPDATE action_tokens SET used_at = $3
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`,
+		userID, purpose, time.Now(),
+	)
+	return err
+}
+
+// DeleteByUser removes all tokens issued to a user
+func (r *PostgresActionTokenRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `DELETE FROM action_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
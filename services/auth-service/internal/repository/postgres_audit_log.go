@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// auditEventColumns lists the columns of the auth_audit_log table in
+// scanAuditEvent order
+const auditEventColumns = `id, type, user_id, email, ip_address, user_agent, reason, actor_id, created_at`
+
+// PostgresAuditLogRepository implements AuditLogRepository using PostgreSQL
+type PostgresAuditLogRepository struct {
+	db *database.Postgres
+}
+
+// NewPostgresAuditLogRepository creates a new PostgreSQL audit log repository
+func NewPostgresAuditLogRepository(db *database.Postgres) *PostgresAuditLogRepository {
+	return &PostgresAuditLogRepository{db: db}
+}
+
+// Create appends an event to the audit log
+func (r *PostgresAuditLogRepository) Create(ctx context.Context, event *domain.AuthAuditEvent) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO auth_audit_log (`+auditEventColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		event.ID, event.Type, event.UserID, event.Email, event.IPAddress, event.UserAgent, event.Reason, event.ActorID, event.CreatedAt,
+	)
+	return err
+}
+
+// List retrieves a paginated list of events, newest first
+func (r *PostgresAuditLogRepository) List(ctx context.Context, offset, limit int, filters AuditFilters) ([]*domain.AuthAuditEvent, int, error) {
+	var filter sqlFilter
+
+	if filters.UserID != nil {
+		filter.add("user_id = $%d", *filters.UserID)
+	}
+
+	if filters.Email != "" {
+		filter.add("email = $%d", filters.Email)
+	}
+
+	if filters.Type != nil {
+		filter.add("type = $%d", *filters.Type)
+	}
+
+	return findPage(ctx, r.db.Conn(ctx), "auth_audit_log", auditEventColumns, &filter, "created_at DESC", offset, limit, scanAuditEvent)
+}
+
+// ListByUser retrieves every event about a user, newest first
+func (r *PostgresAuditLogRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.AuthAuditEvent, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, `SELECT `+auditEventColumns+` FROM auth_audit_log
+		WHERE user_id = $1 ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.AuthAuditEvent, error) {
+		return scanAuditEvent(row)
+	})
+}
+
+// AnonymizeByUser strips the email, IP address and user agent from a user's
+// events. The events themselves are kept as a security record.
+func (r *PostgresAuditLogRepository) AnonymizeByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE auth_audit_log SET email = '', ip_address = '', user_agent = ''
+		WHERE user_id = $1 AND (email <> '' OR ip_address <> '' OR user_agent <> '')`,
+		userID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// scanAuditEvent reads a row of auditEventColumns
+func scanAuditEvent(row pgx.Row) (*domain.AuthAuditEvent, error) {
+	var e domain.AuthAuditEvent
+	err := row.Scan(&e.ID, &e.Type, &e.UserID, &e.Email, &e.IPAddress, &e.UserAgent, &e.Reason, &e.ActorID, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// dataRequestColumns lists the columns of the data_requests table in
+// scanDataRequest order
+const dataRequestColumns = `id, user_id, type, status, steps, attempts, error, next_attempt_at, lease_until,
+	archive_ready, created_at, updated_at, completed_at`
+
+// PostgresDataRequestRepository implements DataRequestRepository using PostgreSQL
+type PostgresDataRequestRepository struct {
+	db *database.Postgres
+}
+
+// NewPostgresDataRequestRepository creates a new PostgreSQL data-subject request repository
+func NewPostgresDataRequestRepository(db *database.Postgres) *PostgresDataRequestRepository {
+	return &PostgresDataRequestRepository{db: db}
+}
+
+// Create queues a new request
+func (r *PostgresDataRequestRepository) Create(ctx context.Context, req *domain.DataRequest) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO data_requests (`+dataRequestColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		req.ID, req.UserID, req.Type, req.Status, req.Steps, req.Attempts, req.Error, req.NextAttemptAt, req.LeaseUntil,
+		req.ArchiveReady, req.CreatedAt, req.UpdatedAt, req.CompletedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a request by ID
+func (r *PostgresDataRequestRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.DataRequest, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+dataRequestColumns+` FROM data_requests WHERE id = $1`, id)
+	req, err := scanDataRequest(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrDataRequestNotFound
+		}
+		return nil, err
+	}
+	return req, nil
+}
+
+// GetActiveByUser retrieves a user's queued or running request of a type (nil if none exists)
+func (r *PostgresDataRequestRepository) GetActiveByUser(ctx context.Context, userID uuid.UUID, requestType domain.DataRequestType) (*domain.DataRequest, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+dataRequestColumns+` FROM data_requests
+		WHERE user_id = $1 AND type = $2 AND status IN ($3, $4) LIMIT 1`,
+		userID, requestType, domain.DataRequestPending, domain.DataRequestRunning,
+	)
+	req, err := scanDataRequest(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return req, nil
+}
+
+// List retrieves a paginated list of requests, newest first
+func (r *PostgresDataRequestRepository) List(ctx context.Context, offset, limit int, filters DataRequestFilters) ([]*domain.DataRequest, int, error) {
+	var filter sqlFilter
+
+	if filters.UserID != nil {
+		filter.add("user_id = $%d", *filters.UserID)
+	}
+
+	if filters.Type != nil {
+		filter.add("type = $%d", *filters.Type)
+	}
+
+	if filters.Status != nil {
+		filter.add("status = $%d", *filters.Status)
+	}
+
+	return findPage(ctx, r.db.Conn(ctx), "data_requests", dataRequestColumns, &filter, "created_at DESC", offset, limit, scanDataRequest)
+}
+
+// ClaimNext atomically leases the oldest request that is due (nil if none exists).
+// Running requests whose lease has expired, e.g. after a crash, are claimed again.
+// Rows another worker is claiming are skipped rather than waited for.
+func (r *PostgresDataRequestRepository) ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.DataRequest, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `UPDATE data_requests SET
+			status = $2, lease_until = $4, updated_at = $3, attempts = attempts + 1
+		WHERE id = (
+			SELECT id FROM data_requests
+			WHERE (status = $1 AND next_attempt_at <= $3) OR (status = $2 AND lease_until < $3)
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+dataRequestColumns,
+		domain.DataRequestPending, domain.DataRequestRunning, now, leaseUntil,
+	)
+	req, err := scanDataRequest(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return req, nil
+}
+
+// Save stores the progress of a claimed request
+func (r *PostgresDataRequestRepository) Save(ctx context.Context, req *domain.DataRequest) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `UPDATE data_requests SET
+			user_id = $2, type = $3, status = $4, steps = $5, attempts = $6, error = $7, next_attempt_at = $8,
+			lease_until = $9, archive_ready = $10, created_at = $11, updated_at = $12, completed_at = $13
+		WHERE id = $1`,
+		req.ID, req.UserID, req.Type, req.Status, req.Steps, req.Attempts, req.Error, req.NextAttemptAt,
+		req.LeaseUntil, req.ArchiveReady, req.CreatedAt, req.UpdatedAt, req.CompletedAt,
+	)
+	return err
+}
+
+// scanDataRequest reads a row of dataRequestColumns
+func scanDataRequest(row pgx.Row) (*domain.DataRequest, error) {
+	var req domain.DataRequest
+	err := row.Scan(
+		&req.ID, &req.UserID, &req.Type, &req.Status, &req.Steps, &req.Attempts, &req.Error, &req.NextAttemptAt, &req.LeaseUntil,
+		&req.ArchiveReady, &req.CreatedAt, &req.UpdatedAt, &req.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// PostgresDataExportArchiveRepository implements DataExportArchiveRepository using PostgreSQL
+type PostgresDataExportArchiveRepository struct {
+	db *database.Postgres
+}
+
+// NewPostgresDataExportArchiveRepository creates a new PostgreSQL export archive repository
+func NewPostgresDataExportArchiveRepository(db *database.Postgres) *PostgresDataExportArchiveRepository {
+	return &PostgresDataExportArchiveRepository{db: db}
+}
+
+// Save stores an archive, replacing any earlier one for the request
+func (r *PostgresDataExportArchiveRepository) Save(ctx context.Context, archive *domain.DataExportArchive) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO data_export_archives (request_id, user_id, data, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (request_id) DO UPDATE SET
+			user_id = EXCLUDED.user_id, data = EXCLUDED.data, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at`,
+		archive.RequestID, archive.UserID, archive.Data, archive.CreatedAt, archive.ExpiresAt,
+	)
+	return err
+}
+
+// GetByRequestID retrieves the archive of an export request
+func (r *PostgresDataExportArchiveRepository) GetByRequestID(ctx context.Context, requestID uuid.UUID) (*domain.DataExportArchive, error) {
+	var archive domain.DataExportArchive
+	err := r.db.Conn(ctx).QueryRow(ctx, `SELECT request_id, user_id, data, created_at, expires_at
+		FROM data_export_archives WHERE request_id = $1`,
+		requestID,
+	).Scan(&archive.RequestID, &archive.UserID, &archive.Data, &archive.CreatedAt, &archive.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrDataExportNotFound
+		}
+		return nil, err
+	}
+	return &archive, nil
+}
+
+// DeleteExpired removes archives past their download window
+func (r *PostgresDataExportArchiveRepository) DeleteExpired(ctx context.Context, now time.Time) (int, error) {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `DELETE FROM data_export_archives WHERE expires_at < $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// kycCaseColumns lists the columns of the kyc_cases table in scanKYCCase order
+const kycCaseColumns = `id, user_id, user_email, user_role, user_roles, status, documents, attempt,
+	previous_case_id, reviewed_by, reviewed_at, rejection_reason, history, submitted_at, updated_at`
+
+// PostgresKYCCaseRepository implements KYCCaseRepository using PostgreSQL
+type PostgresKYCCaseRepository struct {
+	db *database.Postgres
+}
+
+// NewPostgresKYCCaseRepository creates a new PostgreSQL verification case repository
+func NewPostgresKYCCaseRepository(db *database.Postgres) *PostgresKYCCaseRepository {
+	return &PostgresKYCCaseRepository{db: db}
+}
+
+// Create stores a new case
+func (r *PostgresKYCCaseRepository) Create(ctx context.Context, c *domain.KYCCase) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO kyc_cases (`+kycCaseColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		c.ID, c.UserID, c.UserEmail, c.UserRole, c.UserRoles, c.Status, c.Documents, c.Attempt,
+		c.PreviousCaseID, c.ReviewedBy, c.ReviewedAt, c.RejectionReason, c.History, c.SubmittedAt, c.UpdatedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a case by ID
+func (r *PostgresKYCCaseRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.KYCCase, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+kycCaseColumns+` FROM kyc_cases WHERE id = $1`, id)
+	c, err := scanKYCCase(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrKYCCaseNotFound
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetLatestByUser retrieves a user's most recent case (nil if none exists)
+func (r *PostgresKYCCaseRepository) GetLatestByUser(ctx context.Context, userID uuid.UUID) (*domain.KYCCase, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+kycCaseColumns+` FROM kyc_cases
+		WHERE user_id = $1 ORDER BY submitted_at DESC LIMIT 1`,
+		userID,
+	)
+	c, err := scanKYCCase(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// List retrieves a paginated list of cases, oldest submission first so the
+// queue is worked in arrival order
+func (r *PostgresKYCCaseRepository) List(ctx context.Context, offset, limit int, filters KYCFilters) ([]*domain.KYCCase, int, error) {
+	var filter sqlFilter
+
+	if filters.Status != nil {
+		filter.add("status = $%d", *filters.Status)
+	}
+
+	if filters.UserID != nil {
+		filter.add("user_id = $%d", *filters.UserID)
+	}
+
+	if filters.Role != nil {
+		filter.add("($%d = ANY(user_roles) OR user_role = $%d)", *filters.Role)
+	}
+
+	if filters.DocumentType != "" {
+		filter.add("documents @> jsonb_build_array(jsonb_build_object('type', $%d::text))", filters.DocumentType)
+	}
+
+	if filters.SubmittedFrom != nil {
+		filter.add("submitted_at >= $%d", *filters.SubmittedFrom)
+	}
+
+	if filters.SubmittedTo != nil {
+		filter.add("submitted_at < $%d", *filters.SubmittedTo)
+	}
+
+	return findPage(ctx, r.db.Conn(ctx), "kyc_cases", kycCaseColumns, &filter, "submitted_at", offset, limit, scanKYCCase)
+}
+
+// SaveDecision stores a reviewer decision if the case is still pending.
+// Returns ErrKYCCaseAlreadyReviewed if another reviewer decided first.
+func (r *PostgresKYCCaseRepository) SaveDecision(ctx context.Context, c *domain.KYCCase) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE kyc_cases SET
+			status = $3, reviewed_by = $4, reviewed_at = $5, rejection_reason = $6, history = $7, updated_at = $8
+		WHERE id = $1 AND status = $2`,
+		c.ID, domain.KYCStatusPending,
+		c.Status, c.ReviewedBy, c.ReviewedAt, c.RejectionReason, c.History, c.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrKYCCaseAlreadyReviewed
+	}
+	return nil
+}
+
+// ListByUser retrieves all of a user's cases, oldest first
+func (r *PostgresKYCCaseRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.KYCCase, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, `SELECT `+kycCaseColumns+` FROM kyc_cases
+		WHERE user_id = $1 ORDER BY submitted_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.KYCCase, error) {
+		return scanKYCCase(row)
+	})
+}
+
+// AnonymizeByUser removes documents, email and free-text notes from a user's
+// cases. The outcome and reviewer stay so decisions remain auditable.
+func (r *PostgresKYCCaseRepository) AnonymizeByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE kyc_cases SET
+			user_email = '', documents = '[]', rejection_reason = '',
+			history = COALESCE((
+				SELECT jsonb_agg(entry - 'reason' ORDER BY n)
+				FROM jsonb_array_elements(history) WITH ORDINALITY AS h(entry, n)
+			), history),
+			updated_at = $2
+		WHERE user_id = $1`,
+		userID, time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// scanKYCCase reads a row of kycCaseColumns
+func scanKYCCase(row pgx.Row) (*domain.KYCCase, error) {
+	var c domain.KYCCase
+	err := row.Scan(
+		&c.ID, &c.UserID, &c.UserEmail, &c.UserRole, &c.UserRoles, &c.Status, &c.Documents, &c.Attempt,
+		&c.PreviousCaseID, &c.ReviewedBy, &c.ReviewedAt, &c.RejectionReason, &c.History, &c.SubmittedAt, &c.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
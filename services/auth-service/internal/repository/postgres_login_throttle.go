@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// loginThrottleColumns lists the columns of the login_throttles table in
+// scanLoginThrottle order
+const loginThrottleColumns = `key, kind, subject, user_id, failures, lockout_count, locked_until,
+	last_failure_at, updated_at`
+
+// PostgresLoginThrottleRepository implements LoginThrottleRepository using PostgreSQL
+type PostgresLoginThrottleRepository struct {
+	db *database.Postgres
+}
+
+// NewPostgresLoginThrottleRepository creates a new PostgreSQL login throttle repository
+func NewPostgresLoginThrottleRepository(db *database.Postgres) *PostgresLoginThrottleRepository {
+	return &PostgresLoginThrottleRepository{db: db}
+}
+
+// Get retrieves a counter by key
+func (r *PostgresLoginThrottleRepository) Get(ctx context.Context, key string) (*domain.LoginThrottle, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+loginThrottleColumns+` FROM login_throttles WHERE key = $1`, key)
+	throttle, err := scanLoginThrottle(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return throttle, nil
+}
+
+// RecordFailure atomically increments a counter with an upsert so the window
+// check and increment happen in a single write
+func (r *PostgresLoginThrottleRepository) RecordFailure(ctx context.Context, kind domain.ThrottleKind, subject string, userID *uuid.UUID, windowStart, decayStart time.Time) (*domain.LoginThrottle, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `INSERT INTO login_throttles AS t
+			(key, kind, subject, user_id, failures, lockout_count, last_failure_at, updated_at)
+		VALUES ($1, $2, $3, $4, 1, 0, $7, $7)
+		ON CONFLICT (key) DO UPDATE SET
+			kind = EXCLUDED.kind,
+			subject = EXCLUDED.subject,
+			user_id = COALESCE(EXCLUDED.user_id, t.user_id),
+			failures = CASE WHEN t.last_failure_at >= $5 THEN t.failures + 1 ELSE 1 END,
+			lockout_count = CASE WHEN t.last_failure_at >= $6 THEN t.lockout_count ELSE 0 END,
+			last_failure_at = EXCLUDED.last_failure_at,
+			updated_at = EXCLUDED.updated_at
+		RETURNING `+loginThrottleColumns,
+		domain.ThrottleKey(kind, subject), kind, subject, userID, windowStart, decayStart, time.Now(),
+	)
+	return scanLoginThrottle(row)
+}
+
+// Lock starts a lockout and clears the failure count
+func (r *PostgresLoginThrottleRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `UPDATE login_throttles SET
+			locked_until = $2, failures = 0, lockout_count = lockout_count + 1, updated_at = $3
+		WHERE key = $1`,
+		key, until, time.Now(),
+	)
+	return err
+}
+
+// Reset clears failures and lockouts for a counter
+func (r *PostgresLoginThrottleRepository) Reset(ctx context.Context, key string) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `DELETE FROM login_throttles WHERE key = $1`, key)
+	return err
+}
+
+// ListLocked retrieves counters of a kind whose lockout is still active
+func (r *PostgresLoginThrottleRepository) ListLocked(ctx context.Context, kind domain.ThrottleKind, now time.Time, offset, limit int) ([]*domain.LoginThrottle, int, error) {
+	var filter sqlFilter
+	filter.add("kind = $%d", kind)
+	filter.add("locked_until > $%d", now)
+
+	return findPage(ctx, r.db.Conn(ctx), "login_throttles", loginThrottleColumns, &filter, "locked_until DESC", offset, limit, scanLoginThrottle)
+}
+
+// scanLoginThrottle reads a row of loginThrottleColumns
+func scanLoginThrottle(row pgx.Row) (*domain.LoginThrottle, error) {
+	var t domain.LoginThrottle
+	err := row.Scan(
+		&t.Key, &t.Kind, &t.Subject, &t.UserID, &t.Failures, &t.LockoutCount, &t.LockedUntil,
+		&t.LastFailureAt, &t.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
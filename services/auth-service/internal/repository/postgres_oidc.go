@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// PostgresOIDCStateRepository implements OIDCStateRepository using PostgreSQL
+type PostgresOIDCStateRepository struct {
+	db *database.Postgres
+}
+
+// NewPostgresOIDCStateRepository creates a new PostgreSQL login state repository
+func NewPostgresOIDCStateRepository(db *database.Postgres) *PostgresOIDCStateRepository {
+	return &PostgresOIDCStateRepository{db: db}
+}
+
+// Create stores a new login state. PostgreSQL has no TTL index, so abandoned
+// states that have expired are swept first.
+func (r *PostgresOIDCStateRepository) Create(ctx context.Context, state *domain.OIDCLoginState) error {
+	conn := r.db.Conn(ctx)
+
+	if _, err := conn.Exec(ctx, `DELETE FROM oidc_states WHERE expires_at < $1`, time.Now()); err != nil {
+		return err
+	}
+
+	_, err := conn.Exec(ctx, `INSERT INTO oidc_states (state_hash, provider, nonce, code_verifier, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		state.StateHash, state.Provider, state.Nonce, state.CodeVerifier, state.ExpiresAt, state.CreatedAt,
+	)
+	return err
+}
+
+// Consume atomically retrieves and deletes a login state.
+// Returns ErrInvalidOIDCState if it does not exist or has expired.
+func (r *PostgresOIDCStateRepository) Consume(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error) {
+	var state domain.OIDCLoginState
+	err := r.db.Conn(ctx).QueryRow(ctx, `DELETE FROM oidc_states WHERE state_hash = $1
+		RETURNING state_hash, provider, nonce, code_verifier, expires_at, created_at`,
+		stateHash,
+	).Scan(&state.StateHash, &state.Provider, &state.Nonce, &state.CodeVerifier, &state.ExpiresAt, &state.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrInvalidOIDCState
+		}
+		return nil, err
+	}
+	if state.IsExpired() {
+		return nil, domain.ErrInvalidOIDCState
+	}
+	return &state, nil
+}
+
+// externalIdentityColumns lists the columns of the external_identities table
+// in scanExternalIdentity order
+const externalIdentityColumns = `id, user_id, provider, subject, email, created_at, last_login_at`
+
+// PostgresExternalIdentityRepository implements ExternalIdentityRepository using PostgreSQL
+type PostgresExternalIdentityRepository struct {
+	db *database.Postgres
+}
+
+// NewPostgresExternalIdentityRepository creates a new PostgreSQL external identity repository
+func NewPostgresExternalIdentityRepository(db *database.Postgres) *PostgresExternalIdentityRepository {
+	return &PostgresExternalIdentityRepository{db: db}
+}
+
+// Create links a provider subject to a user
+func (r *PostgresExternalIdentityRepository) Create(ctx context.Context, identity *domain.ExternalIdentity) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO external_identities (`+externalIdentityColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		identity.ID, identity.UserID, identity.Provider, identity.Subject, identity.Email, identity.CreatedAt, identity.LastLoginAt,
+	)
+	return err
+}
+
+// GetByProviderSubject retrieves the link for a provider subject (nil if none exists)
+func (r *PostgresExternalIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.ExternalIdentity, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+externalIdentityColumns+` FROM external_identities
+		WHERE provider = $1 AND subject = $2`,
+		provider, subject,
+	)
+	identity, err := scanExternalIdentity(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+// TouchLogin records a login through the linked identity
+func (r *PostgresExternalIdentityRepository) TouchLogin(ctx context.Context, id uuid.UUID, email string) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `UPDATE external_identities SET email = $2, last_login_at = $3 WHERE id = $1`,
+		id, email, time.Now(),
+	)
+	return err
+}
+
+// ListByUser retrieves all provider accounts linked to a user
+func (r *PostgresExternalIdentityRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.ExternalIdentity, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, `SELECT `+externalIdentityColumns+` FROM external_identities
+		WHERE user_id = $1 ORDER BY created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.ExternalIdentity, error) {
+		return scanExternalIdentity(row)
+	})
+}
+
+// DeleteByUser unlinks all provider accounts from a user
+func (r *PostgresExternalIdentityRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `DELETE FROM external_identities WHERE user_id = $1`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// scanExternalIdentity reads a row of externalIdentityColumns
+func scanExternalIdentity(row pgx.Row) (*domain.ExternalIdentity, error) {
+	var identity domain.ExternalIdentity
+	err := row.Scan(
+		&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.Email,
+		&identity.CreatedAt, &identity.LastLoginAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
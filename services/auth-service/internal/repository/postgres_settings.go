@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// PostgresSettingsRepository implements SettingsRepository using PostgreSQL
+type PostgresSettingsRepository struct {
+	db *database.Postgres
+}
+
+// NewPostgresSettingsRepository creates a new PostgreSQL settings repository
+func NewPostgresSettingsRepository(db *database.Postgres) *PostgresSettingsRepository {
+	return &PostgresSettingsRepository{db: db}
+}
+
+// GetMFAPolicy retrieves the two-factor policy
+func (r *PostgresSettingsRepository) GetMFAPolicy(ctx context.Context) (*domain.MFAPolicy, error) {
+	var policy domain.MFAPolicy
+	err := r.db.Conn(ctx).QueryRow(ctx, `SELECT required_roles, updated_by, updated_at FROM auth_settings WHERE id = $1`,
+		mfaPolicyID,
+	).Scan(&policy.RequiredRoles, &policy.UpdatedBy, &policy.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &domain.MFAPolicy{RequiredRoles: []domain.UserRole{}}, nil
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SaveMFAPolicy stores the two-factor policy
+func (r *PostgresSettingsRepository) SaveMFAPolicy(ctx context.Context, policy *domain.MFAPolicy) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO auth_settings (id, required_roles, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET
+			required_roles = EXCLUDED.required_roles, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at`,
+		mfaPolicyID, policy.RequiredRoles, policy.UpdatedBy, policy.UpdatedAt,
+	)
+	return err
+}
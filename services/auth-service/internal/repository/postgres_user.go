@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// userColumns lists the columns of the users table in scanUser order
+const userColumns = `id, email, email_verified, email_verified_at, password_hash, first_name, last_name,
+	phone, bio, avatar_url, role, roles, identity_verified, verification_status,
+	refresh_token_hash, refresh_token_expires_at, mfa_enabled, mfa_enabled_at, mfa_secret,
+	mfa_pending_secret, mfa_recovery_codes, mfa_last_used_step, deleted_at, created_at, updated_at`
+
+// PostgresUserRepository implements UserRepository using PostgreSQL
+type PostgresUserRepository struct {
+	db *database.Postgres
+}
+
+// NewPostgresUserRepository creates a new PostgreSQL user repository
+func NewPostgresUserRepository(db *database.Postgres) *PostgresUserRepository {
+	return &PostgresUserRepository{db: db}
+}
+
+// Create creates a new user
+func (r *PostgresUserRepository) Create(ctx context.Context, user *domain.User) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO users (`+userColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+			$21, $22, $23, $24, $25)`,
+		user.ID, user.Email, user.EmailVerified, user.EmailVerifiedAt, user.PasswordHash, user.FirstName, user.LastName,
+		user.Phone, user.Bio, user.AvatarURL, user.Role, user.Roles, user.IdentityVerified, user.VerificationStatus,
+		user.RefreshTokenHash, user.RefreshTokenExpiresAt, user.MFAEnabled, user.MFAEnabledAt, user.MFASecret,
+		user.MFAPendingSecret, user.MFARecoveryCodes, user.MFALastUsedStep, user.DeletedAt, user.CreatedAt, user.UpdatedAt,
+	)
+	if err != nil {
+		if database.IsUniqueViolation(err) {
+			return domain.ErrUserAlreadyExists
+		}
+		return err
+	}
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *PostgresUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.get(ctx, `id = $1`, id)
+}
+
+// GetByEmail retrieves a user by email
+func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+	return r.get(ctx, `email = $1`, email)
+}
+
+// get retrieves the user matching where
+func (r *PostgresUserRepository) get(ctx context.Context, where string, arg interface{}) (*domain.User, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE `+where, arg)
+	user, err := scanUser(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// Update updates a user
+func (r *PostgresUserRepository) Update(ctx context.Context, user *domain.User) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE users SET
+			email = $2, email_verified = $3, email_verified_at = $4, password_hash = $5, first_name = $6,
+			last_name = $7, phone = $8, bio = $9, avatar_url = $10, role = $11, roles = $12,
+			identity_verified = $13, verification_status = $14, refresh_token_hash = $15,
+			refresh_token_expires_at = $16, mfa_enabled = $17, mfa_enabled_at = $18, mfa_secret = $19,
+			mfa_pending_secret = $20, mfa_recovery_codes = $21, mfa_last_used_step = $22, deleted_at = $23,
+			updated_at = $24
+		WHERE id = $1`,
+		user.ID, user.Email, user.EmailVerified, user.EmailVerifiedAt, user.PasswordHash, user.FirstName,
+		user.LastName, user.Phone, user.Bio, user.AvatarURL, user.Role, user.RoleList(),
+		user.IdentityVerified, user.VerificationStatus, user.RefreshTokenHash,
+		user.RefreshTokenExpiresAt, user.MFAEnabled, user.MFAEnabledAt, user.MFASecret,
+		user.MFAPendingSecret, user.MFARecoveryCodes, user.MFALastUsedStep, user.DeletedAt,
+		time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// Delete deletes a user
+func (r *PostgresUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `DELETE FROM users WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// List retrieves a paginated list of users
+func (r *PostgresUserRepository) List(ctx context.Context, offset, limit int, filters UserFilters) ([]*domain.User, int, error) {
+	var filter sqlFilter
+
+	if filters.Role != nil {
+		// Older rows only carry the primary role
+		filter.add("($%d = ANY(roles) OR role = $%d)", *filters.Role)
+	}
+
+	if filters.VerificationStatus != nil {
+		filter.add("verification_status = $%d", *filters.VerificationStatus)
+	}
+
+	return findPage(ctx, r.db.Conn(ctx), "users", userColumns, &filter, "created_at DESC", offset, limit, scanUser)
+}
+
+// UpdateRefreshToken updates the refresh token for a user
+func (r *PostgresUserRepository) UpdateRefreshToken(ctx context.Context, userID uuid.UUID, hash string, expiresAt *time.Time) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE users SET
+			refresh_token_hash = $2, refresh_token_expires_at = $3, updated_at = $4
+		WHERE id = $1`,
+		userID, hash, expiresAt, time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// ClearRefreshToken clears the refresh token for a user
+func (r *PostgresUserRepository) ClearRefreshToken(ctx context.Context, userID uuid.UUID) error {
+	return r.UpdateRefreshToken(ctx, userID, "", nil)
+}
+
+// RecordMFAStep records a used TOTP time step only if it is newer than the last one
+func (r *PostgresUserRepository) RecordMFAStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE users SET mfa_last_used_step = $2, updated_at = $3
+		WHERE id = $1 AND mfa_last_used_step < $2`,
+		userID, step, time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrInvalidMFACode
+	}
+	return nil
+}
+
+// ConsumeRecoveryCode removes a recovery code hash if it is still present
+func (r *PostgresUserRepository) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE users SET
+			mfa_recovery_codes = array_remove(mfa_recovery_codes, $2), updated_at = $3
+		WHERE id = $1 AND $2 = ANY(mfa_recovery_codes)`,
+		userID, codeHash, time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrInvalidMFACode
+	}
+	return nil
+}
+
+// scanUser reads a row of userColumns
+func scanUser(row pgx.Row) (*domain.User, error) {
+	var user domain.User
+	err := row.Scan(
+		&user.ID, &user.Email, &user.EmailVerified, &user.EmailVerifiedAt, &user.PasswordHash, &user.FirstName, &user.LastName,
+		&user.Phone, &user.Bio, &user.AvatarURL, &user.Role, &user.Roles, &user.IdentityVerified, &user.VerificationStatus,
+		&user.RefreshTokenHash, &user.RefreshTokenExpiresAt, &user.MFAEnabled, &user.MFAEnabledAt, &user.MFASecret,
+		&user.MFAPendingSecret, &user.MFARecoveryCodes, &user.MFALastUsedStep, &user.DeletedAt, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// documentColumns lists the columns of the identity_documents table in
+// scanDocument order
+const documentColumns = `id, user_id, document_type, document_url, uploaded_at`
+
+// PostgresDocumentRepository implements DocumentRepository using PostgreSQL
+type PostgresDocumentRepository struct {
+	db *database.Postgres
+}
+
+// NewPostgresDocumentRepository creates a new PostgreSQL document repository
+func NewPostgresDocumentRepository(db *database.Postgres) *PostgresDocumentRepository {
+	return &PostgresDocumentRepository{db: db}
+}
+
+// Create creates a new identity document
+func (r *PostgresDocumentRepository) Create(ctx context.Context, doc *domain.IdentityDocument) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO identity_documents (`+documentColumns+`)
+		VALUES ($1, $2, $3, $4, $5)`,
+		doc.ID, doc.UserID, doc.DocumentType, doc.DocumentURL, doc.UploadedAt,
+	)
+	return err
+}
+
+// GetByID retrieves a document by ID
+func (r *PostgresDocumentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.IdentityDocument, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+documentColumns+` FROM identity_documents WHERE id = $1`, id)
+	doc, err := scanDocument(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrDocumentNotFound
+		}
+		return nil, err
+	}
+	return doc, nil
+}
+
+// GetByUserID retrieves all documents for a user
+func (r *PostgresDocumentRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.IdentityDocument, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, `SELECT `+documentColumns+` FROM identity_documents
+		WHERE user_id = $1 ORDER BY uploaded_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.IdentityDocument, error) {
+		return scanDocument(row)
+	})
+}
+
+// Delete deletes a document
+func (r *PostgresDocumentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `DELETE FROM identity_documents WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDocumentNotFound
+	}
+	return nil
+}
+
+// scanDocument reads a row of documentColumns
+func scanDocument(row pgx.Row) (*domain.IdentityDocument, error) {
+	var doc domain.IdentityDocument
+	if err := row.Scan(&doc.ID, &doc.UserID, &doc.DocumentType, &doc.DocumentURL, &doc.UploadedAt); err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
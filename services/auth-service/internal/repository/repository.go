@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
 )
 
 // UserRepository defines the interface for user data access
@@ -33,6 +34,23 @@ type UserRepository interface {
 
 	// ClearRefreshToken clears the refresh token for a user (logout)
 	ClearRefreshToken(ctx context.Context, userID uuid.UUID) error
+
+	// RecordMFAStep atomically records a used TOTP time step, rejecting replays
+	RecordMFAStep(ctx context.Context, userID uuid.UUID, step int64) error
+
+	// ConsumeRecoveryCode atomically removes a recovery code hash, rejecting reuse
+	ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error
+}
+
+// NewUserRepository returns the UserRepository of the database
+func NewUserRepository(db *database.Backend) UserRepository {
+	if db.Memory {
+		return NewMemoryUserRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresUserRepository(db.Postgres)
+	}
+	return NewMongoUserRepository(db.Mongo.DB)
 }
 
 // UserFilters defines filters for listing users
@@ -55,3 +73,283 @@ type DocumentRepository interface {
 	// Delete deletes a document
 	Delete(ctx context.Context, id uuid.UUID) error
 }
+
+// NewDocumentRepository returns the DocumentRepository of the database
+func NewDocumentRepository(db *database.Backend) DocumentRepository {
+	if db.Memory {
+		return NewMemoryDocumentRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresDocumentRepository(db.Postgres)
+	}
+	return NewMongoDocumentRepository(db.Mongo.DB)
+}
+
+// ActionTokenRepository defines the interface for single-use account token data access
+type ActionTokenRepository interface {
+	// Create stores a new action token
+	Create(ctx context.Context, token *domain.ActionToken) error
+
+	// GetByHash retrieves a token by its hash and purpose
+	GetByHash(ctx context.Context, tokenHash string, purpose domain.TokenPurpose) (*domain.ActionToken, error)
+
+	// MarkUsed atomically marks an unused token as used
+	MarkUsed(ctx context.Context, id uuid.UUID) error
+
+	// InvalidateForUser marks all unused tokens of a purpose for a user as used
+	InvalidateForUser(ctx context.Context, userID uuid.UUID, purpose domain.TokenPurpose) error
+
+	// DeleteByUser removes all tokens issued to a user
+	DeleteByUser(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// NewActionTokenRepository returns the ActionTokenRepository of the database
+func NewActionTokenRepository(db *database.Backend) ActionTokenRepository {
+	if db.Memory {
+		return NewMemoryActionTokenRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresActionTokenRepository(db.Postgres)
+	}
+	return NewMongoActionTokenRepository(db.Mongo.DB)
+}
+
+// SettingsRepository defines the interface for auth-wide settings data access
+type SettingsRepository interface {
+	// GetMFAPolicy retrieves the two-factor policy (empty if never set)
+	GetMFAPolicy(ctx context.Context) (*domain.MFAPolicy, error)
+
+	// SaveMFAPolicy stores the two-factor policy
+	SaveMFAPolicy(ctx context.Context, policy *domain.MFAPolicy) error
+}
+
+// NewSettingsRepository returns the SettingsRepository of the database
+func NewSettingsRepository(db *database.Backend) SettingsRepository {
+	if db.Memory {
+		return NewMemorySettingsRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresSettingsRepository(db.Postgres)
+	}
+	return NewMongoSettingsRepository(db.Mongo.DB)
+}
+
+// LoginThrottleRepository defines the interface for failed-login counter data access
+type LoginThrottleRepository interface {
+	// Get retrieves a counter by key (nil if none exists)
+	Get(ctx context.Context, key string) (*domain.LoginThrottle, error)
+
+	// RecordFailure atomically increments a counter, restarting it if the last
+	// failure is older than windowStart and forgetting past lockouts if older than decayStart
+	RecordFailure(ctx context.Context, kind domain.ThrottleKind, subject string, userID *uuid.UUID, windowStart, decayStart time.Time) (*domain.LoginThrottle, error)
+
+	// Lock starts a lockout and clears the failure count
+	Lock(ctx context.Context, key string, until time.Time) error
+
+	// Reset clears failures and lockouts for a counter
+	Reset(ctx context.Context, key string) error
+
+	// ListLocked retrieves counters of a kind whose lockout is still active
+	ListLocked(ctx context.Context, kind domain.ThrottleKind, now time.Time, offset, limit int) ([]*domain.LoginThrottle, int, error)
+}
+
+// NewLoginThrottleRepository returns the LoginThrottleRepository of the database
+func NewLoginThrottleRepository(db *database.Backend) LoginThrottleRepository {
+	if db.Memory {
+		return NewMemoryLoginThrottleRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresLoginThrottleRepository(db.Postgres)
+	}
+	return NewMongoLoginThrottleRepository(db.Mongo.DB)
+}
+
+// AuditLogRepository defines the interface for auth audit log data access
+type AuditLogRepository interface {
+	// Create appends an event to the audit log
+	Create(ctx context.Context, event *domain.AuthAuditEvent) error
+
+	// List retrieves a paginated list of events, newest first
+	List(ctx context.Context, offset, limit int, filters AuditFilters) ([]*domain.AuthAuditEvent, int, error)
+
+	// ListByUser retrieves every event about a user, newest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.AuthAuditEvent, error)
+
+	// AnonymizeByUser strips the email, IP address and user agent from a user's events
+	AnonymizeByUser(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// NewAuditLogRepository returns the AuditLogRepository of the database
+func NewAuditLogRepository(db *database.Backend) AuditLogRepository {
+	if db.Memory {
+		return NewMemoryAuditLogRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresAuditLogRepository(db.Postgres)
+	}
+	return NewMongoAuditLogRepository(db.Mongo.DB)
+}
+
+// AuditFilters defines filters for listing audit events
+type AuditFilters struct {
+	UserID *uuid.UUID
+	Email  string
+	Type   *domain.AuditEventType
+}
+
+// OIDCStateRepository defines the interface for in-flight social login state data access
+type OIDCStateRepository interface {
+	// Create stores a new login state
+	Create(ctx context.Context, state *domain.OIDCLoginState) error
+
+	// Consume atomically retrieves and deletes a login state so it can only be used once
+	Consume(ctx context.Context, stateHash string) (*domain.OIDCLoginState, error)
+}
+
+// NewOIDCStateRepository returns the OIDCStateRepository of the database
+func NewOIDCStateRepository(db *database.Backend) OIDCStateRepository {
+	if db.Memory {
+		return NewMemoryOIDCStateRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresOIDCStateRepository(db.Postgres)
+	}
+	return NewMongoOIDCStateRepository(db.Mongo.DB)
+}
+
+// ExternalIdentityRepository defines the interface for linked provider account data access
+type ExternalIdentityRepository interface {
+	// Create links a provider subject to a user
+	Create(ctx context.Context, identity *domain.ExternalIdentity) error
+
+	// GetByProviderSubject retrieves the link for a provider subject (nil if none exists)
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*domain.ExternalIdentity, error)
+
+	// TouchLogin records a login through the linked identity
+	TouchLogin(ctx context.Context, id uuid.UUID, email string) error
+
+	// ListByUser retrieves all provider accounts linked to a user
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.ExternalIdentity, error)
+
+	// DeleteByUser unlinks all provider accounts from a user
+	DeleteByUser(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// NewExternalIdentityRepository returns the ExternalIdentityRepository of the database
+func NewExternalIdentityRepository(db *database.Backend) ExternalIdentityRepository {
+	if db.Memory {
+		return NewMemoryExternalIdentityRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresExternalIdentityRepository(db.Postgres)
+	}
+	return NewMongoExternalIdentityRepository(db.Mongo.DB)
+}
+
+// KYCCaseRepository defines the interface for identity verification case data access
+type KYCCaseRepository interface {
+	// Create stores a new case
+	Create(ctx context.Context, c *domain.KYCCase) error
+
+	// GetByID retrieves a case by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.KYCCase, error)
+
+	// GetLatestByUser retrieves a user's most recent case (nil if none exists)
+	GetLatestByUser(ctx context.Context, userID uuid.UUID) (*domain.KYCCase, error)
+
+	// List retrieves a paginated list of cases, oldest submission first
+	List(ctx context.Context, offset, limit int, filters KYCFilters) ([]*domain.KYCCase, int, error)
+
+	// SaveDecision stores a reviewer decision if the case is still pending
+	SaveDecision(ctx context.Context, c *domain.KYCCase) error
+
+	// ListByUser retrieves all of a user's cases, oldest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.KYCCase, error)
+
+	// AnonymizeByUser removes documents, email and history notes from a user's cases
+	AnonymizeByUser(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// NewKYCCaseRepository returns the KYCCaseRepository of the database
+func NewKYCCaseRepository(db *database.Backend) KYCCaseRepository {
+	if db.Memory {
+		return NewMemoryKYCCaseRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresKYCCaseRepository(db.Postgres)
+	}
+	return NewMongoKYCCaseRepository(db.Mongo.DB)
+}
+
+// KYCFilters defines filters for the verification review queue
+type KYCFilters struct {
+	Status        *domain.KYCStatus
+	UserID        *uuid.UUID
+	Role          *domain.UserRole
+	DocumentType  string
+	SubmittedFrom *time.Time
+	SubmittedTo   *time.Time
+}
+
+// DataRequestRepository defines the interface for data-subject request data access
+type DataRequestRepository interface {
+	// Create queues a new request
+	Create(ctx context.Context, req *domain.DataRequest) error
+
+	// GetByID retrieves a request by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.DataRequest, error)
+
+	// GetActiveByUser retrieves a user's queued or running request of a type (nil if none exists)
+	GetActiveByUser(ctx context.Context, userID uuid.UUID, requestType domain.DataRequestType) (*domain.DataRequest, error)
+
+	// List retrieves a paginated list of requests, newest first
+	List(ctx context.Context, offset, limit int, filters DataRequestFilters) ([]*domain.DataRequest, int, error)
+
+	// ClaimNext atomically leases the oldest request that is due (nil if none exists).
+	// Running requests whose lease has expired are claimed again.
+	ClaimNext(ctx context.Context, now, leaseUntil time.Time) (*domain.DataRequest, error)
+
+	// Save stores the progress of a claimed request
+	Save(ctx context.Context, req *domain.DataRequest) error
+}
+
+// NewDataRequestRepository returns the DataRequestRepository of the database
+func NewDataRequestRepository(db *database.Backend) DataRequestRepository {
+	if db.Memory {
+		return NewMemoryDataRequestRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresDataRequestRepository(db.Postgres)
+	}
+	return NewMongoDataRequestRepository(db.Mongo.DB)
+}
+
+// DataRequestFilters defines filters for listing data-subject requests
+type DataRequestFilters struct {
+	UserID *uuid.UUID
+	Type   *domain.DataRequestType
+	Status *domain.DataRequestStatus
+}
+
+// DataExportArchiveRepository defines the interface for export archive data access
+type DataExportArchiveRepository interface {
+	// Save stores an archive, replacing any earlier one for the request
+	Save(ctx context.Context, archive *domain.DataExportArchive) error
+
+	// GetByRequestID retrieves the archive of an export request
+	GetByRequestID(ctx context.Context, requestID uuid.UUID) (*domain.DataExportArchive, error)
+
+	// DeleteExpired removes archives past their download window
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// NewDataExportArchiveRepository returns the DataExportArchiveRepository of the database
+func NewDataExportArchiveRepository(db *database.Backend) DataExportArchiveRepository {
+	if db.Memory {
+		return NewMemoryDataExportArchiveRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresDataExportArchiveRepository(db.Postgres)
+	}
+	return NewMongoDataExportArchiveRepository(db.Mongo.DB)
+}
@@ -0,0 +1,823 @@
+// Package repositorytest is the conformance suite of the auth service's
+// repositories. Every implementation runs it, so the service behaves the same
+// whichever database it stores its data in.
+package repositorytest
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/repository"
+)
+
+// UserRepository runs the conformance tests of UserRepository against the
+// repositories newRepo opens, an empty one for each test
+func UserRepository(t *testing.T, newRepo func(t *testing.T) repository.UserRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		user := newUser("ada@example.com", domain.RoleOwner, time.Now())
+		must(t, "Create", repo.Create(ctx, user))
+
+		got, err := repo.GetByEmail(ctx, "ada@example.com")
+		if err != nil {
+			t.Fatalf("GetByEmail: %v", err)
+		}
+		if got.ID != user.ID || got.FirstName != "Ada" || got.Role != domain.RoleOwner ||
+			len(got.Roles) != 1 || got.Roles[0] != domain.RoleOwner || got.VerificationStatus != domain.VerificationPending {
+			t.Errorf("GetByEmail = %+v, want %+v", got, user)
+		}
+		sameTime(t, "created_at", got.CreatedAt, user.CreatedAt)
+
+		if err := repo.Create(ctx, newUser("ada@example.com", domain.RoleRenter, time.Now())); err != domain.ErrUserAlreadyExists {
+			t.Errorf("Create with a taken email = %v, want ErrUserAlreadyExists", err)
+		}
+		if _, err := repo.GetByID(ctx, uuid.New()); err != domain.ErrUserNotFound {
+			t.Errorf("GetByID of a missing user = %v, want ErrUserNotFound", err)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		repo := newRepo(t)
+		start := time.Now().Add(-time.Hour)
+		renter := newUser("renter@example.com", domain.RoleRenter, start)
+		owner := newUser("owner@example.com", domain.RoleOwner, start.Add(time.Minute))
+		owner.VerificationStatus = domain.VerificationVerified
+		// Accounts created before users could hold several roles
+		legacy := newUser("legacy@example.com", domain.RoleOwner, start.Add(2*time.Minute))
+		legacy.Roles = nil
+		for _, user := range []*domain.User{renter, owner, legacy} {
+			must(t, "Create", repo.Create(ctx, user))
+		}
+
+		role := domain.RoleOwner
+		users, total, err := repo.List(ctx, 0, 10, repository.UserFilters{Role: &role})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if total != 2 || len(users) != 2 || users[0].ID != legacy.ID || users[1].ID != owner.ID {
+			t.Errorf("List of owners = %d users (total %d), want the 2 owners, newest first", len(users), total)
+		}
+
+		status := domain.VerificationVerified
+		users, total, err = repo.List(ctx, 0, 10, repository.UserFilters{VerificationStatus: &status})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if total != 1 || len(users) != 1 || users[0].ID != owner.ID {
+			t.Errorf("List of verified users = %d users (total %d), want the owner", len(users), total)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		user := newUser("grace@example.com", domain.RoleRenter, time.Now())
+		must(t, "Create", repo.Create(ctx, user))
+
+		user.AddRole(domain.RoleOwner)
+		user.Bio = "Lends out camping gear"
+		user.MFAEnabled = true
+		user.MFARecoveryCodes = []string{"code-a", "code-b"}
+		must(t, "Update", repo.Update(ctx, user))
+		got, err := repo.GetByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Role != domain.RoleOwner || len(got.Roles) != 2 || got.Bio != user.Bio || !got.MFAEnabled || len(got.MFARecoveryCodes) != 2 {
+			t.Errorf("GetByID after Update = %+v", got)
+		}
+
+		expires := time.Now().Add(time.Hour)
+		must(t, "UpdateRefreshToken", repo.UpdateRefreshToken(ctx, user.ID, "refresh-hash", &expires))
+		if got, err := repo.GetByID(ctx, user.ID); err != nil || got.RefreshTokenHash != "refresh-hash" || got.RefreshTokenExpiresAt == nil {
+			t.Errorf("GetByID after UpdateRefreshToken = %+v, %v", got, err)
+		}
+		must(t, "ClearRefreshToken", repo.ClearRefreshToken(ctx, user.ID))
+		if got, err := repo.GetByID(ctx, user.ID); err != nil || got.RefreshTokenHash != "" || got.RefreshTokenExpiresAt != nil {
+			t.Errorf("GetByID after ClearRefreshToken = %+v, %v", got, err)
+		}
+
+		must(t, "Delete", repo.Delete(ctx, user.ID))
+		if err := repo.Delete(ctx, user.ID); err != domain.ErrUserNotFound {
+			t.Errorf("second Delete = %v, want ErrUserNotFound", err)
+		}
+		if err := repo.Update(ctx, user); err != domain.ErrUserNotFound {
+			t.Errorf("Update of a deleted user = %v, want ErrUserNotFound", err)
+		}
+	})
+
+	t.Run("MFAReplay", func(t *testing.T) {
+		repo := newRepo(t)
+		user := newUser("mfa@example.com", domain.RoleRenter, time.Now())
+		user.MFARecoveryCodes = []string{"code-a", "code-b"}
+		must(t, "Create", repo.Create(ctx, user))
+
+		must(t, "RecordMFAStep", repo.RecordMFAStep(ctx, user.ID, 5))
+		if err := repo.RecordMFAStep(ctx, user.ID, 5); err != domain.ErrInvalidMFACode {
+			t.Errorf("RecordMFAStep of the same step = %v, want ErrInvalidMFACode", err)
+		}
+		if err := repo.RecordMFAStep(ctx, user.ID, 4); err != domain.ErrInvalidMFACode {
+			t.Errorf("RecordMFAStep of an older step = %v, want ErrInvalidMFACode", err)
+		}
+		must(t, "RecordMFAStep", repo.RecordMFAStep(ctx, user.ID, 6))
+
+		must(t, "ConsumeRecoveryCode", repo.ConsumeRecoveryCode(ctx, user.ID, "code-a"))
+		if err := repo.ConsumeRecoveryCode(ctx, user.ID, "code-a"); err != domain.ErrInvalidMFACode {
+			t.Errorf("ConsumeRecoveryCode of a used code = %v, want ErrInvalidMFACode", err)
+		}
+		got, err := repo.GetByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.MFALastUsedStep != 6 || len(got.MFARecoveryCodes) != 1 || got.MFARecoveryCodes[0] != "code-b" {
+			t.Errorf("GetByID after MFA use = step %d, codes %v", got.MFALastUsedStep, got.MFARecoveryCodes)
+		}
+	})
+}
+
+// DocumentRepository runs the conformance tests of DocumentRepository
+// against the repositories newRepo opens, an empty one for each test
+func DocumentRepository(t *testing.T, newRepo func(t *testing.T) repository.DocumentRepository) {
+	ctx := context.Background()
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		repo := newRepo(t)
+		user := uuid.New()
+		doc := domain.NewIdentityDocument(user, domain.DocTypePassport, "https://cdn.example.com/passport.jpg")
+		must(t, "Create", repo.Create(ctx, doc))
+		must(t, "Create", repo.Create(ctx, domain.NewIdentityDocument(uuid.New(), domain.DocTypeNationalID, "https://cdn.example.com/id.jpg")))
+
+		got, err := repo.GetByID(ctx, doc.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.UserID != user || got.DocumentType != domain.DocTypePassport || got.DocumentURL != doc.DocumentURL {
+			t.Errorf("GetByID = %+v, want %+v", got, doc)
+		}
+
+		docs, err := repo.GetByUserID(ctx, user)
+		if err != nil {
+			t.Fatalf("GetByUserID: %v", err)
+		}
+		if len(docs) != 1 || docs[0].ID != doc.ID {
+			t.Errorf("GetByUserID = %d documents, want the user's 1", len(docs))
+		}
+
+		must(t, "Delete", repo.Delete(ctx, doc.ID))
+		if _, err := repo.GetByID(ctx, doc.ID); err != domain.ErrDocumentNotFound {
+			t.Errorf("GetByID of a deleted document = %v, want ErrDocumentNotFound", err)
+		}
+		if err := repo.Delete(ctx, doc.ID); err != domain.ErrDocumentNotFound {
+			t.Errorf("second Delete = %v, want ErrDocumentNotFound", err)
+		}
+	})
+}
+
+// ActionTokenRepository runs the conformance tests of ActionTokenRepository
+// against the repositories newRepo opens, an empty one for each test
+func ActionTokenRepository(t *testing.T, newRepo func(t *testing.T) repository.ActionTokenRepository) {
+	ctx := context.Background()
+
+	t.Run("SingleUse", func(t *testing.T) {
+		repo := newRepo(t)
+		user := uuid.New()
+		token := domain.NewActionToken(user, domain.PurposePasswordReset, "reset-hash", time.Hour)
+		must(t, "Create", repo.Create(ctx, token))
+
+		got, err := repo.GetByHash(ctx, "reset-hash", domain.PurposePasswordReset)
+		if err != nil {
+			t.Fatalf("GetByHash: %v", err)
+		}
+		if got.ID != token.ID || got.UserID != user || got.UsedAt != nil {
+			t.Errorf("GetByHash = %+v, want %+v", got, token)
+		}
+		sameTime(t, "expires_at", got.ExpiresAt, token.ExpiresAt)
+		if _, err := repo.GetByHash(ctx, "reset-hash", domain.PurposeEmailVerification); err != domain.ErrInvalidActionToken {
+			t.Errorf("GetByHash for another purpose = %v, want ErrInvalidActionToken", err)
+		}
+
+		must(t, "MarkUsed", repo.MarkUsed(ctx, token.ID))
+		if err := repo.MarkUsed(ctx, token.ID); err != domain.ErrInvalidActionToken {
+			t.Errorf("second MarkUsed = %v, want ErrInvalidActionToken", err)
+		}
+	})
+
+	t.Run("InvalidateAndDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		user := uuid.New()
+		reset := domain.NewActionToken(user, domain.PurposePasswordReset, "reset-hash", time.Hour)
+		verify := domain.NewActionToken(user, domain.PurposeEmailVerification, "verify-hash", time.Hour)
+		other := domain.NewActionToken(uuid.New(), domain.PurposePasswordReset, "other-hash", time.Hour)
+		for _, token := range []*domain.ActionToken{reset, verify, other} {
+			must(t, "Create", repo.Create(ctx, token))
+		}
+
+		must(t, "InvalidateForUser", repo.InvalidateForUser(ctx, user, domain.PurposePasswordReset))
+		if got, err := repo.GetByHash(ctx, "reset-hash", domain.PurposePasswordReset); err != nil || got.UsedAt == nil {
+			t.Errorf("invalidated token = %+v, %v", got, err)
+		}
+		if got, err := repo.GetByHash(ctx, "verify-hash", domain.PurposeEmailVerification); err != nil || got.UsedAt != nil {
+			t.Errorf("token of another purpose = %+v, %v", got, err)
+		}
+
+		deleted, err := repo.DeleteByUser(ctx, user)
+		if err != nil {
+			t.Fatalf("DeleteByUser: %v", err)
+		}
+		if deleted != 2 {
+			t.Errorf("DeleteByUser = %d, want 2", deleted)
+		}
+		if _, err := repo.GetByHash(ctx, "other-hash", domain.PurposePasswordReset); err != nil {
+			t.Errorf("another user's token was deleted: %v", err)
+		}
+	})
+}
+
+// SettingsRepository runs the conformance tests of SettingsRepository
+// against the repositories newRepo opens, an empty one for each test
+func SettingsRepository(t *testing.T, newRepo func(t *testing.T) repository.SettingsRepository) {
+	ctx := context.Background()
+
+	t.Run("MFAPolicy", func(t *testing.T) {
+		repo := newRepo(t)
+
+		policy, err := repo.GetMFAPolicy(ctx)
+		if err != nil {
+			t.Fatalf("GetMFAPolicy: %v", err)
+		}
+		if policy.RequiredRoles == nil || len(policy.RequiredRoles) != 0 {
+			t.Errorf("GetMFAPolicy before any save = %+v, want no required roles", policy)
+		}
+
+		admin := uuid.New()
+		must(t, "SaveMFAPolicy", repo.SaveMFAPolicy(ctx, &domain.MFAPolicy{
+			RequiredRoles: []domain.UserRole{domain.RoleAdmin, domain.RoleOwner},
+			UpdatedBy:     &admin,
+			UpdatedAt:     time.Now(),
+		}))
+		must(t, "SaveMFAPolicy", repo.SaveMFAPolicy(ctx, &domain.MFAPolicy{
+			RequiredRoles: []domain.UserRole{domain.RoleAdmin},
+			UpdatedBy:     &admin,
+			UpdatedAt:     time.Now(),
+		}))
+
+		policy, err = repo.GetMFAPolicy(ctx)
+		if err != nil {
+			t.Fatalf("GetMFAPolicy: %v", err)
+		}
+		if len(policy.RequiredRoles) != 1 || policy.RequiredRoles[0] != domain.RoleAdmin ||
+			policy.UpdatedBy == nil || *policy.UpdatedBy != admin {
+			t.Errorf("GetMFAPolicy = %+v, want the last saved policy", policy)
+		}
+	})
+}
+
+// LoginThrottleRepository runs the conformance tests of
+// LoginThrottleRepository against the repositories newRepo opens, an empty
+// one for each test
+func LoginThrottleRepository(t *testing.T, newRepo func(t *testing.T) repository.LoginThrottleRepository) {
+	ctx := context.Background()
+
+	t.Run("Counting", func(t *testing.T) {
+		repo := newRepo(t)
+		key := domain.ThrottleKey(domain.ThrottleAccount, "ada@example.com")
+		if got, err := repo.Get(ctx, key); err != nil || got != nil {
+			t.Fatalf("Get of a missing counter = %+v, %v, want nil", got, err)
+		}
+
+		user := uuid.New()
+		hourAgo := time.Now().Add(-time.Hour)
+		if _, err := repo.RecordFailure(ctx, domain.ThrottleAccount, "ada@example.com", &user, hourAgo, hourAgo); err != nil {
+			t.Fatalf("RecordFailure: %v", err)
+		}
+		throttle, err := repo.RecordFailure(ctx, domain.ThrottleAccount, "ada@example.com", nil, hourAgo, hourAgo)
+		if err != nil {
+			t.Fatalf("RecordFailure: %v", err)
+		}
+		if throttle.Key != key || throttle.Failures != 2 || throttle.UserID == nil || *throttle.UserID != user {
+			t.Errorf("RecordFailure within the window = %+v, want 2 failures of the user", throttle)
+		}
+
+		must(t, "Lock", repo.Lock(ctx, key, time.Now().Add(time.Hour)))
+		throttle, err = repo.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if throttle.Failures != 0 || throttle.LockoutCount != 1 || !throttle.IsLocked(time.Now()) {
+			t.Errorf("Get after Lock = %+v, want a lockout and no failures", throttle)
+		}
+
+		// A window and decay starting after the last failure forget it
+		later := time.Now().Add(time.Hour)
+		throttle, err = repo.RecordFailure(ctx, domain.ThrottleAccount, "ada@example.com", nil, later, later)
+		if err != nil {
+			t.Fatalf("RecordFailure: %v", err)
+		}
+		if throttle.Failures != 1 || throttle.LockoutCount != 0 {
+			t.Errorf("RecordFailure past the window = %+v, want a fresh count", throttle)
+		}
+
+		must(t, "Reset", repo.Reset(ctx, key))
+		if got, err := repo.Get(ctx, key); err != nil || got != nil {
+			t.Errorf("Get after Reset = %+v, %v, want nil", got, err)
+		}
+	})
+
+	t.Run("ListLocked", func(t *testing.T) {
+		repo := newRepo(t)
+		now := time.Now()
+		hourAgo := now.Add(-time.Hour)
+		lock := func(kind domain.ThrottleKind, subject string, until time.Time) {
+			t.Helper()
+			if _, err := repo.RecordFailure(ctx, kind, subject, nil, hourAgo, hourAgo); err != nil {
+				t.Fatalf("RecordFailure: %v", err)
+			}
+			must(t, "Lock", repo.Lock(ctx, domain.ThrottleKey(kind, subject), until))
+		}
+		lock(domain.ThrottleAccount, "soon@example.com", now.Add(time.Minute))
+		lock(domain.ThrottleAccount, "later@example.com", now.Add(time.Hour))
+		lock(domain.ThrottleAccount, "expired@example.com", now.Add(-time.Minute))
+		lock(domain.ThrottleIP, "203.0.113.7", now.Add(time.Hour))
+
+		locked, total, err := repo.ListLocked(ctx, domain.ThrottleAccount, now, 0, 10)
+		if err != nil {
+			t.Fatalf("ListLocked: %v", err)
+		}
+		if total != 2 || len(locked) != 2 || locked[0].Subject != "later@example.com" || locked[1].Subject != "soon@example.com" {
+			t.Errorf("ListLocked = %d counters (total %d), want the 2 locked accounts, longest lockout first", len(locked), total)
+		}
+	})
+}
+
+// AuditLogRepository runs the conformance tests of AuditLogRepository
+// against the repositories newRepo opens, an empty one for each test
+func AuditLogRepository(t *testing.T, newRepo func(t *testing.T) repository.AuditLogRepository) {
+	ctx := context.Background()
+
+	t.Run("ListAndAnonymize", func(t *testing.T) {
+		repo := newRepo(t)
+		user := uuid.New()
+		start := time.Now().Add(-time.Hour)
+		failure := newAuditEvent(domain.AuditLoginFailure, &user, "ada@example.com", start)
+		success := newAuditEvent(domain.AuditLoginSuccess, &user, "ada@example.com", start.Add(time.Minute))
+		other := newAuditEvent(domain.AuditLoginFailure, nil, "eve@example.com", start.Add(2*time.Minute))
+		for _, event := range []*domain.AuthAuditEvent{failure, success, other} {
+			must(t, "Create", repo.Create(ctx, event))
+		}
+
+		events, total, err := repo.List(ctx, 0, 10, repository.AuditFilters{UserID: &user})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if total != 2 || len(events) != 2 || events[0].ID != success.ID || events[1].ID != failure.ID {
+			t.Errorf("List of the user = %d events (total %d), want 2, newest first", len(events), total)
+		}
+
+		failureType := domain.AuditLoginFailure
+		events, total, err = repo.List(ctx, 0, 10, repository.AuditFilters{Email: "eve@example.com", Type: &failureType})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if total != 1 || len(events) != 1 || events[0].ID != other.ID || events[0].UserID != nil {
+			t.Errorf("List by email and type = %d events (total %d), want the other one", len(events), total)
+		}
+
+		anonymized, err := repo.AnonymizeByUser(ctx, user)
+		if err != nil {
+			t.Fatalf("AnonymizeByUser: %v", err)
+		}
+		if anonymized != 2 {
+			t.Errorf("AnonymizeByUser = %d, want 2", anonymized)
+		}
+		events, err = repo.ListByUser(ctx, user)
+		if err != nil {
+			t.Fatalf("ListByUser: %v", err)
+		}
+		if len(events) != 2 || events[0].ID != success.ID {
+			t.Fatalf("ListByUser = %d events, want the user's 2, newest first", len(events))
+		}
+		for _, event := range events {
+			if event.Email != "" || event.IPAddress != "" || event.UserAgent != "" || event.Type == "" {
+				t.Errorf("anonymized event = %+v, want its type without personal data", event)
+			}
+		}
+	})
+}
+
+// OIDCStateRepository runs the conformance tests of OIDCStateRepository
+// against the repositories newRepo opens, an empty one for each test
+func OIDCStateRepository(t *testing.T, newRepo func(t *testing.T) repository.OIDCStateRepository) {
+	ctx := context.Background()
+
+	t.Run("ConsumeOnce", func(t *testing.T) {
+		repo := newRepo(t)
+		state := domain.NewOIDCLoginState("state-hash", "google", "nonce", "verifier", time.Minute)
+		must(t, "Create", repo.Create(ctx, state))
+
+		got, err := repo.Consume(ctx, "state-hash")
+		if err != nil {
+			t.Fatalf("Consume: %v", err)
+		}
+		if got.Provider != "google" || got.Nonce != "nonce" || got.CodeVerifier != "verifier" {
+			t.Errorf("Consume = %+v, want %+v", got, state)
+		}
+		if _, err := repo.Consume(ctx, "state-hash"); err != domain.ErrInvalidOIDCState {
+			t.Errorf("second Consume = %v, want ErrInvalidOIDCState", err)
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		repo := newRepo(t)
+		must(t, "Create", repo.Create(ctx, domain.NewOIDCLoginState("old-hash", "google", "nonce", "verifier", -time.Minute)))
+
+		if _, err := repo.Consume(ctx, "old-hash"); err != domain.ErrInvalidOIDCState {
+			t.Errorf("Consume of an expired state = %v, want ErrInvalidOIDCState", err)
+		}
+	})
+}
+
+// ExternalIdentityRepository runs the conformance tests of
+// ExternalIdentityRepository against the repositories newRepo opens, an empty
+// one for each test
+func ExternalIdentityRepository(t *testing.T, newRepo func(t *testing.T) repository.ExternalIdentityRepository) {
+	ctx := context.Background()
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		repo := newRepo(t)
+		user := uuid.New()
+		google := domain.NewExternalIdentity(user, "google", "google-sub", "ada@gmail.com")
+		must(t, "Create", repo.Create(ctx, google))
+		must(t, "Create", repo.Create(ctx, domain.NewExternalIdentity(user, "github", "github-sub", "ada@example.com")))
+		if err := repo.Create(ctx, domain.NewExternalIdentity(uuid.New(), "google", "google-sub", "eve@gmail.com")); err == nil {
+			t.Error("Create of a linked provider subject succeeded")
+		}
+
+		if got, err := repo.GetByProviderSubject(ctx, "google", "unknown"); err != nil || got != nil {
+			t.Errorf("GetByProviderSubject of an unlinked subject = %+v, %v, want nil", got, err)
+		}
+
+		must(t, "TouchLogin", repo.TouchLogin(ctx, google.ID, "ada@new.example.com"))
+		got, err := repo.GetByProviderSubject(ctx, "google", "google-sub")
+		if err != nil {
+			t.Fatalf("GetByProviderSubject: %v", err)
+		}
+		if got == nil || got.ID != google.ID || got.UserID != user || got.Email != "ada@new.example.com" {
+			t.Errorf("GetByProviderSubject after TouchLogin = %+v", got)
+		}
+
+		identities, err := repo.ListByUser(ctx, user)
+		if err != nil {
+			t.Fatalf("ListByUser: %v", err)
+		}
+		if len(identities) != 2 {
+			t.Errorf("ListByUser = %d identities, want 2", len(identities))
+		}
+
+		deleted, err := repo.DeleteByUser(ctx, user)
+		if err != nil {
+			t.Fatalf("DeleteByUser: %v", err)
+		}
+		if deleted != 2 {
+			t.Errorf("DeleteByUser = %d, want 2", deleted)
+		}
+	})
+}
+
+// KYCCaseRepository runs the conformance tests of KYCCaseRepository against
+// the repositories newRepo opens, an empty one for each test
+func KYCCaseRepository(t *testing.T, newRepo func(t *testing.T) repository.KYCCaseRepository) {
+	ctx := context.Background()
+
+	t.Run("Queue", func(t *testing.T) {
+		repo := newRepo(t)
+		start := time.Now().Add(-time.Hour)
+		owner := newUser("owner@example.com", domain.RoleOwner, start)
+		renter := newUser("renter@example.com", domain.RoleRenter, start)
+		first := newKYCCase(owner, domain.DocTypePassport, nil, start)
+		first.Reject(uuid.New(), "Blurry photo")
+		second := newKYCCase(owner, domain.DocTypeNationalID, first, start.Add(time.Minute))
+		third := newKYCCase(renter, domain.DocTypePassport, nil, start.Add(2*time.Minute))
+		for _, c := range []*domain.KYCCase{first, second, third} {
+			must(t, "Create", repo.Create(ctx, c))
+		}
+
+		got, err := repo.GetByID(ctx, first.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.UserEmail != owner.Email || got.Status != domain.KYCStatusRejected || got.RejectionReason != "Blurry photo" ||
+			len(got.Documents) != 1 || got.Documents[0].Type != domain.DocTypePassport ||
+			len(got.History) != 2 || got.History[1].Reason != "Blurry photo" {
+			t.Errorf("GetByID = %+v, want %+v", got, first)
+		}
+		if _, err := repo.GetByID(ctx, uuid.New()); err != domain.ErrKYCCaseNotFound {
+			t.Errorf("GetByID of a missing case = %v, want ErrKYCCaseNotFound", err)
+		}
+
+		latest, err := repo.GetLatestByUser(ctx, owner.ID)
+		if err != nil {
+			t.Fatalf("GetLatestByUser: %v", err)
+		}
+		if latest == nil || latest.ID != second.ID || latest.Attempt != 2 || latest.PreviousCaseID == nil || *latest.PreviousCaseID != first.ID {
+			t.Errorf("GetLatestByUser = %+v, want the second attempt", latest)
+		}
+		if latest, err := repo.GetLatestByUser(ctx, uuid.New()); err != nil || latest != nil {
+			t.Errorf("GetLatestByUser of a user without cases = %+v, %v, want nil", latest, err)
+		}
+
+		pending := domain.KYCStatusPending
+		cases, total, err := repo.List(ctx, 0, 10, repository.KYCFilters{Status: &pending})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if total != 2 || len(cases) != 2 || cases[0].ID != second.ID || cases[1].ID != third.ID {
+			t.Errorf("List of pending cases = %d cases (total %d), want 2, oldest first", len(cases), total)
+		}
+
+		role := domain.RoleOwner
+		cases, total, err = repo.List(ctx, 0, 10, repository.KYCFilters{Role: &role, DocumentType: domain.DocTypeNationalID})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if total != 1 || len(cases) != 1 || cases[0].ID != second.ID {
+			t.Errorf("List by role and document type = %d cases (total %d), want the second", len(cases), total)
+		}
+
+		from, to := start.Add(30*time.Second), start.Add(90*time.Second)
+		cases, total, err = repo.List(ctx, 0, 10, repository.KYCFilters{SubmittedFrom: &from, SubmittedTo: &to})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if total != 1 || len(cases) != 1 || cases[0].ID != second.ID {
+			t.Errorf("List by submission time = %d cases (total %d), want the second", len(cases), total)
+		}
+	})
+
+	t.Run("SaveDecision", func(t *testing.T) {
+		repo := newRepo(t)
+		c := newKYCCase(newUser("owner@example.com", domain.RoleOwner, time.Now()), domain.DocTypePassport, nil, time.Now())
+		must(t, "Create", repo.Create(ctx, c))
+
+		reviewer := uuid.New()
+		c.Approve(reviewer, "Looks good")
+		must(t, "SaveDecision", repo.SaveDecision(ctx, c))
+
+		got, err := repo.GetByID(ctx, c.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != domain.KYCStatusApproved || got.ReviewedBy == nil || *got.ReviewedBy != reviewer ||
+			got.ReviewedAt == nil || len(got.History) != 2 {
+			t.Errorf("GetByID after SaveDecision = %+v", got)
+		}
+
+		c.Reject(uuid.New(), "Too late")
+		if err := repo.SaveDecision(ctx, c); err != domain.ErrKYCCaseAlreadyReviewed {
+			t.Errorf("second SaveDecision = %v, want ErrKYCCaseAlreadyReviewed", err)
+		}
+	})
+
+	t.Run("AnonymizeByUser", func(t *testing.T) {
+		repo := newRepo(t)
+		start := time.Now().Add(-time.Hour)
+		user := newUser("owner@example.com", domain.RoleOwner, start)
+		rejected := newKYCCase(user, domain.DocTypePassport, nil, start)
+		rejected.Reject(uuid.New(), "Name does not match")
+		retry := newKYCCase(user, domain.DocTypePassport, rejected, start.Add(time.Minute))
+		other := newKYCCase(newUser("other@example.com", domain.RoleRenter, start), domain.DocTypePassport, nil, start)
+		for _, c := range []*domain.KYCCase{rejected, retry, other} {
+			must(t, "Create", repo.Create(ctx, c))
+		}
+
+		anonymized, err := repo.AnonymizeByUser(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("AnonymizeByUser: %v", err)
+		}
+		if anonymized != 2 {
+			t.Errorf("AnonymizeByUser = %d, want 2", anonymized)
+		}
+
+		cases, err := repo.ListByUser(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("ListByUser: %v", err)
+		}
+		if len(cases) != 2 || cases[0].ID != rejected.ID {
+			t.Fatalf("ListByUser = %d cases, want the user's 2, oldest first", len(cases))
+		}
+		got := cases[0]
+		if got.UserEmail != "" || len(got.Documents) != 0 || got.RejectionReason != "" ||
+			got.Status != domain.KYCStatusRejected || len(got.History) != 2 || got.History[1].Reason != "" ||
+			got.History[1].Action != domain.KYCActionRejected {
+			t.Errorf("anonymized case = %+v, want the outcome without documents or notes", got)
+		}
+		if got, err := repo.GetByID(ctx, other.ID); err != nil || got.UserEmail == "" {
+			t.Errorf("an unrelated case changed: %+v, %v", got, err)
+		}
+	})
+}
+
+// DataRequestRepository runs the conformance tests of DataRequestRepository
+// against the repositories newRepo opens, an empty one for each test
+func DataRequestRepository(t *testing.T, newRepo func(t *testing.T) repository.DataRequestRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		user := uuid.New()
+		req := newDataRequest(user, domain.DataRequestExport, time.Now())
+		must(t, "Create", repo.Create(ctx, req))
+
+		got, err := repo.GetByID(ctx, req.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.UserID != user || got.Type != domain.DataRequestExport || got.Status != domain.DataRequestPending ||
+			len(got.Steps) != len(req.Steps) || got.Steps[0].Service != req.Steps[0].Service {
+			t.Errorf("GetByID = %+v, want %+v", got, req)
+		}
+		if _, err := repo.GetByID(ctx, uuid.New()); err != domain.ErrDataRequestNotFound {
+			t.Errorf("GetByID of a missing request = %v, want ErrDataRequestNotFound", err)
+		}
+
+		active, err := repo.GetActiveByUser(ctx, user, domain.DataRequestExport)
+		if err != nil {
+			t.Fatalf("GetActiveByUser: %v", err)
+		}
+		if active == nil || active.ID != req.ID {
+			t.Errorf("GetActiveByUser = %+v, want the queued request", active)
+		}
+		if active, err := repo.GetActiveByUser(ctx, user, domain.DataRequestDeletion); err != nil || active != nil {
+			t.Errorf("GetActiveByUser of another type = %+v, %v, want nil", active, err)
+		}
+	})
+
+	t.Run("ClaimAndSave", func(t *testing.T) {
+		repo := newRepo(t)
+		now := time.Now()
+		older := newDataRequest(uuid.New(), domain.DataRequestExport, now.Add(-2*time.Hour))
+		newer := newDataRequest(uuid.New(), domain.DataRequestDeletion, now.Add(-time.Hour))
+		backedOff := newDataRequest(uuid.New(), domain.DataRequestExport, now.Add(-3*time.Hour))
+		backedOff.NextAttemptAt = now.Add(3 * time.Hour)
+		for _, req := range []*domain.DataRequest{older, newer, backedOff} {
+			must(t, "Create", repo.Create(ctx, req))
+		}
+
+		lease := now.Add(time.Hour)
+		claimed, err := repo.ClaimNext(ctx, now, lease)
+		if err != nil {
+			t.Fatalf("ClaimNext: %v", err)
+		}
+		if claimed == nil || claimed.ID != older.ID || claimed.Status != domain.DataRequestRunning ||
+			claimed.Attempts != 1 || claimed.LeaseUntil == nil {
+			t.Fatalf("ClaimNext = %+v, want the oldest due request, leased", claimed)
+		}
+		sameTime(t, "lease_until", *claimed.LeaseUntil, lease)
+
+		if claimed, err := repo.ClaimNext(ctx, now, lease); err != nil || claimed == nil || claimed.ID != newer.ID {
+			t.Errorf("second ClaimNext = %+v, %v, want the newer request", claimed, err)
+		}
+		if claimed, err := repo.ClaimNext(ctx, now, lease); err != nil || claimed != nil {
+			t.Errorf("ClaimNext with nothing due = %+v, %v, want nil", claimed, err)
+		}
+
+		// Leases that ran out are claimed again, the oldest request first
+		later := now.Add(2 * time.Hour)
+		reclaimed, err := repo.ClaimNext(ctx, later, later.Add(time.Hour))
+		if err != nil {
+			t.Fatalf("ClaimNext: %v", err)
+		}
+		if reclaimed == nil || reclaimed.ID != older.ID || reclaimed.Attempts != 2 {
+			t.Fatalf("ClaimNext after the lease = %+v, want the oldest request again", reclaimed)
+		}
+
+		for i := range reclaimed.Steps {
+			reclaimed.CompleteStep(i, domain.DataStepDone, map[string]interface{}{"records": 1})
+		}
+		reclaimed.Complete()
+		must(t, "Save", repo.Save(ctx, reclaimed))
+
+		got, err := repo.GetByID(ctx, older.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != domain.DataRequestCompleted || got.CompletedAt == nil ||
+			got.Steps[0].Status != domain.DataStepDone || got.Steps[0].Summary["records"] == nil {
+			t.Errorf("GetByID after Save = %+v", got)
+		}
+		if active, err := repo.GetActiveByUser(ctx, older.UserID, domain.DataRequestExport); err != nil || active != nil {
+			t.Errorf("GetActiveByUser of a completed request = %+v, %v, want nil", active, err)
+		}
+
+		completed := domain.DataRequestCompleted
+		requests, total, err := repo.List(ctx, 0, 10, repository.DataRequestFilters{Status: &completed})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if total != 1 || len(requests) != 1 || requests[0].ID != older.ID {
+			t.Errorf("List of completed requests = %d requests (total %d), want 1", len(requests), total)
+		}
+	})
+}
+
+// DataExportArchiveRepository runs the conformance tests of
+// DataExportArchiveRepository against the repositories newRepo opens, an
+// empty one for each test
+func DataExportArchiveRepository(t *testing.T, newRepo func(t *testing.T) repository.DataExportArchiveRepository) {
+	ctx := context.Background()
+
+	t.Run("SaveAndExpire", func(t *testing.T) {
+		repo := newRepo(t)
+		now := time.Now()
+		archive := &domain.DataExportArchive{
+			RequestID: uuid.New(),
+			UserID:    uuid.New(),
+			Data:      []byte("first"),
+			CreatedAt: now,
+			ExpiresAt: now.Add(time.Hour),
+		}
+		must(t, "Save", repo.Save(ctx, archive))
+		archive.Data = []byte("second")
+		must(t, "Save", repo.Save(ctx, archive))
+		expired := &domain.DataExportArchive{
+			RequestID: uuid.New(),
+			UserID:    uuid.New(),
+			Data:      []byte("old"),
+			CreatedAt: now.Add(-48 * time.Hour),
+			ExpiresAt: now.Add(-time.Hour),
+		}
+		must(t, "Save", repo.Save(ctx, expired))
+
+		got, err := repo.GetByRequestID(ctx, archive.RequestID)
+		if err != nil {
+			t.Fatalf("GetByRequestID: %v", err)
+		}
+		if got.UserID != archive.UserID || !bytes.Equal(got.Data, []byte("second")) {
+			t.Errorf("GetByRequestID = %+v, want the last saved archive", got)
+		}
+
+		deleted, err := repo.DeleteExpired(ctx, now)
+		if err != nil {
+			t.Fatalf("DeleteExpired: %v", err)
+		}
+		if deleted != 1 {
+			t.Errorf("DeleteExpired = %d, want 1", deleted)
+		}
+		if _, err := repo.GetByRequestID(ctx, expired.RequestID); err != domain.ErrDataExportNotFound {
+			t.Errorf("GetByRequestID of an expired archive = %v, want ErrDataExportNotFound", err)
+		}
+	})
+}
+
+// newUser returns a user holding role, created at createdAt
+func newUser(email string, role domain.UserRole, createdAt time.Time) *domain.User {
+	user := domain.NewUser(email, "password-hash", "Ada", "Lovelace", "+15550100", role)
+	user.CreatedAt = createdAt
+	user.UpdatedAt = createdAt
+	return user
+}
+
+// newAuditEvent returns an event with a client address, created at createdAt
+func newAuditEvent(eventType domain.AuditEventType, userID *uuid.UUID, email string, createdAt time.Time) *domain.AuthAuditEvent {
+	event := domain.NewAuthAuditEvent(eventType, userID, email, "203.0.113.7", "Mozilla/5.0", "")
+	event.CreatedAt = createdAt
+	return event
+}
+
+// newKYCCase returns a pending case with one document, submitted at submittedAt
+func newKYCCase(user *domain.User, docType string, previous *domain.KYCCase, submittedAt time.Time) *domain.KYCCase {
+	c := domain.NewKYCCase(user, []domain.KYCDocument{{Type: docType, URL: "https://cdn.example.com/" + docType + ".jpg"}}, previous)
+	c.SubmittedAt = submittedAt
+	c.UpdatedAt = submittedAt
+	c.History[0].At = submittedAt
+	return c
+}
+
+// newDataRequest returns a request that is due, created at createdAt
+func newDataRequest(userID uuid.UUID, requestType domain.DataRequestType, createdAt time.Time) *domain.DataRequest {
+	req := domain.NewDataRequest(userID, requestType)
+	req.NextAttemptAt = createdAt
+	req.CreatedAt = createdAt
+	req.UpdatedAt = createdAt
+	return req
+}
+
+func must(t *testing.T, op string, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %v", op, err)
+	}
+}
+
+// sameTime fails the test unless got is want at the millisecond precision
+// every database keeps
+func sameTime(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if diff := got.Sub(want); diff > time.Millisecond || diff < -time.Millisecond {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/token"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+)
+
+// RequestEmailVerification issues a new verification token and emails it to the user
+func (s *AuthService) RequestEmailVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerified {
+		return domain.ErrAlreadyVerified
+	}
+
+	return s.sendEmailVerification(ctx, user)
+}
+
+// VerifyEmail redeems an email verification token
+func (s *AuthService) VerifyEmail(ctx context.Context, rawToken string) (*domain.User, error) {
+	var user *domain.User
+	err := s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		actionToken, err := s.redeemActionToken(ctx, rawToken, domain.PurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		user, err = s.userRepo.GetByID(ctx, actionToken.UserID)
+		if err != nil {
+			return err
+		}
+
+		if user.EmailVerified {
+			return nil
+		}
+		user.MarkEmailVerified()
+		return s.userRepo.Update(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// RequestPasswordReset emails a password reset link if the address belongs to a user.
+// It does not reveal whether the address is registered.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil
+		}
+		return err
+	}
+
+	rawToken, err := s.issueActionToken(ctx, user.ID, domain.PurposePasswordReset, s.accountTokens.PasswordResetTTL)
+	if err != nil {
+		return err
+	}
+
+	if s.notifier == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"UserName":  user.FirstName,
+		"ExpiresIn": formatTTL(s.accountTokens.PasswordResetTTL),
+	}
+	if err := s.notifier.SendPasswordReset(ctx, user.Email, rawToken, data); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to send password reset email")
+	}
+
+	return nil
+}
+
+// ResetPassword redeems a password reset token and sets a new password.
+// All existing sessions are signed out.
+func (s *AuthService) ResetPassword(ctx context.Context, rawToken, newPassword string) error {
+	if err := s.passService.ValidatePasswordStrength(newPassword); err != nil {
+		return err
+	}
+
+	newPasswordHash, err := s.passService.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	// The token is only spent if the new password is saved
+	return s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		actionToken, err := s.redeemActionToken(ctx, rawToken, domain.PurposePasswordReset)
+		if err != nil {
+			return err
+		}
+
+		user, err := s.userRepo.GetByID(ctx, actionToken.UserID)
+		if err != nil {
+			return err
+		}
+
+		user.PasswordHash = newPasswordHash
+		user.ClearRefreshToken()
+
+		// Receiving the reset link proves ownership of the address
+		if !user.EmailVerified {
+			user.MarkEmailVerified()
+		}
+
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+
+		// A successful reset lifts any lockout on the account
+		return s.throttleRepo.Reset(ctx, domain.ThrottleKey(domain.ThrottleAccount, normalizeEmail(user.Email)))
+	})
+}
+
+// sendEmailVerification issues a verification token and emails the link.
+// Delivery failures are logged rather than returned.
+func (s *AuthService) sendEmailVerification(ctx context.Context, user *domain.User) error {
+	rawToken, err := s.issueActionToken(ctx, user.ID, domain.PurposeEmailVerification, s.accountTokens.EmailVerificationTTL)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to issue email verification token")
+		return err
+	}
+
+	if s.notifier == nil {
+		return nil
+	}
+
+	data := map[string]interface{}{
+		"UserName":  user.FirstName,
+		"ExpiresIn": formatTTL(s.accountTokens.EmailVerificationTTL),
+	}
+	if err := s.notifier.SendEmailVerification(ctx, user.Email, rawToken, data); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to send email verification email")
+	}
+
+	return nil
+}
+
+// issueActionToken invalidates outstanding tokens of the same purpose and stores a new one.
+// The raw token is returned for delivery; only its hash is persisted.
+func (s *AuthService) issueActionToken(ctx context.Context, userID uuid.UUID, purpose domain.TokenPurpose, ttl time.Duration) (string, error) {
+	rawToken, err := token.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	actionToken := domain.NewActionToken(userID, purpose, s.passService.HashRefreshToken(rawToken), ttl)
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.tokenRepo.InvalidateForUser(ctx, userID, purpose); err != nil {
+			return err
+		}
+		return s.tokenRepo.Create(ctx, actionToken)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return rawToken, nil
+}
+
+// redeemActionToken validates a raw token and marks it used
+func (s *AuthService) redeemActionToken(ctx context.Context, rawToken string, purpose domain.TokenPurpose) (*domain.ActionToken, error) {
+	if rawToken == "" {
+		return nil, domain.ErrInvalidActionToken
+	}
+
+	actionToken, err := s.tokenRepo.GetByHash(ctx, s.passService.HashRefreshToken(rawToken), purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	if !actionToken.IsUsable() {
+		return nil, domain.ErrInvalidActionToken
+	}
+
+	if err := s.tokenRepo.MarkUsed(ctx, actionToken.ID); err != nil {
+		return nil, err
+	}
+
+	return actionToken, nil
+}
+
+// formatTTL renders a token lifetime for email copy
+func formatTTL(ttl time.Duration) string {
+	if ttl >= time.Hour && ttl%time.Hour == 0 {
+		hours := int(ttl.Hours())
+		if hours == 1 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", hours)
+	}
+	return fmt.Sprintf("%d minutes", int(ttl.Minutes()))
+}
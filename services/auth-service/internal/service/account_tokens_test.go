@@ -0,0 +1,97 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/service"
+)
+
+func TestVerifyEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		ttl     time.Duration
+		token   func(t *testing.T, e *env, user *domain.User) string
+		wantErr error
+		// The user ends up verified, by this token or an earlier one
+		verified bool
+	}{
+		{"MailedToken", time.Hour, mailedVerification, nil, true},
+		{"Empty", time.Hour, func(*testing.T, *env, *domain.User) string { return "" }, domain.ErrInvalidActionToken, false},
+		{"Unknown", time.Hour, func(*testing.T, *env, *domain.User) string { return "not-a-token" }, domain.ErrInvalidActionToken, false},
+		{"Expired", -time.Minute, mailedVerification, domain.ErrInvalidActionToken, false},
+		{"AlreadyUsed", time.Hour, func(t *testing.T, e *env, user *domain.User) string {
+			token := mailedVerification(t, e, user)
+			if _, err := e.svc.VerifyEmail(context.Background(), token); err != nil {
+				t.Fatalf("first VerifyEmail: %v", err)
+			}
+			return token
+		}, domain.ErrInvalidActionToken, true},
+		{"Superseded", time.Hour, func(t *testing.T, e *env, user *domain.User) string {
+			token := mailedVerification(t, e, user)
+			if err := e.svc.RequestEmailVerification(context.Background(), user.ID); err != nil {
+				t.Fatalf("RequestEmailVerification: %v", err)
+			}
+			return token
+		}, domain.ErrInvalidActionToken, false},
+		{"PasswordResetToken", time.Hour, func(t *testing.T, e *env, user *domain.User) string {
+			if err := e.svc.RequestPasswordReset(context.Background(), user.Email); err != nil {
+				t.Fatalf("RequestPasswordReset: %v", err)
+			}
+			return e.mail.last(t, user.Email, "password-reset").Token
+		}, domain.ErrInvalidActionToken, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEnv(t, options{accountTokens: service.AccountTokenConfig{EmailVerificationTTL: tt.ttl, PasswordResetTTL: time.Hour}})
+			user := e.register(t, "renter@example.com")
+			token := tt.token(t, e, user)
+
+			_, err := e.svc.VerifyEmail(context.Background(), token)
+			if err != tt.wantErr {
+				t.Fatalf("VerifyEmail error = %v, want %v", err, tt.wantErr)
+			}
+
+			stored, err := e.users.GetByID(context.Background(), user.ID)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if stored.EmailVerified != tt.verified {
+				t.Errorf("email verified = %v, want %v", stored.EmailVerified, tt.verified)
+			}
+		})
+	}
+}
+
+func TestResetPasswordSpendsToken(t *testing.T) {
+	e := newEnv(t, options{})
+	user := e.register(t, "renter@example.com")
+	if err := e.svc.RequestPasswordReset(context.Background(), user.Email); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	token := e.mail.last(t, user.Email, "password-reset").Token
+
+	// A rejected password leaves the token usable
+	if err := e.svc.ResetPassword(context.Background(), token, "short"); err == nil {
+		t.Fatal("ResetPassword accepted a weak password")
+	}
+	if err := e.svc.ResetPassword(context.Background(), token, "NewPassword456!"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if err := e.svc.ResetPassword(context.Background(), token, "OtherPassword789!"); err != domain.ErrInvalidActionToken {
+		t.Fatalf("second ResetPassword error = %v, want %v", err, domain.ErrInvalidActionToken)
+	}
+
+	if _, err := e.svc.Login(context.Background(), user.Email, "NewPassword456!", client); err != nil {
+		t.Errorf("Login with the new password: %v", err)
+	}
+}
+
+// mailedVerification returns the token of the verification email sent at
+// registration
+func mailedVerification(t *testing.T, e *env, user *domain.User) string {
+	t.Helper()
+	return e.mail.last(t, user.Email, "email-verification").Token
+}
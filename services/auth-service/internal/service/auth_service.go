@@ -6,39 +6,96 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/notifier"
+	"github.com/rentalflow/auth-service/internal/oidc"
 	"github.com/rentalflow/auth-service/internal/repository"
 	"github.com/rentalflow/auth-service/internal/token"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/database"
 )
 
 // AuthService handles authentication business logic
 type AuthService struct {
-	userRepo    repository.UserRepository
-	docRepo     repository.DocumentRepository
-	jwtService  *token.JWTService
-	passService *token.PasswordService
+	userRepo      repository.UserRepository
+	docRepo       repository.DocumentRepository
+	tokenRepo     repository.ActionTokenRepository
+	settingsRepo  repository.SettingsRepository
+	throttleRepo  repository.LoginThrottleRepository
+	auditRepo     repository.AuditLogRepository
+	oidcStateRepo repository.OIDCStateRepository
+	identityRepo  repository.ExternalIdentityRepository
+	kycRepo       repository.KYCCaseRepository
+	tx            database.Transactor
+	jwtService    *token.JWTService
+	passService   *token.PasswordService
+	notifier      *notifier.Client
+	oidcProviders *oidc.Registry
+	accountTokens AccountTokenConfig
+	mfa           MFAConfig
+	lockout       LockoutConfig
+	oidc          OIDCConfig
+}
+
+// AccountTokenConfig configures email verification and password reset tokens
+type AccountTokenConfig struct {
+	EmailVerificationTTL time.Duration
+	PasswordResetTTL     time.Duration
 }
 
 // NewAuthService creates a new auth service
 func NewAuthService(
 	userRepo repository.UserRepository,
 	docRepo repository.DocumentRepository,
+	tokenRepo repository.ActionTokenRepository,
+	settingsRepo repository.SettingsRepository,
+	throttleRepo repository.LoginThrottleRepository,
+	auditRepo repository.AuditLogRepository,
+	oidcStateRepo repository.OIDCStateRepository,
+	identityRepo repository.ExternalIdentityRepository,
+	kycRepo repository.KYCCaseRepository,
+	tx database.Transactor,
 	jwtService *token.JWTService,
 	passService *token.PasswordService,
+	notifier *notifier.Client,
+	oidcProviders *oidc.Registry,
+	accountTokens AccountTokenConfig,
+	mfa MFAConfig,
+	lockout LockoutConfig,
+	oidcConfig OIDCConfig,
 ) *AuthService {
 	return &AuthService{
-		userRepo:    userRepo,
-		docRepo:     docRepo,
-		jwtService:  jwtService,
-		passService: passService,
+		userRepo:      userRepo,
+		docRepo:       docRepo,
+		tokenRepo:     tokenRepo,
+		settingsRepo:  settingsRepo,
+		throttleRepo:  throttleRepo,
+		auditRepo:     auditRepo,
+		oidcStateRepo: oidcStateRepo,
+		identityRepo:  identityRepo,
+		kycRepo:       kycRepo,
+		tx:            tx,
+		jwtService:    jwtService,
+		passService:   passService,
+		notifier:      notifier,
+		oidcProviders: oidcProviders,
+		accountTokens: accountTokens,
+		mfa:           mfa,
+		lockout:       lockout,
+		oidc:          oidcConfig,
 	}
 }
 
-// AuthResult contains the result of authentication
+// AuthResult contains the result of authentication.
+// When MFARequired is set no tokens are issued; the client must finish
+// the login with MFAToken and a second factor.
 type AuthResult struct {
-	User         *domain.User
-	AccessToken  string
-	RefreshToken string
-	ExpiresIn    int64
+	User             *domain.User
+	AccessToken      string
+	RefreshToken     string
+	ExpiresIn        int64
+	MFARequired      bool
+	MFASetupRequired bool
+	MFAToken         string
 }
 
 // Register registers a new user
@@ -80,49 +137,66 @@ func (s *AuthService) Register(ctx context.Context, email, password, firstName,
 		return nil, err
 	}
 
-	// Generate tokens
-	tokenPair, err := s.jwtService.GenerateTokenPair(user)
+	// Send verification email; registration succeeds even if delivery fails
+	s.sendEmailVerification(ctx, user)
+
+	// Roles that require two-factor authentication must enroll before getting tokens
+	challenge, err := s.mfaChallenge(ctx, user)
 	if err != nil {
 		return nil, err
 	}
-
-	// Store refresh token hash
-	refreshHash := s.passService.HashRefreshToken(tokenPair.RefreshToken)
-	if err := s.userRepo.UpdateRefreshToken(ctx, user.ID, refreshHash, &tokenPair.ExpiresAt); err != nil {
-		return nil, err
+	if challenge != nil {
+		return challenge, nil
 	}
 
-	return &AuthResult{
-		User:         user,
-		AccessToken:  tokenPair.AccessToken,
-		RefreshToken: tokenPair.RefreshToken,
-		ExpiresIn:    tokenPair.ExpiresIn,
-	}, nil
+	return s.issueTokens(ctx, user)
 }
 
 // Login authenticates a user
-func (s *AuthService) Login(ctx context.Context, email, password string) (*AuthResult, error) {
+func (s *AuthService) Login(ctx context.Context, email, password string, client ClientInfo) (*AuthResult, error) {
+	// Refuse attempts while the account or client is locked out
+	if err := s.checkLoginAllowed(ctx, email, client); err != nil {
+		return nil, err
+	}
+
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if err == domain.ErrUserNotFound {
-			return nil, domain.ErrInvalidCredentials
+			return nil, s.recordLoginFailure(ctx, domain.AuditLoginFailure, email, nil, client, "unknown email", domain.ErrInvalidCredentials)
 		}
 		return nil, err
 	}
 
 	// Verify password
 	if !s.passService.VerifyPassword(password, user.PasswordHash) {
-		return nil, domain.ErrInvalidCredentials
+		return nil, s.recordLoginFailure(ctx, domain.AuditLoginFailure, email, user, client, "wrong password", domain.ErrInvalidCredentials)
+	}
+
+	// Hand out a challenge instead of tokens when a second factor is needed.
+	// Counters are only reset once the whole login succeeds.
+	challenge, err := s.mfaChallenge(ctx, user)
+	if err != nil {
+		return nil, err
 	}
+	if challenge != nil {
+		return challenge, nil
+	}
+
+	if err := s.recordLoginSuccess(ctx, user, client); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user)
+}
 
-	// Generate tokens
+// issueTokens generates a token pair and stores the refresh token hash
+func (s *AuthService) issueTokens(ctx context.Context, user *domain.User) (*AuthResult, error) {
 	tokenPair, err := s.jwtService.GenerateTokenPair(user)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store refresh token hash
 	refreshHash := s.passService.HashRefreshToken(tokenPair.RefreshToken)
 	if err := s.userRepo.UpdateRefreshToken(ctx, user.ID, refreshHash, &tokenPair.ExpiresAt); err != nil {
 		return nil, err
@@ -202,6 +276,24 @@ func (s *AuthService) ValidateToken(ctx context.Context, tokenString string) (*t
 	return s.jwtService.ValidateAccessToken(tokenString)
 }
 
+// requireAdmin checks that the acting user may manage users
+func (s *AuthService) requireAdmin(ctx context.Context, userID uuid.UUID) error {
+	return s.requirePermission(ctx, userID, auth.PermUsersManage)
+}
+
+// requirePermission checks that one of the acting user's roles grants perm.
+// The user is reloaded so a role change takes effect before tokens expire.
+func (s *AuthService) requirePermission(ctx context.Context, userID uuid.UUID, perm auth.Permission) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.HasPermission(perm) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
 // GetUserByID retrieves a user by ID
 func (s *AuthService) GetUserByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	return s.userRepo.GetByID(ctx, id)
@@ -323,23 +415,31 @@ func (s *AuthService) GetVerificationStatus(ctx context.Context, userID uuid.UUI
 	return user.VerificationStatus, docs, nil
 }
 
-// VerifyUser updates a user's verification status (admin only)
-func (s *AuthService) VerifyUser(ctx context.Context, userID uuid.UUID, status domain.VerificationStatus) (*domain.User, error) {
-	user, err := s.userRepo.GetByID(ctx, userID)
+// VerifyUser decides the user's pending verification case (admin only).
+// Kept for the VerifyUser RPC; reviewers should use ApproveKYC and RejectKYC
+// so the decision carries a reason.
+func (s *AuthService) VerifyUser(ctx context.Context, adminID, userID uuid.UUID, status domain.VerificationStatus, notes string) (*domain.User, error) {
+	kycCase, err := s.kycRepo.GetLatestByUser(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
-
-	user.VerificationStatus = status
-	if status == domain.VerificationVerified {
-		user.IdentityVerified = true
+	if kycCase == nil || !kycCase.IsPending() {
+		return nil, domain.ErrKYCCaseNotFound
 	}
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
+	switch status {
+	case domain.VerificationVerified:
+		_, err = s.ApproveKYC(ctx, adminID, kycCase.ID, notes)
+	case domain.VerificationRejected:
+		_, err = s.RejectKYC(ctx, adminID, kycCase.ID, notes)
+	default:
+		return nil, domain.ErrInvalidKYCDecision
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	return user, nil
+	return s.userRepo.GetByID(ctx, userID)
 }
 
 // ListUsers lists users with pagination and filters (admin only)
@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/notifier"
+	"github.com/rentalflow/auth-service/internal/oidc"
+	"github.com/rentalflow/auth-service/internal/repository"
+	"github.com/rentalflow/auth-service/internal/service"
+	"github.com/rentalflow/auth-service/internal/token"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	secret   = "test-secret"
+	password = "Password123!"
+)
+
+// client is the address test logins come from
+var client = service.ClientInfo{IPAddress: "192.0.2.1", UserAgent: "test"}
+
+// options changes the configuration newEnv builds the service with
+type options struct {
+	accountTokens service.AccountTokenConfig
+	lockout       service.LockoutConfig
+	oidc          []config.OIDCProviderConfig
+}
+
+// env is an auth service on the memory repositories, with a stand-in for
+// notification-service
+type env struct {
+	svc      *service.AuthService
+	users    *repository.MemoryUserRepository
+	throttle *repository.MemoryLoginThrottleRepository
+	kyc      *repository.MemoryKYCCaseRepository
+	mail     *mailbox
+}
+
+func newEnv(t *testing.T, opts options) *env {
+	t.Helper()
+
+	if opts.accountTokens == (service.AccountTokenConfig{}) {
+		opts.accountTokens = service.AccountTokenConfig{EmailVerificationTTL: time.Hour, PasswordResetTTL: time.Hour}
+	}
+	if opts.lockout == (service.LockoutConfig{}) {
+		opts.lockout = service.LockoutConfig{
+			AccountMaxFailures: 5,
+			IPMaxFailures:      100,
+			FailureWindow:      15 * time.Minute,
+			BaseLockout:        time.Minute,
+			MaxLockout:         time.Hour,
+		}
+	}
+
+	mail := &mailbox{}
+	srv := httptest.NewServer(mail)
+	t.Cleanup(srv.Close)
+
+	e := &env{
+		users:    repository.NewMemoryUserRepository(),
+		throttle: repository.NewMemoryLoginThrottleRepository(),
+		kyc:      repository.NewMemoryKYCCaseRepository(),
+		mail:     mail,
+	}
+	e.svc = service.NewAuthService(
+		e.users,
+		repository.NewMemoryDocumentRepository(),
+		repository.NewMemoryActionTokenRepository(),
+		repository.NewMemorySettingsRepository(),
+		e.throttle,
+		repository.NewMemoryAuditLogRepository(),
+		repository.NewMemoryOIDCStateRepository(),
+		repository.NewMemoryExternalIdentityRepository(),
+		e.kyc,
+		&database.Backend{Memory: true},
+		token.NewJWTService(secret, 15*time.Minute, 24*time.Hour, "rentalflow"),
+		token.NewPasswordService(bcrypt.MinCost),
+		notifier.NewClient(srv.URL, auth.NewChecker(secret)),
+		oidc.NewRegistry(opts.oidc),
+		opts.accountTokens,
+		service.MFAConfig{Issuer: "RentalFlow", ChallengeTTL: 5 * time.Minute},
+		opts.lockout,
+		service.OIDCConfig{StateTTL: 10 * time.Minute},
+	)
+	return e
+}
+
+// register signs up a renter with password
+func (e *env) register(t *testing.T, email string) *domain.User {
+	t.Helper()
+
+	result, err := e.svc.Register(context.Background(), email, password, "Test", "User", "", domain.RoleRenter)
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	return result.User
+}
+
+// mail is an email auth-service asked notification-service to send
+type mail struct {
+	Path  string                 `json:"-"`
+	To    string                 `json:"to"`
+	Token string                 `json:"token"`
+	Data  map[string]interface{} `json:"data"`
+}
+
+// mailbox stands in for notification-service and keeps the emails it is
+// asked to send
+type mailbox struct {
+	mu    sync.Mutex
+	mails []mail
+}
+
+func (m *mailbox) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	msg := mail{Path: r.URL.Path}
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.mails = append(m.mails, msg)
+}
+
+// last returns the latest email sent to an address through an endpoint
+// ending in kind, such as "password-reset"
+func (m *mailbox) last(t *testing.T, to, kind string) mail {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := len(m.mails) - 1; i >= 0; i-- {
+		if m.mails[i].To == to && strings.HasSuffix(m.mails[i].Path, "/"+kind) {
+			return m.mails[i]
+		}
+	}
+	t.Fatalf("no %s email to %s", kind, to)
+	return mail{}
+}
@@ -0,0 +1,471 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/datasubject"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/repository"
+	"github.com/rentalflow/auth-service/internal/token"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+)
+
+// deletionConfirmation must be sent to confirm an account deletion request
+const deletionConfirmation = "DELETE"
+
+// DataRequestConfig configures the data-subject request worker
+type DataRequestConfig struct {
+	PollInterval  time.Duration
+	LeaseDuration time.Duration
+	RetryBackoff  time.Duration
+	MaxAttempts   int
+	ArchiveTTL    time.Duration
+}
+
+// DataSource exports and erases a user's data held by another service
+type DataSource interface {
+	Export(ctx context.Context, userID uuid.UUID) (json.RawMessage, error)
+	Erase(ctx context.Context, userID uuid.UUID) (*datasubject.EraseResult, error)
+}
+
+// DataRequestService runs personal-data exports and account deletions as
+// asynchronous jobs spanning every service that stores user data
+type DataRequestService struct {
+	userRepo     repository.UserRepository
+	docRepo      repository.DocumentRepository
+	tokenRepo    repository.ActionTokenRepository
+	throttleRepo repository.LoginThrottleRepository
+	auditRepo    repository.AuditLogRepository
+	identityRepo repository.ExternalIdentityRepository
+	kycRepo      repository.KYCCaseRepository
+	requestRepo  repository.DataRequestRepository
+	archiveRepo  repository.DataExportArchiveRepository
+	passService  *token.PasswordService
+	sources      map[string]DataSource
+	config       DataRequestConfig
+}
+
+// NewDataRequestService creates a new data-subject request service.
+// sources maps each entry of domain.DataSubjectServices except "auth" to its client.
+func NewDataRequestService(
+	userRepo repository.UserRepository,
+	docRepo repository.DocumentRepository,
+	tokenRepo repository.ActionTokenRepository,
+	throttleRepo repository.LoginThrottleRepository,
+	auditRepo repository.AuditLogRepository,
+	identityRepo repository.ExternalIdentityRepository,
+	kycRepo repository.KYCCaseRepository,
+	requestRepo repository.DataRequestRepository,
+	archiveRepo repository.DataExportArchiveRepository,
+	passService *token.PasswordService,
+	sources map[string]DataSource,
+	config DataRequestConfig,
+) *DataRequestService {
+	return &DataRequestService{
+		userRepo:     userRepo,
+		docRepo:      docRepo,
+		tokenRepo:    tokenRepo,
+		throttleRepo: throttleRepo,
+		auditRepo:    auditRepo,
+		identityRepo: identityRepo,
+		kycRepo:      kycRepo,
+		requestRepo:  requestRepo,
+		archiveRepo:  archiveRepo,
+		passService:  passService,
+		sources:      sources,
+		config:       config,
+	}
+}
+
+// RequestExport queues an export of everything RentalFlow stores about the user
+func (s *DataRequestService) RequestExport(ctx context.Context, userID uuid.UUID) (*domain.DataRequest, error) {
+	return s.queue(ctx, userID, domain.DataRequestExport)
+}
+
+// RequestDeletion queues the erasure of the user's account. confirm must be "DELETE".
+func (s *DataRequestService) RequestDeletion(ctx context.Context, userID uuid.UUID, confirm string) (*domain.DataRequest, error) {
+	if confirm != deletionConfirmation {
+		return nil, domain.ErrDeletionNotConfirmed
+	}
+	return s.queue(ctx, userID, domain.DataRequestDeletion)
+}
+
+// queue creates a request unless one of the same type is already in progress
+func (s *DataRequestService) queue(ctx context.Context, userID uuid.UUID, requestType domain.DataRequestType) (*domain.DataRequest, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDeleted() {
+		return nil, domain.ErrUserNotFound
+	}
+
+	active, err := s.requestRepo.GetActiveByUser(ctx, userID, requestType)
+	if err != nil {
+		return nil, err
+	}
+	if active != nil {
+		return nil, domain.ErrDataRequestInProgress
+	}
+
+	req := domain.NewDataRequest(userID, requestType)
+	if err := s.requestRepo.Create(ctx, req); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, domain.AuditDataRequested, user, nil, string(requestType)+" "+req.ID.String())
+	return req, nil
+}
+
+// GetDataRequest returns a request to its subject or to a data administrator
+func (s *DataRequestService) GetDataRequest(ctx context.Context, actorID, requestID uuid.UUID) (*domain.DataRequest, error) {
+	req, err := s.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if req.UserID == actorID {
+		return req, nil
+	}
+
+	if err := s.requireDataAdmin(ctx, actorID); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// ListUserDataRequests lists the user's own requests, newest first
+func (s *DataRequestService) ListUserDataRequests(ctx context.Context, userID uuid.UUID) ([]*domain.DataRequest, error) {
+	requests, _, err := s.requestRepo.List(ctx, 0, 50, repository.DataRequestFilters{UserID: &userID})
+	return requests, err
+}
+
+// ListDataRequests lists requests across users (user_data:manage)
+func (s *DataRequestService) ListDataRequests(ctx context.Context, adminID uuid.UUID, page, pageSize int, filters repository.DataRequestFilters) ([]*domain.DataRequest, int, error) {
+	if err := s.requireDataAdmin(ctx, adminID); err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	return s.requestRepo.List(ctx, offset, pageSize, filters)
+}
+
+// DownloadExport returns the JSON archive of a completed export. Only the
+// data subject can download it.
+func (s *DataRequestService) DownloadExport(ctx context.Context, userID, requestID uuid.UUID) ([]byte, error) {
+	req, err := s.requestRepo.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if req.UserID != userID || req.Type != domain.DataRequestExport {
+		return nil, domain.ErrDataRequestNotFound
+	}
+	if !req.ArchiveReady {
+		return nil, domain.ErrDataExportNotReady
+	}
+
+	archive, err := s.archiveRepo.GetByRequestID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if archive.IsExpired() {
+		return nil, domain.ErrDataExportNotFound
+	}
+	return archive.Data, nil
+}
+
+// Run processes queued requests until ctx is cancelled
+func (s *DataRequestService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.processDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processDue works through every request that is due, then drops expired archives
+func (s *DataRequestService) processDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		now := time.Now()
+		req, err := s.requestRepo.ClaimNext(ctx, now, now.Add(s.config.LeaseDuration))
+		if err != nil {
+			logger.Ctx(ctx).Error().Err(err).Msg("Failed to claim data request")
+			return
+		}
+		if req == nil {
+			break
+		}
+		s.process(ctx, req)
+	}
+
+	if _, err := s.archiveRepo.DeleteExpired(ctx, time.Now()); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to delete expired data exports")
+	}
+}
+
+// process runs the steps of a claimed request. Deletion steps that already
+// succeeded are skipped on retry; exports start over because partial results
+// are not stored.
+func (s *DataRequestService) process(ctx context.Context, req *domain.DataRequest) {
+	services := make(map[string]json.RawMessage)
+
+	for i := range req.Steps {
+		step := &req.Steps[i]
+		if req.Type == domain.DataRequestDeletion && (step.Status == domain.DataStepDone || step.Status == domain.DataStepRetained) {
+			continue
+		}
+
+		var err error
+		if req.Type == domain.DataRequestExport {
+			var data json.RawMessage
+			data, err = s.exportStep(ctx, step.Service, req.UserID)
+			if err == nil {
+				services[step.Service] = data
+				req.CompleteStep(i, domain.DataStepDone, nil)
+			}
+		} else {
+			var result *datasubject.EraseResult
+			result, err = s.eraseStep(ctx, step.Service, req.UserID)
+			if err == nil {
+				status := domain.DataStepDone
+				if result.Status == "retained" {
+					status = domain.DataStepRetained
+				}
+				req.CompleteStep(i, status, result.Summary)
+			}
+		}
+
+		if err != nil {
+			req.FailStep(i, err)
+			s.retryOrFail(ctx, req, fmt.Errorf("%s: %w", step.Service, err))
+			return
+		}
+	}
+
+	if req.Type == domain.DataRequestExport {
+		if err := s.saveArchive(ctx, req, services); err != nil {
+			s.retryOrFail(ctx, req, err)
+			return
+		}
+		req.ArchiveReady = true
+	}
+
+	req.Complete()
+	if err := s.requestRepo.Save(ctx, req); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to save data request")
+		return
+	}
+
+	if req.Type == domain.DataRequestDeletion {
+		s.audit(ctx, domain.AuditAccountDeleted, nil, &req.UserID, "request "+req.ID.String())
+	}
+}
+
+// retryOrFail requeues a request after a transient error with a growing delay,
+// or fails it when the error is permanent or attempts are exhausted
+func (s *DataRequestService) retryOrFail(ctx context.Context, req *domain.DataRequest, err error) {
+	if datasubject.IsPermanent(err) || req.Attempts >= s.config.MaxAttempts {
+		req.Fail(err)
+	} else {
+		req.Retry(err, time.Now().Add(s.config.RetryBackoff*time.Duration(req.Attempts)))
+	}
+
+	logger.Ctx(ctx).Error().Err(err).Msg("Data request step failed")
+	if err := s.requestRepo.Save(ctx, req); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to save data request")
+	}
+}
+
+// exportStep collects one service's data about the user
+func (s *DataRequestService) exportStep(ctx context.Context, service string, userID uuid.UUID) (json.RawMessage, error) {
+	if service == "auth" {
+		return s.exportAuthData(ctx, userID)
+	}
+
+	source, ok := s.sources[service]
+	if !ok {
+		return nil, fmt.Errorf("no data source configured for %s", service)
+	}
+	return source.Export(ctx, userID)
+}
+
+// eraseStep erases or anonymizes one service's data about the user
+func (s *DataRequestService) eraseStep(ctx context.Context, service string, userID uuid.UUID) (*datasubject.EraseResult, error) {
+	if service == "auth" {
+		return s.eraseAuthData(ctx, userID)
+	}
+
+	source, ok := s.sources[service]
+	if !ok {
+		return nil, fmt.Errorf("no data source configured for %s", service)
+	}
+	return source.Erase(ctx, userID)
+}
+
+// saveArchive stores the combined export as one JSON document
+func (s *DataRequestService) saveArchive(ctx context.Context, req *domain.DataRequest, services map[string]json.RawMessage) error {
+	now := time.Now()
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"request_id":   req.ID,
+		"user_id":      req.UserID,
+		"generated_at": now,
+		"services":     services,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return s.archiveRepo.Save(ctx, &domain.DataExportArchive{
+		RequestID: req.ID,
+		UserID:    req.UserID,
+		Data:      data,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.config.ArchiveTTL),
+	})
+}
+
+// exportAuthData collects the account, linked logins, verification cases,
+// uploaded documents and security log
+func (s *DataRequestService) exportAuthData(ctx context.Context, userID uuid.UUID) (json.RawMessage, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	identities, err := s.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	kycCases, err := s.kycRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	documents, err := s.docRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.auditRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"account":            user,
+		"linked_logins":      identities,
+		"verification_cases": kycCases,
+		"identity_documents": documents,
+		"security_log":       events,
+		"permissions":        user.Permissions(),
+	})
+}
+
+// eraseAuthData anonymizes the account and removes linked logins, documents
+// and outstanding tokens. It is safe to run again after a partial failure.
+func (s *DataRequestService) eraseAuthData(ctx context.Context, userID uuid.UUID) (*datasubject.EraseResult, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsDeleted() {
+		key := domain.ThrottleKey(domain.ThrottleAccount, normalizeEmail(user.Email))
+		if err := s.throttleRepo.Reset(ctx, key); err != nil {
+			return nil, err
+		}
+
+		secret, err := token.GenerateOpaqueToken()
+		if err != nil {
+			return nil, err
+		}
+		passwordHash, err := s.passService.HashPassword(secret)
+		if err != nil {
+			return nil, err
+		}
+
+		user.Anonymize(passwordHash)
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+	}
+
+	identities, err := s.identityRepo.DeleteByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.tokenRepo.DeleteByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	kycCases, err := s.kycRepo.AnonymizeByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.auditRepo.AnonymizeByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := s.docRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range documents {
+		if err := s.docRepo.Delete(ctx, doc.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &datasubject.EraseResult{
+		Status: "erased",
+		Summary: map[string]interface{}{
+			"linked_logins_removed":       identities,
+			"tokens_removed":              tokens,
+			"identity_documents_removed":  len(documents),
+			"verification_cases_redacted": kycCases,
+			"security_events_redacted":    events,
+		},
+	}, nil
+}
+
+// requireDataAdmin checks that the acting user may manage other users' data
+func (s *DataRequestService) requireDataAdmin(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.HasPermission(auth.PermUserDataManage) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// audit writes an audit log entry; failures are logged
+func (s *DataRequestService) audit(ctx context.Context, eventType domain.AuditEventType, user *domain.User, userID *uuid.UUID, reason string) {
+	email := ""
+	if user != nil {
+		userID = &user.ID
+		email = normalizeEmail(user.Email)
+	}
+
+	event := domain.NewAuthAuditEvent(eventType, userID, email, "", "", reason)
+	if err := s.auditRepo.Create(ctx, event); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to write auth audit event")
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/repository"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+)
+
+// maxKYCDocuments caps the number of documents in a single submission
+const maxKYCDocuments = 5
+
+// SubmitKYC opens a verification case for review. A user with a rejected case
+// resubmits by calling this again; the new case links to the previous one.
+func (s *AuthService) SubmitKYC(ctx context.Context, userID uuid.UUID, documents []domain.KYCDocument) (*domain.KYCCase, error) {
+	if err := validateKYCDocuments(documents); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IdentityVerified {
+		return nil, domain.ErrIdentityAlreadyVerified
+	}
+
+	latest, err := s.kycRepo.GetLatestByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil && latest.IsPending() {
+		return nil, domain.ErrKYCCasePending
+	}
+
+	kycCase := domain.NewKYCCase(user, documents, latest)
+	user.VerificationStatus = domain.VerificationPending
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.kycRepo.Create(ctx, kycCase); err != nil {
+			return err
+		}
+		return s.userRepo.Update(ctx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, domain.AuditKYCSubmitted, &user.ID, user.Email, ClientInfo{}, "case "+kycCase.ID.String())
+
+	return kycCase, nil
+}
+
+// GetLatestKYCCase returns the user's most recent verification case (nil if none)
+func (s *AuthService) GetLatestKYCCase(ctx context.Context, userID uuid.UUID) (*domain.KYCCase, error) {
+	return s.kycRepo.GetLatestByUser(ctx, userID)
+}
+
+// GetKYCCase returns a case to its applicant or to a reviewer
+func (s *AuthService) GetKYCCase(ctx context.Context, actorID, caseID uuid.UUID) (*domain.KYCCase, error) {
+	kycCase, err := s.kycRepo.GetByID(ctx, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if kycCase.UserID == actorID {
+		return kycCase, nil
+	}
+
+	if err := s.requirePermission(ctx, actorID, auth.PermKYCReview); err != nil {
+		return nil, err
+	}
+	return kycCase, nil
+}
+
+// ListKYCCases lists verification cases for review (kyc:review)
+func (s *AuthService) ListKYCCases(ctx context.Context, adminID uuid.UUID, page, pageSize int, filters repository.KYCFilters) ([]*domain.KYCCase, int, error) {
+	if err := s.requirePermission(ctx, adminID, auth.PermKYCReview); err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	return s.kycRepo.List(ctx, offset, pageSize, filters)
+}
+
+// ApproveKYC approves a pending case and marks the applicant verified (kyc:review)
+func (s *AuthService) ApproveKYC(ctx context.Context, adminID, caseID uuid.UUID, note string) (*domain.KYCCase, error) {
+	kycCase, user, err := s.pendingKYCCase(ctx, adminID, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	kycCase.Approve(adminID, strings.TrimSpace(note))
+	if err := s.applyKYCDecision(ctx, kycCase, user, domain.VerificationVerified); err != nil {
+		return nil, err
+	}
+
+	s.auditKYCDecision(ctx, domain.AuditKYCApproved, kycCase, user, adminID, note)
+	s.sendKYCDecision(ctx, kycCase, user)
+
+	return kycCase, nil
+}
+
+// RejectKYC rejects a pending case with a reason shown to the applicant (kyc:review)
+func (s *AuthService) RejectKYC(ctx context.Context, adminID, caseID uuid.UUID, reason string) (*domain.KYCCase, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, domain.ErrRejectionReasonRequired
+	}
+
+	kycCase, user, err := s.pendingKYCCase(ctx, adminID, caseID)
+	if err != nil {
+		return nil, err
+	}
+
+	kycCase.Reject(adminID, reason)
+	if err := s.applyKYCDecision(ctx, kycCase, user, domain.VerificationRejected); err != nil {
+		return nil, err
+	}
+
+	s.auditKYCDecision(ctx, domain.AuditKYCRejected, kycCase, user, adminID, reason)
+	s.sendKYCDecision(ctx, kycCase, user)
+
+	return kycCase, nil
+}
+
+// pendingKYCCase loads a case awaiting review together with its applicant.
+// Reviewers cannot decide their own case.
+func (s *AuthService) pendingKYCCase(ctx context.Context, adminID, caseID uuid.UUID) (*domain.KYCCase, *domain.User, error) {
+	if err := s.requirePermission(ctx, adminID, auth.PermKYCReview); err != nil {
+		return nil, nil, err
+	}
+
+	kycCase, err := s.kycRepo.GetByID(ctx, caseID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !kycCase.IsPending() {
+		return nil, nil, domain.ErrKYCCaseAlreadyReviewed
+	}
+	if kycCase.UserID == adminID {
+		return nil, nil, domain.ErrForbidden
+	}
+
+	user, err := s.userRepo.GetByID(ctx, kycCase.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return kycCase, user, nil
+}
+
+// applyKYCDecision stores the decision and updates the applicant's verification flags
+func (s *AuthService) applyKYCDecision(ctx context.Context, kycCase *domain.KYCCase, user *domain.User, status domain.VerificationStatus) error {
+	user.VerificationStatus = status
+	user.IdentityVerified = status == domain.VerificationVerified
+
+	return s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.kycRepo.SaveDecision(ctx, kycCase); err != nil {
+			return err
+		}
+		return s.userRepo.Update(ctx, user)
+	})
+}
+
+// auditKYCDecision records which reviewer decided a case
+func (s *AuthService) auditKYCDecision(ctx context.Context, eventType domain.AuditEventType, kycCase *domain.KYCCase, user *domain.User, adminID uuid.UUID, reason string) {
+	note := "case " + kycCase.ID.String()
+	if reason = strings.TrimSpace(reason); reason != "" {
+		note += ": " + reason
+	}
+
+	event := domain.NewAuthAuditEvent(eventType, &user.ID, normalizeEmail(user.Email), "", "", note)
+	event.ActorID = &adminID
+	if err := s.auditRepo.Create(ctx, event); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to write auth audit event")
+	}
+}
+
+// sendKYCDecision emails the applicant the outcome; delivery failures are logged
+func (s *AuthService) sendKYCDecision(ctx context.Context, kycCase *domain.KYCCase, user *domain.User) {
+	if s.notifier == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"UserName": user.FirstName,
+	}
+
+	var err error
+	if kycCase.Status == domain.KYCStatusApproved {
+		err = s.notifier.SendKYCApproved(ctx, user.Email, data)
+	} else {
+		data["Reason"] = kycCase.RejectionReason
+		err = s.notifier.SendKYCRejected(ctx, user.Email, data)
+	}
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to send verification decision email")
+	}
+}
+
+// validateKYCDocuments checks document types and that URLs point at https storage
+func validateKYCDocuments(documents []domain.KYCDocument) error {
+	if len(documents) == 0 {
+		return domain.ErrNoDocuments
+	}
+	if len(documents) > maxKYCDocuments {
+		return domain.ErrTooManyDocuments
+	}
+
+	for _, doc := range documents {
+		switch doc.Type {
+		case domain.DocTypeDriverLicense, domain.DocTypeNationalID, domain.DocTypePassport:
+		default:
+			return domain.ErrInvalidDocumentType
+		}
+
+		u, err := url.Parse(doc.URL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return domain.ErrInvalidDocumentURL
+		}
+	}
+	return nil
+}
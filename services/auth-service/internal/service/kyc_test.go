@@ -0,0 +1,130 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+)
+
+var idDocument = []domain.KYCDocument{{Type: domain.DocTypeNationalID, URL: "https://storage.example.com/id.jpg"}}
+
+// createAdmin stores an admin, who may review verification cases
+func createAdmin(t *testing.T, e *env) *domain.User {
+	t.Helper()
+
+	admin := domain.NewUser("admin@example.com", "hash", "Admin", "User", "", domain.RoleAdmin)
+	if err := e.users.Create(context.Background(), admin); err != nil {
+		t.Fatalf("create admin: %v", err)
+	}
+	return admin
+}
+
+func TestKYCReview(t *testing.T) {
+	// Each step submits a case or approves or rejects the last one
+	// submitted. Every step but the last must succeed.
+	tests := []struct {
+		name        string
+		steps       []string
+		wantErr     error
+		wantCase    domain.KYCStatus
+		wantUser    domain.VerificationStatus
+		wantAttempt int
+	}{
+		{"Submit", []string{"submit"}, nil, domain.KYCStatusPending, domain.VerificationPending, 1},
+		{"SubmitWhilePending", []string{"submit", "submit"}, domain.ErrKYCCasePending, domain.KYCStatusPending, domain.VerificationPending, 1},
+		{"Approve", []string{"submit", "approve"}, nil, domain.KYCStatusApproved, domain.VerificationVerified, 1},
+		{"Reject", []string{"submit", "reject"}, nil, domain.KYCStatusRejected, domain.VerificationRejected, 1},
+		{"RejectWithoutReason", []string{"submit", "reject without reason"}, domain.ErrRejectionReasonRequired, domain.KYCStatusPending, domain.VerificationPending, 1},
+		{"ApproveTwice", []string{"submit", "approve", "approve"}, domain.ErrKYCCaseAlreadyReviewed, domain.KYCStatusApproved, domain.VerificationVerified, 1},
+		{"RejectApproved", []string{"submit", "approve", "reject"}, domain.ErrKYCCaseAlreadyReviewed, domain.KYCStatusApproved, domain.VerificationVerified, 1},
+		{"ApproveRejected", []string{"submit", "reject", "approve"}, domain.ErrKYCCaseAlreadyReviewed, domain.KYCStatusRejected, domain.VerificationRejected, 1},
+		{"SubmitAfterApproval", []string{"submit", "approve", "submit"}, domain.ErrIdentityAlreadyVerified, domain.KYCStatusApproved, domain.VerificationVerified, 1},
+		{"ResubmitAfterRejection", []string{"submit", "reject", "submit"}, nil, domain.KYCStatusPending, domain.VerificationPending, 2},
+		{"ApproveResubmission", []string{"submit", "reject", "submit", "approve"}, nil, domain.KYCStatusApproved, domain.VerificationVerified, 2},
+		{"ApproveByApplicant", []string{"submit", "approve by applicant"}, domain.ErrForbidden, domain.KYCStatusPending, domain.VerificationPending, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEnv(t, options{})
+			ctx := context.Background()
+			applicant := e.register(t, "owner@example.com")
+			admin := createAdmin(t, e)
+
+			var caseID uuid.UUID
+			for i, step := range tt.steps {
+				var err error
+				switch step {
+				case "submit":
+					var submitted *domain.KYCCase
+					if submitted, err = e.svc.SubmitKYC(ctx, applicant.ID, idDocument); err == nil {
+						caseID = submitted.ID
+					}
+				case "approve":
+					_, err = e.svc.ApproveKYC(ctx, admin.ID, caseID, "")
+				case "approve by applicant":
+					_, err = e.svc.ApproveKYC(ctx, applicant.ID, caseID, "")
+				case "reject":
+					_, err = e.svc.RejectKYC(ctx, admin.ID, caseID, "document is blurry")
+				case "reject without reason":
+					_, err = e.svc.RejectKYC(ctx, admin.ID, caseID, " ")
+				}
+
+				if i < len(tt.steps)-1 {
+					if err != nil {
+						t.Fatalf("%s: %v", step, err)
+					}
+				} else if err != tt.wantErr {
+					t.Fatalf("%s error = %v, want %v", step, err, tt.wantErr)
+				}
+			}
+
+			latest, err := e.kyc.GetByID(ctx, caseID)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if latest.Status != tt.wantCase || latest.Attempt != tt.wantAttempt {
+				t.Errorf("case status, attempt = %s, %d, want %s, %d", latest.Status, latest.Attempt, tt.wantCase, tt.wantAttempt)
+			}
+			if tt.wantAttempt > 1 && latest.PreviousCaseID == nil {
+				t.Error("resubmission does not link to the rejected case")
+			}
+
+			user, err := e.users.GetByID(ctx, applicant.ID)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if user.VerificationStatus != tt.wantUser || user.IdentityVerified != (tt.wantUser == domain.VerificationVerified) {
+				t.Errorf("user verification = %s (identity verified %v), want %s", user.VerificationStatus, user.IdentityVerified, tt.wantUser)
+			}
+		})
+	}
+}
+
+func TestKYCDecisionEmails(t *testing.T) {
+	e := newEnv(t, options{})
+	ctx := context.Background()
+	applicant := e.register(t, "owner@example.com")
+	admin := createAdmin(t, e)
+
+	rejected, err := e.svc.SubmitKYC(ctx, applicant.ID, idDocument)
+	if err != nil {
+		t.Fatalf("SubmitKYC: %v", err)
+	}
+	if _, err := e.svc.RejectKYC(ctx, admin.ID, rejected.ID, "document is blurry"); err != nil {
+		t.Fatalf("RejectKYC: %v", err)
+	}
+	if got := e.mail.last(t, applicant.Email, "kyc-rejected").Data["Reason"]; got != "document is blurry" {
+		t.Errorf("rejection email reason = %v", got)
+	}
+
+	resubmitted, err := e.svc.SubmitKYC(ctx, applicant.ID, idDocument)
+	if err != nil {
+		t.Fatalf("SubmitKYC: %v", err)
+	}
+	if _, err := e.svc.ApproveKYC(ctx, admin.ID, resubmitted.ID, ""); err != nil {
+		t.Fatalf("ApproveKYC: %v", err)
+	}
+	e.mail.last(t, applicant.Email, "kyc-approved")
+}
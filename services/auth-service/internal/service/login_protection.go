@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/repository"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+)
+
+// LockoutConfig configures failed-login counters and exponential lockouts
+type LockoutConfig struct {
+	AccountMaxFailures int
+	IPMaxFailures      int
+	FailureWindow      time.Duration
+	BaseLockout        time.Duration
+	MaxLockout         time.Duration
+}
+
+// ClientInfo identifies where a login attempt came from
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+}
+
+// LockedAccount is an account in an active lockout
+type LockedAccount struct {
+	Email        string
+	UserID       *uuid.UUID
+	LockedUntil  time.Time
+	LockoutCount int
+}
+
+// checkLoginAllowed rejects attempts while the account or client IP is locked out
+func (s *AuthService) checkLoginAllowed(ctx context.Context, email string, client ClientInfo) error {
+	now := time.Now()
+
+	if client.IPAddress != "" {
+		throttle, err := s.throttleRepo.Get(ctx, domain.ThrottleKey(domain.ThrottleIP, client.IPAddress))
+		if err != nil {
+			return err
+		}
+		if throttle != nil && throttle.IsLocked(now) {
+			s.audit(ctx, domain.AuditLoginBlocked, throttle.UserID, email, client, "ip locked")
+			return domain.ErrTooManyAttempts
+		}
+	}
+
+	throttle, err := s.throttleRepo.Get(ctx, domain.ThrottleKey(domain.ThrottleAccount, normalizeEmail(email)))
+	if err != nil {
+		return err
+	}
+	if throttle != nil && throttle.IsLocked(now) {
+		s.audit(ctx, domain.AuditLoginBlocked, throttle.UserID, email, client, "account locked")
+		return domain.ErrAccountLocked
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt against the account and client IP.
+// It returns the error to surface to the caller: a lockout error if this attempt
+// triggered one, otherwise fallback.
+func (s *AuthService) recordLoginFailure(ctx context.Context, eventType domain.AuditEventType, email string, user *domain.User, client ClientInfo, reason string, fallback error) error {
+	var userID *uuid.UUID
+	if user != nil {
+		userID = &user.ID
+	}
+	s.audit(ctx, eventType, userID, email, client, reason)
+
+	now := time.Now()
+	windowStart := now.Add(-s.lockout.FailureWindow)
+	decayStart := now.Add(-s.lockout.MaxLockout)
+
+	if client.IPAddress != "" {
+		throttle, err := s.throttleRepo.RecordFailure(ctx, domain.ThrottleIP, client.IPAddress, nil, windowStart, decayStart)
+		if err != nil {
+			return err
+		}
+		if throttle.Failures >= s.lockout.IPMaxFailures {
+			until := now.Add(s.lockoutDuration(throttle.LockoutCount))
+			if err := s.throttleRepo.Lock(ctx, throttle.Key, until); err != nil {
+				return err
+			}
+			s.audit(ctx, domain.AuditIPLocked, userID, email, client, "until "+until.Format(time.RFC3339))
+		}
+	}
+
+	throttle, err := s.throttleRepo.RecordFailure(ctx, domain.ThrottleAccount, normalizeEmail(email), userID, windowStart, decayStart)
+	if err != nil {
+		return err
+	}
+	if throttle.Failures < s.lockout.AccountMaxFailures {
+		return fallback
+	}
+
+	until := now.Add(s.lockoutDuration(throttle.LockoutCount))
+	if err := s.throttleRepo.Lock(ctx, throttle.Key, until); err != nil {
+		return err
+	}
+	s.audit(ctx, domain.AuditAccountLocked, userID, email, client, "until "+until.Format(time.RFC3339))
+
+	if user != nil {
+		s.sendAccountLocked(ctx, user, until)
+	}
+
+	return domain.ErrAccountLocked
+}
+
+// recordLoginSuccess clears the account counter and logs the login
+func (s *AuthService) recordLoginSuccess(ctx context.Context, user *domain.User, client ClientInfo) error {
+	return s.recordLoginSuccessVia(ctx, user, client, "")
+}
+
+// recordLoginSuccessVia is recordLoginSuccess with the login method noted in the audit log
+func (s *AuthService) recordLoginSuccessVia(ctx context.Context, user *domain.User, client ClientInfo, method string) error {
+	if err := s.throttleRepo.Reset(ctx, domain.ThrottleKey(domain.ThrottleAccount, normalizeEmail(user.Email))); err != nil {
+		return err
+	}
+	s.audit(ctx, domain.AuditLoginSuccess, &user.ID, user.Email, client, method)
+	return nil
+}
+
+// lockoutDuration doubles the base lockout for each previous lockout, up to the maximum
+func (s *AuthService) lockoutDuration(previousLockouts int) time.Duration {
+	duration := s.lockout.BaseLockout
+	for i := 0; i < previousLockouts; i++ {
+		duration *= 2
+		if duration >= s.lockout.MaxLockout {
+			return s.lockout.MaxLockout
+		}
+	}
+	return duration
+}
+
+// ListLockedAccounts lists accounts in an active lockout (admin only)
+func (s *AuthService) ListLockedAccounts(ctx context.Context, adminID uuid.UUID, page, pageSize int) ([]*LockedAccount, int, error) {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	offset := (page - 1) * pageSize
+	throttles, total, err := s.throttleRepo.ListLocked(ctx, domain.ThrottleAccount, time.Now(), offset, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	accounts := make([]*LockedAccount, len(throttles))
+	for i, t := range throttles {
+		accounts[i] = &LockedAccount{
+			Email:        t.Subject,
+			UserID:       t.UserID,
+			LockedUntil:  *t.LockedUntil,
+			LockoutCount: t.LockoutCount,
+		}
+	}
+
+	return accounts, total, nil
+}
+
+// UnlockAccount lifts a lockout and resets its counters (admin only)
+func (s *AuthService) UnlockAccount(ctx context.Context, adminID uuid.UUID, email string) error {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return err
+	}
+
+	key := domain.ThrottleKey(domain.ThrottleAccount, normalizeEmail(email))
+	throttle, err := s.throttleRepo.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if throttle == nil || !throttle.IsLocked(time.Now()) {
+		return domain.ErrLockoutNotFound
+	}
+
+	if err := s.throttleRepo.Reset(ctx, key); err != nil {
+		return err
+	}
+
+	event := domain.NewAuthAuditEvent(domain.AuditAccountUnlocked, throttle.UserID, throttle.Subject, "", "", "unlocked by admin")
+	event.ActorID = &adminID
+	if err := s.auditRepo.Create(ctx, event); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to write auth audit event")
+	}
+
+	return nil
+}
+
+// ListAuditEvents lists auth audit log entries (admin only)
+func (s *AuthService) ListAuditEvents(ctx context.Context, adminID uuid.UUID, page, pageSize int, filters repository.AuditFilters) ([]*domain.AuthAuditEvent, int, error) {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	if filters.Email != "" {
+		filters.Email = normalizeEmail(filters.Email)
+	}
+
+	offset := (page - 1) * pageSize
+	return s.auditRepo.List(ctx, offset, pageSize, filters)
+}
+
+// audit writes an audit log entry; failures are logged rather than failing the login
+func (s *AuthService) audit(ctx context.Context, eventType domain.AuditEventType, userID *uuid.UUID, email string, client ClientInfo, reason string) {
+	event := domain.NewAuthAuditEvent(eventType, userID, normalizeEmail(email), client.IPAddress, client.UserAgent, reason)
+	if err := s.auditRepo.Create(ctx, event); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to write auth audit event")
+	}
+}
+
+// sendAccountLocked emails the user about the lockout; delivery failures are logged
+func (s *AuthService) sendAccountLocked(ctx context.Context, user *domain.User, until time.Time) {
+	if s.notifier == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"UserName":    user.FirstName,
+		"LockedUntil": until.UTC().Format("Jan 2, 2006 15:04 MST"),
+	}
+	if err := s.notifier.SendAccountLocked(ctx, user.Email, data); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to send account locked email")
+	}
+}
+
+// normalizeEmail makes counter keys and audit entries case-insensitive
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,155 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/service"
+)
+
+// accountKey is the throttle key of a user's account counter
+func accountKey(email string) string {
+	return domain.ThrottleKey(domain.ThrottleAccount, email)
+}
+
+// attempt logs in with the right password when ok is set and a wrong one
+// otherwise
+func attempt(e *env, email string, ok bool) error {
+	pw := password
+	if !ok {
+		pw = "wrong-password"
+	}
+	_, err := e.svc.Login(context.Background(), email, pw, client)
+	return err
+}
+
+func TestLoginLockout(t *testing.T) {
+	tests := []struct {
+		name       string
+		attempts   string // f for a failed login, s for a successful one
+		wantErr    error
+		wantLocked bool
+	}{
+		{"BelowThreshold", "ffff", domain.ErrInvalidCredentials, false},
+		{"AtThreshold", "fffff", domain.ErrAccountLocked, true},
+		{"WhileLocked", "fffffs", domain.ErrAccountLocked, true},
+		{"Success", "ffffs", nil, false},
+		{"SuccessResetsCount", "ffffsffff", domain.ErrInvalidCredentials, false},
+		{"SuccessThenThreshold", "ffffsfffff", domain.ErrAccountLocked, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEnv(t, options{})
+			user := e.register(t, "renter@example.com")
+
+			var err error
+			for _, a := range tt.attempts {
+				err = attempt(e, user.Email, a == 's')
+			}
+			if err != tt.wantErr {
+				t.Fatalf("last attempt error = %v, want %v", err, tt.wantErr)
+			}
+
+			throttle, err := e.throttle.Get(context.Background(), accountKey(user.Email))
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if locked := throttle != nil && throttle.IsLocked(time.Now()); locked != tt.wantLocked {
+				t.Errorf("locked = %v, want %v", locked, tt.wantLocked)
+			}
+			if tt.wantErr == nil && throttle != nil {
+				t.Errorf("a successful login left the counter at %d failures", throttle.Failures)
+			}
+		})
+	}
+}
+
+func TestLoginLockoutEscalates(t *testing.T) {
+	lockout := service.LockoutConfig{
+		AccountMaxFailures: 3,
+		IPMaxFailures:      100,
+		FailureWindow:      15 * time.Minute,
+		BaseLockout:        time.Minute,
+		MaxLockout:         10 * time.Minute,
+	}
+
+	tests := []struct {
+		previousLockouts int
+		want             time.Duration
+	}{
+		{0, time.Minute},
+		{1, 2 * time.Minute},
+		{2, 4 * time.Minute},
+		{3, 8 * time.Minute},
+		{4, 10 * time.Minute},
+		{10, 10 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("After%dLockouts", tt.previousLockouts), func(t *testing.T) {
+			e := newEnv(t, options{lockout: lockout})
+			user := e.register(t, "renter@example.com")
+			ctx := context.Background()
+
+			// Lockouts that have ended, as if the account had been locked
+			// before and waited them out
+			for i := 0; i < tt.previousLockouts; i++ {
+				if _, err := e.throttle.RecordFailure(ctx, domain.ThrottleAccount, user.Email, &user.ID, time.Now().Add(-time.Hour), time.Now().Add(-time.Hour)); err != nil {
+					t.Fatalf("RecordFailure: %v", err)
+				}
+				if err := e.throttle.Lock(ctx, accountKey(user.Email), time.Now().Add(-time.Second)); err != nil {
+					t.Fatalf("Lock: %v", err)
+				}
+			}
+
+			start := time.Now()
+			for i := 0; i < lockout.AccountMaxFailures; i++ {
+				attempt(e, user.Email, false)
+			}
+
+			throttle, err := e.throttle.Get(ctx, accountKey(user.Email))
+			if err != nil || throttle == nil || throttle.LockedUntil == nil {
+				t.Fatalf("account was not locked: %+v, %v", throttle, err)
+			}
+			if got := throttle.LockedUntil.Sub(start); got < tt.want || got > tt.want+time.Second {
+				t.Errorf("locked for %v, want %v", got, tt.want)
+			}
+			if throttle.LockoutCount != tt.previousLockouts+1 {
+				t.Errorf("lockout count = %d, want %d", throttle.LockoutCount, tt.previousLockouts+1)
+			}
+		})
+	}
+}
+
+func TestResetPasswordLiftsLockout(t *testing.T) {
+	e := newEnv(t, options{})
+	user := e.register(t, "renter@example.com")
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		attempt(e, user.Email, false)
+	}
+	if err := attempt(e, user.Email, true); err != domain.ErrAccountLocked {
+		t.Fatalf("login after five failures: error = %v, want %v", err, domain.ErrAccountLocked)
+	}
+
+	if err := e.svc.RequestPasswordReset(ctx, user.Email); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	if err := e.svc.ResetPassword(ctx, e.mail.last(t, user.Email, "password-reset").Token, "NewPassword456!"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+
+	throttle, err := e.throttle.Get(ctx, accountKey(user.Email))
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if throttle != nil {
+		t.Errorf("reset left the account counter: %+v", throttle)
+	}
+	if _, err := e.svc.Login(ctx, user.Email, "NewPassword456!", client); err != nil {
+		t.Errorf("Login after the reset: %v", err)
+	}
+}
@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/token"
+)
+
+// MFAConfig configures TOTP two-factor authentication
+type MFAConfig struct {
+	Issuer       string
+	ChallengeTTL time.Duration
+}
+
+// MFAEnrollment contains what a client needs to add the account to an authenticator app
+type MFAEnrollment struct {
+	Secret          string
+	ProvisioningURI string
+}
+
+// mfaChallenge returns a challenge result if the user must present a second factor,
+// or nil if the login can complete with the password alone
+func (s *AuthService) mfaChallenge(ctx context.Context, user *domain.User) (*AuthResult, error) {
+	setupRequired := false
+	if !user.MFAEnabled {
+		policy, err := s.settingsRepo.GetMFAPolicy(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !policy.RequiresMFAForUser(user) {
+			return nil, nil
+		}
+		setupRequired = true
+	}
+
+	mfaToken, err := s.jwtService.GenerateMFAChallengeToken(user.ID, setupRequired, s.mfa.ChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthResult{
+		User:             user,
+		MFARequired:      true,
+		MFASetupRequired: setupRequired,
+		MFAToken:         mfaToken,
+		ExpiresIn:        int64(s.mfa.ChallengeTTL.Seconds()),
+	}, nil
+}
+
+// CompleteMFALogin finishes a login with a TOTP or recovery code.
+// Wrong codes count towards the same lockout as wrong passwords.
+func (s *AuthService) CompleteMFALogin(ctx context.Context, mfaToken, code string, client ClientInfo) (*AuthResult, error) {
+	claims, err := s.jwtService.ValidateMFAChallengeToken(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.challengeUser(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled {
+		return nil, domain.ErrMFANotEnabled
+	}
+
+	if err := s.checkLoginAllowed(ctx, user.Email, client); err != nil {
+		return nil, err
+	}
+
+	if err := s.verifySecondFactor(ctx, user, code); err != nil {
+		if err != domain.ErrInvalidMFACode {
+			return nil, err
+		}
+		return nil, s.recordLoginFailure(ctx, domain.AuditMFAFailure, user.Email, user, client, "invalid code", err)
+	}
+
+	if err := s.recordLoginSuccess(ctx, user, client); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user)
+}
+
+// BeginMFAEnrollmentForChallenge starts enrollment for a user whose role requires
+// two-factor authentication but who has not set it up yet
+func (s *AuthService) BeginMFAEnrollmentForChallenge(ctx context.Context, mfaToken string) (*MFAEnrollment, error) {
+	userID, err := s.setupChallengeUserID(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.BeginMFAEnrollment(ctx, userID)
+}
+
+// ConfirmMFAEnrollmentForChallenge confirms enrollment started from a login
+// challenge and completes that login
+func (s *AuthService) ConfirmMFAEnrollmentForChallenge(ctx context.Context, mfaToken, code string, client ClientInfo) (*AuthResult, []string, error) {
+	userID, err := s.setupChallengeUserID(mfaToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recoveryCodes, err := s.ConfirmMFAEnrollment(ctx, userID, code)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.recordLoginSuccess(ctx, user, client); err != nil {
+		return nil, nil, err
+	}
+
+	result, err := s.issueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result, recoveryCodes, nil
+}
+
+// BeginMFAEnrollment generates a new TOTP secret awaiting confirmation
+func (s *AuthService) BeginMFAEnrollment(ctx context.Context, userID uuid.UUID) (*MFAEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := token.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	user.StartMFAEnrollment(secret)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: token.TOTPProvisioningURI(s.mfa.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmMFAEnrollment activates two-factor authentication once the user proves
+// their authenticator works, returning plaintext recovery codes shown only once
+func (s *AuthService) ConfirmMFAEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+	if user.MFAPendingSecret == "" {
+		return nil, domain.ErrMFAEnrollmentNotFound
+	}
+
+	step, ok := token.ValidateTOTP(user.MFAPendingSecret, code, time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+
+	recoveryCodes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	user.EnableMFA(hashes)
+	user.MFALastUsedStep = step
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// DisableMFA turns off two-factor authentication after re-checking both factors
+func (s *AuthService) DisableMFA(ctx context.Context, userID uuid.UUID, password, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return domain.ErrMFANotEnabled
+	}
+
+	policy, err := s.settingsRepo.GetMFAPolicy(ctx)
+	if err != nil {
+		return err
+	}
+	if policy.RequiresMFAForUser(user) {
+		return domain.ErrMFARequiredByPolicy
+	}
+
+	if !s.passService.VerifyPassword(password, user.PasswordHash) {
+		return domain.ErrInvalidCredentials
+	}
+	if err := s.verifySecondFactor(ctx, user, code); err != nil {
+		return err
+	}
+
+	user.DisableMFA()
+	return s.userRepo.Update(ctx, user)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, invalidating the old ones
+func (s *AuthService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled {
+		return nil, domain.ErrMFANotEnabled
+	}
+
+	step, ok := token.ValidateTOTP(user.MFASecret, code, time.Now())
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+	if err := s.userRepo.RecordMFAStep(ctx, user.ID, step); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, hashes, err := s.newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload so the step just recorded is not overwritten
+	user, err = s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.MFARecoveryCodes = hashes
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// GetMFAPolicy returns the roles that must use two-factor authentication
+func (s *AuthService) GetMFAPolicy(ctx context.Context) (*domain.MFAPolicy, error) {
+	return s.settingsRepo.GetMFAPolicy(ctx)
+}
+
+// UpdateMFAPolicy sets the roles that must use two-factor authentication (admin only)
+func (s *AuthService) UpdateMFAPolicy(ctx context.Context, adminID uuid.UUID, roles []domain.UserRole) (*domain.MFAPolicy, error) {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[domain.UserRole]bool)
+	required := make([]domain.UserRole, 0, len(roles))
+	for _, role := range roles {
+		if !role.IsValid() {
+			return nil, domain.ErrInvalidRole
+		}
+		if !seen[role] {
+			seen[role] = true
+			required = append(required, role)
+		}
+	}
+
+	policy := &domain.MFAPolicy{
+		RequiredRoles: required,
+		UpdatedBy:     &adminID,
+		UpdatedAt:     time.Now(),
+	}
+	if err := s.settingsRepo.SaveMFAPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// verifySecondFactor accepts either a current TOTP code or an unused recovery code
+func (s *AuthService) verifySecondFactor(ctx context.Context, user *domain.User, code string) error {
+	if step, ok := token.ValidateTOTP(user.MFASecret, code, time.Now()); ok {
+		return s.userRepo.RecordMFAStep(ctx, user.ID, step)
+	}
+
+	normalized := token.NormalizeRecoveryCode(code)
+	if normalized == "" {
+		return domain.ErrInvalidMFACode
+	}
+	return s.userRepo.ConsumeRecoveryCode(ctx, user.ID, s.passService.HashRefreshToken(normalized))
+}
+
+// newRecoveryCodes generates recovery codes and their storage hashes
+func (s *AuthService) newRecoveryCodes() ([]string, []string, error) {
+	codes, err := token.GenerateRecoveryCodes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = s.passService.HashRefreshToken(token.NormalizeRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// challengeUser loads the user a challenge token was issued to
+func (s *AuthService) challengeUser(ctx context.Context, claims *token.MFAChallengeClaims) (*domain.User, error) {
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+	return s.userRepo.GetByID(ctx, userID)
+}
+
+// setupChallengeUserID validates a challenge issued to a user who must enroll
+func (s *AuthService) setupChallengeUserID(mfaToken string) (uuid.UUID, error) {
+	claims, err := s.jwtService.ValidateMFAChallengeToken(mfaToken)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if !claims.SetupRequired {
+		return uuid.Nil, domain.ErrInvalidMFAChallenge
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, domain.ErrInvalidMFAChallenge
+	}
+	return userID, nil
+}
@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rentalflow/auth-service/internal/domain"
+)
+
+// totpCode computes the code of a base32 secret for the step holding at
+func totpCode(t *testing.T, secret string, at time.Time) string {
+	t.Helper()
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(at.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1000000)
+}
+
+// enrollMFA turns on two-factor authentication for a user, confirming it with
+// the code of the previous step, and returns the secret
+func enrollMFA(t *testing.T, e *env, user *domain.User) string {
+	t.Helper()
+
+	enrollment, err := e.svc.BeginMFAEnrollment(context.Background(), user.ID)
+	if err != nil {
+		t.Fatalf("BeginMFAEnrollment: %v", err)
+	}
+	code := totpCode(t, enrollment.Secret, time.Now().Add(-30*time.Second))
+	if _, err := e.svc.ConfirmMFAEnrollment(context.Background(), user.ID, code); err != nil {
+		t.Fatalf("ConfirmMFAEnrollment: %v", err)
+	}
+	return enrollment.Secret
+}
+
+func TestCompleteMFALogin(t *testing.T) {
+	// Codes are computed from the step enrollment was confirmed with, which
+	// is the one before the current step
+	tests := []struct {
+		name    string
+		offset  time.Duration
+		wantErr error
+	}{
+		{"CurrentStep", 30 * time.Second, nil},
+		{"NextStep", 60 * time.Second, nil},
+		{"StepUsedAtEnrollment", 0, domain.ErrInvalidMFACode},
+		{"OutsideSkew", -60 * time.Second, domain.ErrInvalidMFACode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newEnv(t, options{})
+			user := e.register(t, "renter@example.com")
+			secret := enrollMFA(t, e, user)
+			enrolled, err := e.users.GetByID(context.Background(), user.ID)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			code := totpCode(t, secret, time.Unix(enrolled.MFALastUsedStep*30, 0).Add(tt.offset))
+
+			_, err = e.svc.CompleteMFALogin(context.Background(), mfaChallenge(t, e, user), code, client)
+			if err != tt.wantErr {
+				t.Fatalf("CompleteMFALogin error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			// Each step's code signs in once
+			if _, err := e.svc.CompleteMFALogin(context.Background(), mfaChallenge(t, e, user), code, client); err != domain.ErrInvalidMFACode {
+				t.Errorf("replayed code: error = %v, want %v", err, domain.ErrInvalidMFACode)
+			}
+		})
+	}
+}
+
+func TestCompleteMFALoginRecoveryCode(t *testing.T) {
+	e := newEnv(t, options{})
+	user := e.register(t, "renter@example.com")
+	enrollment, err := e.svc.BeginMFAEnrollment(context.Background(), user.ID)
+	if err != nil {
+		t.Fatalf("BeginMFAEnrollment: %v", err)
+	}
+	recoveryCodes, err := e.svc.ConfirmMFAEnrollment(context.Background(), user.ID, totpCode(t, enrollment.Secret, time.Now()))
+	if err != nil {
+		t.Fatalf("ConfirmMFAEnrollment: %v", err)
+	}
+
+	if _, err := e.svc.CompleteMFALogin(context.Background(), mfaChallenge(t, e, user), recoveryCodes[0], client); err != nil {
+		t.Fatalf("CompleteMFALogin with a recovery code: %v", err)
+	}
+	if _, err := e.svc.CompleteMFALogin(context.Background(), mfaChallenge(t, e, user), recoveryCodes[0], client); err != domain.ErrInvalidMFACode {
+		t.Errorf("reused recovery code: error = %v, want %v", err, domain.ErrInvalidMFACode)
+	}
+}
+
+// mfaChallenge logs a user with two-factor authentication in with their
+// password and returns the challenge token
+func mfaChallenge(t *testing.T, e *env, user *domain.User) string {
+	t.Helper()
+
+	result, err := e.svc.Login(context.Background(), user.Email, password, client)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if !result.MFARequired || result.MFAToken == "" {
+		t.Fatalf("Login did not ask for a second factor: %+v", result)
+	}
+	return result.MFAToken
+}
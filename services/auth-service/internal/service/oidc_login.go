@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/oidc"
+	"github.com/rentalflow/auth-service/internal/token"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"golang.org/x/oauth2"
+)
+
+// OIDCConfig configures social login
+type OIDCConfig struct {
+	StateTTL time.Duration
+}
+
+// OIDCAuthorization is where to send the browser to start a social login
+type OIDCAuthorization struct {
+	AuthorizationURL string
+	State            string
+	ExpiresIn        int64
+}
+
+// ListOIDCProviders returns the configured social login providers
+func (s *AuthService) ListOIDCProviders() []oidc.ProviderInfo {
+	if s.oidcProviders == nil {
+		return []oidc.ProviderInfo{}
+	}
+	return s.oidcProviders.Providers()
+}
+
+// StartOIDCLogin creates a single-use state with a nonce and PKCE verifier
+// and returns the provider authorization URL
+func (s *AuthService) StartOIDCLogin(ctx context.Context, provider string) (*OIDCAuthorization, error) {
+	if s.oidcProviders == nil {
+		return nil, domain.ErrUnknownOIDCProvider
+	}
+
+	state, err := token.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := token.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	authURL, err := s.oidcProviders.AuthCodeURL(ctx, provider, state, nonce, verifier)
+	if err != nil {
+		if errors.Is(err, oidc.ErrUnknownProvider) {
+			return nil, domain.ErrUnknownOIDCProvider
+		}
+		return nil, err
+	}
+
+	loginState := domain.NewOIDCLoginState(s.passService.HashRefreshToken(state), provider, nonce, verifier, s.oidc.StateTTL)
+	if err := s.oidcStateRepo.Create(ctx, loginState); err != nil {
+		return nil, err
+	}
+
+	return &OIDCAuthorization{
+		AuthorizationURL: authURL,
+		State:            state,
+		ExpiresIn:        int64(s.oidc.StateTTL.Seconds()),
+	}, nil
+}
+
+// CompleteOIDCLogin redeems the authorization code returned to the callback,
+// verifies the ID token and signs in the linked user. A first login links the
+// provider account to an existing user with the same verified email, or creates
+// a new renter.
+func (s *AuthService) CompleteOIDCLogin(ctx context.Context, state, code string, client ClientInfo) (*AuthResult, error) {
+	if state == "" || code == "" {
+		return nil, domain.ErrInvalidOIDCState
+	}
+
+	loginState, err := s.oidcStateRepo.Consume(ctx, s.passService.HashRefreshToken(state))
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.oidcProviders.Exchange(ctx, loginState.Provider, code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("OIDC code exchange failed")
+		return nil, domain.ErrOIDCAuthenticationFailed
+	}
+
+	user, err := s.oidcUser(ctx, identity, client)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkLoginAllowed(ctx, user.Email, client); err != nil {
+		return nil, err
+	}
+
+	// The provider is the first factor; local two-factor policy still applies
+	challenge, err := s.mfaChallenge(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return challenge, nil
+	}
+
+	if err := s.recordLoginSuccessVia(ctx, user, client, "oidc:"+identity.Provider); err != nil {
+		return nil, err
+	}
+
+	return s.issueTokens(ctx, user)
+}
+
+// oidcUser resolves the user for a verified provider identity, linking or
+// creating an account on first login
+func (s *AuthService) oidcUser(ctx context.Context, identity *oidc.Identity, client ClientInfo) (*domain.User, error) {
+	link, err := s.identityRepo.GetByProviderSubject(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil {
+		if err := s.identityRepo.TouchLogin(ctx, link.ID, identity.Email); err != nil {
+			return nil, err
+		}
+		return s.userRepo.GetByID(ctx, link.UserID)
+	}
+
+	// Linking by email is only safe when the provider vouches for the address
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, domain.ErrOIDCEmailNotVerified
+	}
+
+	// The account is only claimed or created along with its link, so a
+	// failed login can be retried
+	var user *domain.User
+	err = s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.userRepo.GetByEmail(ctx, identity.Email)
+		switch {
+		case err == nil:
+			if !user.EmailVerified {
+				// Someone registered this address without proving they own it.
+				// Drop their credentials so a pre-registered account cannot be used
+				// to hijack the provider login.
+				if err := s.claimUnverifiedUser(ctx, user); err != nil {
+					return err
+				}
+			}
+		case err == domain.ErrUserNotFound:
+			user, err = s.createOIDCUser(ctx, identity)
+			if err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+
+		link := domain.NewExternalIdentity(user.ID, identity.Provider, identity.Subject, identity.Email)
+		return s.identityRepo.Create(ctx, link)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.audit(ctx, domain.AuditIdentityLinked, &user.ID, user.Email, client, "oidc:"+identity.Provider)
+
+	return user, nil
+}
+
+// createOIDCUser creates a renter for a first-time social login. The account
+// gets an unusable random password; the user can set one via password reset.
+func (s *AuthService) createOIDCUser(ctx context.Context, identity *oidc.Identity) (*domain.User, error) {
+	passwordHash, err := s.unusablePasswordHash()
+	if err != nil {
+		return nil, err
+	}
+
+	firstName, lastName := identity.GivenName, identity.FamilyName
+	if firstName == "" && lastName == "" {
+		firstName, lastName, _ = strings.Cut(identity.Name, " ")
+	}
+
+	user := domain.NewUser(identity.Email, passwordHash, firstName, lastName, "", domain.RoleRenter)
+	user.MarkEmailVerified()
+
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// claimUnverifiedUser verifies the email of an existing account and resets its
+// password, sessions and two-factor settings
+func (s *AuthService) claimUnverifiedUser(ctx context.Context, user *domain.User) error {
+	passwordHash, err := s.unusablePasswordHash()
+	if err != nil {
+		return err
+	}
+
+	user.PasswordHash = passwordHash
+	user.ClearRefreshToken()
+	user.DisableMFA()
+	user.MarkEmailVerified()
+
+	return s.userRepo.Update(ctx, user)
+}
+
+// unusablePasswordHash hashes a random secret nobody knows
+func (s *AuthService) unusablePasswordHash() (string, error) {
+	secret, err := token.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	return s.passService.HashPassword(secret)
+}
@@ -0,0 +1,293 @@
+package service_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/config"
+)
+
+// clientID is the client the tests register with the issuer
+const clientID = "rentalflow"
+
+// issuer is a stand-in OpenID provider. It serves discovery, its signing key
+// and a token endpoint that checks the PKCE verifier of each code.
+type issuer struct {
+	srv *httptest.Server
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	grants map[string]grant
+}
+
+// grant is an authorization code and what redeeming it returns
+type grant struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newIssuer(t *testing.T) *issuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	i := &issuer{key: key, grants: make(map[string]grant)}
+	i.srv = httptest.NewServer(i)
+	t.Cleanup(i.srv.Close)
+	return i
+}
+
+// provider is the config of a provider named "test" backed by the issuer
+func (i *issuer) provider() config.OIDCProviderConfig {
+	return config.OIDCProviderConfig{
+		Name:         "test",
+		IssuerURL:    i.srv.URL,
+		ClientID:     clientID,
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost/auth/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+	}
+}
+
+func (i *issuer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                i.srv.URL,
+			"authorization_endpoint":                i.srv.URL + "/authorize",
+			"token_endpoint":                        i.srv.URL + "/token",
+			"jwks_uri":                              i.srv.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	case "/jwks":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "test",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(i.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(i.key.E)).Bytes()),
+			}},
+		})
+	case "/token":
+		i.token(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// token redeems a code once, if the verifier matches its challenge
+func (i *issuer) token(w http.ResponseWriter, r *http.Request) {
+	i.mu.Lock()
+	g, ok := i.grants[r.FormValue("code")]
+	delete(i.grants, r.FormValue("code"))
+	i.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, g.claims)
+	idToken.Header["kid"] = "test"
+	signed, err := idToken.SignedString(i.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"access_token": "provider-access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signed,
+	})
+}
+
+// authorize plays a user signing in at the provider. It issues a code for
+// the login started with authURL whose ID token carries claims, and returns
+// the state and code the browser brings back.
+func (i *issuer) authorize(t *testing.T, authURL string, claims jwt.MapClaims) (string, string) {
+	t.Helper()
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("parse authorization URL: %v", err)
+	}
+	query := u.Query()
+	if query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("authorization URL has no S256 challenge: %s", authURL)
+	}
+
+	idClaims := jwt.MapClaims{
+		"iss":   i.srv.URL,
+		"aud":   clientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": query.Get("nonce"),
+	}
+	for k, v := range claims {
+		idClaims[k] = v
+	}
+
+	code := "code-" + query.Get("state")
+	i.mu.Lock()
+	i.grants[code] = grant{challenge: query.Get("code_challenge"), claims: idClaims}
+	i.mu.Unlock()
+	return query.Get("state"), code
+}
+
+// verifiedIdentity is an ID token's claims for a provider account whose
+// email the provider has verified
+func verifiedIdentity(subject, email string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":            subject,
+		"email":          email,
+		"email_verified": true,
+		"given_name":     "Social",
+		"family_name":    "User",
+	}
+}
+
+func TestCompleteOIDCLogin(t *testing.T) {
+	const email = "renter@example.com"
+
+	tests := []struct {
+		name string
+		// setup prepares the local accounts and returns the ID of the user
+		// the login should sign in; without it the login is a new user
+		setup   func(t *testing.T, e *env) string
+		claims  jwt.MapClaims
+		wantErr error
+		// The account's password still works after the login
+		keepsPassword bool
+	}{
+		{"NewUser", nil, verifiedIdentity("sub-1", email), nil, false},
+		{"LinksVerifiedEmail", func(t *testing.T, e *env) string {
+			user := e.register(t, email)
+			if _, err := e.svc.VerifyEmail(context.Background(), mailedVerification(t, e, user)); err != nil {
+				t.Fatalf("VerifyEmail: %v", err)
+			}
+			return user.ID.String()
+		}, verifiedIdentity("sub-1", email), nil, true},
+		// Someone registered the address without proving they own it, so
+		// the provider account takes it over and their password stops working
+		{"ClaimsUnverifiedAccount", func(t *testing.T, e *env) string {
+			return e.register(t, email).ID.String()
+		}, verifiedIdentity("sub-1", email), nil, false},
+		{"RefusesUnverifiedEmail", func(t *testing.T, e *env) string {
+			return e.register(t, email).ID.String()
+		}, jwt.MapClaims{"sub": "sub-1", "email": email, "email_verified": false}, domain.ErrOIDCEmailNotVerified, true},
+		{"RefusesMissingEmail", nil, jwt.MapClaims{"sub": "sub-1"}, domain.ErrOIDCEmailNotVerified, false},
+		{"NonceMismatch", nil, jwt.MapClaims{"sub": "sub-1", "email": email, "email_verified": true, "nonce": "another-login"}, domain.ErrOIDCAuthenticationFailed, false},
+		{"WrongAudience", nil, jwt.MapClaims{"sub": "sub-1", "email": email, "email_verified": true, "aud": "another-client"}, domain.ErrOIDCAuthenticationFailed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newIssuer(t)
+			e := newEnv(t, options{oidc: []config.OIDCProviderConfig{idp.provider()}})
+			ctx := context.Background()
+
+			wantUserID := ""
+			if tt.setup != nil {
+				wantUserID = tt.setup(t, e)
+			}
+
+			start, err := e.svc.StartOIDCLogin(ctx, "test")
+			if err != nil {
+				t.Fatalf("StartOIDCLogin: %v", err)
+			}
+			state, code := idp.authorize(t, start.AuthorizationURL, tt.claims)
+
+			result, err := e.svc.CompleteOIDCLogin(ctx, state, code, client)
+			if err != tt.wantErr {
+				t.Fatalf("CompleteOIDCLogin error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil {
+				if wantUserID != "" && result.User.ID.String() != wantUserID {
+					t.Errorf("signed in user %s, want %s", result.User.ID, wantUserID)
+				}
+				if !result.User.EmailVerified || result.AccessToken == "" {
+					t.Errorf("login result: verified %v, access token %q", result.User.EmailVerified, result.AccessToken)
+				}
+			} else if tt.setup == nil {
+				if _, err := e.users.GetByEmail(ctx, email); err != domain.ErrUserNotFound {
+					t.Errorf("failed login created a user: %v", err)
+				}
+			}
+
+			if tt.setup != nil {
+				_, err := e.svc.Login(ctx, email, password, client)
+				if keeps := err == nil; keeps != tt.keepsPassword {
+					t.Errorf("password login error = %v, want it to work %v", err, tt.keepsPassword)
+				}
+			}
+		})
+	}
+}
+
+func TestCompleteOIDCLoginPKCEAndState(t *testing.T) {
+	idp := newIssuer(t)
+	e := newEnv(t, options{oidc: []config.OIDCProviderConfig{idp.provider()}})
+	ctx := context.Background()
+
+	// A code issued to one login cannot finish another, since the issuer
+	// checks it against the first login's PKCE challenge
+	first, err := e.svc.StartOIDCLogin(ctx, "test")
+	if err != nil {
+		t.Fatalf("StartOIDCLogin: %v", err)
+	}
+	second, err := e.svc.StartOIDCLogin(ctx, "test")
+	if err != nil {
+		t.Fatalf("StartOIDCLogin: %v", err)
+	}
+	_, code := idp.authorize(t, first.AuthorizationURL, verifiedIdentity("sub-1", "renter@example.com"))
+	if _, err := e.svc.CompleteOIDCLogin(ctx, second.State, code, client); err != domain.ErrOIDCAuthenticationFailed {
+		t.Fatalf("code of another login: error = %v, want %v", err, domain.ErrOIDCAuthenticationFailed)
+	}
+
+	// Each state finishes one login
+	state, code := idp.authorize(t, first.AuthorizationURL, verifiedIdentity("sub-1", "renter@example.com"))
+	result, err := e.svc.CompleteOIDCLogin(ctx, state, code, client)
+	if err != nil {
+		t.Fatalf("CompleteOIDCLogin: %v", err)
+	}
+	if _, err := e.svc.CompleteOIDCLogin(ctx, state, code, client); err != domain.ErrInvalidOIDCState {
+		t.Errorf("reused state: error = %v, want %v", err, domain.ErrInvalidOIDCState)
+	}
+
+	// A returning provider account signs in to the user it was linked to
+	// whatever its email is now
+	next, err := e.svc.StartOIDCLogin(ctx, "test")
+	if err != nil {
+		t.Fatalf("StartOIDCLogin: %v", err)
+	}
+	state, code = idp.authorize(t, next.AuthorizationURL, verifiedIdentity("sub-1", "renamed@example.com"))
+	again, err := e.svc.CompleteOIDCLogin(ctx, state, code, client)
+	if err != nil {
+		t.Fatalf("returning CompleteOIDCLogin: %v", err)
+	}
+	if again.User.ID != result.User.ID {
+		t.Errorf("returning login signed in %s, want %s", again.User.ID, result.User.ID)
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+)
+
+// AddRole lets users take on another marketplace role, such as a renter who
+// starts listing items. Admin cannot be self-assigned. Fresh tokens are issued
+// so the new permissions apply immediately.
+func (s *AuthService) AddRole(ctx context.Context, userID uuid.UUID, role domain.UserRole) (*AuthResult, error) {
+	if !role.IsValid() {
+		return nil, domain.ErrInvalidRole
+	}
+	if role == domain.RoleAdmin {
+		return nil, domain.ErrRoleNotSelfService
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.HasRole(role) {
+		user.AddRole(role)
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return nil, err
+		}
+		s.audit(ctx, domain.AuditRoleAdded, &user.ID, user.Email, ClientInfo{}, string(role))
+	}
+
+	return s.issueTokens(ctx, user)
+}
+
+// SetUserRoles replaces a user's roles (admin only). Changes reach other
+// services when the user's access token is next refreshed.
+func (s *AuthService) SetUserRoles(ctx context.Context, adminID, userID uuid.UUID, roles []domain.UserRole) (*domain.User, error) {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[domain.UserRole]bool)
+	unique := make([]domain.UserRole, 0, len(roles))
+	for _, role := range roles {
+		if !role.IsValid() {
+			return nil, domain.ErrInvalidRole
+		}
+		if !seen[role] {
+			seen[role] = true
+			unique = append(unique, role)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, domain.ErrNoRoles
+	}
+	if adminID == userID && !seen[domain.RoleAdmin] {
+		return nil, domain.ErrRemoveOwnAdminRole
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.SetRoles(unique)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(unique))
+	for i, role := range unique {
+		names[i] = string(role)
+	}
+	event := domain.NewAuthAuditEvent(domain.AuditRolesChanged, &user.ID, normalizeEmail(user.Email), "", "", strings.Join(names, ","))
+	event.ActorID = &adminID
+	if err := s.auditRepo.Create(ctx, event); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Msg("Failed to write auth audit event")
+	}
+
+	return user, nil
+}
+
+// CreateAdmin creates an administrator, which no API can do until the first
+// one exists. It is for bootstrapping a deployment and is not served.
+func (s *AuthService) CreateAdmin(ctx context.Context, email, password, firstName, lastName string) (*domain.User, error) {
+	if err := s.passService.ValidatePasswordStrength(password); err != nil {
+		return nil, err
+	}
+
+	_, err := s.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		return nil, domain.ErrUserAlreadyExists
+	}
+	if err != domain.ErrUserNotFound {
+		return nil, err
+	}
+
+	passwordHash, err := s.passService.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := domain.NewUser(email, passwordHash, firstName, lastName, "", domain.RoleAdmin)
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	s.audit(ctx, domain.AuditRoleAdded, &user.ID, user.Email, ClientInfo{}, string(domain.RoleAdmin))
+	return user, nil
+}
@@ -14,11 +14,24 @@ import (
 // Claims represents the JWT claims
 type Claims struct {
 	jwt.RegisteredClaims
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID        string   `json:"user_id"`
+	Email         string   `json:"email"`
+	Role          string   `json:"role"`
+	Roles         []string `json:"roles"`
+	Permissions   []string `json:"permissions"`
+	EmailVerified bool     `json:"email_verified"`
 }
 
+// MFAChallengeClaims represents the claims of a short-lived two-factor challenge token.
+// It carries no user_id claim so it can never pass as an access token.
+type MFAChallengeClaims struct {
+	jwt.RegisteredClaims
+	SetupRequired bool `json:"mfa_setup_required"`
+}
+
+// mfaChallengeAudience distinguishes challenge tokens from access tokens
+const mfaChallengeAudience = "mfa-challenge"
+
 // JWTService handles JWT token generation and validation
 type JWTService struct {
 	secretKey       []byte
@@ -82,17 +95,34 @@ func (s *JWTService) generateAccessToken(user *domain.User) (string, error) {
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			ID:        uuid.New().String(),
 		},
-		UserID: user.ID.String(),
-		Email:  user.Email,
-		Role:   string(user.Role),
+		UserID:        user.ID.String(),
+		Email:         user.Email,
+		Role:          string(user.Role),
+		Roles:         roleNames(user.RoleList()),
+		Permissions:   user.Permissions(),
+		EmailVerified: user.EmailVerified,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString(s.secretKey)
 }
 
+// roleNames converts roles to their claim values
+func roleNames(roles []domain.UserRole) []string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = string(role)
+	}
+	return names
+}
+
 // generateRefreshToken generates a random refresh token
 func (s *JWTService) generateRefreshToken() (string, error) {
+	return GenerateOpaqueToken()
+}
+
+// GenerateOpaqueToken generates a random URL-safe token for single-use links
+func GenerateOpaqueToken() (string, error) {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
 		return "", err
@@ -118,13 +148,53 @@ func (s *JWTService) ValidateAccessToken(tokenString string) (*Claims, error) {
 	}
 
 	claims, ok := token.Claims.(*Claims)
-	if !ok || !token.Valid {
+	if !ok || !token.Valid || len(claims.Audience) > 0 {
 		return nil, domain.ErrInvalidToken
 	}
 
 	return claims, nil
 }
 
+// GenerateMFAChallengeToken issues a token that proves the password step of a login succeeded
+func (s *JWTService) GenerateMFAChallengeToken(userID uuid.UUID, setupRequired bool, ttl time.Duration) (string, error) {
+	now := time.Now()
+
+	claims := MFAChallengeClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   userID.String(),
+			Audience:  jwt.ClaimStrings{mfaChallengeAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			ID:        uuid.New().String(),
+		},
+		SetupRequired: setupRequired,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(s.secretKey)
+}
+
+// ValidateMFAChallengeToken validates a challenge token and returns its claims
+func (s *JWTService) ValidateMFAChallengeToken(tokenString string) (*MFAChallengeClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &MFAChallengeClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("invalid signing method")
+		}
+		return s.secretKey, nil
+	}, jwt.WithAudience(mfaChallengeAudience))
+	if err != nil {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+
+	claims, ok := token.Claims.(*MFAChallengeClaims)
+	if !ok || !token.Valid {
+		return nil, domain.ErrInvalidMFAChallenge
+	}
+
+	return claims, nil
+}
+
 // GetAccessTokenDuration returns the access token duration
 func (s *JWTService) GetAccessTokenDuration() time.Duration {
 	return s.accessDuration
@@ -0,0 +1,74 @@
+package token_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/auth-service/internal/domain"
+	"github.com/rentalflow/auth-service/internal/token"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+)
+
+const secret = "test-secret"
+
+// TestTokenAudiences checks that a two-factor challenge cannot be used as an
+// access token and the other way round
+func TestTokenAudiences(t *testing.T) {
+	jwtService := token.NewJWTService(secret, 15*time.Minute, 24*time.Hour, "rentalflow")
+	user := domain.NewUser("renter@example.com", "hash", "Test", "User", "", domain.RoleRenter)
+
+	pair, err := jwtService.GenerateTokenPair(user)
+	if err != nil {
+		t.Fatalf("GenerateTokenPair: %v", err)
+	}
+	challenge, err := jwtService.GenerateMFAChallengeToken(user.ID, false, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateMFAChallengeToken: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		// Whether each validator accepts the token
+		access    bool
+		challenge bool
+	}{
+		{"AccessToken", pair.AccessToken, true, false},
+		{"MFAChallenge", challenge, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := jwtService.ValidateAccessToken(tt.token); (err == nil) != tt.access {
+				t.Errorf("ValidateAccessToken error = %v, want accepted %v", err, tt.access)
+			}
+			// Other services verify access tokens with pkg/auth
+			if _, err := auth.NewChecker(secret).Verify(tt.token); (err == nil) != tt.access {
+				t.Errorf("Checker.Verify error = %v, want accepted %v", err, tt.access)
+			}
+			if _, err := jwtService.ValidateMFAChallengeToken(tt.token); (err == nil) != tt.challenge {
+				t.Errorf("ValidateMFAChallengeToken error = %v, want accepted %v", err, tt.challenge)
+			}
+		})
+	}
+}
+
+func TestValidateMFAChallengeToken(t *testing.T) {
+	jwtService := token.NewJWTService(secret, 15*time.Minute, 24*time.Hour, "rentalflow")
+	userID := uuid.New()
+
+	expired, err := jwtService.GenerateMFAChallengeToken(userID, false, -time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateMFAChallengeToken: %v", err)
+	}
+	other, err := token.NewJWTService("other-secret", 15*time.Minute, 24*time.Hour, "rentalflow").GenerateMFAChallengeToken(userID, false, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateMFAChallengeToken: %v", err)
+	}
+
+	for name, tokenString := range map[string]string{"Expired": expired, "OtherSecret": other} {
+		if _, err := jwtService.ValidateMFAChallengeToken(tokenString); err != domain.ErrInvalidMFAChallenge {
+			t.Errorf("%s: error = %v, want %v", name, err, domain.ErrInvalidMFAChallenge)
+		}
+	}
+}
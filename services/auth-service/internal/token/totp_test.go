@@ -0,0 +1,94 @@
+package token_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rentalflow/auth-service/internal/token"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors, "12345678901234567890",
+// in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// TestValidateTOTPVectors checks the SHA-1 vectors of RFC 6238 appendix B,
+// truncated to six digits
+func TestValidateTOTPVectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			step, ok := token.ValidateTOTP(rfcSecret, tt.code, time.Unix(tt.unix, 0))
+			if !ok {
+				t.Fatalf("code %s rejected at %d", tt.code, tt.unix)
+			}
+			if want := tt.unix / 30; step != want {
+				t.Errorf("step = %d, want %d", step, want)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPSkew(t *testing.T) {
+	// 081804 is the code of the step holding 1111111109
+	issued := time.Unix(1111111109, 0)
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		want   bool
+	}{
+		{"SameStep", 0, true},
+		{"StepBefore", -30 * time.Second, true},
+		{"StepAfter", 30 * time.Second, true},
+		{"TwoStepsBefore", -60 * time.Second, false},
+		{"TwoStepsAfter", 60 * time.Second, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := token.ValidateTOTP(rfcSecret, "081804", issued.Add(tt.offset))
+			if ok != tt.want {
+				t.Fatalf("accepted = %v, want %v", ok, tt.want)
+			}
+			// The step is the one the code was issued for, so a replay is
+			// caught wherever in the window it is presented
+			if ok && step != issued.Unix()/30 {
+				t.Errorf("step = %d, want %d", step, issued.Unix()/30)
+			}
+		})
+	}
+}
+
+func TestValidateTOTPInput(t *testing.T) {
+	at := time.Unix(1111111109, 0)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		want   bool
+	}{
+		{"Padded", rfcSecret, " 081804 ", true},
+		{"LowercaseSecret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", "081804", true},
+		{"WrongCode", rfcSecret, "081805", false},
+		{"Short", rfcSecret, "81804", false},
+		{"Long", rfcSecret, "0818040", false},
+		{"InvalidSecret", "not base32!", "081804", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := token.ValidateTOTP(tt.secret, tt.code, at); ok != tt.want {
+				t.Errorf("accepted = %v, want %v", ok, tt.want)
+			}
+		})
+	}
+}
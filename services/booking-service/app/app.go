@@ -0,0 +1,164 @@
+// Package app assembles the booking service, so that cmd/server and the
+// system tests run the same wiring
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rentalflow/booking-service/internal/clients"
+	"github.com/rentalflow/booking-service/internal/handler"
+	"github.com/rentalflow/booking-service/internal/migrations"
+	"github.com/rentalflow/booking-service/internal/repository"
+	"github.com/rentalflow/booking-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/rentalflow/pkg/health"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/messaging"
+	"github.com/rentalflow/rentalflow/pkg/metrics"
+	pb "github.com/rentalflow/rentalflow/pkg/pb/booking"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// App is a booking service ready to serve
+type App struct {
+	log             zerolog.Logger
+	db              *database.Backend
+	broker          messaging.Broker
+	ownsBroker      bool
+	inventoryClient *clients.InventoryClient
+	grpcServer      *grpc.Server
+	httpServer      *http.Server
+}
+
+// New connects to the database and builds the service. Events go to broker;
+// if it is nil the service opens the broker the config selects, and runs
+// without messaging when it can't.
+func New(cfg *config.Config, broker messaging.Broker) (*App, error) {
+	// Built here rather than at package init, after main has set up logging
+	log := logger.NewLogger("app")
+	a := &App{log: log, broker: broker}
+
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	a.db = db
+
+	log.Info().Str("driver", db.Driver()).Str("uri", cfg.Database.GetURI()).Msg("Connected to database")
+
+	// Apply pending index and data migrations. Deployments that run the
+	// migrate command as a release step turn this off.
+	if cfg.Database.MigrateOnStart {
+		migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), 5*time.Minute)
+		_, err := db.Migrator(migrations.All, migrations.Postgres).Up(migrateCtx)
+		cancelMigrate()
+		if err != nil {
+			a.close()
+			return nil, fmt.Errorf("failed to apply migrations: %w", err)
+		}
+	}
+
+	// Initialize messaging
+	if a.broker == nil {
+		broker, err := messaging.Open(cfg)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to connect to the message broker, running without messaging")
+		} else {
+			log.Info().Str("driver", cfg.Messaging.Driver).Msg("Connected to the message broker")
+			a.broker = broker
+			a.ownsBroker = true
+		}
+	}
+
+	// Initialize repositories
+	bookingRepo := repository.NewBookingRepository(db)
+	authClient := clients.NewAuthClient(cfg.ServiceURLs.Auth)
+	checker := auth.NewChecker(cfg.JWT.Secret)
+	inventoryClient, err := clients.NewInventoryClient(cfg.Services.InventoryServiceAddr, checker)
+	if err != nil {
+		a.close()
+		return nil, fmt.Errorf("failed to create inventory client: %w", err)
+	}
+	a.inventoryClient = inventoryClient
+
+	bookingService := service.NewBookingService(bookingRepo, db, a.broker, authClient, inventoryClient, cfg.Booking.HighValueThreshold)
+
+	bookingHandler := handler.NewBookingHandler(bookingService, checker)
+	httpHandler := handler.NewHTTPHandler(bookingService, checker)
+
+	a.grpcServer = grpc.NewServer(tracing.ServerOption(), metrics.ServerOption(), logger.ServerOption())
+	pb.RegisterBookingServiceServer(a.grpcServer, bookingHandler)
+
+	healthServer := grpchealth.NewServer()
+	grpc_health_v1.RegisterHealthServer(a.grpcServer, healthServer)
+	healthServer.SetServingStatus("booking.BookingService", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	if cfg.Environment == "development" {
+		reflection.Register(a.grpcServer)
+	}
+
+	// Dependency checks behind /ready
+	checks := health.NewRegistry(cfg.Health.Timeout, cfg.Health.CacheTTL)
+	checks.Register(db.Driver(), db.Health)
+	checks.RegisterOptional("broker", messaging.Health(a.broker))
+
+	mux := http.NewServeMux()
+	httpHandler.RegisterRoutes(mux)
+	mux.Handle("/ready", checks.Handler())
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/debug/config", cfg.DebugHandler(checker))
+
+	a.httpServer = &http.Server{
+		Handler: tracing.Handler(logger.Handler(metrics.Instrument(mux)), cfg.ServiceName),
+	}
+
+	return a, nil
+}
+
+// Start serves gRPC on grpcLis and the HTTP API on httpLis until Shutdown
+func (a *App) Start(grpcLis, httpLis net.Listener) {
+	go func() {
+		a.log.Info().Str("addr", grpcLis.Addr().String()).Msg("gRPC server listening")
+		if err := a.grpcServer.Serve(grpcLis); err != nil {
+			a.log.Error().Err(err).Msg("gRPC server failed")
+		}
+	}()
+
+	go func() {
+		a.log.Info().Str("addr", httpLis.Addr().String()).Msg("HTTP API server listening")
+		if err := a.httpServer.Serve(httpLis); err != nil && err != http.ErrServerClosed {
+			a.log.Error().Err(err).Msg("HTTP server failed")
+		}
+	}()
+}
+
+// Shutdown stops the servers and closes the connections the service opened
+func (a *App) Shutdown(ctx context.Context) {
+	a.grpcServer.GracefulStop()
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		a.log.Error().Err(err).Msg("HTTP server shutdown failed")
+	}
+	a.close()
+}
+
+// close releases the clients and connections opened so far
+func (a *App) close() {
+	if a.inventoryClient != nil {
+		a.inventoryClient.Close()
+	}
+	if a.ownsBroker {
+		a.broker.Close()
+	}
+	a.db.Close(context.Background())
+}
@@ -0,0 +1,113 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/booking-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	pb "github.com/rentalflow/rentalflow/pkg/pb/inventory"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// inventoryDateLayout is the YYYY-MM-DD format inventory-service uses for availability
+const inventoryDateLayout = "2006-01-02"
+
+// InventoryClient calls inventory-service over gRPC
+type InventoryClient struct {
+	conn    *grpc.ClientConn
+	client  pb.InventoryServiceClient
+	timeout time.Duration
+}
+
+// NewInventoryClient creates a new inventory-service client. The connection
+// is established lazily on the first call. Every call carries a service token
+// signed by checker, which inventory-service requires to hold dates.
+func NewInventoryClient(addr string, checker *auth.Checker) (*InventoryClient, error) {
+	conn, err := grpc.Dial(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		checker.ServiceDialOption("booking-service", auth.PermDatesHold),
+		tracing.DialOption(),
+		logger.DialOption(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial inventory service: %w", err)
+	}
+
+	return &InventoryClient{
+		conn:    conn,
+		client:  pb.NewInventoryServiceClient(conn),
+		timeout: 5 * time.Second,
+	}, nil
+}
+
+// Close closes the underlying connection
+func (c *InventoryClient) Close() error {
+	return c.conn.Close()
+}
+
+// GetItem fetches a rental item
+func (c *InventoryClient) GetItem(ctx context.Context, itemID uuid.UUID) (*pb.RentalItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	item, err := c.client.GetItem(ctx, &pb.GetItemRequest{ItemId: itemID.String()})
+	if err != nil {
+		return nil, inventoryError(err)
+	}
+	return item, nil
+}
+
+// BlockDates holds the item's dates for a booking
+func (c *InventoryClient) BlockDates(ctx context.Context, itemID uuid.UUID, startDate, endDate time.Time, bookingID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	_, err := c.client.BlockDates(ctx, &pb.BlockDatesRequest{
+		ItemId:    itemID.String(),
+		StartDate: startDate.Format(inventoryDateLayout),
+		EndDate:   endDate.Format(inventoryDateLayout),
+		BookingId: bookingID.String(),
+	})
+	if err != nil {
+		return inventoryError(err)
+	}
+	return nil
+}
+
+// UnblockDates releases the dates held for a booking. Releasing dates that
+// are not held is not an error.
+func (c *InventoryClient) UnblockDates(ctx context.Context, itemID, bookingID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	_, err := c.client.UnblockDates(ctx, &pb.UnblockDatesRequest{
+		ItemId:    itemID.String(),
+		BookingId: bookingID.String(),
+	})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return inventoryError(err)
+	}
+	return nil
+}
+
+// inventoryError maps inventory-service status codes to booking domain errors
+func inventoryError(err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return domain.ErrItemNotFound
+	case codes.AlreadyExists:
+		return domain.ErrDateConflict
+	case codes.InvalidArgument:
+		return domain.ErrInvalidDates
+	default:
+		return fmt.Errorf("inventory service: %w", err)
+	}
+}
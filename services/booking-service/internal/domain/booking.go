@@ -24,6 +24,22 @@ const (
 	PolicyStrict   CancellationPolicy = "strict"
 )
 
+// Payment statuses reported by payment-service
+const (
+	PaymentStatusPaid          = "paid"
+	PaymentStatusPartiallyPaid = "partially_paid"
+	PaymentStatusRefunded      = "refunded"
+)
+
+// IsValidPaymentStatus checks if a payment status is one payment-service reports
+func IsValidPaymentStatus(status string) bool {
+	switch status {
+	case PaymentStatusPaid, PaymentStatusPartiallyPaid, PaymentStatusRefunded:
+		return true
+	}
+	return false
+}
+
 type Booking struct {
 	ID                 uuid.UUID          `json:"id" bson:"_id"`
 	BookingNumber      string             `json:"booking_number" bson:"booking_number"`
@@ -54,6 +70,7 @@ type Booking struct {
 	PaymentID          *uuid.UUID         `json:"payment_id,omitempty" bson:"payment_id,omitempty"`
 	CreatedAt          time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt          time.Time          `json:"updated_at" bson:"updated_at"`
+	Version            int64              `json:"version" bson:"version"` // incremented by every update
 }
 
 func NewBooking(renterID, ownerID, rentalItemID uuid.UUID, startDate, endDate time.Time, dailyRate, securityDeposit float64) *Booking {
@@ -85,7 +102,17 @@ func NewBooking(renterID, ownerID, rentalItemID uuid.UUID, startDate, endDate ti
 		CancellationPolicy: PolicyModerate,
 		CreatedAt:          now,
 		UpdatedAt:          now,
+		Version:            1,
+	}
+}
+
+// IsOpen checks if the booking has not yet finished or been cancelled
+func (b *Booking) IsOpen() bool {
+	switch b.Status {
+	case StatusPending, StatusConfirmed, StatusActive:
+		return true
 	}
+	return false
 }
 
 func generateBookingNumber() string {
@@ -13,4 +13,10 @@ var (
 	ErrCannotCancel        = errors.New("booking cannot be cancelled")
 	ErrAgreementNotSigned  = errors.New("rental agreement not signed")
 	ErrPaymentNotCompleted = errors.New("payment not completed")
+	ErrEmailNotVerified    = errors.New("email address must be verified before booking")
+	ErrIdentityNotVerified = errors.New("identity verification is required for high-value bookings")
+	ErrOpenBookings        = errors.New("user has open bookings")
+	ErrItemNotFound        = errors.New("rental item not found")
+	ErrInvalidPayment      = errors.New("invalid payment status")
+	ErrVersionConflict     = errors.New("booking was modified by another request")
 )
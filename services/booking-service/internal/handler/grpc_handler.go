@@ -0,0 +1,314 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/booking-service/internal/domain"
+	"github.com/rentalflow/booking-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	apperrors "github.com/rentalflow/rentalflow/pkg/errors"
+	"github.com/rentalflow/rentalflow/pkg/etag"
+	pb "github.com/rentalflow/rentalflow/pkg/pb/booking"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// BookingHandler implements the gRPC BookingService
+type BookingHandler struct {
+	pb.UnimplementedBookingServiceServer
+	bookingService *service.BookingService
+	checker        *auth.Checker
+}
+
+// NewBookingHandler creates a new booking gRPC handler
+func NewBookingHandler(bookingService *service.BookingService, checker *auth.Checker) *BookingHandler {
+	return &BookingHandler{
+		bookingService: bookingService,
+		checker:        checker,
+	}
+}
+
+// CreateBooking books an item for the renter
+func (h *BookingHandler) CreateBooking(ctx context.Context, req *pb.CreateBookingRequest) (*pb.Booking, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	renterID, err := resolveUser(principal, req.RenterId)
+	if err != nil {
+		return nil, err
+	}
+	rentalItemID, err := uuid.Parse(req.RentalItemId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid rental_item_id")
+	}
+	if req.StartDate == nil || req.EndDate == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_date and end_date are required")
+	}
+
+	booking, err := h.bookingService.CreateBooking(ctx, renterID, uuid.Nil, rentalItemID, req.StartDate.AsTime(), req.EndDate.AsTime(), 0, 0)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoBooking(booking), nil
+}
+
+// GetBooking returns a booking to its renter or owner
+func (h *BookingHandler) GetBooking(ctx context.Context, req *pb.GetBookingRequest) (*pb.Booking, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bookingID, err := uuid.Parse(req.BookingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid booking_id")
+	}
+
+	booking, err := h.bookingService.GetBooking(ctx, bookingID)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	if !principal.CanActFor(booking.RenterID.String()) && !principal.CanActFor(booking.OwnerID.String()) {
+		return nil, toGRPCError(domain.ErrUnauthorized)
+	}
+
+	etag.SetHeader(ctx, booking.Version)
+	return toProtoBooking(booking), nil
+}
+
+// ListRenterBookings lists the bookings a user made
+func (h *BookingHandler) ListRenterBookings(ctx context.Context, req *pb.ListBookingsRequest) (*pb.ListBookingsResponse, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	renterID, err := resolveUser(principal, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	bookings, total, err := h.bookingService.GetRenterBookings(ctx, renterID, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoBookingList(bookings, total, req.Page, req.PageSize), nil
+}
+
+// ListOwnerBookings lists the bookings of an owner's items
+func (h *BookingHandler) ListOwnerBookings(ctx context.Context, req *pb.ListBookingsRequest) (*pb.ListBookingsResponse, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID, err := resolveUser(principal, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	bookings, total, err := h.bookingService.GetOwnerBookings(ctx, ownerID, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoBookingList(bookings, total, req.Page, req.PageSize), nil
+}
+
+// CancelBooking cancels a booking on behalf of its renter or owner
+func (h *BookingHandler) CancelBooking(ctx context.Context, req *pb.CancelBookingRequest) (*pb.Booking, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := resolveUser(principal, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	bookingID, err := uuid.Parse(req.BookingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid booking_id")
+	}
+
+	booking, err := h.bookingService.CancelBooking(ctx, bookingID, userID, req.Reason, etag.FromContext(ctx))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	etag.SetHeader(ctx, booking.Version)
+	return toProtoBooking(booking), nil
+}
+
+// ConfirmBooking confirms a pending booking on behalf of the owner
+func (h *BookingHandler) ConfirmBooking(ctx context.Context, req *pb.ConfirmBookingRequest) (*pb.Booking, error) {
+	principal, err := h.checker.RequireContext(ctx, auth.PermBookingsConfirm)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID, err := resolveUser(principal, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+	bookingID, err := uuid.Parse(req.BookingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid booking_id")
+	}
+
+	booking, err := h.bookingService.ConfirmBooking(ctx, bookingID, ownerID, etag.FromContext(ctx))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	etag.SetHeader(ctx, booking.Version)
+	return toProtoBooking(booking), nil
+}
+
+// GetBookingById returns a booking without an ownership check. It is called
+// by payment-service with a service token and is not exposed through the gateway.
+func (h *BookingHandler) GetBookingById(ctx context.Context, req *pb.GetBookingRequest) (*pb.Booking, error) {
+	if _, err := h.checker.RequireContext(ctx, auth.PermBookingsPayment); err != nil {
+		return nil, err
+	}
+	bookingID, err := uuid.Parse(req.BookingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid booking_id")
+	}
+
+	booking, err := h.bookingService.GetBooking(ctx, bookingID)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	etag.SetHeader(ctx, booking.Version)
+	return toProtoBooking(booking), nil
+}
+
+// UpdateBookingPaymentStatus records a payment outcome. It is called by
+// payment-service with a service token and is not exposed through the gateway.
+func (h *BookingHandler) UpdateBookingPaymentStatus(ctx context.Context, req *pb.UpdatePaymentStatusRequest) (*pb.Booking, error) {
+	if _, err := h.checker.RequireContext(ctx, auth.PermBookingsPayment); err != nil {
+		return nil, err
+	}
+	bookingID, err := uuid.Parse(req.BookingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid booking_id")
+	}
+	paymentID, err := uuid.Parse(req.PaymentId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid payment_id")
+	}
+
+	booking, err := h.bookingService.UpdatePaymentStatus(ctx, bookingID, req.PaymentStatus, paymentID)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	etag.SetHeader(ctx, booking.Version)
+	return toProtoBooking(booking), nil
+}
+
+// resolveUser defaults the user to the caller and checks the caller may act for it
+func resolveUser(principal *auth.Principal, userID string) (uuid.UUID, error) {
+	if !principal.CanActFor(userID) {
+		return uuid.Nil, toGRPCError(domain.ErrUnauthorized)
+	}
+	if userID == "" {
+		userID = principal.UserID
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	return id, nil
+}
+
+func toProtoBooking(booking *domain.Booking) *pb.Booking {
+	protoBooking := &pb.Booking{
+		Id:                 booking.ID.String(),
+		BookingNumber:      booking.BookingNumber,
+		RenterId:           booking.RenterID.String(),
+		OwnerId:            booking.OwnerID.String(),
+		RentalItemId:       booking.RentalItemID.String(),
+		Status:             string(booking.Status),
+		StartDate:          timestamppb.New(booking.StartDate),
+		EndDate:            timestamppb.New(booking.EndDate),
+		TotalDays:          int32(booking.TotalDays),
+		DailyRate:          booking.DailyRate,
+		Subtotal:           booking.Subtotal,
+		SecurityDeposit:    booking.SecurityDeposit,
+		ServiceFee:         booking.ServiceFee,
+		TotalAmount:        booking.TotalAmount,
+		CancellationPolicy: string(booking.CancellationPolicy),
+		AgreementSigned:    booking.AgreementSigned,
+		AgreementUrl:       booking.AgreementURL,
+		CreatedAt:          timestamppb.New(booking.CreatedAt),
+		UpdatedAt:          timestamppb.New(booking.UpdatedAt),
+	}
+
+	if booking.PickupAddress != "" || booking.PickupTime != nil {
+		protoBooking.PickupLocation = toProtoLocation(booking.PickupAddress, booking.PickupNotes, booking.PickupTime)
+	}
+	if booking.ReturnAddress != "" || booking.ReturnTime != nil {
+		protoBooking.ReturnLocation = toProtoLocation(booking.ReturnAddress, booking.ReturnNotes, booking.ReturnTime)
+	}
+	if booking.CancelledBy != nil {
+		cancelledBy := booking.CancelledBy.String()
+		protoBooking.CancelledBy = &cancelledBy
+	}
+	if booking.CancellationReason != "" {
+		reason := booking.CancellationReason
+		protoBooking.CancellationReason = &reason
+	}
+
+	return protoBooking
+}
+
+func toProtoLocation(address, notes string, at *time.Time) *pb.PickupReturnLocation {
+	location := &pb.PickupReturnLocation{Address: address, Notes: notes}
+	if at != nil {
+		location.Time = timestamppb.New(*at)
+	}
+	return location
+}
+
+func toProtoBookingList(bookings []*domain.Booking, total int, page, pageSize int32) *pb.ListBookingsResponse {
+	resp := &pb.ListBookingsResponse{
+		Bookings: make([]*pb.Booking, len(bookings)),
+		Total:    int32(total),
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for i, booking := range bookings {
+		resp.Bookings[i] = toProtoBooking(booking)
+	}
+	return resp
+}
+
+// toGRPCError maps domain errors to gRPC status errors
+func toGRPCError(err error) error {
+	switch err {
+	case domain.ErrBookingNotFound, domain.ErrItemNotFound:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeNotFound, err))
+	case domain.ErrUnauthorized, domain.ErrEmailNotVerified, domain.ErrIdentityNotVerified:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeForbidden, err))
+	case domain.ErrInvalidDates, domain.ErrInvalidPayment:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeValidation, err))
+	case domain.ErrDateConflict, domain.ErrOpenBookings, domain.ErrVersionConflict:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeConflict, err))
+	case domain.ErrInvalidStatus, domain.ErrAlreadyCancelled, domain.ErrCannotCancel,
+		domain.ErrAgreementNotSigned, domain.ErrPaymentNotCompleted:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypePrecondition, err))
+	default:
+		return apperrors.ToGRPCError(err)
+	}
+}
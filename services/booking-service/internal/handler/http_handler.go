@@ -9,24 +9,28 @@ import (
 	"github.com/google/uuid"
 	"github.com/rentalflow/booking-service/internal/domain"
 	"github.com/rentalflow/booking-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/etag"
+	"github.com/rentalflow/rentalflow/pkg/userdata"
 )
 
 type HTTPHandler struct {
 	bookingService *service.BookingService
+	checker        *auth.Checker
 }
 
-func NewHTTPHandler(bookingService *service.BookingService) *HTTPHandler {
-	return &HTTPHandler{bookingService: bookingService}
+func NewHTTPHandler(bookingService *service.BookingService, checker *auth.Checker) *HTTPHandler {
+	return &HTTPHandler{bookingService: bookingService, checker: checker}
 }
 
 func (h *HTTPHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/health", h.Health)
-	mux.HandleFunc("/ready", h.Ready)
 	mux.HandleFunc("/api/bookings", h.HandleBookings)
 	mux.HandleFunc("/api/bookings/renter", h.GetRenterBookings)
 	mux.HandleFunc("/api/bookings/owner", h.GetOwnerBookings)
 	mux.HandleFunc("/api/bookings/confirm", h.ConfirmBooking)
 	mux.HandleFunc("/api/bookings/cancel", h.CancelBooking)
+	userdata.NewHandler(h.checker, h.bookingService.ExportUserData, h.bookingService.EraseUserData, userdata.StatusRetained, h.handleError).Register(mux)
 }
 
 func (h *HTTPHandler) Health(w http.ResponseWriter, r *http.Request) {
@@ -34,11 +38,6 @@ func (h *HTTPHandler) Health(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
 }
 
-func (h *HTTPHandler) Ready(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "ready"})
-}
-
 func (h *HTTPHandler) HandleBookings(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodPost:
@@ -111,6 +110,7 @@ func (h *HTTPHandler) GetBooking(w http.ResponseWriter, r *http.Request, booking
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	etag.Set(w, booking.Version)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"id":               booking.ID.String(),
 		"booking_number":   booking.BookingNumber,
@@ -124,6 +124,7 @@ func (h *HTTPHandler) GetBooking(w http.ResponseWriter, r *http.Request, booking
 		"daily_rate":       booking.DailyRate,
 		"total_amount":     booking.TotalAmount,
 		"agreement_signed": booking.AgreementSigned,
+		"version":          booking.Version,
 	})
 }
 
@@ -171,6 +172,11 @@ func (h *HTTPHandler) ConfirmBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	principal, ok := h.checker.Require(w, r, auth.PermBookingsConfirm)
+	if !ok {
+		return
+	}
+
 	var req struct {
 		BookingID string `json:"booking_id"`
 		OwnerID   string `json:"owner_id"`
@@ -181,19 +187,29 @@ func (h *HTTPHandler) ConfirmBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !principal.CanActFor(req.OwnerID) {
+		h.handleError(w, domain.ErrUnauthorized)
+		return
+	}
+	if req.OwnerID == "" {
+		req.OwnerID = principal.UserID
+	}
+
 	bookingID, _ := uuid.Parse(req.BookingID)
 	ownerID, _ := uuid.Parse(req.OwnerID)
 
-	booking, err := h.bookingService.ConfirmBooking(r.Context(), bookingID, ownerID)
+	booking, err := h.bookingService.ConfirmBooking(r.Context(), bookingID, ownerID, etag.IfMatch(r))
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	etag.Set(w, booking.Version)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"id":     booking.ID.String(),
-		"status": booking.Status,
+		"id":      booking.ID.String(),
+		"status":  booking.Status,
+		"version": booking.Version,
 	})
 }
 
@@ -217,16 +233,18 @@ func (h *HTTPHandler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 	bookingID, _ := uuid.Parse(req.BookingID)
 	userID, _ := uuid.Parse(req.UserID)
 
-	booking, err := h.bookingService.CancelBooking(r.Context(), bookingID, userID, req.Reason)
+	booking, err := h.bookingService.CancelBooking(r.Context(), bookingID, userID, req.Reason, etag.IfMatch(r))
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	etag.Set(w, booking.Version)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"id":     booking.ID.String(),
-		"status": booking.Status,
+		"id":      booking.ID.String(),
+		"status":  booking.Status,
+		"version": booking.Version,
 	})
 }
 
@@ -234,12 +252,14 @@ func (h *HTTPHandler) handleError(w http.ResponseWriter, err error) {
 	w.Header().Set("Content-Type", "application/json")
 
 	switch err {
-	case domain.ErrBookingNotFound:
+	case domain.ErrBookingNotFound, domain.ErrItemNotFound:
 		w.WriteHeader(http.StatusNotFound)
-	case domain.ErrUnauthorized:
+	case domain.ErrUnauthorized, domain.ErrEmailNotVerified, domain.ErrIdentityNotVerified:
 		w.WriteHeader(http.StatusForbidden)
 	case domain.ErrInvalidStatus, domain.ErrInvalidDates:
 		w.WriteHeader(http.StatusBadRequest)
+	case domain.ErrOpenBookings, domain.ErrDateConflict, domain.ErrVersionConflict:
+		w.WriteHeader(http.StatusConflict)
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
 	}
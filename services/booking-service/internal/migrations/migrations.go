@@ -0,0 +1,35 @@
+// Package migrations declares the booking service's indexes, and its schema
+// on PostgreSQL in the .sql files of postgres/. Released versions must never
+// change: add a new one instead.
+package migrations
+
+import (
+	"embed"
+
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// All lists the booking service's migrations
+var All = []database.Migration{
+	{
+		Version:     1,
+		Description: "Index bookings by renter and owner, newest first",
+		Up: database.CreateIndexes("bookings",
+			mongo.IndexModel{Keys: bson.D{{Key: "renter_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		),
+	},
+	{
+		Version:     2,
+		Description: "Start bookings stored before versioning at version 1",
+		Up:          database.BackfillVersion("bookings"),
+	},
+}
+
+//go:embed postgres/*.sql
+var postgresFiles embed.FS
+
+// Postgres lists the booking service's PostgreSQL migrations
+var Postgres = database.MustLoadSQLMigrations(postgresFiles, "postgres")
@@ -0,0 +1,35 @@
+CREATE TABLE bookings (
+    id                  uuid PRIMARY KEY,
+    booking_number      text NOT NULL,
+    renter_id           uuid NOT NULL,
+    owner_id            uuid NOT NULL,
+    rental_item_id      uuid NOT NULL,
+    status              text NOT NULL,
+    start_date          timestamptz NOT NULL,
+    end_date            timestamptz NOT NULL,
+    total_days          integer NOT NULL,
+    daily_rate          double precision NOT NULL,
+    subtotal            double precision NOT NULL,
+    security_deposit    double precision NOT NULL,
+    service_fee         double precision NOT NULL,
+    total_amount        double precision NOT NULL,
+    pickup_address      text NOT NULL DEFAULT '',
+    pickup_notes        text NOT NULL DEFAULT '',
+    pickup_time         timestamptz,
+    return_address      text NOT NULL DEFAULT '',
+    return_notes        text NOT NULL DEFAULT '',
+    return_time         timestamptz,
+    cancellation_policy text NOT NULL,
+    agreement_signed    boolean NOT NULL DEFAULT false,
+    agreement_url       text NOT NULL DEFAULT '',
+    cancelled_by        uuid,
+    cancellation_reason text NOT NULL DEFAULT '',
+    payment_status      text NOT NULL DEFAULT '',
+    payment_id          uuid,
+    created_at          timestamptz NOT NULL,
+    updated_at          timestamptz NOT NULL,
+    version             bigint NOT NULL DEFAULT 1
+);
+
+CREATE INDEX bookings_renter_id_created_at_idx ON bookings (renter_id, created_at DESC);
+CREATE INDEX bookings_owner_id_created_at_idx ON bookings (owner_id, created_at DESC);
@@ -0,0 +1,28 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/rentalflow/booking-service/internal/migrations"
+	"github.com/rentalflow/booking-service/internal/repository"
+	"github.com/rentalflow/booking-service/internal/repository/repositorytest"
+	"github.com/rentalflow/rentalflow/pkg/database/databasetest"
+)
+
+func TestMongoBookingRepository(t *testing.T) {
+	repositorytest.BookingRepository(t, func(t *testing.T) repository.BookingRepository {
+		return repository.NewMongoBookingRepository(databasetest.Mongo(t, migrations.All).DB)
+	})
+}
+
+func TestPostgresBookingRepository(t *testing.T) {
+	repositorytest.BookingRepository(t, func(t *testing.T) repository.BookingRepository {
+		return repository.NewPostgresBookingRepository(databasetest.Postgres(t, migrations.Postgres))
+	})
+}
+
+func TestMemoryBookingRepository(t *testing.T) {
+	repositorytest.BookingRepository(t, func(t *testing.T) repository.BookingRepository {
+		return repository.NewMemoryBookingRepository()
+	})
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/booking-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// MemoryBookingRepository keeps bookings in memory, for tests and local runs
+type MemoryBookingRepository struct {
+	mu       sync.Mutex
+	bookings map[uuid.UUID]*domain.Booking
+}
+
+func NewMemoryBookingRepository() *MemoryBookingRepository {
+	return &MemoryBookingRepository{
+		bookings: make(map[uuid.UUID]*domain.Booking),
+	}
+}
+
+func (r *MemoryBookingRepository) Create(ctx context.Context, booking *domain.Booking) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.bookings[booking.ID] = database.Clone(booking)
+	return nil
+}
+
+func (r *MemoryBookingRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Booking, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	booking, ok := r.bookings[id]
+	if !ok {
+		return nil, domain.ErrBookingNotFound
+	}
+	return database.Clone(booking), nil
+}
+
+func (r *MemoryBookingRepository) GetByRenter(ctx context.Context, renterID uuid.UUID, offset, limit int) ([]*domain.Booking, int, error) {
+	return r.page(func(b *domain.Booking) bool { return b.RenterID == renterID }, offset, limit)
+}
+
+func (r *MemoryBookingRepository) GetByOwner(ctx context.Context, ownerID uuid.UUID, offset, limit int) ([]*domain.Booking, int, error) {
+	return r.page(func(b *domain.Booking) bool { return b.OwnerID == ownerID }, offset, limit)
+}
+
+// page returns a page of the matching bookings, newest first
+func (r *MemoryBookingRepository) page(match func(*domain.Booking) bool, offset, limit int) ([]*domain.Booking, int, error) {
+	bookings := r.find(match)
+	sort.SliceStable(bookings, func(i, j int) bool {
+		return bookings[i].CreatedAt.After(bookings[j].CreatedAt)
+	})
+	return database.Page(bookings, offset, limit), len(bookings), nil
+}
+
+func (r *MemoryBookingRepository) Update(ctx context.Context, booking *domain.Booking) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.bookings[booking.ID]
+	if !ok {
+		return domain.ErrBookingNotFound
+	}
+	if stored.Version != booking.Version {
+		return domain.ErrVersionConflict
+	}
+
+	now := time.Now()
+	stored.Status = booking.Status
+	stored.AgreementSigned = booking.AgreementSigned
+	stored.CancelledBy = booking.CancelledBy
+	stored.CancellationReason = booking.CancellationReason
+	stored.PaymentStatus = booking.PaymentStatus
+	stored.PaymentID = booking.PaymentID
+	stored.UpdatedAt = now
+	stored.Version++
+	r.bookings[booking.ID] = database.Clone(stored)
+
+	booking.UpdatedAt = now
+	booking.Version++
+	return nil
+}
+
+// ListByUser returns every booking where the user is the renter or the owner
+func (r *MemoryBookingRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Booking, error) {
+	bookings := r.find(func(b *domain.Booking) bool { return b.RenterID == userID || b.OwnerID == userID })
+	sort.SliceStable(bookings, func(i, j int) bool {
+		return bookings[i].CreatedAt.Before(bookings[j].CreatedAt)
+	})
+	return bookings, nil
+}
+
+// find returns copies of the bookings that match
+func (r *MemoryBookingRepository) find(match func(*domain.Booking) bool) []*domain.Booking {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bookings := []*domain.Booking{}
+	for _, booking := range r.bookings {
+		if match(booking) {
+			bookings = append(bookings, database.Clone(booking))
+		}
+	}
+	return bookings
+}
@@ -92,21 +92,56 @@ func (r *MongoBookingRepository) GetByOwner(ctx context.Context, ownerID uuid.UU
 }
 
 func (r *MongoBookingRepository) Update(ctx context.Context, booking *domain.Booking) error {
+	now := time.Now()
 	update := bson.M{
 		"$set": bson.M{
-			"status":           booking.Status,
-			"agreement_signed": booking.AgreementSigned,
-			"updated_at":       time.Now(),
-			// Add other updatable fields as needed based on logic
+			"status":              booking.Status,
+			"agreement_signed":    booking.AgreementSigned,
+			"cancelled_by":        booking.CancelledBy,
+			"cancellation_reason": booking.CancellationReason,
+			"payment_status":      booking.PaymentStatus,
+			"payment_id":          booking.PaymentID,
+			"updated_at":          now,
 		},
+		"$inc": bson.M{"version": 1},
 	}
 
-	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": booking.ID}, update)
+	filter := bson.M{"_id": booking.ID, "version": booking.Version}
+	result, err := r.coll.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
 	if result.MatchedCount == 0 {
+		// Tell a stale version from a missing booking
+		count, err := r.coll.CountDocuments(ctx, bson.M{"_id": booking.ID})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.ErrVersionConflict
+		}
 		return domain.ErrBookingNotFound
 	}
+
+	booking.UpdatedAt = now
+	booking.Version++
 	return nil
 }
+
+// ListByUser returns every booking where the user is the renter or the owner
+func (r *MongoBookingRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Booking, error) {
+	filter := bson.M{"$or": []bson.M{{"renter_id": userID}, {"owner_id": userID}}}
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var bookings []*domain.Booking
+	if err := cursor.All(ctx, &bookings); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/booking-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// bookingColumns lists the columns of the bookings table in scanBooking order
+const bookingColumns = `id, booking_number, renter_id, owner_id, rental_item_id, status,
+	start_date, end_date, total_days, daily_rate, subtotal, security_deposit, service_fee, total_amount,
+	pickup_address, pickup_notes, pickup_time, return_address, return_notes, return_time,
+	cancellation_policy, agreement_signed, agreement_url, cancelled_by, cancellation_reason,
+	payment_status, payment_id, created_at, updated_at, version`
+
+// PostgresBookingRepository implements BookingRepository using PostgreSQL
+type PostgresBookingRepository struct {
+	db *database.Postgres
+}
+
+func NewPostgresBookingRepository(db *database.Postgres) *PostgresBookingRepository {
+	return &PostgresBookingRepository{db: db}
+}
+
+func (r *PostgresBookingRepository) Create(ctx context.Context, b *domain.Booking) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO bookings (`+bookingColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15,
+			$16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30)`,
+		b.ID, b.BookingNumber, b.RenterID, b.OwnerID, b.RentalItemID, b.Status,
+		b.StartDate, b.EndDate, b.TotalDays, b.DailyRate, b.Subtotal, b.SecurityDeposit, b.ServiceFee, b.TotalAmount,
+		b.PickupAddress, b.PickupNotes, b.PickupTime, b.ReturnAddress, b.ReturnNotes, b.ReturnTime,
+		b.CancellationPolicy, b.AgreementSigned, b.AgreementURL, b.CancelledBy, b.CancellationReason,
+		b.PaymentStatus, b.PaymentID, b.CreatedAt, b.UpdatedAt, b.Version,
+	)
+	return err
+}
+
+func (r *PostgresBookingRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Booking, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+bookingColumns+` FROM bookings WHERE id = $1`, id)
+	booking, err := scanBooking(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrBookingNotFound
+		}
+		return nil, err
+	}
+	return booking, nil
+}
+
+func (r *PostgresBookingRepository) GetByRenter(ctx context.Context, renterID uuid.UUID, offset, limit int) ([]*domain.Booking, int, error) {
+	return r.page(ctx, "renter_id", renterID, offset, limit)
+}
+
+func (r *PostgresBookingRepository) GetByOwner(ctx context.Context, ownerID uuid.UUID, offset, limit int) ([]*domain.Booking, int, error) {
+	return r.page(ctx, "owner_id", ownerID, offset, limit)
+}
+
+// page returns a page of the bookings whose column holds id, newest first
+func (r *PostgresBookingRepository) page(ctx context.Context, column string, id uuid.UUID, offset, limit int) ([]*domain.Booking, int, error) {
+	conn := r.db.Conn(ctx)
+
+	var total int
+	if err := conn.QueryRow(ctx, `SELECT count(*) FROM bookings WHERE `+column+` = $1`, id).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := conn.Query(ctx, `SELECT `+bookingColumns+` FROM bookings
+		WHERE `+column+` = $1 ORDER BY created_at DESC OFFSET $2 LIMIT $3`,
+		id, offset, limit,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	bookings, err := collectBookings(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bookings, total, nil
+}
+
+func (r *PostgresBookingRepository) Update(ctx context.Context, booking *domain.Booking) error {
+	now := time.Now()
+	conn := r.db.Conn(ctx)
+
+	tag, err := conn.Exec(ctx, `UPDATE bookings SET
+			status = $3, agreement_signed = $4, cancelled_by = $5, cancellation_reason = $6,
+			payment_status = $7, payment_id = $8, updated_at = $9, version = version + 1
+		WHERE id = $1 AND version = $2`,
+		booking.ID, booking.Version,
+		booking.Status, booking.AgreementSigned, booking.CancelledBy, booking.CancellationReason,
+		booking.PaymentStatus, booking.PaymentID, now,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		// Tell a stale version from a missing booking
+		var exists bool
+		if err := conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM bookings WHERE id = $1)`, booking.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrVersionConflict
+		}
+		return domain.ErrBookingNotFound
+	}
+
+	booking.UpdatedAt = now
+	booking.Version++
+	return nil
+}
+
+// ListByUser returns every booking where the user is the renter or the owner
+func (r *PostgresBookingRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Booking, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, `SELECT `+bookingColumns+` FROM bookings
+		WHERE renter_id = $1 OR owner_id = $1 ORDER BY created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectBookings(rows)
+}
+
+// scanBooking reads a row of bookingColumns
+func scanBooking(row pgx.Row) (*domain.Booking, error) {
+	var b domain.Booking
+	err := row.Scan(
+		&b.ID, &b.BookingNumber, &b.RenterID, &b.OwnerID, &b.RentalItemID, &b.Status,
+		&b.StartDate, &b.EndDate, &b.TotalDays, &b.DailyRate, &b.Subtotal, &b.SecurityDeposit, &b.ServiceFee, &b.TotalAmount,
+		&b.PickupAddress, &b.PickupNotes, &b.PickupTime, &b.ReturnAddress, &b.ReturnNotes, &b.ReturnTime,
+		&b.CancellationPolicy, &b.AgreementSigned, &b.AgreementURL, &b.CancelledBy, &b.CancellationReason,
+		&b.PaymentStatus, &b.PaymentID, &b.CreatedAt, &b.UpdatedAt, &b.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// collectBookings reads every row of bookingColumns and closes rows
+func collectBookings(rows pgx.Rows) ([]*domain.Booking, error) {
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.Booking, error) {
+		return scanBooking(row)
+	})
+}
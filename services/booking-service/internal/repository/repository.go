@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rentalflow/booking-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
 )
 
 type BookingRepository interface {
@@ -12,5 +13,19 @@ type BookingRepository interface {
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Booking, error)
 	GetByRenter(ctx context.Context, renterID uuid.UUID, offset, limit int) ([]*domain.Booking, int, error)
 	GetByOwner(ctx context.Context, ownerID uuid.UUID, offset, limit int) ([]*domain.Booking, int, error)
+	// Update saves the booking if it is still at booking.Version, and
+	// returns domain.ErrVersionConflict if another update came first
 	Update(ctx context.Context, booking *domain.Booking) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Booking, error)
+}
+
+// NewBookingRepository returns the BookingRepository of the database
+func NewBookingRepository(db *database.Backend) BookingRepository {
+	if db.Memory {
+		return NewMemoryBookingRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresBookingRepository(db.Postgres)
+	}
+	return NewMongoBookingRepository(db.Mongo.DB)
 }
@@ -0,0 +1,199 @@
+// Package repositorytest is the conformance suite of the booking service's
+// repositories. Every implementation runs it, so the service behaves the
+// same whichever database it stores its data in.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/booking-service/internal/domain"
+	"github.com/rentalflow/booking-service/internal/repository"
+)
+
+// BookingRepository runs the conformance tests of BookingRepository against
+// the repositories newRepo opens, an empty one for each test
+func BookingRepository(t *testing.T, newRepo func(t *testing.T) repository.BookingRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		booking := newBooking(uuid.New(), uuid.New(), time.Now())
+		pickup := time.Now().Add(48 * time.Hour)
+		booking.PickupAddress = "Bole Road"
+		booking.PickupTime = &pickup
+		mustCreate(t, repo, booking)
+
+		got, err := repo.GetByID(ctx, booking.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.BookingNumber != booking.BookingNumber || got.RenterID != booking.RenterID ||
+			got.OwnerID != booking.OwnerID || got.RentalItemID != booking.RentalItemID ||
+			got.Status != booking.Status || got.TotalDays != booking.TotalDays ||
+			got.TotalAmount != booking.TotalAmount || got.PickupAddress != booking.PickupAddress ||
+			got.Version != 1 {
+			t.Errorf("GetByID = %+v, want %+v", got, booking)
+		}
+		sameTime(t, "start_date", got.StartDate, booking.StartDate)
+		if got.PickupTime == nil {
+			t.Fatal("pickup_time was not stored")
+		}
+		sameTime(t, "pickup_time", *got.PickupTime, pickup)
+		if got.CancelledBy != nil || got.PaymentID != nil || got.ReturnTime != nil {
+			t.Errorf("unset optional fields came back set: %+v", got)
+		}
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.GetByID(ctx, uuid.New()); err != domain.ErrBookingNotFound {
+			t.Errorf("GetByID = %v, want ErrBookingNotFound", err)
+		}
+	})
+
+	t.Run("PagesNewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		renter, owner := uuid.New(), uuid.New()
+		start := time.Now().Add(-time.Hour)
+		var created []*domain.Booking
+		for i := 0; i < 3; i++ {
+			booking := newBooking(renter, owner, start.Add(time.Duration(i)*time.Minute))
+			mustCreate(t, repo, booking)
+			created = append(created, booking)
+		}
+		mustCreate(t, repo, newBooking(uuid.New(), uuid.New(), start))
+
+		page, total, err := repo.GetByRenter(ctx, renter, 0, 2)
+		if err != nil {
+			t.Fatalf("GetByRenter: %v", err)
+		}
+		if total != 3 || len(page) != 2 || page[0].ID != created[2].ID || page[1].ID != created[1].ID {
+			t.Errorf("GetByRenter first page = %v (total %d), want the two newest of 3", ids(page), total)
+		}
+
+		page, total, err = repo.GetByOwner(ctx, owner, 2, 2)
+		if err != nil {
+			t.Fatalf("GetByOwner: %v", err)
+		}
+		if total != 3 || len(page) != 1 || page[0].ID != created[0].ID {
+			t.Errorf("GetByOwner second page = %v (total %d), want the oldest of 3", ids(page), total)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		booking := newBooking(uuid.New(), uuid.New(), time.Now())
+		mustCreate(t, repo, booking)
+
+		paymentID := booking.RenterID
+		booking.Status = domain.StatusCancelled
+		booking.CancelledBy = &booking.RenterID
+		booking.CancellationReason = "plans changed"
+		booking.PaymentStatus = domain.PaymentStatusRefunded
+		booking.PaymentID = &paymentID
+		if err := repo.Update(ctx, booking); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if booking.Version != 2 {
+			t.Errorf("Version after Update = %d, want 2", booking.Version)
+		}
+
+		got, err := repo.GetByID(ctx, booking.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != domain.StatusCancelled || got.CancellationReason != "plans changed" ||
+			got.CancelledBy == nil || *got.CancelledBy != booking.RenterID ||
+			got.PaymentStatus != domain.PaymentStatusRefunded || got.PaymentID == nil ||
+			got.Version != 2 {
+			t.Errorf("GetByID after Update = %+v", got)
+		}
+	})
+
+	t.Run("UpdateStaleVersion", func(t *testing.T) {
+		repo := newRepo(t)
+		booking := newBooking(uuid.New(), uuid.New(), time.Now())
+		mustCreate(t, repo, booking)
+
+		stale := *booking
+		booking.Status = domain.StatusConfirmed
+		if err := repo.Update(ctx, booking); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		stale.Status = domain.StatusCancelled
+		if err := repo.Update(ctx, &stale); err != domain.ErrVersionConflict {
+			t.Fatalf("Update of a stale version = %v, want ErrVersionConflict", err)
+		}
+
+		got, err := repo.GetByID(ctx, booking.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != domain.StatusConfirmed {
+			t.Errorf("Status = %s, the stale update overwrote %s", got.Status, domain.StatusConfirmed)
+		}
+	})
+
+	t.Run("UpdateMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		booking := newBooking(uuid.New(), uuid.New(), time.Now())
+		if err := repo.Update(ctx, booking); err != domain.ErrBookingNotFound {
+			t.Errorf("Update = %v, want ErrBookingNotFound", err)
+		}
+	})
+
+	t.Run("ListByUser", func(t *testing.T) {
+		repo := newRepo(t)
+		user := uuid.New()
+		start := time.Now().Add(-time.Hour)
+		asRenter := newBooking(user, uuid.New(), start)
+		asOwner := newBooking(uuid.New(), user, start.Add(time.Minute))
+		mustCreate(t, repo, asRenter)
+		mustCreate(t, repo, asOwner)
+		mustCreate(t, repo, newBooking(uuid.New(), uuid.New(), start))
+
+		bookings, err := repo.ListByUser(ctx, user)
+		if err != nil {
+			t.Fatalf("ListByUser: %v", err)
+		}
+		if len(bookings) != 2 || bookings[0].ID != asRenter.ID || bookings[1].ID != asOwner.ID {
+			t.Errorf("ListByUser = %v, want both of the user's bookings, oldest first", ids(bookings))
+		}
+	})
+}
+
+// newBooking returns a pending three-day booking created at createdAt
+func newBooking(renterID, ownerID uuid.UUID, createdAt time.Time) *domain.Booking {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	booking := domain.NewBooking(renterID, ownerID, uuid.New(), start, start.Add(72*time.Hour), 500, 1000)
+	booking.CreatedAt = createdAt
+	booking.UpdatedAt = createdAt
+	return booking
+}
+
+func mustCreate(t *testing.T, repo repository.BookingRepository, booking *domain.Booking) {
+	t.Helper()
+	if err := repo.Create(context.Background(), booking); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
+
+// sameTime fails the test unless got is want at the millisecond precision
+// every database keeps
+func sameTime(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if diff := got.Sub(want); diff > time.Millisecond || diff < -time.Millisecond {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func ids(bookings []*domain.Booking) []uuid.UUID {
+	ids := make([]uuid.UUID, len(bookings))
+	for i, booking := range bookings {
+		ids[i] = booking.ID
+	}
+	return ids
+}
@@ -5,36 +5,81 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rentalflow/booking-service/internal/clients"
 	"github.com/rentalflow/booking-service/internal/domain"
 	"github.com/rentalflow/booking-service/internal/repository"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/rentalflow/pkg/logger"
 	"github.com/rentalflow/rentalflow/pkg/messaging"
+	"github.com/rentalflow/rentalflow/pkg/metrics"
 )
 
 type BookingService struct {
-	bookingRepo repository.BookingRepository
-	broker      *messaging.MessageBroker
+	bookingRepo        repository.BookingRepository
+	tx                 database.Transactor
+	publisher          messaging.Publisher
+	authClient         *clients.AuthClient
+	inventoryClient    *clients.InventoryClient
+	highValueThreshold float64
 }
 
-func NewBookingService(bookingRepo repository.BookingRepository, broker *messaging.MessageBroker) *BookingService {
+func NewBookingService(bookingRepo repository.BookingRepository, tx database.Transactor, publisher messaging.Publisher, authClient *clients.AuthClient, inventoryClient *clients.InventoryClient, highValueThreshold float64) *BookingService {
 	return &BookingService{
-		bookingRepo: bookingRepo,
-		broker:      broker,
+		bookingRepo:        bookingRepo,
+		tx:                 tx,
+		publisher:          publisher,
+		authClient:         authClient,
+		inventoryClient:    inventoryClient,
+		highValueThreshold: highValueThreshold,
 	}
 }
 
+// CreateBooking books an item. When inventory-service is configured the owner
+// and rates come from the listing and the dates are blocked before the
+// booking is stored.
 func (s *BookingService) CreateBooking(ctx context.Context, renterID, ownerID, rentalItemID uuid.UUID, startDate, endDate time.Time, dailyRate, securityDeposit float64) (*domain.Booking, error) {
 	if endDate.Before(startDate) {
 		return nil, domain.ErrInvalidDates
 	}
 
+	if s.inventoryClient != nil {
+		item, err := s.inventoryClient.GetItem(ctx, rentalItemID)
+		if err != nil {
+			return nil, err
+		}
+		if !item.IsActive {
+			return nil, domain.ErrItemNotFound
+		}
+		if ownerID, err = uuid.Parse(item.OwnerId); err != nil {
+			return nil, err
+		}
+		dailyRate = item.DailyRate
+		securityDeposit = item.SecurityDeposit
+	}
+
 	booking := domain.NewBooking(renterID, ownerID, rentalItemID, startDate, endDate, dailyRate, securityDeposit)
+
+	if err := s.ensureRenterVerified(ctx, renterID, booking.TotalAmount); err != nil {
+		return nil, err
+	}
+
+	if s.inventoryClient != nil {
+		// Same-day bookings are charged as one day and hold one day
+		blockedUntil := booking.StartDate.AddDate(0, 0, booking.TotalDays)
+		if err := s.inventoryClient.BlockDates(ctx, rentalItemID, booking.StartDate, blockedUntil, booking.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := s.bookingRepo.Create(ctx, booking); err != nil {
+		s.releaseDates(ctx, booking)
 		return nil, err
 	}
+	metrics.BookingCreated()
 
 	// Publish event
-	if s.broker != nil {
-		s.broker.Publish(ctx, "booking_events", "booking.created", booking)
+	if s.publisher != nil {
+		s.publisher.Publish(ctx, "booking_events", "booking.created", booking)
 	}
 
 	return booking, nil
@@ -66,62 +111,169 @@ func (s *BookingService) GetOwnerBookings(ctx context.Context, ownerID uuid.UUID
 	return s.bookingRepo.GetByOwner(ctx, ownerID, offset, pageSize)
 }
 
-func (s *BookingService) ConfirmBooking(ctx context.Context, bookingID, ownerID uuid.UUID) (*domain.Booking, error) {
-	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+// ConfirmBooking confirms a pending booking on behalf of its owner.
+// expectedVersion is the version the owner last saw, or 0 for any.
+func (s *BookingService) ConfirmBooking(ctx context.Context, bookingID, ownerID uuid.UUID, expectedVersion int64) (*domain.Booking, error) {
+	booking, err := s.updateBooking(ctx, bookingID, expectedVersion, func(booking *domain.Booking) error {
+		if booking.OwnerID != ownerID {
+			return domain.ErrUnauthorized
+		}
+		if booking.Status != domain.StatusPending {
+			return domain.ErrInvalidStatus
+		}
+
+		booking.Status = domain.StatusConfirmed
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if booking.OwnerID != ownerID {
-		return nil, domain.ErrUnauthorized
+	// Publish event
+	if s.publisher != nil {
+		s.publisher.Publish(ctx, "booking_events", "booking.confirmed", booking)
 	}
 
-	if booking.Status != domain.StatusPending {
-		return nil, domain.ErrInvalidStatus
-	}
+	return booking, nil
+}
 
-	booking.Status = domain.StatusConfirmed
-	if err := s.bookingRepo.Update(ctx, booking); err != nil {
+// CancelBooking cancels a booking on behalf of its renter or owner and
+// releases its dates. expectedVersion is the version the caller last saw,
+// or 0 for any.
+func (s *BookingService) CancelBooking(ctx context.Context, bookingID, userID uuid.UUID, reason string, expectedVersion int64) (*domain.Booking, error) {
+	booking, err := s.updateBooking(ctx, bookingID, expectedVersion, func(booking *domain.Booking) error {
+		if booking.RenterID != userID && booking.OwnerID != userID {
+			return domain.ErrUnauthorized
+		}
+		if booking.Status == domain.StatusCancelled {
+			return domain.ErrAlreadyCancelled
+		}
+		if booking.Status == domain.StatusCompleted {
+			return domain.ErrCannotCancel
+		}
+
+		booking.Status = domain.StatusCancelled
+		booking.CancelledBy = &userID
+		booking.CancellationReason = reason
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
+	metrics.BookingCancelled()
+
+	// The dates are released only once the cancellation is saved, so a
+	// failed save never leaves a live booking with free dates
+	s.releaseDates(ctx, booking)
 
 	// Publish event
-	if s.broker != nil {
-		s.broker.Publish(ctx, "booking_events", "booking.confirmed", booking)
+	if s.publisher != nil {
+		s.publisher.Publish(ctx, "booking_events", "booking.cancelled", booking)
 	}
 
 	return booking, nil
 }
 
-func (s *BookingService) CancelBooking(ctx context.Context, bookingID, userID uuid.UUID, reason string) (*domain.Booking, error) {
-	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
-	if err != nil {
-		return nil, err
+// UpdatePaymentStatus records the payment state reported by payment-service
+func (s *BookingService) UpdatePaymentStatus(ctx context.Context, bookingID uuid.UUID, paymentStatus string, paymentID uuid.UUID) (*domain.Booking, error) {
+	if !domain.IsValidPaymentStatus(paymentStatus) {
+		return nil, domain.ErrInvalidPayment
+	}
+
+	return s.updateBooking(ctx, bookingID, 0, func(booking *domain.Booking) error {
+		booking.PaymentStatus = paymentStatus
+		booking.PaymentID = &paymentID
+		return nil
+	})
+}
+
+// maxUpdateAttempts bounds how often an update that lost a race with
+// another update of the same booking is tried again
+const maxUpdateAttempts = 3
+
+// updateBooking loads a booking, applies change and saves it. When another
+// update saves first, the booking is reloaded and change applied again, so
+// its checks always see the latest state. A non-zero expectedVersion is the
+// version the caller based the update on, as sent in If-Match: the update
+// then fails with ErrVersionConflict once the booking has moved past it.
+func (s *BookingService) updateBooking(ctx context.Context, bookingID uuid.UUID, expectedVersion int64, change func(*domain.Booking) error) (*domain.Booking, error) {
+	for attempt := 1; ; attempt++ {
+		booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+		if err != nil {
+			return nil, err
+		}
+		if expectedVersion != 0 && booking.Version != expectedVersion {
+			return nil, domain.ErrVersionConflict
+		}
+		if err := change(booking); err != nil {
+			return nil, err
+		}
+
+		err = s.bookingRepo.Update(ctx, booking)
+		if err == domain.ErrVersionConflict && expectedVersion == 0 && attempt < maxUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return booking, nil
 	}
+}
 
-	if booking.RenterID != userID && booking.OwnerID != userID {
-		return nil, domain.ErrUnauthorized
+// releaseDates frees the dates of a booking that was cancelled or could not
+// be stored. A failure leaves the dates held, which is logged for follow-up.
+func (s *BookingService) releaseDates(ctx context.Context, booking *domain.Booking) {
+	if s.inventoryClient == nil {
+		return
+	}
+	if err := s.inventoryClient.UnblockDates(ctx, booking.RentalItemID, booking.ID); err != nil {
+		logger.Ctx(ctx).Error().Err(err).
+			Str("booking_id", booking.ID.String()).
+			Str("item_id", booking.RentalItemID.String()).
+			Msg("Failed to release booking dates")
 	}
+}
 
-	if booking.Status == domain.StatusCancelled {
-		return nil, domain.ErrAlreadyCancelled
+// ensureRenterVerified requires a verified email, and a verified identity for high-value bookings
+func (s *BookingService) ensureRenterVerified(ctx context.Context, renterID uuid.UUID, totalAmount float64) error {
+	if s.authClient == nil {
+		return nil
 	}
 
-	if booking.Status == domain.StatusCompleted {
-		return nil, domain.ErrCannotCancel
+	status, err := s.authClient.GetUserStatus(ctx, renterID)
+	if err != nil {
+		return err
+	}
+	if !status.EmailVerified {
+		return domain.ErrEmailNotVerified
 	}
+	if s.highValueThreshold > 0 && totalAmount >= s.highValueThreshold && !status.IdentityVerified {
+		return domain.ErrIdentityNotVerified
+	}
+	return nil
+}
 
-	booking.Status = domain.StatusCancelled
-	booking.CancelledBy = &userID
-	booking.CancellationReason = reason
-	if err := s.bookingRepo.Update(ctx, booking); err != nil {
+// ExportUserData returns the bookings a user made as renter or received as owner
+func (s *BookingService) ExportUserData(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+	bookings, err := s.bookingRepo.ListByUser(ctx, userID)
+	if err != nil {
 		return nil, err
 	}
+	return map[string]interface{}{"bookings": bookings}, nil
+}
 
-	// Publish event
-	if s.broker != nil {
-		s.broker.Publish(ctx, "booking_events", "booking.cancelled", booking)
+// EraseUserData checks that a deleted user has no open bookings. Finished
+// bookings are financial records and are retained as they are.
+func (s *BookingService) EraseUserData(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+	bookings, err := s.bookingRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	return booking, nil
+	for _, booking := range bookings {
+		if booking.IsOpen() {
+			return nil, domain.ErrOpenBookings
+		}
+	}
+	return map[string]interface{}{"bookings_retained": len(bookings)}, nil
 }
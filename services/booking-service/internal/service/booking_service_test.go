@@ -0,0 +1,350 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/booking-service/internal/clients"
+	"github.com/rentalflow/booking-service/internal/domain"
+	"github.com/rentalflow/booking-service/internal/repository"
+	"github.com/rentalflow/booking-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/rentalflow/pkg/messaging"
+)
+
+var (
+	renter   = uuid.New()
+	owner    = uuid.New()
+	stranger = uuid.New()
+)
+
+// newService returns a booking service on the memory repository and an
+// in-process bus, without auth-service or inventory-service
+func newService(t *testing.T) (*service.BookingService, repository.BookingRepository, *messaging.Bus) {
+	t.Helper()
+
+	repo := repository.NewMemoryBookingRepository()
+	bus := messaging.NewBus()
+	t.Cleanup(bus.Close)
+
+	return service.NewBookingService(repo, &database.Backend{Memory: true}, bus, nil, nil, 0), repo, bus
+}
+
+// createBooking books an item from renter for three days from tomorrow
+func createBooking(t *testing.T, svc *service.BookingService) *domain.Booking {
+	t.Helper()
+
+	start := time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+	booking, err := svc.CreateBooking(context.Background(), renter, owner, uuid.New(), start, start.AddDate(0, 0, 3), 100, 50)
+	if err != nil {
+		t.Fatalf("CreateBooking: %v", err)
+	}
+	return booking
+}
+
+func TestCreateBooking(t *testing.T) {
+	start := time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		start     time.Time
+		end       time.Time
+		wantErr   error
+		wantDays  int
+		wantTotal float64
+	}{
+		{"ThreeDays", start, start.AddDate(0, 0, 3), nil, 3, 3*100*1.1 + 50},
+		{"SameDay", start, start, nil, 1, 100*1.1 + 50},
+		{"EndBeforeStart", start, start.AddDate(0, 0, -1), domain.ErrInvalidDates, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, bus := newService(t)
+			created := watch(t, bus, "booking.created")
+
+			booking, err := svc.CreateBooking(context.Background(), renter, owner, uuid.New(), tt.start, tt.end, 100, 50)
+			if err != tt.wantErr {
+				t.Fatalf("CreateBooking error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if booking.Status != domain.StatusPending {
+				t.Errorf("status = %s, want pending", booking.Status)
+			}
+			if booking.TotalDays != tt.wantDays || booking.TotalAmount != tt.wantTotal {
+				t.Errorf("days, total = %d, %v, want %d, %v", booking.TotalDays, booking.TotalAmount, tt.wantDays, tt.wantTotal)
+			}
+			if _, err := repo.GetByID(context.Background(), booking.ID); err != nil {
+				t.Errorf("stored booking: %v", err)
+			}
+			if got := created.next(t); got.ID != booking.ID {
+				t.Errorf("booking.created carried %s, want %s", got.ID, booking.ID)
+			}
+		})
+	}
+}
+
+// authServer serves a user status the way auth-service does and returns its
+// URL. A zero code returns the URL of a server that has shut down.
+func authServer(t *testing.T, code int, status clients.UserStatus) string {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(status)
+	}))
+	if code == 0 {
+		srv.Close()
+	} else {
+		t.Cleanup(srv.Close)
+	}
+	return srv.URL
+}
+
+func TestCreateBookingChecksRenter(t *testing.T) {
+	verified := clients.UserStatus{EmailVerified: true, IdentityVerified: true}
+	emailOnly := clients.UserStatus{EmailVerified: true}
+
+	tests := []struct {
+		name      string
+		code      int
+		status    clients.UserStatus
+		dailyRate float64
+		wantErr   error
+		refused   bool
+	}{
+		{"Verified", http.StatusOK, verified, 1000, nil, false},
+		{"EmailOnly", http.StatusOK, emailOnly, 100, nil, false},
+		{"EmailOnlyHighValue", http.StatusOK, emailOnly, 1000, domain.ErrIdentityNotVerified, true},
+		{"EmailNotVerified", http.StatusOK, clients.UserStatus{}, 100, domain.ErrEmailNotVerified, true},
+		// Renters are refused while their status cannot be checked
+		{"AuthServiceError", http.StatusInternalServerError, verified, 100, nil, true},
+		{"AuthServiceDown", 0, verified, 100, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryBookingRepository()
+			authClient := clients.NewAuthClient(authServer(t, tt.code, tt.status))
+			svc := service.NewBookingService(repo, &database.Backend{Memory: true}, nil, authClient, nil, 1000)
+
+			start := time.Now().AddDate(0, 0, 1).Truncate(24 * time.Hour)
+			_, err := svc.CreateBooking(context.Background(), renter, owner, uuid.New(), start, start.AddDate(0, 0, 3), tt.dailyRate, 0)
+			if tt.wantErr != nil && err != tt.wantErr {
+				t.Fatalf("CreateBooking error = %v, want %v", err, tt.wantErr)
+			}
+			if refused := err != nil; refused != tt.refused {
+				t.Fatalf("CreateBooking error = %v, want refused %v", err, tt.refused)
+			}
+
+			bookings, err := repo.ListByUser(context.Background(), renter)
+			if err != nil {
+				t.Fatalf("ListByUser: %v", err)
+			}
+			if stored := len(bookings) > 0; stored == tt.refused {
+				t.Errorf("stored %d bookings, want refused %v", len(bookings), tt.refused)
+			}
+		})
+	}
+}
+
+func TestConfirmBooking(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, svc *service.BookingService, booking *domain.Booking)
+		ownerID uuid.UUID
+		version func(booking *domain.Booking) int64
+		wantErr error
+	}{
+		{"ByOwner", nil, owner, anyVersion, nil},
+		{"AtCurrentVersion", nil, owner, currentVersion, nil},
+		{"ByRenter", nil, renter, anyVersion, domain.ErrUnauthorized},
+		{"AtStaleVersion", nil, owner, staleVersion, domain.ErrVersionConflict},
+		{"AlreadyConfirmed", confirm, owner, anyVersion, domain.ErrInvalidStatus},
+		{"Cancelled", cancel, owner, anyVersion, domain.ErrInvalidStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, bus := newService(t)
+			booking := createBooking(t, svc)
+			if tt.setup != nil {
+				tt.setup(t, svc, booking)
+			}
+			confirmed := watch(t, bus, "booking.confirmed")
+
+			got, err := svc.ConfirmBooking(context.Background(), booking.ID, tt.ownerID, tt.version(booking))
+			if err != tt.wantErr {
+				t.Fatalf("ConfirmBooking error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.Status != domain.StatusConfirmed {
+				t.Errorf("status = %s, want confirmed", got.Status)
+			}
+			if got.Version != booking.Version+1 {
+				t.Errorf("version = %d, want %d", got.Version, booking.Version+1)
+			}
+			if event := confirmed.next(t); event.Status != domain.StatusConfirmed {
+				t.Errorf("booking.confirmed carried status %s", event.Status)
+			}
+		})
+	}
+}
+
+func TestCancelBooking(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, svc *service.BookingService, booking *domain.Booking)
+		userID  uuid.UUID
+		version func(booking *domain.Booking) int64
+		wantErr error
+	}{
+		{"ByRenter", nil, renter, anyVersion, nil},
+		{"ByOwner", nil, owner, anyVersion, nil},
+		{"Confirmed", confirm, renter, anyVersion, nil},
+		{"ByStranger", nil, stranger, anyVersion, domain.ErrUnauthorized},
+		{"AtStaleVersion", nil, renter, staleVersion, domain.ErrVersionConflict},
+		{"AlreadyCancelled", cancel, renter, anyVersion, domain.ErrAlreadyCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, bus := newService(t)
+			booking := createBooking(t, svc)
+			if tt.setup != nil {
+				tt.setup(t, svc, booking)
+			}
+			cancelled := watch(t, bus, "booking.cancelled")
+
+			got, err := svc.CancelBooking(context.Background(), booking.ID, tt.userID, "plans changed", tt.version(booking))
+			if err != tt.wantErr {
+				t.Fatalf("CancelBooking error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if got.Status != domain.StatusCancelled || got.CancellationReason != "plans changed" {
+				t.Errorf("status, reason = %s, %q", got.Status, got.CancellationReason)
+			}
+			if got.CancelledBy == nil || *got.CancelledBy != tt.userID {
+				t.Errorf("cancelled by %v, want %s", got.CancelledBy, tt.userID)
+			}
+			if event := cancelled.next(t); event.ID != booking.ID {
+				t.Errorf("booking.cancelled carried %s, want %s", event.ID, booking.ID)
+			}
+		})
+	}
+
+	t.Run("Completed", func(t *testing.T) {
+		svc, repo, _ := newService(t)
+		booking := createBooking(t, svc)
+		booking.Status = domain.StatusCompleted
+		if err := repo.Update(context.Background(), booking); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		if _, err := svc.CancelBooking(context.Background(), booking.ID, renter, "", 0); err != domain.ErrCannotCancel {
+			t.Errorf("CancelBooking error = %v, want %v", err, domain.ErrCannotCancel)
+		}
+	})
+}
+
+func TestUpdatePaymentStatus(t *testing.T) {
+	tests := []struct {
+		status  string
+		wantErr error
+	}{
+		{domain.PaymentStatusPaid, nil},
+		{domain.PaymentStatusPartiallyPaid, nil},
+		{domain.PaymentStatusRefunded, nil},
+		{"pending", domain.ErrInvalidPayment},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			svc, _, _ := newService(t)
+			booking := createBooking(t, svc)
+			paymentID := uuid.New()
+
+			got, err := svc.UpdatePaymentStatus(context.Background(), booking.ID, tt.status, paymentID)
+			if err != tt.wantErr {
+				t.Fatalf("UpdatePaymentStatus error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.PaymentStatus != tt.status || got.PaymentID == nil || *got.PaymentID != paymentID {
+				t.Errorf("payment = %s %v, want %s %s", got.PaymentStatus, got.PaymentID, tt.status, paymentID)
+			}
+		})
+	}
+
+	t.Run("UnknownBooking", func(t *testing.T) {
+		svc, _, _ := newService(t)
+		if _, err := svc.UpdatePaymentStatus(context.Background(), uuid.New(), domain.PaymentStatusPaid, uuid.New()); err != domain.ErrBookingNotFound {
+			t.Errorf("UpdatePaymentStatus error = %v, want %v", err, domain.ErrBookingNotFound)
+		}
+	})
+}
+
+func confirm(t *testing.T, svc *service.BookingService, booking *domain.Booking) {
+	t.Helper()
+	if _, err := svc.ConfirmBooking(context.Background(), booking.ID, owner, 0); err != nil {
+		t.Fatalf("ConfirmBooking: %v", err)
+	}
+}
+
+func cancel(t *testing.T, svc *service.BookingService, booking *domain.Booking) {
+	t.Helper()
+	if _, err := svc.CancelBooking(context.Background(), booking.ID, renter, "", 0); err != nil {
+		t.Fatalf("CancelBooking: %v", err)
+	}
+}
+
+func anyVersion(*domain.Booking) int64 { return 0 }
+
+// currentVersion is the version of the booking as created; the cases that
+// use it change nothing before the call
+func currentVersion(booking *domain.Booking) int64 { return booking.Version }
+
+func staleVersion(booking *domain.Booking) int64 { return booking.Version + 1 }
+
+// events receives the bookings published under one key
+type events chan domain.Booking
+
+func watch(t *testing.T, bus *messaging.Bus, key string) events {
+	t.Helper()
+
+	ch := make(events, 10)
+	err := bus.Subscribe("booking_events", key, "", func(ctx context.Context, body []byte) error {
+		var booking domain.Booking
+		if err := json.Unmarshal(body, &booking); err != nil {
+			return err
+		}
+		ch <- booking
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	return ch
+}
+
+func (e events) next(t *testing.T) domain.Booking {
+	t.Helper()
+
+	select {
+	case booking := <-e:
+		return booking
+	case <-time.After(time.Second):
+		t.Fatal("no event was published")
+		return domain.Booking{}
+	}
+}
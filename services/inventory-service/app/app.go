@@ -0,0 +1,166 @@
+// Package app assembles the inventory service, so that cmd/server and the
+// system tests run the same wiring
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rentalflow/inventory-service/internal/clients"
+	"github.com/rentalflow/inventory-service/internal/handler"
+	"github.com/rentalflow/inventory-service/internal/migrations"
+	"github.com/rentalflow/inventory-service/internal/repository"
+	"github.com/rentalflow/inventory-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/rentalflow/pkg/health"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/messaging"
+	"github.com/rentalflow/rentalflow/pkg/metrics"
+	pb "github.com/rentalflow/rentalflow/pkg/pb/inventory"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// App is an inventory service ready to serve
+type App struct {
+	log        zerolog.Logger
+	db         *database.Backend
+	broker     messaging.Broker
+	ownsBroker bool
+	grpcServer *grpc.Server
+	httpServer *http.Server
+}
+
+// New connects to the database and builds the service. Item events go to
+// broker; if it is nil the service opens the broker the config selects, and
+// runs without messaging when it can't.
+func New(cfg *config.Config, broker messaging.Broker) (*App, error) {
+	// Built here rather than at package init, after main has set up logging
+	log := logger.NewLogger("app")
+	a := &App{log: log, broker: broker}
+
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	a.db = db
+
+	log.Info().Str("driver", db.Driver()).Str("uri", cfg.Database.GetURI()).Msg("Connected to database")
+
+	// Apply pending index and data migrations. Deployments that run the
+	// migrate command as a release step turn this off.
+	if cfg.Database.MigrateOnStart {
+		migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), 5*time.Minute)
+		_, err := db.Migrator(migrations.All, migrations.Postgres).Up(migrateCtx)
+		cancelMigrate()
+		if err != nil {
+			a.close()
+			return nil, fmt.Errorf("failed to apply migrations: %w", err)
+		}
+	}
+
+	// Initialize messaging
+	if a.broker == nil {
+		broker, err := messaging.Open(cfg)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to connect to the message broker, running without messaging")
+		} else {
+			log.Info().Str("driver", cfg.Messaging.Driver).Msg("Connected to the message broker")
+			a.broker = broker
+			a.ownsBroker = true
+		}
+	}
+
+	// Initialize repositories
+	itemRepo := repository.NewItemRepository(db)
+	availabilityRepo := repository.NewAvailabilityRepository(db)
+	maintenanceRepo := repository.NewMaintenanceRepository(db)
+
+	// Initialize service
+	authClient := clients.NewAuthClient(cfg.ServiceURLs.Auth)
+	inventoryService := service.NewInventoryService(itemRepo, availabilityRepo, maintenanceRepo, db, authClient, a.broker)
+
+	checker := auth.NewChecker(cfg.JWT.Secret)
+
+	// Initialize gRPC handler
+	inventoryHandler := handler.NewInventoryHandler(inventoryService, checker)
+
+	// Initialize HTTP handler
+	httpHandler := handler.NewHTTPHandler(inventoryService, checker)
+
+	// Create gRPC server
+	a.grpcServer = grpc.NewServer(tracing.ServerOption(), metrics.ServerOption(), logger.ServerOption())
+
+	// Register services
+	pb.RegisterInventoryServiceServer(a.grpcServer, inventoryHandler)
+
+	// Register health check
+	healthServer := grpchealth.NewServer()
+	grpc_health_v1.RegisterHealthServer(a.grpcServer, healthServer)
+	healthServer.SetServingStatus("inventory.InventoryService", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	// Enable reflection for development
+	if cfg.Environment == "development" {
+		reflection.Register(a.grpcServer)
+	}
+
+	// Dependency checks behind /ready
+	checks := health.NewRegistry(cfg.Health.Timeout, cfg.Health.CacheTTL)
+	checks.Register(db.Driver(), db.Health)
+	checks.RegisterOptional("broker", messaging.Health(a.broker))
+
+	mux := http.NewServeMux()
+	httpHandler.RegisterRoutes(mux)
+	mux.Handle("/ready", checks.Handler())
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/debug/config", cfg.DebugHandler(checker))
+
+	a.httpServer = &http.Server{
+		Handler: tracing.Handler(logger.Handler(metrics.Instrument(mux)), cfg.ServiceName),
+	}
+
+	return a, nil
+}
+
+// Start serves gRPC on grpcLis and the HTTP API on httpLis until Shutdown
+func (a *App) Start(grpcLis, httpLis net.Listener) {
+	go func() {
+		a.log.Info().Str("addr", grpcLis.Addr().String()).Msg("gRPC server listening")
+		if err := a.grpcServer.Serve(grpcLis); err != nil {
+			a.log.Error().Err(err).Msg("gRPC server failed")
+		}
+	}()
+
+	go func() {
+		a.log.Info().Str("addr", httpLis.Addr().String()).Msg("HTTP API server listening")
+		if err := a.httpServer.Serve(httpLis); err != nil && err != http.ErrServerClosed {
+			a.log.Error().Err(err).Msg("HTTP server failed")
+		}
+	}()
+}
+
+// Shutdown stops the servers and closes the connections the service opened
+func (a *App) Shutdown(ctx context.Context) {
+	a.grpcServer.GracefulStop()
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		a.log.Error().Err(err).Msg("HTTP server shutdown failed")
+	}
+	a.close()
+}
+
+// close releases the connections opened so far
+func (a *App) close() {
+	if a.ownsBroker {
+		a.broker.Close()
+	}
+	a.db.Close(context.Background())
+}
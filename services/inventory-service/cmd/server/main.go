@@ -3,87 +3,66 @@ package main
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/rentalflow/inventory-service/internal/config"
-	"github.com/rentalflow/inventory-service/internal/handler"
-	"github.com/rentalflow/inventory-service/internal/repository"
-	"github.com/rentalflow/inventory-service/internal/service"
-	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/inventory-service/app"
+	"github.com/rentalflow/rentalflow/pkg/config"
 	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
 )
 
 func main() {
-	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load("inventory")
 	if err != nil {
 		fmt.Printf("Failed to load config: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Initialize logger
-	logger.Init(cfg.ServiceName, cfg.LogLevel)
+	logger.Init(cfg.ServiceName, cfg.LogLevel, cfg.LogFormat)
 	log := logger.NewLogger("main")
 
 	log.Info().Msg("Starting Inventory Service...")
 
-	// Connect to database
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	client, err := database.New(cfg.Database.GetURI(), cfg.Database.Database)
+	shutdownTracing, err := tracing.Init(cfg.ServiceName, cfg.Tracing)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to connect to database")
+		log.Fatal().Err(err).Msg("Failed to initialize tracing")
 	}
-	defer client.Close(ctx)
-
-	log.Info().Str("uri", cfg.Database.GetURI()).Msg("Connected to database")
-
-	// Initialize repositories
-	itemRepo := repository.NewMongoItemRepository(client.DB)
-	availabilityRepo := repository.NewMongoAvailabilityRepository(client.DB)
-	maintenanceRepo := repository.NewMongoMaintenanceRepository(client.DB)
-
-	// Initialize service
-	inventoryService := service.NewInventoryService(itemRepo, availabilityRepo, maintenanceRepo)
-
-	// Initialize HTTP handler
-	httpHandler := handler.NewHTTPHandler(inventoryService)
+	defer shutdownTracing(context.Background())
 
-	// Start HTTP server
-	httpAddr := fmt.Sprintf(":%d", cfg.HTTPPort)
-	mux := http.NewServeMux()
-	httpHandler.RegisterRoutes(mux)
+	// The log level can change in the config file without a restart
+	cfg.Watch(func(next *config.Config) {
+		logger.SetLevel(next.LogLevel)
+		log.Info().Str("log_level", next.LogLevel).Msg("Configuration reloaded")
+	})
 
-	httpServer := &http.Server{
-		Addr:    httpAddr,
-		Handler: mux,
+	a, err := app.New(cfg, nil)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to start inventory service")
 	}
 
-	go func() {
-		log.Info().Str("addr", httpAddr).Msg("HTTP API server listening")
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Error().Err(err).Msg("HTTP server failed")
-		}
-	}()
+	grpcLis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to listen")
+	}
+	httpLis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.HTTPPort))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to listen")
+	}
+	a.Start(grpcLis, httpLis)
 
-	// Wait for interrupt signal
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Info().Msg("Shutting down server...")
+	log.Info().Msg("Shutting down servers...")
 
-	// Graceful shutdown
-	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	if err := httpServer.Shutdown(ctx); err != nil {
-		log.Error().Err(err).Msg("HTTP server shutdown failed")
-	}
+	a.Shutdown(ctx)
 
 	log.Info().Msg("Server stopped")
 }
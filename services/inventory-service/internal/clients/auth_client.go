@@ -0,0 +1,64 @@
+package clients
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+)
+
+// UserStatus is the subset of an auth-service profile inventory-service relies on
+type UserStatus struct {
+	ID               string `json:"id"`
+	EmailVerified    bool   `json:"email_verified"`
+	IdentityVerified bool   `json:"identity_verified"`
+}
+
+// AuthClient looks up user status in auth-service
+type AuthClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewAuthClient creates a new auth-service client
+func NewAuthClient(baseURL string) *AuthClient {
+	return &AuthClient{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout:   5 * time.Second,
+			Transport: tracing.Transport(logger.Transport(nil)),
+		},
+	}
+}
+
+// GetUserStatus fetches verification flags for a user
+func (c *AuthClient) GetUserStatus(ctx context.Context, userID uuid.UUID) (*UserStatus, error) {
+	url := fmt.Sprintf("%s/api/auth/profile?user_id=%s", c.baseURL, userID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach auth service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("auth service returned status %d", resp.StatusCode)
+	}
+
+	var status UserStatus
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode user status: %w", err)
+	}
+
+	return &status, nil
+}
@@ -9,11 +9,16 @@ var (
 	ErrUnauthorized    = errors.New("unauthorized to perform this action")
 	ErrInvalidCategory = errors.New("invalid item category")
 	ErrInvalidPrice    = errors.New("invalid pricing information")
+	ErrVersionConflict = errors.New("rental item was modified by another request")
+
+	// Owner errors
+	ErrIdentityNotVerified = errors.New("identity verification is required before listing your first item")
 
 	// Availability errors
 	ErrSlotNotFound     = errors.New("availability slot not found")
 	ErrDateConflict     = errors.New("date range conflicts with existing bookings")
 	ErrInvalidDateRange = errors.New("invalid date range")
+	ErrSlotConflict     = errors.New("availability slot was modified by another request")
 
 	// Maintenance errors
 	ErrMaintenanceNotFound = errors.New("maintenance log not found")
@@ -73,6 +73,7 @@ type RentalItem struct {
 	IsActive  bool      `json:"is_active" bson:"is_active"`
 	CreatedAt time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+	Version   int64     `json:"version" bson:"version"` // incremented by every update
 }
 
 // NewRentalItem creates a new rental item
@@ -90,6 +91,7 @@ func NewRentalItem(ownerID uuid.UUID, title, description string, category ItemCa
 		IsActive:       true,
 		CreatedAt:      now,
 		UpdatedAt:      now,
+		Version:        1,
 	}
 }
 
@@ -102,6 +104,7 @@ type AvailabilitySlot struct {
 	Status       AvailabilityStatus `json:"status" bson:"status"`
 	BookingID    *uuid.UUID         `json:"booking_id,omitempty" bson:"booking_id,omitempty"`
 	CreatedAt    time.Time          `json:"created_at" bson:"created_at"`
+	Version      int64              `json:"version" bson:"version"` // incremented by every update
 }
 
 // NewAvailabilitySlot creates a new availability slot
@@ -113,6 +116,7 @@ func NewAvailabilitySlot(rentalItemID uuid.UUID, startDate, endDate time.Time, s
 		EndDate:      endDate,
 		Status:       status,
 		CreatedAt:    time.Now(),
+		Version:      1,
 	}
 }
 
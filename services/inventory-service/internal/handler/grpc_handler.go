@@ -0,0 +1,454 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/inventory-service/internal/domain"
+	"github.com/rentalflow/inventory-service/internal/repository"
+	"github.com/rentalflow/inventory-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	apperrors "github.com/rentalflow/rentalflow/pkg/errors"
+	"github.com/rentalflow/rentalflow/pkg/etag"
+	pb "github.com/rentalflow/rentalflow/pkg/pb/inventory"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// dateLayout is the YYYY-MM-DD format used for availability dates
+const dateLayout = "2006-01-02"
+
+// InventoryHandler implements the gRPC InventoryService
+type InventoryHandler struct {
+	pb.UnimplementedInventoryServiceServer
+	inventoryService *service.InventoryService
+	checker          *auth.Checker
+}
+
+// NewInventoryHandler creates a new inventory gRPC handler
+func NewInventoryHandler(inventoryService *service.InventoryService, checker *auth.Checker) *InventoryHandler {
+	return &InventoryHandler{
+		inventoryService: inventoryService,
+		checker:          checker,
+	}
+}
+
+// ListItems lists active items with filters
+func (h *InventoryHandler) ListItems(ctx context.Context, req *pb.ListItemsRequest) (*pb.ListItemsResponse, error) {
+	filters := repository.ItemFilters{
+		City:     req.City,
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+	}
+	if req.Category != nil {
+		category := domain.ItemCategory(*req.Category)
+		filters.Category = &category
+	}
+
+	items, total, err := h.inventoryService.ListItems(ctx, int(req.Page), int(req.PageSize), filters)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoItemList(items, total, req.Page, req.PageSize), nil
+}
+
+// GetItem retrieves an item by ID. It is also used by booking-service.
+func (h *InventoryHandler) GetItem(ctx context.Context, req *pb.GetItemRequest) (*pb.RentalItem, error) {
+	itemID, err := uuid.Parse(req.ItemId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid item_id")
+	}
+
+	item, err := h.inventoryService.GetItem(ctx, itemID)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	etag.SetHeader(ctx, item.Version)
+	return toProtoItem(item), nil
+}
+
+// SearchItems searches items by query and filters
+func (h *InventoryHandler) SearchItems(ctx context.Context, req *pb.SearchItemsRequest) (*pb.ListItemsResponse, error) {
+	filters := repository.ItemFilters{
+		City:     req.City,
+		MinPrice: req.MinPrice,
+		MaxPrice: req.MaxPrice,
+	}
+	if req.Category != nil {
+		category := domain.ItemCategory(*req.Category)
+		filters.Category = &category
+	}
+
+	items, total, err := h.inventoryService.SearchItems(ctx, req.Query, int(req.Page), int(req.PageSize), filters)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoItemList(items, total, req.Page, req.PageSize), nil
+}
+
+// CreateItem creates a new rental item
+func (h *InventoryHandler) CreateItem(ctx context.Context, req *pb.CreateItemRequest) (*pb.RentalItem, error) {
+	principal, err := h.checker.RequireContext(ctx, auth.PermItemsWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID, err := resolveOwner(principal, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+	if req.Title == "" {
+		return nil, status.Error(codes.InvalidArgument, "title is required")
+	}
+
+	var location domain.Location
+	if req.Location != nil {
+		location = domain.Location{
+			Address:   req.Location.Address,
+			City:      req.Location.City,
+			Latitude:  req.Location.Latitude,
+			Longitude: req.Location.Longitude,
+		}
+	}
+
+	item, err := h.inventoryService.CreateItem(
+		ctx, ownerID, req.Title, req.Description,
+		domain.ItemCategory(req.Category), req.Subcategory,
+		req.DailyRate, req.WeeklyRate, req.MonthlyRate, req.SecurityDeposit,
+		location, req.Specifications, req.Images,
+	)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoItem(item), nil
+}
+
+// UpdateItem updates an owner's item
+func (h *InventoryHandler) UpdateItem(ctx context.Context, req *pb.UpdateItemRequest) (*pb.RentalItem, error) {
+	principal, err := h.checker.RequireContext(ctx, auth.PermItemsWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID, err := resolveOwner(principal, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+	itemID, err := uuid.Parse(req.ItemId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid item_id")
+	}
+
+	updates := make(map[string]interface{})
+	if req.Title != nil {
+		updates["title"] = *req.Title
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.DailyRate != nil {
+		updates["daily_rate"] = *req.DailyRate
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	item, err := h.inventoryService.UpdateItem(ctx, itemID, ownerID, updates, etag.FromContext(ctx))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	etag.SetHeader(ctx, item.Version)
+	return toProtoItem(item), nil
+}
+
+// DeleteItem deletes an owner's item
+func (h *InventoryHandler) DeleteItem(ctx context.Context, req *pb.DeleteItemRequest) (*pb.DeleteItemResponse, error) {
+	principal, err := h.checker.RequireContext(ctx, auth.PermItemsWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID, err := resolveOwner(principal, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+	itemID, err := uuid.Parse(req.ItemId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid item_id")
+	}
+
+	if err := h.inventoryService.DeleteItem(ctx, itemID, ownerID); err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.DeleteItemResponse{Success: true}, nil
+}
+
+// GetOwnerItems lists the items of an owner
+func (h *InventoryHandler) GetOwnerItems(ctx context.Context, req *pb.GetOwnerItemsRequest) (*pb.ListItemsResponse, error) {
+	ownerID, err := uuid.Parse(req.OwnerId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid owner_id")
+	}
+
+	items, total, err := h.inventoryService.GetOwnerItems(ctx, ownerID, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoItemList(items, total, req.Page, req.PageSize), nil
+}
+
+// GetAvailability lists the booked and blocked slots of an item
+func (h *InventoryHandler) GetAvailability(ctx context.Context, req *pb.GetAvailabilityRequest) (*pb.AvailabilityResponse, error) {
+	itemID, err := uuid.Parse(req.ItemId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid item_id")
+	}
+	startDate, endDate, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := h.inventoryService.GetAvailability(ctx, itemID, startDate, endDate)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	resp := &pb.AvailabilityResponse{
+		ItemId: itemID.String(),
+		Slots:  make([]*pb.AvailabilitySlot, len(slots)),
+	}
+	for i, slot := range slots {
+		resp.Slots[i] = toProtoSlot(slot)
+	}
+	return resp, nil
+}
+
+// BlockDates holds an item's dates for a booking. It is called by
+// booking-service with a service token and is not exposed through the gateway.
+func (h *InventoryHandler) BlockDates(ctx context.Context, req *pb.BlockDatesRequest) (*pb.BlockDatesResponse, error) {
+	if _, err := h.checker.RequireContext(ctx, auth.PermDatesHold); err != nil {
+		return nil, err
+	}
+	itemID, err := uuid.Parse(req.ItemId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid item_id")
+	}
+	bookingID, err := uuid.Parse(req.BookingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid booking_id")
+	}
+	startDate, endDate, err := parseDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := h.inventoryService.BlockDates(ctx, itemID, startDate, endDate, bookingID)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.BlockDatesResponse{Success: true, SlotId: slot.ID.String()}, nil
+}
+
+// UnblockDates releases the dates held for a booking. It is called by
+// booking-service with a service token and is not exposed through the gateway.
+func (h *InventoryHandler) UnblockDates(ctx context.Context, req *pb.UnblockDatesRequest) (*pb.UnblockDatesResponse, error) {
+	if _, err := h.checker.RequireContext(ctx, auth.PermDatesHold); err != nil {
+		return nil, err
+	}
+	itemID, err := uuid.Parse(req.ItemId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid item_id")
+	}
+	bookingID, err := uuid.Parse(req.BookingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid booking_id")
+	}
+
+	if err := h.inventoryService.UnblockDates(ctx, itemID, bookingID); err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.UnblockDatesResponse{Success: true}, nil
+}
+
+// CreateMaintenanceLog records maintenance on an owner's item
+func (h *InventoryHandler) CreateMaintenanceLog(ctx context.Context, req *pb.CreateMaintenanceRequest) (*pb.MaintenanceLog, error) {
+	principal, err := h.checker.RequireContext(ctx, auth.PermItemsWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID, err := resolveOwner(principal, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+	itemID, err := uuid.Parse(req.RentalItemId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid rental_item_id")
+	}
+	if req.StartDate == nil {
+		return nil, status.Error(codes.InvalidArgument, "start_date is required")
+	}
+
+	log, err := h.inventoryService.CreateMaintenanceLog(ctx, itemID, ownerID, req.MaintenanceType, req.Description, req.StartDate.AsTime(), req.Cost)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoMaintenanceLog(log), nil
+}
+
+// GetMaintenanceLogs lists the maintenance history of an owner's item
+func (h *InventoryHandler) GetMaintenanceLogs(ctx context.Context, req *pb.GetMaintenanceLogsRequest) (*pb.MaintenanceLogsResponse, error) {
+	principal, err := h.checker.RequireContext(ctx, auth.PermItemsWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerID, err := resolveOwner(principal, req.OwnerId)
+	if err != nil {
+		return nil, err
+	}
+	itemID, err := uuid.Parse(req.RentalItemId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid rental_item_id")
+	}
+
+	logs, total, err := h.inventoryService.GetMaintenanceLogs(ctx, itemID, ownerID, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	resp := &pb.MaintenanceLogsResponse{
+		Logs:  make([]*pb.MaintenanceLog, len(logs)),
+		Total: int32(total),
+	}
+	for i, log := range logs {
+		resp.Logs[i] = toProtoMaintenanceLog(log)
+	}
+	return resp, nil
+}
+
+// resolveOwner defaults the owner to the caller and checks the caller may act for it
+func resolveOwner(principal *auth.Principal, ownerID string) (uuid.UUID, error) {
+	if !principal.CanActFor(ownerID) {
+		return uuid.Nil, toGRPCError(domain.ErrUnauthorized)
+	}
+	if ownerID == "" {
+		ownerID = principal.UserID
+	}
+
+	id, err := uuid.Parse(ownerID)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid owner_id")
+	}
+	return id, nil
+}
+
+func parseDateRange(start, end string) (time.Time, time.Time, error) {
+	startDate, err := time.Parse(dateLayout, start)
+	if err != nil {
+		return time.Time{}, time.Time{}, status.Error(codes.InvalidArgument, "start_date must be YYYY-MM-DD")
+	}
+	endDate, err := time.Parse(dateLayout, end)
+	if err != nil {
+		return time.Time{}, time.Time{}, status.Error(codes.InvalidArgument, "end_date must be YYYY-MM-DD")
+	}
+	return startDate, endDate, nil
+}
+
+func toProtoItem(item *domain.RentalItem) *pb.RentalItem {
+	return &pb.RentalItem{
+		Id:              item.ID.String(),
+		OwnerId:         item.OwnerID.String(),
+		Title:           item.Title,
+		Description:     item.Description,
+		Category:        string(item.Category),
+		Subcategory:     item.Subcategory,
+		DailyRate:       item.DailyRate,
+		WeeklyRate:      item.WeeklyRate,
+		MonthlyRate:     item.MonthlyRate,
+		SecurityDeposit: item.SecurityDeposit,
+		Location: &pb.Location{
+			Address:   item.Address,
+			City:      item.City,
+			Latitude:  item.Latitude,
+			Longitude: item.Longitude,
+		},
+		Specifications: item.Specifications,
+		Images:         item.Images,
+		IsActive:       item.IsActive,
+		CreatedAt:      timestamppb.New(item.CreatedAt),
+		UpdatedAt:      timestamppb.New(item.UpdatedAt),
+	}
+}
+
+func toProtoItemList(items []*domain.RentalItem, total int, page, pageSize int32) *pb.ListItemsResponse {
+	resp := &pb.ListItemsResponse{
+		Items:    make([]*pb.RentalItem, len(items)),
+		Total:    int32(total),
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for i, item := range items {
+		resp.Items[i] = toProtoItem(item)
+	}
+	return resp
+}
+
+func toProtoSlot(slot *domain.AvailabilitySlot) *pb.AvailabilitySlot {
+	protoSlot := &pb.AvailabilitySlot{
+		Id:        slot.ID.String(),
+		StartDate: slot.StartDate.Format(dateLayout),
+		EndDate:   slot.EndDate.Format(dateLayout),
+		Status:    string(slot.Status),
+	}
+	if slot.BookingID != nil {
+		bookingID := slot.BookingID.String()
+		protoSlot.BookingId = &bookingID
+	}
+	return protoSlot
+}
+
+func toProtoMaintenanceLog(log *domain.MaintenanceLog) *pb.MaintenanceLog {
+	protoLog := &pb.MaintenanceLog{
+		Id:              log.ID.String(),
+		RentalItemId:    log.RentalItemID.String(),
+		MaintenanceType: log.MaintenanceType,
+		Description:     log.Description,
+		StartDate:       timestamppb.New(log.StartDate),
+		Cost:            log.Cost,
+		Status:          string(log.Status),
+		CreatedAt:       timestamppb.New(log.CreatedAt),
+	}
+	if log.EndDate != nil {
+		protoLog.EndDate = timestamppb.New(*log.EndDate)
+	}
+	return protoLog
+}
+
+// toGRPCError maps domain errors to gRPC status errors
+func toGRPCError(err error) error {
+	switch err {
+	case domain.ErrItemNotFound, domain.ErrSlotNotFound, domain.ErrMaintenanceNotFound:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeNotFound, err))
+	case domain.ErrUnauthorized, domain.ErrIdentityNotVerified:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeForbidden, err))
+	case domain.ErrInvalidCategory, domain.ErrInvalidPrice, domain.ErrInvalidDateRange, domain.ErrInvalidStatus:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeValidation, err))
+	case domain.ErrDateConflict, domain.ErrVersionConflict, domain.ErrSlotConflict:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeConflict, err))
+	default:
+		return apperrors.ToGRPCError(err)
+	}
+}
@@ -9,27 +9,31 @@ import (
 	"github.com/rentalflow/inventory-service/internal/domain"
 	"github.com/rentalflow/inventory-service/internal/repository"
 	"github.com/rentalflow/inventory-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/etag"
+	"github.com/rentalflow/rentalflow/pkg/userdata"
 )
 
 // HTTPHandler provides REST endpoints for testing
 type HTTPHandler struct {
 	inventoryService *service.InventoryService
+	checker          *auth.Checker
 }
 
 // NewHTTPHandler creates a new HTTP handler
-func NewHTTPHandler(inventoryService *service.InventoryService) *HTTPHandler {
-	return &HTTPHandler{inventoryService: inventoryService}
+func NewHTTPHandler(inventoryService *service.InventoryService, checker *auth.Checker) *HTTPHandler {
+	return &HTTPHandler{inventoryService: inventoryService, checker: checker}
 }
 
 // RegisterRoutes registers HTTP routes
 func (h *HTTPHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/health", h.Health)
-	mux.HandleFunc("/ready", h.Ready)
 	mux.HandleFunc("/api/items", h.HandleItems)
 	mux.HandleFunc("/api/items/owner", h.GetOwnerItems)
 	mux.HandleFunc("/api/items/search", h.SearchItems)
 	mux.HandleFunc("/api/availability/block", h.BlockDates)
 	mux.HandleFunc("/api/maintenance", h.CreateMaintenance)
+	userdata.NewHandler(h.checker, h.inventoryService.ExportUserData, h.inventoryService.EraseUserData, userdata.StatusErased, h.handleError).Register(mux)
 }
 
 func (h *HTTPHandler) Health(w http.ResponseWriter, r *http.Request) {
@@ -37,11 +41,6 @@ func (h *HTTPHandler) Health(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
 }
 
-func (h *HTTPHandler) Ready(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "ready"})
-}
-
 type CreateItemRequest struct {
 	OwnerID         string            `json:"owner_id"`
 	Title           string            `json:"title"`
@@ -81,12 +80,25 @@ func (h *HTTPHandler) HandleItems(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *HTTPHandler) CreateItem(w http.ResponseWriter, r *http.Request) {
+	principal, ok := h.checker.Require(w, r, auth.PermItemsWrite)
+	if !ok {
+		return
+	}
+
 	var req CreateItemRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	if !principal.CanActFor(req.OwnerID) {
+		h.handleError(w, domain.ErrUnauthorized)
+		return
+	}
+	if req.OwnerID == "" {
+		req.OwnerID = principal.UserID
+	}
+
 	ownerID, err := uuid.Parse(req.OwnerID)
 	if err != nil {
 		http.Error(w, "Invalid owner_id", http.StatusBadRequest)
@@ -139,6 +151,7 @@ func (h *HTTPHandler) GetItem(w http.ResponseWriter, r *http.Request, itemID str
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	etag.Set(w, item.Version)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"id":               item.ID.String(),
 		"owner_id":         item.OwnerID.String(),
@@ -156,6 +169,7 @@ func (h *HTTPHandler) GetItem(w http.ResponseWriter, r *http.Request, itemID str
 		"images":           item.Images,
 		"is_active":        item.IsActive,
 		"created_at":       item.CreatedAt,
+		"version":          item.Version,
 	})
 }
 
@@ -225,8 +239,20 @@ func (h *HTTPHandler) ListItems(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *HTTPHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
+	principal, ok := h.checker.Require(w, r, auth.PermItemsWrite)
+	if !ok {
+		return
+	}
+
 	itemID := r.URL.Query().Get("id")
 	ownerID := r.URL.Query().Get("owner_id")
+	if !principal.CanActFor(ownerID) {
+		h.handleError(w, domain.ErrUnauthorized)
+		return
+	}
+	if ownerID == "" {
+		ownerID = principal.UserID
+	}
 
 	id, err := uuid.Parse(itemID)
 	if err != nil {
@@ -246,23 +272,37 @@ func (h *HTTPHandler) UpdateItem(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	item, err := h.inventoryService.UpdateItem(r.Context(), id, oid, updates)
+	item, err := h.inventoryService.UpdateItem(r.Context(), id, oid, updates, etag.IfMatch(r))
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	etag.Set(w, item.Version)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"id":        item.ID.String(),
 		"title":     item.Title,
 		"is_active": item.IsActive,
+		"version":   item.Version,
 	})
 }
 
 func (h *HTTPHandler) DeleteItem(w http.ResponseWriter, r *http.Request) {
+	principal, ok := h.checker.Require(w, r, auth.PermItemsWrite)
+	if !ok {
+		return
+	}
+
 	itemID := r.URL.Query().Get("id")
 	ownerID := r.URL.Query().Get("owner_id")
+	if !principal.CanActFor(ownerID) {
+		h.handleError(w, domain.ErrUnauthorized)
+		return
+	}
+	if ownerID == "" {
+		ownerID = principal.UserID
+	}
 
 	id, err := uuid.Parse(itemID)
 	if err != nil {
@@ -406,6 +446,10 @@ func (h *HTTPHandler) BlockDates(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := h.checker.Require(w, r, auth.PermItemsWrite); !ok {
+		return
+	}
+
 	var req struct {
 		ItemID    string `json:"item_id"`
 		StartDate string `json:"start_date"`
@@ -428,6 +472,10 @@ func (h *HTTPHandler) CreateMaintenance(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if _, ok := h.checker.Require(w, r, auth.PermItemsWrite); !ok {
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
@@ -438,10 +486,12 @@ func (h *HTTPHandler) handleError(w http.ResponseWriter, err error) {
 	switch err {
 	case domain.ErrItemNotFound:
 		w.WriteHeader(http.StatusNotFound)
-	case domain.ErrUnauthorized:
+	case domain.ErrUnauthorized, domain.ErrIdentityNotVerified:
 		w.WriteHeader(http.StatusForbidden)
 	case domain.ErrInvalidCategory:
 		w.WriteHeader(http.StatusBadRequest)
+	case domain.ErrVersionConflict:
+		w.WriteHeader(http.StatusConflict)
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
 	}
@@ -0,0 +1,54 @@
+// Package migrations declares the inventory service's indexes, and its schema
+// on PostgreSQL in the .sql files of postgres/. Released versions must never
+// change: add a new one instead.
+package migrations
+
+import (
+	"embed"
+
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// All lists the inventory service's migrations
+var All = []database.Migration{
+	{
+		Version:     1,
+		Description: "Index rental items by owner, catalog filters and price",
+		Up: database.CreateIndexes("rental_items",
+			mongo.IndexModel{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "is_active", Value: 1}, {Key: "created_at", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "category", Value: 1}, {Key: "created_at", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "city", Value: 1}, {Key: "created_at", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "daily_rate", Value: 1}}},
+		),
+	},
+	{
+		Version:     2,
+		Description: "Index availability slots and maintenance logs by item",
+		Up: database.Steps(
+			database.CreateIndexes("availability_slots",
+				mongo.IndexModel{Keys: bson.D{{Key: "rental_item_id", Value: 1}, {Key: "start_date", Value: 1}, {Key: "end_date", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "rental_item_id", Value: 1}, {Key: "booking_id", Value: 1}}},
+			),
+			database.CreateIndexes("maintenance_logs",
+				mongo.IndexModel{Keys: bson.D{{Key: "rental_item_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			),
+		),
+	},
+	{
+		Version:     3,
+		Description: "Version rental items and availability slots",
+		Up: database.Steps(
+			database.BackfillVersion("rental_items"),
+			database.BackfillVersion("availability_slots"),
+		),
+	},
+}
+
+//go:embed postgres/*.sql
+var postgresFiles embed.FS
+
+// Postgres lists the inventory service's PostgreSQL migrations
+var Postgres = database.MustLoadSQLMigrations(postgresFiles, "postgres")
@@ -0,0 +1,28 @@
+CREATE TABLE rental_items (
+    id               uuid PRIMARY KEY,
+    owner_id         uuid NOT NULL,
+    title            text NOT NULL,
+    description      text NOT NULL,
+    category         text NOT NULL,
+    subcategory      text NOT NULL,
+    daily_rate       double precision NOT NULL,
+    weekly_rate      double precision NOT NULL,
+    monthly_rate     double precision NOT NULL,
+    security_deposit double precision NOT NULL,
+    address          text NOT NULL,
+    city             text NOT NULL,
+    latitude         double precision NOT NULL,
+    longitude        double precision NOT NULL,
+    specifications   jsonb,
+    images           text[],
+    is_active        boolean NOT NULL,
+    created_at       timestamptz NOT NULL,
+    updated_at       timestamptz NOT NULL,
+    version          bigint NOT NULL DEFAULT 1
+);
+
+CREATE INDEX rental_items_owner_id_created_at_idx ON rental_items (owner_id, created_at DESC);
+CREATE INDEX rental_items_is_active_created_at_idx ON rental_items (is_active, created_at DESC);
+CREATE INDEX rental_items_category_created_at_idx ON rental_items (category, created_at DESC);
+CREATE INDEX rental_items_city_created_at_idx ON rental_items (city, created_at DESC);
+CREATE INDEX rental_items_daily_rate_idx ON rental_items (daily_rate);
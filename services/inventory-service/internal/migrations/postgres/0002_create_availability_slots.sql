@@ -0,0 +1,23 @@
+-- btree_gist lets the exclusion constraint compare item IDs with = next to
+-- the range overlap
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE availability_slots (
+    id             uuid PRIMARY KEY,
+    rental_item_id uuid NOT NULL,
+    start_date     timestamptz NOT NULL,
+    end_date       timestamptz NOT NULL,
+    status         text NOT NULL,
+    booking_id     uuid,
+    created_at     timestamptz NOT NULL,
+    version        bigint NOT NULL DEFAULT 1,
+
+    -- Two slots that hold an item may not overlap, however many replicas
+    -- book it at once. Free slots may.
+    CONSTRAINT availability_slots_no_overlap EXCLUDE USING gist (
+        rental_item_id WITH =,
+        tstzrange(start_date, end_date) WITH &&
+    ) WHERE (status <> 'available')
+);
+
+CREATE INDEX availability_slots_rental_item_id_booking_id_idx ON availability_slots (rental_item_id, booking_id);
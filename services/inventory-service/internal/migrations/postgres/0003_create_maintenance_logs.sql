@@ -0,0 +1,13 @@
+CREATE TABLE maintenance_logs (
+    id               uuid PRIMARY KEY,
+    rental_item_id   uuid NOT NULL,
+    maintenance_type text NOT NULL,
+    description      text NOT NULL,
+    start_date       timestamptz NOT NULL,
+    end_date         timestamptz,
+    cost             double precision NOT NULL,
+    status           text NOT NULL,
+    created_at       timestamptz NOT NULL
+);
+
+CREATE INDEX maintenance_logs_rental_item_id_created_at_idx ON maintenance_logs (rental_item_id, created_at DESC);
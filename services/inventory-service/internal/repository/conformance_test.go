@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/rentalflow/inventory-service/internal/migrations"
+	"github.com/rentalflow/inventory-service/internal/repository"
+	"github.com/rentalflow/inventory-service/internal/repository/repositorytest"
+	"github.com/rentalflow/rentalflow/pkg/database/databasetest"
+)
+
+func TestMongoRepositories(t *testing.T) {
+	t.Run("Item", func(t *testing.T) {
+		repositorytest.ItemRepository(t, func(t *testing.T) repository.ItemRepository {
+			return repository.NewMongoItemRepository(databasetest.Mongo(t, migrations.All).DB)
+		})
+	})
+	t.Run("Availability", func(t *testing.T) {
+		repositorytest.AvailabilityRepository(t, func(t *testing.T) repository.AvailabilityRepository {
+			return repository.NewMongoAvailabilityRepository(databasetest.Mongo(t, migrations.All).DB)
+		})
+	})
+	t.Run("Maintenance", func(t *testing.T) {
+		repositorytest.MaintenanceRepository(t, func(t *testing.T) repository.MaintenanceRepository {
+			return repository.NewMongoMaintenanceRepository(databasetest.Mongo(t, migrations.All).DB)
+		})
+	})
+}
+
+func TestPostgresRepositories(t *testing.T) {
+	t.Run("Item", func(t *testing.T) {
+		repositorytest.ItemRepository(t, func(t *testing.T) repository.ItemRepository {
+			return repository.NewPostgresItemRepository(databasetest.Postgres(t, migrations.Postgres))
+		})
+	})
+	t.Run("Availability", func(t *testing.T) {
+		repositorytest.AvailabilityRepository(t, func(t *testing.T) repository.AvailabilityRepository {
+			return repository.NewPostgresAvailabilityRepository(databasetest.Postgres(t, migrations.Postgres))
+		})
+	})
+	t.Run("Maintenance", func(t *testing.T) {
+		repositorytest.MaintenanceRepository(t, func(t *testing.T) repository.MaintenanceRepository {
+			return repository.NewPostgresMaintenanceRepository(databasetest.Postgres(t, migrations.Postgres))
+		})
+	})
+}
+
+func TestMemoryRepositories(t *testing.T) {
+	t.Run("Item", func(t *testing.T) {
+		repositorytest.ItemRepository(t, func(t *testing.T) repository.ItemRepository {
+			return repository.NewMemoryItemRepository()
+		})
+	})
+	t.Run("Availability", func(t *testing.T) {
+		repositorytest.AvailabilityRepository(t, func(t *testing.T) repository.AvailabilityRepository {
+			return repository.NewMemoryAvailabilityRepository()
+		})
+	})
+	t.Run("Maintenance", func(t *testing.T) {
+		repositorytest.MaintenanceRepository(t, func(t *testing.T) repository.MaintenanceRepository {
+			return repository.NewMemoryMaintenanceRepository()
+		})
+	})
+}
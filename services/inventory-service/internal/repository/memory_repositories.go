@@ -0,0 +1,380 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/inventory-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// MemoryItemRepository implements ItemRepository in memory, for tests and
+// local runs
+type MemoryItemRepository struct {
+	mu    sync.Mutex
+	items map[uuid.UUID]*domain.RentalItem
+}
+
+// NewMemoryItemRepository creates an empty in-memory item repository
+func NewMemoryItemRepository() *MemoryItemRepository {
+	return &MemoryItemRepository{
+		items: make(map[uuid.UUID]*domain.RentalItem),
+	}
+}
+
+func (r *MemoryItemRepository) Create(ctx context.Context, item *domain.RentalItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items[item.ID] = database.Clone(item)
+	return nil
+}
+
+func (r *MemoryItemRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.RentalItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	item, ok := r.items[id]
+	if !ok {
+		return nil, domain.ErrItemNotFound
+	}
+	return database.Clone(item), nil
+}
+
+func (r *MemoryItemRepository) GetByOwner(ctx context.Context, ownerID uuid.UUID, offset, limit int) ([]*domain.RentalItem, int, error) {
+	items := r.find(func(item *domain.RentalItem) bool { return item.OwnerID == ownerID })
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.After(items[j].CreatedAt)
+	})
+	return database.Page(items, offset, limit), len(items), nil
+}
+
+func (r *MemoryItemRepository) List(ctx context.Context, offset, limit int, filters ItemFilters) ([]*domain.RentalItem, int, error) {
+	return r.page(func(item *domain.RentalItem) bool { return itemMatches(item, filters) }, filters, offset, limit)
+}
+
+func (r *MemoryItemRepository) Search(ctx context.Context, query string, filters ItemFilters, offset, limit int) ([]*domain.RentalItem, int, error) {
+	// Case-insensitive regex search on title or description, as MongoDB runs it
+	re, err := regexp.Compile("(?i)" + query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid search query: %w", err)
+	}
+	return r.page(func(item *domain.RentalItem) bool {
+		return itemMatches(item, filters) && (re.MatchString(item.Title) || re.MatchString(item.Description))
+	}, filters, offset, limit)
+}
+
+// page returns a page of the matching items, in the filters' order
+func (r *MemoryItemRepository) page(match func(*domain.RentalItem) bool, filters ItemFilters, offset, limit int) ([]*domain.RentalItem, int, error) {
+	items := r.find(match)
+
+	less := func(a, b *domain.RentalItem) bool { return a.CreatedAt.After(b.CreatedAt) }
+	if filters.SortBy != nil {
+		switch *filters.SortBy {
+		case "price_low":
+			less = func(a, b *domain.RentalItem) bool { return a.DailyRate < b.DailyRate }
+		case "price_high":
+			less = func(a, b *domain.RentalItem) bool { return a.DailyRate > b.DailyRate }
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return less(items[i], items[j]) })
+
+	return database.Page(items, offset, limit), len(items), nil
+}
+
+// itemMatches reports whether an item passes filters
+func itemMatches(item *domain.RentalItem, filters ItemFilters) bool {
+	if filters.Category != nil && item.Category != *filters.Category {
+		return false
+	}
+	if filters.City != nil && item.City != *filters.City {
+		return false
+	}
+	if filters.IsActive != nil && item.IsActive != *filters.IsActive {
+		return false
+	}
+	if filters.MinPrice != nil && item.DailyRate < *filters.MinPrice {
+		return false
+	}
+	if filters.MaxPrice != nil && item.DailyRate > *filters.MaxPrice {
+		return false
+	}
+	return true
+}
+
+func (r *MemoryItemRepository) Update(ctx context.Context, item *domain.RentalItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.items[item.ID]
+	if !ok {
+		return domain.ErrItemNotFound
+	}
+	if stored.Version != item.Version {
+		return domain.ErrVersionConflict
+	}
+
+	now := time.Now()
+	updated := database.Clone(item)
+	updated.OwnerID = stored.OwnerID
+	updated.CreatedAt = stored.CreatedAt
+	updated.UpdatedAt = now
+	updated.Version++
+	r.items[item.ID] = updated
+
+	item.UpdatedAt = now
+	item.Version++
+	return nil
+}
+
+func (r *MemoryItemRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[id]; !ok {
+		return domain.ErrItemNotFound
+	}
+	delete(r.items, id)
+	return nil
+}
+
+// ListAllByOwner returns every item of an owner, including inactive ones
+func (r *MemoryItemRepository) ListAllByOwner(ctx context.Context, ownerID uuid.UUID) ([]*domain.RentalItem, error) {
+	items := r.find(func(item *domain.RentalItem) bool { return item.OwnerID == ownerID })
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].CreatedAt.Before(items[j].CreatedAt)
+	})
+	return items, nil
+}
+
+// AnonymizeByOwner unlists an owner's items and removes their street address
+// and coordinates. Items are kept because past bookings reference them.
+func (r *MemoryItemRepository) AnonymizeByOwner(ctx context.Context, ownerID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, item := range r.items {
+		if item.OwnerID == ownerID {
+			item.Address = ""
+			item.Latitude = 0
+			item.Longitude = 0
+			item.IsActive = false
+			item.UpdatedAt = time.Now()
+			item.Version++
+			count++
+		}
+	}
+	return count, nil
+}
+
+// find returns copies of the items that match
+func (r *MemoryItemRepository) find(match func(*domain.RentalItem) bool) []*domain.RentalItem {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := []*domain.RentalItem{}
+	for _, item := range r.items {
+		if match(item) {
+			items = append(items, database.Clone(item))
+		}
+	}
+	return items
+}
+
+// MemoryAvailabilityRepository implements AvailabilityRepository in memory
+type MemoryAvailabilityRepository struct {
+	mu    sync.Mutex
+	slots map[uuid.UUID]*domain.AvailabilitySlot
+}
+
+func NewMemoryAvailabilityRepository() *MemoryAvailabilityRepository {
+	return &MemoryAvailabilityRepository{
+		slots: make(map[uuid.UUID]*domain.AvailabilitySlot),
+	}
+}
+
+func (r *MemoryAvailabilityRepository) Create(ctx context.Context, slot *domain.AvailabilitySlot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Checked under the lock, so two bookings cannot both take the dates
+	if slot.Status != domain.StatusAvailable && r.conflicts(slot.RentalItemID, slot.StartDate, slot.EndDate, nil) {
+		return domain.ErrDateConflict
+	}
+	r.slots[slot.ID] = database.Clone(slot)
+	return nil
+}
+
+func (r *MemoryAvailabilityRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AvailabilitySlot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slot, ok := r.slots[id]
+	if !ok {
+		return nil, domain.ErrSlotNotFound
+	}
+	return database.Clone(slot), nil
+}
+
+func (r *MemoryAvailabilityRepository) GetByItem(ctx context.Context, itemID uuid.UUID, startDate, endDate time.Time) ([]*domain.AvailabilitySlot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	slots := []*domain.AvailabilitySlot{}
+	for _, slot := range r.slots {
+		if slot.RentalItemID == itemID && !slot.StartDate.Before(startDate) && !slot.EndDate.After(endDate) {
+			slots = append(slots, database.Clone(slot))
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].StartDate.Before(slots[j].StartDate)
+	})
+	return slots, nil
+}
+
+func (r *MemoryAvailabilityRepository) Update(ctx context.Context, slot *domain.AvailabilitySlot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.slots[slot.ID]
+	if !ok {
+		return domain.ErrSlotNotFound
+	}
+	if stored.Version != slot.Version {
+		return domain.ErrSlotConflict
+	}
+	stored.Status = slot.Status
+	stored.BookingID = slot.BookingID
+	stored.Version++
+	r.slots[slot.ID] = database.Clone(stored)
+
+	slot.Version++
+	return nil
+}
+
+func (r *MemoryAvailabilityRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.slots[id]; !ok {
+		return domain.ErrSlotNotFound
+	}
+	delete(r.slots, id)
+	return nil
+}
+
+func (r *MemoryAvailabilityRepository) DeleteByBooking(ctx context.Context, itemID, bookingID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, slot := range r.slots {
+		if slot.RentalItemID == itemID && slot.BookingID != nil && *slot.BookingID == bookingID {
+			delete(r.slots, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryAvailabilityRepository) CheckConflict(ctx context.Context, itemID uuid.UUID, startDate, endDate time.Time, excludeSlotID *uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.conflicts(itemID, startDate, endDate, excludeSlotID), nil
+}
+
+// conflicts reports whether a slot that is not 'available' overlaps the
+// dates. The caller holds the lock.
+func (r *MemoryAvailabilityRepository) conflicts(itemID uuid.UUID, startDate, endDate time.Time, excludeSlotID *uuid.UUID) bool {
+	for _, slot := range r.slots {
+		if slot.RentalItemID != itemID || slot.Status == domain.StatusAvailable {
+			continue
+		}
+		if excludeSlotID != nil && slot.ID == *excludeSlotID {
+			continue
+		}
+		if slot.StartDate.Before(endDate) && slot.EndDate.After(startDate) {
+			return true
+		}
+	}
+	return false
+}
+
+// MemoryMaintenanceRepository implements MaintenanceRepository in memory
+type MemoryMaintenanceRepository struct {
+	mu   sync.Mutex
+	logs map[uuid.UUID]*domain.MaintenanceLog
+}
+
+func NewMemoryMaintenanceRepository() *MemoryMaintenanceRepository {
+	return &MemoryMaintenanceRepository{
+		logs: make(map[uuid.UUID]*domain.MaintenanceLog),
+	}
+}
+
+func (r *MemoryMaintenanceRepository) Create(ctx context.Context, log *domain.MaintenanceLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.logs[log.ID] = database.Clone(log)
+	return nil
+}
+
+func (r *MemoryMaintenanceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.MaintenanceLog, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	log, ok := r.logs[id]
+	if !ok {
+		return nil, domain.ErrMaintenanceNotFound
+	}
+	return database.Clone(log), nil
+}
+
+func (r *MemoryMaintenanceRepository) GetByItem(ctx context.Context, itemID uuid.UUID, offset, limit int) ([]*domain.MaintenanceLog, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	logs := []*domain.MaintenanceLog{}
+	for _, log := range r.logs {
+		if log.RentalItemID == itemID {
+			logs = append(logs, database.Clone(log))
+		}
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].CreatedAt.After(logs[j].CreatedAt)
+	})
+	return database.Page(logs, offset, limit), len(logs), nil
+}
+
+func (r *MemoryMaintenanceRepository) Update(ctx context.Context, log *domain.MaintenanceLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.logs[log.ID]
+	if !ok {
+		return domain.ErrMaintenanceNotFound
+	}
+	stored.Status = log.Status
+	stored.EndDate = log.EndDate
+	r.logs[log.ID] = database.Clone(stored)
+	return nil
+}
+
+func (r *MemoryMaintenanceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.logs[id]; !ok {
+		return domain.ErrMaintenanceNotFound
+	}
+	delete(r.logs, id)
+	return nil
+}
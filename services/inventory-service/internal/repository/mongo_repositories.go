@@ -69,33 +69,22 @@ func (r *MongoItemRepository) GetByOwner(ctx context.Context, ownerID uuid.UUID,
 }
 
 func (r *MongoItemRepository) List(ctx context.Context, offset, limit int, filters ItemFilters) ([]*domain.RentalItem, int, error) {
-	filter := bson.M{}
+	return r.find(ctx, itemFilter(filters), filters, offset, limit)
+}
 
-	if filters.Category != nil {
-		filter["category"] = *filters.Category
-	}
-	if filters.City != nil {
-		filter["city"] = *filters.City
-	}
-	if filters.IsActive != nil {
-		filter["is_active"] = *filters.IsActive
-	}
-	// Note: MinPrice/MaxPrice implemented if referenced in filter?
-	// The postgres repo did NOT implement MinPrice/MaxPrice logic in List function shown above (step 773),
-	// it only handled Category, City, IsActive in `List`.
-	// However, `ItemFilters` struct HAS MinPrice/MaxPrice.
-	// I will implement them here for completeness.
-	if filters.MinPrice != nil || filters.MaxPrice != nil {
-		priceFilter := bson.M{}
-		if filters.MinPrice != nil {
-			priceFilter["$gte"] = *filters.MinPrice
-		}
-		if filters.MaxPrice != nil {
-			priceFilter["$lte"] = *filters.MaxPrice
-		}
-		filter["daily_rate"] = priceFilter
+func (r *MongoItemRepository) Search(ctx context.Context, query string, filters ItemFilters, offset, limit int) ([]*domain.RentalItem, int, error) {
+	// Simple regex search on title or description
+	regex := bson.M{"$regex": query, "$options": "i"}
+	filter := itemFilter(filters)
+	filter["$or"] = []bson.M{
+		{"title": regex},
+		{"description": regex},
 	}
+	return r.find(ctx, filter, filters, offset, limit)
+}
 
+// find returns a page of the items matching filter, in the filters' order
+func (r *MongoItemRepository) find(ctx context.Context, filter bson.M, filters ItemFilters, offset, limit int) ([]*domain.RentalItem, int, error) {
 	total, err := r.coll.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
@@ -132,17 +121,10 @@ func (r *MongoItemRepository) List(ctx context.Context, offset, limit int, filte
 	return items, int(total), nil
 }
 
-func (r *MongoItemRepository) Search(ctx context.Context, query string, filters ItemFilters, offset, limit int) ([]*domain.RentalItem, int, error) {
-	// Simple regex search on title or description
-	regex := bson.M{"$regex": query, "$options": "i"}
-	filter := bson.M{
-		"$or": []bson.M{
-			{"title": regex},
-			{"description": regex},
-		},
-	}
+// itemFilter matches the items that pass filters
+func itemFilter(filters ItemFilters) bson.M {
+	filter := bson.M{}
 
-	// Apply other filters
 	if filters.Category != nil {
 		filter["category"] = *filters.Category
 	}
@@ -152,44 +134,21 @@ func (r *MongoItemRepository) Search(ctx context.Context, query string, filters
 	if filters.IsActive != nil {
 		filter["is_active"] = *filters.IsActive
 	}
-
-	total, err := r.coll.CountDocuments(ctx, filter)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	sort := bson.M{"created_at": -1}
-	if filters.SortBy != nil {
-		switch *filters.SortBy {
-		case "price_low":
-			sort = bson.M{"daily_rate": 1}
-		case "price_high":
-			sort = bson.M{"daily_rate": -1}
-		case "newest":
-			sort = bson.M{"created_at": -1}
+	if filters.MinPrice != nil || filters.MaxPrice != nil {
+		priceFilter := bson.M{}
+		if filters.MinPrice != nil {
+			priceFilter["$gte"] = *filters.MinPrice
 		}
+		if filters.MaxPrice != nil {
+			priceFilter["$lte"] = *filters.MaxPrice
+		}
+		filter["daily_rate"] = priceFilter
 	}
-
-	opts := options.Find().
-		SetSort(sort).
-		SetSkip(int64(offset)).
-		SetLimit(int64(limit))
-
-	cursor, err := r.coll.Find(ctx, filter, opts)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer cursor.Close(ctx)
-
-	var items []*domain.RentalItem
-	if err := cursor.All(ctx, &items); err != nil {
-		return nil, 0, err
-	}
-
-	return items, int(total), nil
+	return filter
 }
 
 func (r *MongoItemRepository) Update(ctx context.Context, item *domain.RentalItem) error {
+	now := time.Now()
 	update := bson.M{
 		"$set": bson.M{
 			"title":            item.Title,
@@ -207,18 +166,31 @@ func (r *MongoItemRepository) Update(ctx context.Context, item *domain.RentalIte
 			"specifications":   item.Specifications,
 			"images":           item.Images,
 			"is_active":        item.IsActive,
-			"updated_at":       time.Now(),
+			"updated_at":       now,
 		},
+		"$inc": bson.M{"version": 1},
 	}
 
-	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": item.ID}, update)
+	filter := bson.M{"_id": item.ID, "version": item.Version}
+	result, err := r.coll.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
 
 	if result.MatchedCount == 0 {
+		// Tell a stale version from a missing item
+		count, err := r.coll.CountDocuments(ctx, bson.M{"_id": item.ID})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.ErrVersionConflict
+		}
 		return domain.ErrItemNotFound
 	}
+
+	item.UpdatedAt = now
+	item.Version++
 	return nil
 }
 
@@ -233,18 +205,82 @@ func (r *MongoItemRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// ListAllByOwner returns every item of an owner, including inactive ones
+func (r *MongoItemRepository) ListAllByOwner(ctx context.Context, ownerID uuid.UUID) ([]*domain.RentalItem, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+
+	cursor, err := r.coll.Find(ctx, bson.M{"owner_id": ownerID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var items []*domain.RentalItem
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// AnonymizeByOwner unlists an owner's items and removes their street address
+// and coordinates. Items are kept because past bookings reference them.
+func (r *MongoItemRepository) AnonymizeByOwner(ctx context.Context, ownerID uuid.UUID) (int, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"address":    "",
+			"latitude":   0,
+			"longitude":  0,
+			"is_active":  false,
+			"updated_at": time.Now(),
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := r.coll.UpdateMany(ctx, bson.M{"owner_id": ownerID}, update)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
+
 // MongoAvailabilityRepository implements AvailabilityRepository using MongoDB
 type MongoAvailabilityRepository struct {
-	coll *mongo.Collection
+	coll  *mongo.Collection
+	holds *mongo.Collection
 }
 
 func NewMongoAvailabilityRepository(db *mongo.Database) *MongoAvailabilityRepository {
 	return &MongoAvailabilityRepository{
-		coll: db.Collection("availability_slots"),
+		coll:  db.Collection("availability_slots"),
+		holds: db.Collection("availability_holds"),
 	}
 }
 
+// claimItem writes the item's document in availability_holds. Two
+// transactions holding dates of the same item then conflict, and the one
+// that retries sees the other's slot in its conflict check.
+func (r *MongoAvailabilityRepository) claimItem(ctx context.Context, itemID uuid.UUID) error {
+	_, err := r.holds.UpdateOne(ctx,
+		bson.M{"_id": itemID},
+		bson.M{"$inc": bson.M{"holds": 1}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
 func (r *MongoAvailabilityRepository) Create(ctx context.Context, slot *domain.AvailabilitySlot) error {
+	if slot.Status != domain.StatusAvailable {
+		if err := r.claimItem(ctx, slot.RentalItemID); err != nil {
+			return err
+		}
+		conflict, err := r.CheckConflict(ctx, slot.RentalItemID, slot.StartDate, slot.EndDate, nil)
+		if err != nil {
+			return err
+		}
+		if conflict {
+			return domain.ErrDateConflict
+		}
+	}
 	_, err := r.coll.InsertOne(ctx, slot)
 	return err
 }
@@ -289,14 +325,25 @@ func (r *MongoAvailabilityRepository) Update(ctx context.Context, slot *domain.A
 			"status":     slot.Status,
 			"booking_id": slot.BookingID,
 		},
+		"$inc": bson.M{"version": 1},
 	}
-	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": slot.ID}, update)
+	filter := bson.M{"_id": slot.ID, "version": slot.Version}
+	result, err := r.coll.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
 	if result.MatchedCount == 0 {
+		// Tell a stale version from a missing slot
+		count, err := r.coll.CountDocuments(ctx, bson.M{"_id": slot.ID})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.ErrSlotConflict
+		}
 		return domain.ErrSlotNotFound
 	}
+	slot.Version++
 	return nil
 }
 
@@ -311,6 +358,14 @@ func (r *MongoAvailabilityRepository) Delete(ctx context.Context, id uuid.UUID)
 	return nil
 }
 
+func (r *MongoAvailabilityRepository) DeleteByBooking(ctx context.Context, itemID, bookingID uuid.UUID) (int, error) {
+	result, err := r.coll.DeleteMany(ctx, bson.M{"rental_item_id": itemID, "booking_id": bookingID})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.DeletedCount), nil
+}
+
 func (r *MongoAvailabilityRepository) CheckConflict(ctx context.Context, itemID uuid.UUID, startDate, endDate time.Time, excludeSlotID *uuid.UUID) (bool, error) {
 	// Find any slot that overlaps and is not 'available'
 	// Overlap logic: (StartA <= EndB) and (EndA >= StartB)
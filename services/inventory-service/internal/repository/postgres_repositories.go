@@ -0,0 +1,448 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/inventory-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// itemColumns lists the columns of the rental_items table in scanItem order
+const itemColumns = `id, owner_id, title, description, category, subcategory,
+	daily_rate, weekly_rate, monthly_rate, security_deposit,
+	address, city, latitude, longitude, specifications, images,
+	is_active, created_at, updated_at, version`
+
+// PostgresItemRepository implements ItemRepository using PostgreSQL
+type PostgresItemRepository struct {
+	db *database.Postgres
+}
+
+// NewPostgresItemRepository creates a new PostgreSQL item repository
+func NewPostgresItemRepository(db *database.Postgres) *PostgresItemRepository {
+	return &PostgresItemRepository{db: db}
+}
+
+func (r *PostgresItemRepository) Create(ctx context.Context, item *domain.RentalItem) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO rental_items (`+itemColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`,
+		item.ID, item.OwnerID, item.Title, item.Description, item.Category, item.Subcategory,
+		item.DailyRate, item.WeeklyRate, item.MonthlyRate, item.SecurityDeposit,
+		item.Address, item.City, item.Latitude, item.Longitude, item.Specifications, item.Images,
+		item.IsActive, item.CreatedAt, item.UpdatedAt, item.Version,
+	)
+	return err
+}
+
+func (r *PostgresItemRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.RentalItem, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+itemColumns+` FROM rental_items WHERE id = $1`, id)
+	item, err := scanItem(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrItemNotFound
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+func (r *PostgresItemRepository) GetByOwner(ctx context.Context, ownerID uuid.UUID, offset, limit int) ([]*domain.RentalItem, int, error) {
+	return r.find(ctx, "owner_id = $1", []interface{}{ownerID}, "created_at DESC", offset, limit)
+}
+
+func (r *PostgresItemRepository) List(ctx context.Context, offset, limit int, filters ItemFilters) ([]*domain.RentalItem, int, error) {
+	where, args := itemWhere(filters, nil)
+	return r.find(ctx, where, args, itemOrder(filters), offset, limit)
+}
+
+func (r *PostgresItemRepository) Search(ctx context.Context, query string, filters ItemFilters, offset, limit int) ([]*domain.RentalItem, int, error) {
+	// Case-insensitive regular expression on title or description, as on MongoDB
+	where, args := itemWhere(filters, []interface{}{query})
+	where += " AND (title ~* $1 OR description ~* $1)"
+	return r.find(ctx, where, args, itemOrder(filters), offset, limit)
+}
+
+// find returns a page of the items matching where, in order
+func (r *PostgresItemRepository) find(ctx context.Context, where string, args []interface{}, order string, offset, limit int) ([]*domain.RentalItem, int, error) {
+	conn := r.db.Conn(ctx)
+
+	var total int
+	if err := conn.QueryRow(ctx, `SELECT count(*) FROM rental_items WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	n := len(args)
+	rows, err := conn.Query(ctx, fmt.Sprintf(`SELECT %s FROM rental_items WHERE %s ORDER BY %s OFFSET $%d LIMIT $%d`,
+		itemColumns, where, order, n+1, n+2),
+		append(args, offset, limit)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	items, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.RentalItem, error) {
+		return scanItem(row)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// itemWhere returns the condition matching the items that pass filters, with
+// its arguments appended to args
+func itemWhere(filters ItemFilters, args []interface{}) (string, []interface{}) {
+	conditions := []string{"true"}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filters.Category != nil {
+		add("category = $%d", *filters.Category)
+	}
+	if filters.City != nil {
+		add("city = $%d", *filters.City)
+	}
+	if filters.IsActive != nil {
+		add("is_active = $%d", *filters.IsActive)
+	}
+	if filters.MinPrice != nil {
+		add("daily_rate >= $%d", *filters.MinPrice)
+	}
+	if filters.MaxPrice != nil {
+		add("daily_rate <= $%d", *filters.MaxPrice)
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// itemOrder returns the ORDER BY of the filters' sort
+func itemOrder(filters ItemFilters) string {
+	if filters.SortBy != nil {
+		switch *filters.SortBy {
+		case "price_low":
+			return "daily_rate"
+		case "price_high":
+			return "daily_rate DESC"
+		}
+	}
+	return "created_at DESC"
+}
+
+func (r *PostgresItemRepository) Update(ctx context.Context, item *domain.RentalItem) error {
+	now := time.Now()
+	conn := r.db.Conn(ctx)
+
+	tag, err := conn.Exec(ctx, `UPDATE rental_items SET
+			title = $3, description = $4, category = $5, subcategory = $6,
+			daily_rate = $7, weekly_rate = $8, monthly_rate = $9, security_deposit = $10,
+			address = $11, city = $12, latitude = $13, longitude = $14,
+			specifications = $15, images = $16, is_active = $17,
+			updated_at = $18, version = version + 1
+		WHERE id = $1 AND version = $2`,
+		item.ID, item.Version,
+		item.Title, item.Description, item.Category, item.Subcategory,
+		item.DailyRate, item.WeeklyRate, item.MonthlyRate, item.SecurityDeposit,
+		item.Address, item.City, item.Latitude, item.Longitude,
+		item.Specifications, item.Images, item.IsActive, now,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		// Tell a stale version from a missing item
+		var exists bool
+		if err := conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM rental_items WHERE id = $1)`, item.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrVersionConflict
+		}
+		return domain.ErrItemNotFound
+	}
+
+	item.UpdatedAt = now
+	item.Version++
+	return nil
+}
+
+func (r *PostgresItemRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `DELETE FROM rental_items WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrItemNotFound
+	}
+	return nil
+}
+
+// ListAllByOwner returns every item of an owner, including inactive ones
+func (r *PostgresItemRepository) ListAllByOwner(ctx context.Context, ownerID uuid.UUID) ([]*domain.RentalItem, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, `SELECT `+itemColumns+` FROM rental_items
+		WHERE owner_id = $1 ORDER BY created_at`,
+		ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.RentalItem, error) {
+		return scanItem(row)
+	})
+}
+
+// AnonymizeByOwner unlists an owner's items and removes their street address
+// and coordinates. Items are kept because past bookings reference them.
+func (r *PostgresItemRepository) AnonymizeByOwner(ctx context.Context, ownerID uuid.UUID) (int, error) {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE rental_items SET
+			address = '', latitude = 0, longitude = 0, is_active = false,
+			updated_at = $2, version = version + 1
+		WHERE owner_id = $1`,
+		ownerID, time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// scanItem reads a row of itemColumns
+func scanItem(row pgx.Row) (*domain.RentalItem, error) {
+	var item domain.RentalItem
+	err := row.Scan(
+		&item.ID, &item.OwnerID, &item.Title, &item.Description, &item.Category, &item.Subcategory,
+		&item.DailyRate, &item.WeeklyRate, &item.MonthlyRate, &item.SecurityDeposit,
+		&item.Address, &item.City, &item.Latitude, &item.Longitude, &item.Specifications, &item.Images,
+		&item.IsActive, &item.CreatedAt, &item.UpdatedAt, &item.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// slotColumns lists the columns of the availability_slots table in scanSlot order
+const slotColumns = `id, rental_item_id, start_date, end_date, status, booking_id, created_at, version`
+
+// PostgresAvailabilityRepository implements AvailabilityRepository using
+// PostgreSQL. The table's exclusion constraint keeps the slots that hold an
+// item from overlapping.
+type PostgresAvailabilityRepository struct {
+	db *database.Postgres
+}
+
+func NewPostgresAvailabilityRepository(db *database.Postgres) *PostgresAvailabilityRepository {
+	return &PostgresAvailabilityRepository{db: db}
+}
+
+func (r *PostgresAvailabilityRepository) Create(ctx context.Context, slot *domain.AvailabilitySlot) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO availability_slots (`+slotColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		slot.ID, slot.RentalItemID, slot.StartDate, slot.EndDate, slot.Status, slot.BookingID, slot.CreatedAt, slot.Version,
+	)
+	if database.IsExclusionViolation(err) {
+		return domain.ErrDateConflict
+	}
+	return err
+}
+
+func (r *PostgresAvailabilityRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.AvailabilitySlot, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+slotColumns+` FROM availability_slots WHERE id = $1`, id)
+	slot, err := scanSlot(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSlotNotFound
+		}
+		return nil, err
+	}
+	return slot, nil
+}
+
+func (r *PostgresAvailabilityRepository) GetByItem(ctx context.Context, itemID uuid.UUID, startDate, endDate time.Time) ([]*domain.AvailabilitySlot, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, `SELECT `+slotColumns+` FROM availability_slots
+		WHERE rental_item_id = $1 AND start_date >= $2 AND end_date <= $3
+		ORDER BY start_date`,
+		itemID, startDate, endDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.AvailabilitySlot, error) {
+		return scanSlot(row)
+	})
+}
+
+func (r *PostgresAvailabilityRepository) Update(ctx context.Context, slot *domain.AvailabilitySlot) error {
+	conn := r.db.Conn(ctx)
+
+	tag, err := conn.Exec(ctx, `UPDATE availability_slots SET
+			status = $3, booking_id = $4, version = version + 1
+		WHERE id = $1 AND version = $2`,
+		slot.ID, slot.Version, slot.Status, slot.BookingID,
+	)
+	if err != nil {
+		if database.IsExclusionViolation(err) {
+			return domain.ErrDateConflict
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		// Tell a stale version from a missing slot
+		var exists bool
+		if err := conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM availability_slots WHERE id = $1)`, slot.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrSlotConflict
+		}
+		return domain.ErrSlotNotFound
+	}
+	slot.Version++
+	return nil
+}
+
+func (r *PostgresAvailabilityRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `DELETE FROM availability_slots WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrSlotNotFound
+	}
+	return nil
+}
+
+func (r *PostgresAvailabilityRepository) DeleteByBooking(ctx context.Context, itemID, bookingID uuid.UUID) (int, error) {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `DELETE FROM availability_slots WHERE rental_item_id = $1 AND booking_id = $2`,
+		itemID, bookingID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *PostgresAvailabilityRepository) CheckConflict(ctx context.Context, itemID uuid.UUID, startDate, endDate time.Time, excludeSlotID *uuid.UUID) (bool, error) {
+	// Any slot that overlaps and is not 'available', as the exclusion
+	// constraint defines it
+	var conflict bool
+	err := r.db.Conn(ctx).QueryRow(ctx, `SELECT EXISTS (
+			SELECT 1 FROM availability_slots
+			WHERE rental_item_id = $1 AND status <> $2
+				AND start_date < $4 AND end_date > $3
+				AND ($5::uuid IS NULL OR id <> $5)
+		)`,
+		itemID, domain.StatusAvailable, startDate, endDate, excludeSlotID,
+	).Scan(&conflict)
+	return conflict, err
+}
+
+// scanSlot reads a row of slotColumns
+func scanSlot(row pgx.Row) (*domain.AvailabilitySlot, error) {
+	var slot domain.AvailabilitySlot
+	err := row.Scan(
+		&slot.ID, &slot.RentalItemID, &slot.StartDate, &slot.EndDate, &slot.Status, &slot.BookingID, &slot.CreatedAt, &slot.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+// maintenanceColumns lists the columns of the maintenance_logs table in
+// scanMaintenanceLog order
+const maintenanceColumns = `id, rental_item_id, maintenance_type, description, start_date, end_date, cost, status, created_at`
+
+// PostgresMaintenanceRepository implements MaintenanceRepository
+type PostgresMaintenanceRepository struct {
+	db *database.Postgres
+}
+
+func NewPostgresMaintenanceRepository(db *database.Postgres) *PostgresMaintenanceRepository {
+	return &PostgresMaintenanceRepository{db: db}
+}
+
+func (r *PostgresMaintenanceRepository) Create(ctx context.Context, log *domain.MaintenanceLog) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO maintenance_logs (`+maintenanceColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		log.ID, log.RentalItemID, log.MaintenanceType, log.Description, log.StartDate, log.EndDate, log.Cost, log.Status, log.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresMaintenanceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.MaintenanceLog, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+maintenanceColumns+` FROM maintenance_logs WHERE id = $1`, id)
+	log, err := scanMaintenanceLog(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrMaintenanceNotFound
+		}
+		return nil, err
+	}
+	return log, nil
+}
+
+func (r *PostgresMaintenanceRepository) GetByItem(ctx context.Context, itemID uuid.UUID, offset, limit int) ([]*domain.MaintenanceLog, int, error) {
+	conn := r.db.Conn(ctx)
+
+	var total int
+	if err := conn.QueryRow(ctx, `SELECT count(*) FROM maintenance_logs WHERE rental_item_id = $1`, itemID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := conn.Query(ctx, `SELECT `+maintenanceColumns+` FROM maintenance_logs
+		WHERE rental_item_id = $1 ORDER BY created_at DESC OFFSET $2 LIMIT $3`,
+		itemID, offset, limit,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	logs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.MaintenanceLog, error) {
+		return scanMaintenanceLog(row)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+func (r *PostgresMaintenanceRepository) Update(ctx context.Context, log *domain.MaintenanceLog) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE maintenance_logs SET status = $2, end_date = $3 WHERE id = $1`,
+		log.ID, log.Status, log.EndDate,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrMaintenanceNotFound
+	}
+	return nil
+}
+
+func (r *PostgresMaintenanceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `DELETE FROM maintenance_logs WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrMaintenanceNotFound
+	}
+	return nil
+}
+
+// scanMaintenanceLog reads a row of maintenanceColumns
+func scanMaintenanceLog(row pgx.Row) (*domain.MaintenanceLog, error) {
+	var log domain.MaintenanceLog
+	err := row.Scan(
+		&log.ID, &log.RentalItemID, &log.MaintenanceType, &log.Description, &log.StartDate, &log.EndDate, &log.Cost, &log.Status, &log.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rentalflow/inventory-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
 )
 
 // ItemRepository defines the interface for rental item data access
@@ -15,8 +16,12 @@ type ItemRepository interface {
 	GetByOwner(ctx context.Context, ownerID uuid.UUID, offset, limit int) ([]*domain.RentalItem, int, error)
 	List(ctx context.Context, offset, limit int, filters ItemFilters) ([]*domain.RentalItem, int, error)
 	Search(ctx context.Context, query string, filters ItemFilters, offset, limit int) ([]*domain.RentalItem, int, error)
+	// Update saves the item if it is still at item.Version, and returns
+	// domain.ErrVersionConflict if another update came first
 	Update(ctx context.Context, item *domain.RentalItem) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	ListAllByOwner(ctx context.Context, ownerID uuid.UUID) ([]*domain.RentalItem, error)
+	AnonymizeByOwner(ctx context.Context, ownerID uuid.UUID) (int, error)
 }
 
 // ItemFilters defines filters for listing items
@@ -31,11 +36,16 @@ type ItemFilters struct {
 
 // AvailabilityRepository defines the interface for availability slot data access
 type AvailabilityRepository interface {
+	// Create saves the slot, and returns domain.ErrDateConflict if it holds
+	// the item on dates another slot already holds it
 	Create(ctx context.Context, slot *domain.AvailabilitySlot) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.AvailabilitySlot, error)
 	GetByItem(ctx context.Context, itemID uuid.UUID, startDate, endDate time.Time) ([]*domain.AvailabilitySlot, error)
+	// Update saves the slot if it is still at slot.Version, and returns
+	// domain.ErrSlotConflict if another update came first
 	Update(ctx context.Context, slot *domain.AvailabilitySlot) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteByBooking(ctx context.Context, itemID, bookingID uuid.UUID) (int, error)
 	CheckConflict(ctx context.Context, itemID uuid.UUID, startDate, endDate time.Time, excludeSlotID *uuid.UUID) (bool, error)
 }
 
@@ -47,3 +57,36 @@ type MaintenanceRepository interface {
 	Update(ctx context.Context, log *domain.MaintenanceLog) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
+
+// NewItemRepository returns the ItemRepository of the database
+func NewItemRepository(db *database.Backend) ItemRepository {
+	if db.Memory {
+		return NewMemoryItemRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresItemRepository(db.Postgres)
+	}
+	return NewMongoItemRepository(db.Mongo.DB)
+}
+
+// NewAvailabilityRepository returns the AvailabilityRepository of the database
+func NewAvailabilityRepository(db *database.Backend) AvailabilityRepository {
+	if db.Memory {
+		return NewMemoryAvailabilityRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresAvailabilityRepository(db.Postgres)
+	}
+	return NewMongoAvailabilityRepository(db.Mongo.DB)
+}
+
+// NewMaintenanceRepository returns the MaintenanceRepository of the database
+func NewMaintenanceRepository(db *database.Backend) MaintenanceRepository {
+	if db.Memory {
+		return NewMemoryMaintenanceRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresMaintenanceRepository(db.Postgres)
+	}
+	return NewMongoMaintenanceRepository(db.Mongo.DB)
+}
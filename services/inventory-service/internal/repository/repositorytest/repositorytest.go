@@ -0,0 +1,517 @@
+// Package repositorytest is the conformance suite of the inventory service's
+// repositories. Every implementation runs it, so the service behaves the
+// same whichever database it stores its data in.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/inventory-service/internal/domain"
+	"github.com/rentalflow/inventory-service/internal/repository"
+)
+
+// ItemRepository runs the conformance tests of ItemRepository against the
+// repositories newRepo opens, an empty one for each test
+func ItemRepository(t *testing.T, newRepo func(t *testing.T) repository.ItemRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		item := newItem(uuid.New(), "Toyota Vitz", 1500, time.Now())
+		item.Specifications["transmission"] = "automatic"
+		item.Images = []string{"https://cdn.example.com/vitz.jpg"}
+		mustCreateItem(t, repo, item)
+
+		got, err := repo.GetByID(ctx, item.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.OwnerID != item.OwnerID || got.Title != item.Title || got.Category != item.Category ||
+			got.DailyRate != item.DailyRate || got.City != item.City || got.Latitude != item.Latitude ||
+			!got.IsActive || got.Version != 1 {
+			t.Errorf("GetByID = %+v, want %+v", got, item)
+		}
+		if got.Specifications["transmission"] != "automatic" || len(got.Images) != 1 || got.Images[0] != item.Images[0] {
+			t.Errorf("specifications %v and images %v did not round-trip", got.Specifications, got.Images)
+		}
+		sameTime(t, "created_at", got.CreatedAt, item.CreatedAt)
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.GetByID(ctx, uuid.New()); err != domain.ErrItemNotFound {
+			t.Errorf("GetByID = %v, want ErrItemNotFound", err)
+		}
+	})
+
+	t.Run("GetByOwner", func(t *testing.T) {
+		repo := newRepo(t)
+		owner := uuid.New()
+		start := time.Now().Add(-time.Hour)
+		older := newItem(owner, "Generator", 300, start)
+		newer := newItem(owner, "Tent", 200, start.Add(time.Minute))
+		mustCreateItem(t, repo, older)
+		mustCreateItem(t, repo, newer)
+		mustCreateItem(t, repo, newItem(uuid.New(), "Drill", 100, start))
+
+		items, total, err := repo.GetByOwner(ctx, owner, 0, 1)
+		if err != nil {
+			t.Fatalf("GetByOwner: %v", err)
+		}
+		if total != 2 || len(items) != 1 || items[0].ID != newer.ID {
+			t.Errorf("GetByOwner = %v (total %d), want the newest of 2", itemIDs(items), total)
+		}
+	})
+
+	t.Run("ListFilters", func(t *testing.T) {
+		repo := newRepo(t)
+		start := time.Now().Add(-time.Hour)
+		cheap := newItem(uuid.New(), "Bicycle", 100, start)
+		mid := newItem(uuid.New(), "Scooter", 500, start.Add(time.Minute))
+		dear := newItem(uuid.New(), "Pickup truck", 3000, start.Add(2*time.Minute))
+		dear.City = "Adama"
+		hidden := newItem(uuid.New(), "Motorbike", 800, start.Add(3*time.Minute))
+		hidden.IsActive = false
+		house := newItem(uuid.New(), "Villa", 5000, start.Add(4*time.Minute))
+		house.Category = domain.CategoryProperty
+		for _, item := range []*domain.RentalItem{cheap, mid, dear, hidden, house} {
+			mustCreateItem(t, repo, item)
+		}
+
+		active, vehicle, city := true, domain.CategoryVehicle, "Addis Ababa"
+		minPrice, maxPrice := 100.0, 3000.0
+		priceLow, priceHigh := "price_low", "price_high"
+
+		tests := []struct {
+			name    string
+			filters repository.ItemFilters
+			want    []*domain.RentalItem
+		}{
+			{"Newest", repository.ItemFilters{}, []*domain.RentalItem{house, hidden, dear, mid, cheap}},
+			{"Active", repository.ItemFilters{IsActive: &active}, []*domain.RentalItem{house, dear, mid, cheap}},
+			{"Category", repository.ItemFilters{Category: &vehicle, IsActive: &active}, []*domain.RentalItem{dear, mid, cheap}},
+			{"City", repository.ItemFilters{City: &city, Category: &vehicle}, []*domain.RentalItem{hidden, mid, cheap}},
+			{"PriceRange", repository.ItemFilters{MinPrice: &minPrice, MaxPrice: &maxPrice, SortBy: &priceLow}, []*domain.RentalItem{cheap, mid, hidden, dear}},
+			{"PriceHigh", repository.ItemFilters{IsActive: &active, SortBy: &priceHigh}, []*domain.RentalItem{house, dear, mid, cheap}},
+		}
+		for _, tt := range tests {
+			items, total, err := repo.List(ctx, 0, 10, tt.filters)
+			if err != nil {
+				t.Fatalf("%s: List: %v", tt.name, err)
+			}
+			if total != len(tt.want) || !sameItems(items, tt.want) {
+				t.Errorf("%s: List = %v (total %d), want %v", tt.name, itemIDs(items), total, itemIDs(tt.want))
+			}
+		}
+
+		items, total, err := repo.List(ctx, 1, 2, repository.ItemFilters{})
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if total != 5 || !sameItems(items, []*domain.RentalItem{hidden, dear}) {
+			t.Errorf("List second and third = %v (total %d), want %v", itemIDs(items), total, itemIDs([]*domain.RentalItem{hidden, dear}))
+		}
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		repo := newRepo(t)
+		start := time.Now().Add(-time.Hour)
+		byTitle := newItem(uuid.New(), "Canon camera", 400, start)
+		byDescription := newItem(uuid.New(), "Lens kit", 300, start.Add(time.Minute))
+		byDescription.Description = "Fits any CAMERA body"
+		other := newItem(uuid.New(), "Tripod", 100, start.Add(2*time.Minute))
+		for _, item := range []*domain.RentalItem{byTitle, byDescription, other} {
+			mustCreateItem(t, repo, item)
+		}
+
+		items, total, err := repo.Search(ctx, "camera", repository.ItemFilters{}, 0, 10)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if total != 2 || !sameItems(items, []*domain.RentalItem{byDescription, byTitle}) {
+			t.Errorf("Search = %v (total %d), want the two camera items, newest first", itemIDs(items), total)
+		}
+
+		maxPrice := 350.0
+		items, total, err = repo.Search(ctx, "camera", repository.ItemFilters{MaxPrice: &maxPrice}, 0, 10)
+		if err != nil {
+			t.Fatalf("Search: %v", err)
+		}
+		if total != 1 || !sameItems(items, []*domain.RentalItem{byDescription}) {
+			t.Errorf("Search under %.0f = %v (total %d), want the lens kit", maxPrice, itemIDs(items), total)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		item := newItem(uuid.New(), "Camping chair", 50, time.Now())
+		mustCreateItem(t, repo, item)
+
+		stale := *item
+		item.Title = "Folding camping chair"
+		item.DailyRate = 60
+		item.Images = []string{"a.jpg", "b.jpg"}
+		if err := repo.Update(ctx, item); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if item.Version != 2 {
+			t.Errorf("Version after Update = %d, want 2", item.Version)
+		}
+		got, err := repo.GetByID(ctx, item.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Title != item.Title || got.DailyRate != 60 || len(got.Images) != 2 || got.Version != 2 {
+			t.Errorf("GetByID after Update = %+v", got)
+		}
+
+		if err := repo.Update(ctx, &stale); err != domain.ErrVersionConflict {
+			t.Errorf("Update of a stale version = %v, want ErrVersionConflict", err)
+		}
+		missing := newItem(uuid.New(), "Missing", 1, time.Now())
+		if err := repo.Update(ctx, missing); err != domain.ErrItemNotFound {
+			t.Errorf("Update of a missing item = %v, want ErrItemNotFound", err)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		item := newItem(uuid.New(), "Kayak", 700, time.Now())
+		mustCreateItem(t, repo, item)
+
+		if err := repo.Delete(ctx, item.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repo.GetByID(ctx, item.ID); err != domain.ErrItemNotFound {
+			t.Errorf("GetByID after Delete = %v, want ErrItemNotFound", err)
+		}
+		if err := repo.Delete(ctx, item.ID); err != domain.ErrItemNotFound {
+			t.Errorf("second Delete = %v, want ErrItemNotFound", err)
+		}
+	})
+
+	t.Run("AnonymizeByOwner", func(t *testing.T) {
+		repo := newRepo(t)
+		owner := uuid.New()
+		start := time.Now().Add(-time.Hour)
+		first := newItem(owner, "Projector", 250, start)
+		second := newItem(owner, "Speaker", 150, start.Add(time.Minute))
+		second.IsActive = false
+		other := newItem(uuid.New(), "Screen", 100, start)
+		for _, item := range []*domain.RentalItem{first, second, other} {
+			mustCreateItem(t, repo, item)
+		}
+
+		items, err := repo.ListAllByOwner(ctx, owner)
+		if err != nil {
+			t.Fatalf("ListAllByOwner: %v", err)
+		}
+		if !sameItems(items, []*domain.RentalItem{first, second}) {
+			t.Errorf("ListAllByOwner = %v, want both items, oldest first", itemIDs(items))
+		}
+
+		n, err := repo.AnonymizeByOwner(ctx, owner)
+		if err != nil {
+			t.Fatalf("AnonymizeByOwner: %v", err)
+		}
+		if n != 2 {
+			t.Errorf("AnonymizeByOwner = %d, want 2", n)
+		}
+		got, err := repo.GetByID(ctx, first.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Address != "" || got.Latitude != 0 || got.Longitude != 0 || got.IsActive || got.Version != 2 {
+			t.Errorf("anonymized item = %+v", got)
+		}
+		if got, err := repo.GetByID(ctx, other.ID); err != nil || got.Address == "" {
+			t.Errorf("another owner's item was anonymized: %+v, %v", got, err)
+		}
+	})
+}
+
+// AvailabilityRepository runs the conformance tests of
+// AvailabilityRepository against the repositories newRepo opens, an empty
+// one for each test
+func AvailabilityRepository(t *testing.T, newRepo func(t *testing.T) repository.AvailabilityRepository) {
+	ctx := context.Background()
+	day := func(n int) time.Time {
+		return time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, n)
+	}
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		bookingID := uuid.New()
+		slot := domain.NewAvailabilitySlot(uuid.New(), day(0), day(3), domain.StatusBooked)
+		slot.BookingID = &bookingID
+		mustCreateSlot(t, repo, slot)
+
+		got, err := repo.GetByID(ctx, slot.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.RentalItemID != slot.RentalItemID || got.Status != domain.StatusBooked ||
+			got.BookingID == nil || *got.BookingID != bookingID || got.Version != 1 {
+			t.Errorf("GetByID = %+v, want %+v", got, slot)
+		}
+		sameTime(t, "start_date", got.StartDate, slot.StartDate)
+		sameTime(t, "end_date", got.EndDate, slot.EndDate)
+
+		if _, err := repo.GetByID(ctx, uuid.New()); err != domain.ErrSlotNotFound {
+			t.Errorf("GetByID of a missing slot = %v, want ErrSlotNotFound", err)
+		}
+	})
+
+	t.Run("Overlap", func(t *testing.T) {
+		repo := newRepo(t)
+		item := uuid.New()
+		mustCreateSlot(t, repo, domain.NewAvailabilitySlot(item, day(0), day(3), domain.StatusBooked))
+
+		overlapping := domain.NewAvailabilitySlot(item, day(2), day(5), domain.StatusBlocked)
+		if err := repo.Create(ctx, overlapping); err != domain.ErrDateConflict {
+			t.Errorf("Create of an overlapping slot = %v, want ErrDateConflict", err)
+		}
+		if _, err := repo.GetByID(ctx, overlapping.ID); err != domain.ErrSlotNotFound {
+			t.Errorf("the overlapping slot was stored: %v", err)
+		}
+
+		// Back-to-back slots, free slots and other items do not conflict
+		mustCreateSlot(t, repo, domain.NewAvailabilitySlot(item, day(3), day(5), domain.StatusMaintenance))
+		mustCreateSlot(t, repo, domain.NewAvailabilitySlot(item, day(1), day(4), domain.StatusAvailable))
+		mustCreateSlot(t, repo, domain.NewAvailabilitySlot(uuid.New(), day(0), day(3), domain.StatusBooked))
+	})
+
+	t.Run("CheckConflict", func(t *testing.T) {
+		repo := newRepo(t)
+		item := uuid.New()
+		booked := domain.NewAvailabilitySlot(item, day(10), day(12), domain.StatusBooked)
+		mustCreateSlot(t, repo, booked)
+		mustCreateSlot(t, repo, domain.NewAvailabilitySlot(item, day(0), day(10), domain.StatusAvailable))
+
+		tests := []struct {
+			name       string
+			start, end time.Time
+			exclude    *uuid.UUID
+			want       bool
+		}{
+			{"Inside", day(10), day(11), nil, true},
+			{"Around", day(9), day(13), nil, true},
+			{"Before", day(8), day(10), nil, false},
+			{"After", day(12), day(14), nil, false},
+			{"Excluded", day(10), day(11), &booked.ID, false},
+		}
+		for _, tt := range tests {
+			got, err := repo.CheckConflict(ctx, item, tt.start, tt.end, tt.exclude)
+			if err != nil {
+				t.Fatalf("%s: CheckConflict: %v", tt.name, err)
+			}
+			if got != tt.want {
+				t.Errorf("%s: CheckConflict = %v, want %v", tt.name, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("GetByItem", func(t *testing.T) {
+		repo := newRepo(t)
+		item := uuid.New()
+		later := domain.NewAvailabilitySlot(item, day(5), day(7), domain.StatusBooked)
+		earlier := domain.NewAvailabilitySlot(item, day(1), day(3), domain.StatusBlocked)
+		mustCreateSlot(t, repo, later)
+		mustCreateSlot(t, repo, earlier)
+		mustCreateSlot(t, repo, domain.NewAvailabilitySlot(item, day(9), day(12), domain.StatusBooked))
+		mustCreateSlot(t, repo, domain.NewAvailabilitySlot(uuid.New(), day(1), day(3), domain.StatusBooked))
+
+		slots, err := repo.GetByItem(ctx, item, day(0), day(10))
+		if err != nil {
+			t.Fatalf("GetByItem: %v", err)
+		}
+		if len(slots) != 2 || slots[0].ID != earlier.ID || slots[1].ID != later.ID {
+			t.Errorf("GetByItem = %d slots, want the two within the range, by start date", len(slots))
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		slot := domain.NewAvailabilitySlot(uuid.New(), day(0), day(2), domain.StatusAvailable)
+		mustCreateSlot(t, repo, slot)
+
+		stale := *slot
+		bookingID := uuid.New()
+		slot.Status = domain.StatusBooked
+		slot.BookingID = &bookingID
+		if err := repo.Update(ctx, slot); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := repo.GetByID(ctx, slot.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != domain.StatusBooked || got.BookingID == nil || *got.BookingID != bookingID || got.Version != 2 {
+			t.Errorf("GetByID after Update = %+v", got)
+		}
+
+		if err := repo.Update(ctx, &stale); err != domain.ErrSlotConflict {
+			t.Errorf("Update of a stale version = %v, want ErrSlotConflict", err)
+		}
+		missing := domain.NewAvailabilitySlot(uuid.New(), day(0), day(2), domain.StatusBooked)
+		if err := repo.Update(ctx, missing); err != domain.ErrSlotNotFound {
+			t.Errorf("Update of a missing slot = %v, want ErrSlotNotFound", err)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		item, bookingID := uuid.New(), uuid.New()
+		for _, n := range []int{0, 5} {
+			slot := domain.NewAvailabilitySlot(item, day(n), day(n+2), domain.StatusBooked)
+			slot.BookingID = &bookingID
+			mustCreateSlot(t, repo, slot)
+		}
+		free := domain.NewAvailabilitySlot(item, day(10), day(12), domain.StatusAvailable)
+		mustCreateSlot(t, repo, free)
+
+		released, err := repo.DeleteByBooking(ctx, item, bookingID)
+		if err != nil {
+			t.Fatalf("DeleteByBooking: %v", err)
+		}
+		if released != 2 {
+			t.Errorf("DeleteByBooking = %d, want 2", released)
+		}
+		if released, err := repo.DeleteByBooking(ctx, item, bookingID); err != nil || released != 0 {
+			t.Errorf("second DeleteByBooking = %d, %v, want 0", released, err)
+		}
+
+		if err := repo.Delete(ctx, free.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if err := repo.Delete(ctx, free.ID); err != domain.ErrSlotNotFound {
+			t.Errorf("second Delete = %v, want ErrSlotNotFound", err)
+		}
+	})
+}
+
+// MaintenanceRepository runs the conformance tests of MaintenanceRepository
+// against the repositories newRepo opens, an empty one for each test
+func MaintenanceRepository(t *testing.T, newRepo func(t *testing.T) repository.MaintenanceRepository) {
+	ctx := context.Background()
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		repo := newRepo(t)
+		item := uuid.New()
+		start := time.Now().Add(-time.Hour)
+		older := domain.NewMaintenanceLog(item, "service", "Oil change", start, 1200)
+		older.CreatedAt = start
+		newer := domain.NewMaintenanceLog(item, "repair", "New tyres", start, 8000)
+		for _, log := range []*domain.MaintenanceLog{older, newer, domain.NewMaintenanceLog(uuid.New(), "service", "Wash", start, 100)} {
+			if err := repo.Create(ctx, log); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+		}
+
+		got, err := repo.GetByID(ctx, older.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.RentalItemID != item || got.MaintenanceType != "service" || got.Description != "Oil change" ||
+			got.Cost != 1200 || got.Status != domain.MaintenanceScheduled || got.EndDate != nil {
+			t.Errorf("GetByID = %+v, want %+v", got, older)
+		}
+
+		logs, total, err := repo.GetByItem(ctx, item, 0, 1)
+		if err != nil {
+			t.Fatalf("GetByItem: %v", err)
+		}
+		if total != 2 || len(logs) != 1 || logs[0].ID != newer.ID {
+			t.Errorf("GetByItem = %d logs (total %d), want the newest of 2", len(logs), total)
+		}
+
+		end := time.Now()
+		older.Status = domain.MaintenanceCompleted
+		older.EndDate = &end
+		if err := repo.Update(ctx, older); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err = repo.GetByID(ctx, older.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != domain.MaintenanceCompleted || got.EndDate == nil {
+			t.Fatalf("GetByID after Update = %+v", got)
+		}
+		sameTime(t, "end_date", *got.EndDate, end)
+
+		if err := repo.Delete(ctx, older.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repo.GetByID(ctx, older.ID); err != domain.ErrMaintenanceNotFound {
+			t.Errorf("GetByID after Delete = %v, want ErrMaintenanceNotFound", err)
+		}
+		if err := repo.Update(ctx, older); err != domain.ErrMaintenanceNotFound {
+			t.Errorf("Update of a deleted log = %v, want ErrMaintenanceNotFound", err)
+		}
+		if err := repo.Delete(ctx, older.ID); err != domain.ErrMaintenanceNotFound {
+			t.Errorf("second Delete = %v, want ErrMaintenanceNotFound", err)
+		}
+	})
+}
+
+// newItem returns an active vehicle in Addis Ababa created at createdAt
+func newItem(ownerID uuid.UUID, title string, dailyRate float64, createdAt time.Time) *domain.RentalItem {
+	item := domain.NewRentalItem(ownerID, title, title+" for rent", domain.CategoryVehicle, "")
+	item.DailyRate = dailyRate
+	item.WeeklyRate = dailyRate * 6
+	item.Address = "Bole Road"
+	item.City = "Addis Ababa"
+	item.Latitude = 9.0
+	item.Longitude = 38.75
+	item.CreatedAt = createdAt
+	item.UpdatedAt = createdAt
+	return item
+}
+
+func mustCreateItem(t *testing.T, repo repository.ItemRepository, item *domain.RentalItem) {
+	t.Helper()
+	if err := repo.Create(context.Background(), item); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
+
+func mustCreateSlot(t *testing.T, repo repository.AvailabilityRepository, slot *domain.AvailabilitySlot) {
+	t.Helper()
+	if err := repo.Create(context.Background(), slot); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
+
+// sameTime fails the test unless got is want at the millisecond precision
+// every database keeps
+func sameTime(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if diff := got.Sub(want); diff > time.Millisecond || diff < -time.Millisecond {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+// sameItems reports whether got holds the want items in order
+func sameItems(got, want []*domain.RentalItem) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i].ID != want[i].ID {
+			return false
+		}
+	}
+	return true
+}
+
+func itemIDs(items []*domain.RentalItem) []uuid.UUID {
+	ids := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
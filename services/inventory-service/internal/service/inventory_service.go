@@ -5,15 +5,26 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rentalflow/inventory-service/internal/clients"
 	"github.com/rentalflow/inventory-service/internal/domain"
 	"github.com/rentalflow/inventory-service/internal/repository"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/messaging"
 )
 
+// ItemEventsTopic is the topic item changes are published to, with keys
+// item.created, item.updated, item.deleted and item.erased
+const ItemEventsTopic = "inventory_events"
+
 // InventoryService handles inventory business logic
 type InventoryService struct {
 	itemRepo         repository.ItemRepository
 	availabilityRepo repository.AvailabilityRepository
 	maintenanceRepo  repository.MaintenanceRepository
+	tx               database.Transactor
+	authClient       *clients.AuthClient
+	publisher        messaging.Publisher
 }
 
 // NewInventoryService creates a new inventory service
@@ -21,11 +32,28 @@ func NewInventoryService(
 	itemRepo repository.ItemRepository,
 	availabilityRepo repository.AvailabilityRepository,
 	maintenanceRepo repository.MaintenanceRepository,
+	tx database.Transactor,
+	authClient *clients.AuthClient,
+	publisher messaging.Publisher,
 ) *InventoryService {
 	return &InventoryService{
 		itemRepo:         itemRepo,
 		availabilityRepo: availabilityRepo,
 		maintenanceRepo:  maintenanceRepo,
+		tx:               tx,
+		authClient:       authClient,
+		publisher:        publisher,
+	}
+}
+
+// publish announces an item change so caches of the catalog can be dropped.
+// A failure is logged; the cached catalog then expires with its TTL.
+func (s *InventoryService) publish(ctx context.Context, key string, body interface{}) {
+	if s.publisher == nil {
+		return
+	}
+	if err := s.publisher.Publish(ctx, ItemEventsTopic, key, body); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("key", key).Msg("Failed to publish item event")
 	}
 }
 
@@ -38,6 +66,10 @@ func (s *InventoryService) CreateItem(ctx context.Context, ownerID uuid.UUID, ti
 		return nil, domain.ErrInvalidCategory
 	}
 
+	if err := s.ensureOwnerCanList(ctx, ownerID); err != nil {
+		return nil, err
+	}
+
 	item := domain.NewRentalItem(ownerID, title, description, category, subcategory)
 	item.DailyRate = dailyRate
 	item.WeeklyRate = weeklyRate
@@ -54,9 +86,35 @@ func (s *InventoryService) CreateItem(ctx context.Context, ownerID uuid.UUID, ti
 		return nil, err
 	}
 
+	s.publish(ctx, "item.created", item)
+
 	return item, nil
 }
 
+// ensureOwnerCanList requires a verified identity before an owner's first listing.
+func (s *InventoryService) ensureOwnerCanList(ctx context.Context, ownerID uuid.UUID) error {
+	if s.authClient == nil {
+		return nil
+	}
+
+	_, total, err := s.itemRepo.GetByOwner(ctx, ownerID, 0, 1)
+	if err != nil {
+		return err
+	}
+	if total > 0 {
+		return nil
+	}
+
+	status, err := s.authClient.GetUserStatus(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+	if !status.IdentityVerified {
+		return domain.ErrIdentityNotVerified
+	}
+	return nil
+}
+
 // GetItem retrieves an item by ID
 func (s *InventoryService) GetItem(ctx context.Context, itemID uuid.UUID) (*domain.RentalItem, error) {
 	return s.itemRepo.GetByID(ctx, itemID)
@@ -98,36 +156,53 @@ func (s *InventoryService) GetOwnerItems(ctx context.Context, ownerID uuid.UUID,
 	return s.itemRepo.GetByOwner(ctx, ownerID, offset, pageSize)
 }
 
-// UpdateItem updates an existing item
-func (s *InventoryService) UpdateItem(ctx context.Context, itemID, ownerID uuid.UUID, updates map[string]interface{}) (*domain.RentalItem, error) {
-	item, err := s.itemRepo.GetByID(ctx, itemID)
-	if err != nil {
-		return nil, err
-	}
+// maxUpdateAttempts bounds how often an update that lost a race with
+// another update of the same item is tried again
+const maxUpdateAttempts = 3
 
-	if item.OwnerID != ownerID {
-		return nil, domain.ErrUnauthorized
-	}
+// UpdateItem updates an existing item. expectedVersion is the version the
+// owner last saw, as sent in If-Match, or 0 to apply the changes to
+// whatever version is current.
+func (s *InventoryService) UpdateItem(ctx context.Context, itemID, ownerID uuid.UUID, updates map[string]interface{}, expectedVersion int64) (*domain.RentalItem, error) {
+	for attempt := 1; ; attempt++ {
+		item, err := s.itemRepo.GetByID(ctx, itemID)
+		if err != nil {
+			return nil, err
+		}
 
-	// Apply updates
-	if v, ok := updates["title"].(string); ok {
-		item.Title = v
-	}
-	if v, ok := updates["description"].(string); ok {
-		item.Description = v
-	}
-	if v, ok := updates["daily_rate"].(float64); ok {
-		item.DailyRate = v
-	}
-	if v, ok := updates["is_active"].(bool); ok {
-		item.IsActive = v
-	}
+		if item.OwnerID != ownerID {
+			return nil, domain.ErrUnauthorized
+		}
+		if expectedVersion != 0 && item.Version != expectedVersion {
+			return nil, domain.ErrVersionConflict
+		}
 
-	if err := s.itemRepo.Update(ctx, item); err != nil {
-		return nil, err
-	}
+		// Apply updates
+		if v, ok := updates["title"].(string); ok {
+			item.Title = v
+		}
+		if v, ok := updates["description"].(string); ok {
+			item.Description = v
+		}
+		if v, ok := updates["daily_rate"].(float64); ok {
+			item.DailyRate = v
+		}
+		if v, ok := updates["is_active"].(bool); ok {
+			item.IsActive = v
+		}
 
-	return item, nil
+		err = s.itemRepo.Update(ctx, item)
+		if err == domain.ErrVersionConflict && expectedVersion == 0 && attempt < maxUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		s.publish(ctx, "item.updated", item)
+
+		return item, nil
+	}
 }
 
 // DeleteItem deletes an item
@@ -141,30 +216,102 @@ func (s *InventoryService) DeleteItem(ctx context.Context, itemID, ownerID uuid.
 		return domain.ErrUnauthorized
 	}
 
-	return s.itemRepo.Delete(ctx, itemID)
+	if err := s.itemRepo.Delete(ctx, itemID); err != nil {
+		return err
+	}
+
+	s.publish(ctx, "item.deleted", item)
+
+	return nil
 }
 
-// BlockDates blocks dates for booking
-func (s *InventoryService) BlockDates(ctx context.Context, itemID uuid.UUID, startDate, endDate time.Time, bookingID uuid.UUID) (*domain.AvailabilitySlot, error) {
-	// Check for conflicts
-	hasConflict, err := s.availabilityRepo.CheckConflict(ctx, itemID, startDate, endDate, nil)
+// ExportUserData returns the items a user lists
+func (s *InventoryService) ExportUserData(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+	items, err := s.itemRepo.ListAllByOwner(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
-	if hasConflict {
-		return nil, domain.ErrDateConflict
+	return map[string]interface{}{"items": items}, nil
+}
+
+// EraseUserData unlists a deleted user's items and strips their location.
+// The items are updated together, so a failure leaves none of them changed.
+func (s *InventoryService) EraseUserData(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+	var items int
+	err := s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		items, err = s.itemRepo.AnonymizeByOwner(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if items > 0 {
+		s.publish(ctx, "item.erased", map[string]string{"owner_id": userID.String()})
+	}
+	return map[string]interface{}{"items_unlisted": items}, nil
+}
+
+// BlockDates blocks dates for booking. The item check, the conflict check
+// and the new slot run in one transaction.
+func (s *InventoryService) BlockDates(ctx context.Context, itemID uuid.UUID, startDate, endDate time.Time, bookingID uuid.UUID) (*domain.AvailabilitySlot, error) {
+	if !endDate.After(startDate) {
+		return nil, domain.ErrInvalidDateRange
 	}
 
 	slot := domain.NewAvailabilitySlot(itemID, startDate, endDate, domain.StatusBooked)
 	slot.BookingID = &bookingID
 
-	if err := s.availabilityRepo.Create(ctx, slot); err != nil {
+	err := s.tx.WithTransaction(ctx, func(ctx context.Context) error {
+		item, err := s.itemRepo.GetByID(ctx, itemID)
+		if err != nil {
+			return err
+		}
+		if !item.IsActive {
+			return domain.ErrItemNotFound
+		}
+
+		// Check for conflicts
+		hasConflict, err := s.availabilityRepo.CheckConflict(ctx, itemID, startDate, endDate, nil)
+		if err != nil {
+			return err
+		}
+		if hasConflict {
+			return domain.ErrDateConflict
+		}
+
+		return s.availabilityRepo.Create(ctx, slot)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return slot, nil
 }
 
+// UnblockDates releases the slots held for a booking
+func (s *InventoryService) UnblockDates(ctx context.Context, itemID, bookingID uuid.UUID) error {
+	released, err := s.availabilityRepo.DeleteByBooking(ctx, itemID, bookingID)
+	if err != nil {
+		return err
+	}
+	if released == 0 {
+		return domain.ErrSlotNotFound
+	}
+	return nil
+}
+
+// GetAvailability lists the slots of an item within a date range
+func (s *InventoryService) GetAvailability(ctx context.Context, itemID uuid.UUID, startDate, endDate time.Time) ([]*domain.AvailabilitySlot, error) {
+	if !endDate.After(startDate) {
+		return nil, domain.ErrInvalidDateRange
+	}
+	if _, err := s.itemRepo.GetByID(ctx, itemID); err != nil {
+		return nil, err
+	}
+	return s.availabilityRepo.GetByItem(ctx, itemID, startDate, endDate)
+}
+
 // CreateMaintenanceLog creates a maintenance log
 func (s *InventoryService) CreateMaintenanceLog(ctx context.Context, itemID, ownerID uuid.UUID, maintenanceType, description string, startDate time.Time, cost float64) (*domain.MaintenanceLog, error) {
 	// Verify owner
@@ -184,3 +331,23 @@ func (s *InventoryService) CreateMaintenanceLog(ctx context.Context, itemID, own
 
 	return log, nil
 }
+
+// GetMaintenanceLogs lists the maintenance history of an owner's item
+func (s *InventoryService) GetMaintenanceLogs(ctx context.Context, itemID, ownerID uuid.UUID, page, pageSize int) ([]*domain.MaintenanceLog, int, error) {
+	item, err := s.itemRepo.GetByID(ctx, itemID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if item.OwnerID != ownerID {
+		return nil, 0, domain.ErrUnauthorized
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	offset := (page - 1) * pageSize
+	return s.maintenanceRepo.GetByItem(ctx, itemID, offset, pageSize)
+}
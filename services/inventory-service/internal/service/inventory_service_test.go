@@ -0,0 +1,309 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/inventory-service/internal/clients"
+	"github.com/rentalflow/inventory-service/internal/domain"
+	"github.com/rentalflow/inventory-service/internal/repository"
+	"github.com/rentalflow/inventory-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/rentalflow/pkg/messaging"
+)
+
+var (
+	owner    = uuid.New()
+	stranger = uuid.New()
+)
+
+// newService returns an inventory service on the memory repositories and an
+// in-process bus, without auth-service
+func newService(t *testing.T) (*service.InventoryService, *messaging.Bus) {
+	t.Helper()
+
+	bus := messaging.NewBus()
+	t.Cleanup(bus.Close)
+
+	svc := service.NewInventoryService(
+		repository.NewMemoryItemRepository(),
+		repository.NewMemoryAvailabilityRepository(),
+		repository.NewMemoryMaintenanceRepository(),
+		&database.Backend{Memory: true},
+		nil,
+		bus,
+	)
+	return svc, bus
+}
+
+func createItem(t *testing.T, svc *service.InventoryService) *domain.RentalItem {
+	t.Helper()
+
+	item, err := svc.CreateItem(context.Background(), owner, "Tent", "Four-person tent",
+		domain.CategoryEquipment, "camping", 20, 120, 400, 50, domain.Location{City: "Addis Ababa"}, nil, nil)
+	if err != nil {
+		t.Fatalf("CreateItem: %v", err)
+	}
+	return item
+}
+
+// authServer serves a user status the way auth-service does and returns its
+// URL. A zero code returns the URL of a server that has shut down.
+func authServer(t *testing.T, code int, status clients.UserStatus) string {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(status)
+	}))
+	if code == 0 {
+		srv.Close()
+	} else {
+		t.Cleanup(srv.Close)
+	}
+	return srv.URL
+}
+
+func TestCreateItemChecksOwner(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    int
+		status  clients.UserStatus
+		wantErr error
+		refused bool
+	}{
+		{"IdentityVerified", http.StatusOK, clients.UserStatus{IdentityVerified: true}, nil, false},
+		{"IdentityNotVerified", http.StatusOK, clients.UserStatus{EmailVerified: true}, domain.ErrIdentityNotVerified, true},
+		// Owners are refused while their status cannot be checked
+		{"AuthServiceError", http.StatusInternalServerError, clients.UserStatus{IdentityVerified: true}, nil, true},
+		{"AuthServiceDown", 0, clients.UserStatus{IdentityVerified: true}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := repository.NewMemoryItemRepository()
+			svc := service.NewInventoryService(
+				items,
+				repository.NewMemoryAvailabilityRepository(),
+				repository.NewMemoryMaintenanceRepository(),
+				&database.Backend{Memory: true},
+				clients.NewAuthClient(authServer(t, tt.code, tt.status)),
+				nil,
+			)
+
+			_, err := svc.CreateItem(context.Background(), owner, "Tent", "Four-person tent",
+				domain.CategoryEquipment, "camping", 20, 120, 400, 50, domain.Location{City: "Addis Ababa"}, nil, nil)
+			if tt.wantErr != nil && err != tt.wantErr {
+				t.Fatalf("CreateItem error = %v, want %v", err, tt.wantErr)
+			}
+			if refused := err != nil; refused != tt.refused {
+				t.Fatalf("CreateItem error = %v, want refused %v", err, tt.refused)
+			}
+
+			_, total, err := items.GetByOwner(context.Background(), owner, 0, 1)
+			if err != nil {
+				t.Fatalf("GetByOwner: %v", err)
+			}
+			if stored := total > 0; stored == tt.refused {
+				t.Errorf("stored %d items, want refused %v", total, tt.refused)
+			}
+		})
+	}
+}
+
+func TestUpdateItem(t *testing.T) {
+	tests := []struct {
+		name    string
+		ownerID uuid.UUID
+		updates map[string]interface{}
+		version func(item *domain.RentalItem) int64
+		wantErr error
+	}{
+		{"AnyVersion", owner, map[string]interface{}{"title": "Big tent", "daily_rate": 25.0}, anyVersion, nil},
+		{"CurrentVersion", owner, map[string]interface{}{"is_active": false}, currentVersion, nil},
+		{"StaleVersion", owner, map[string]interface{}{"title": "Big tent"}, staleVersion, domain.ErrVersionConflict},
+		{"NotOwner", stranger, map[string]interface{}{"title": "Mine now"}, anyVersion, domain.ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, bus := newService(t)
+			item := createItem(t, svc)
+			updated := watch(t, bus, "item.updated")
+
+			got, err := svc.UpdateItem(context.Background(), item.ID, tt.ownerID, tt.updates, tt.version(item))
+			if err != tt.wantErr {
+				t.Fatalf("UpdateItem error = %v, want %v", err, tt.wantErr)
+			}
+
+			stored, getErr := svc.GetItem(context.Background(), item.ID)
+			if getErr != nil {
+				t.Fatalf("GetItem: %v", getErr)
+			}
+			if tt.wantErr != nil {
+				if stored.Version != item.Version || stored.Title != item.Title {
+					t.Errorf("rejected update changed the item to version %d, title %q", stored.Version, stored.Title)
+				}
+				return
+			}
+
+			if got.Version != item.Version+1 || stored.Version != got.Version {
+				t.Errorf("version = %d, stored %d, want %d", got.Version, stored.Version, item.Version+1)
+			}
+			if title, ok := tt.updates["title"].(string); ok && stored.Title != title {
+				t.Errorf("title = %q, want %q", stored.Title, title)
+			}
+			if active, ok := tt.updates["is_active"].(bool); ok && stored.IsActive != active {
+				t.Errorf("is_active = %v, want %v", stored.IsActive, active)
+			}
+			if id := updated.next(t); id != item.ID {
+				t.Errorf("item.updated carried %s, want %s", id, item.ID)
+			}
+		})
+	}
+
+	t.Run("ConcurrentUpdates", func(t *testing.T) {
+		svc, _ := newService(t)
+		item := createItem(t, svc)
+
+		// Updates that name no version retry after losing a race, so all of
+		// them are applied
+		const n = 10
+		var wg sync.WaitGroup
+		errs := make(chan error, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := svc.UpdateItem(context.Background(), item.ID, owner, map[string]interface{}{"description": "busy"}, 0)
+				errs <- err
+			}()
+		}
+		wg.Wait()
+		close(errs)
+
+		applied := 0
+		for err := range errs {
+			switch err {
+			case nil:
+				applied++
+			case domain.ErrVersionConflict:
+				// Allowed once the retries run out
+			default:
+				t.Errorf("UpdateItem: %v", err)
+			}
+		}
+		stored, err := svc.GetItem(context.Background(), item.ID)
+		if err != nil {
+			t.Fatalf("GetItem: %v", err)
+		}
+		if stored.Version != item.Version+int64(applied) {
+			t.Errorf("version = %d after %d applied updates, want %d", stored.Version, applied, item.Version+int64(applied))
+		}
+	})
+}
+
+func TestBlockDates(t *testing.T) {
+	day := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		start   time.Time
+		end     time.Time
+		wantErr error
+	}{
+		{"Free", day.AddDate(0, 0, 10), day.AddDate(0, 0, 12), nil},
+		{"Adjacent", day.AddDate(0, 0, 3), day.AddDate(0, 0, 5), nil},
+		{"Overlapping", day.AddDate(0, 0, 2), day.AddDate(0, 0, 4), domain.ErrDateConflict},
+		{"Same", day, day.AddDate(0, 0, 3), domain.ErrDateConflict},
+		{"Empty", day.AddDate(0, 0, 10), day.AddDate(0, 0, 10), domain.ErrInvalidDateRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newService(t)
+			item := createItem(t, svc)
+			if _, err := svc.BlockDates(context.Background(), item.ID, day, day.AddDate(0, 0, 3), uuid.New()); err != nil {
+				t.Fatalf("BlockDates: %v", err)
+			}
+
+			_, err := svc.BlockDates(context.Background(), item.ID, tt.start, tt.end, uuid.New())
+			if err != tt.wantErr {
+				t.Errorf("BlockDates error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("UnlistedItem", func(t *testing.T) {
+		svc, _ := newService(t)
+		item := createItem(t, svc)
+		if _, err := svc.UpdateItem(context.Background(), item.ID, owner, map[string]interface{}{"is_active": false}, 0); err != nil {
+			t.Fatalf("UpdateItem: %v", err)
+		}
+
+		if _, err := svc.BlockDates(context.Background(), item.ID, day, day.AddDate(0, 0, 1), uuid.New()); err != domain.ErrItemNotFound {
+			t.Errorf("BlockDates error = %v, want %v", err, domain.ErrItemNotFound)
+		}
+	})
+
+	t.Run("ReleasedDates", func(t *testing.T) {
+		svc, _ := newService(t)
+		item := createItem(t, svc)
+		bookingID := uuid.New()
+		if _, err := svc.BlockDates(context.Background(), item.ID, day, day.AddDate(0, 0, 3), bookingID); err != nil {
+			t.Fatalf("BlockDates: %v", err)
+		}
+		if err := svc.UnblockDates(context.Background(), item.ID, bookingID); err != nil {
+			t.Fatalf("UnblockDates: %v", err)
+		}
+
+		if _, err := svc.BlockDates(context.Background(), item.ID, day, day.AddDate(0, 0, 3), uuid.New()); err != nil {
+			t.Errorf("BlockDates of released dates: %v", err)
+		}
+		if err := svc.UnblockDates(context.Background(), item.ID, bookingID); err != domain.ErrSlotNotFound {
+			t.Errorf("second UnblockDates error = %v, want %v", err, domain.ErrSlotNotFound)
+		}
+	})
+}
+
+func anyVersion(*domain.RentalItem) int64 { return 0 }
+
+func currentVersion(item *domain.RentalItem) int64 { return item.Version }
+
+func staleVersion(item *domain.RentalItem) int64 { return item.Version + 1 }
+
+// events receives the IDs of the items published under one key
+type events chan uuid.UUID
+
+func watch(t *testing.T, bus *messaging.Bus, key string) events {
+	t.Helper()
+
+	ch := make(events, 10)
+	err := bus.Subscribe(service.ItemEventsTopic, key, "", func(ctx context.Context, body []byte) error {
+		var item domain.RentalItem
+		if err := json.Unmarshal(body, &item); err != nil {
+			return err
+		}
+		ch <- item.ID
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	return ch
+}
+
+func (e events) next(t *testing.T) uuid.UUID {
+	t.Helper()
+
+	select {
+	case id := <-e:
+		return id
+	case <-time.After(time.Second):
+		t.Fatal("no event was published")
+		return uuid.Nil
+	}
+}
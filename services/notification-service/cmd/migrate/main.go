@@ -0,0 +1,41 @@
+// Command migrate applies the notification service's pending schema migrations, or
+// lists them with "migrate status", using the service's configuration.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rentalflow/notification-service/internal/migrations"
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+)
+
+func main() {
+	cfg, err := config.Load("notification")
+	if err != nil {
+		fmt.Printf("Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+	logger.Init(cfg.ServiceName, cfg.LogLevel, cfg.LogFormat)
+
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		logger.Fatal(err, "Failed to connect to database")
+	}
+	defer db.Close(context.Background())
+
+	command := ""
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+	if err := db.Migrator(migrations.All, migrations.Postgres).Run(ctx, command, os.Stdout); err != nil {
+		logger.Fatal(err, "Migration failed")
+	}
+}
@@ -2,9 +2,15 @@ package email
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
+	"net"
 	"net/smtp"
+	"net/url"
+	"strings"
+
+	"github.com/rentalflow/rentalflow/pkg/metrics"
 )
 
 // Config holds email configuration
@@ -15,6 +21,9 @@ type Config struct {
 	SMTPPassword string
 	FromEmail    string
 	FromName     string
+
+	// Links in account emails point at the frontend here
+	AppBaseURL string
 }
 
 // Service handles email sending
@@ -141,6 +150,173 @@ func (s *Service) loadTemplates() {
         </div>
     </div>
 </body>
+</html>
+	`))
+
+	// Email Verification Template
+	s.templates["email_verification"] = template.Must(template.New("email_verification").Parse(`
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: #4F46E5; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background: #f9f9f9; }
+        .button { background: #4F46E5; color: white; padding: 12px 24px; text-decoration: none; border-radius: 4px; display: inline-block; }
+        .footer { text-align: center; padding: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Verify Your Email</h1>
+        </div>
+        <div class="content">
+            <p>Hi {{.UserName}},</p>
+            <p>Thanks for signing up for RentalFlow. Please confirm your email address to start booking items.</p>
+            <p><a href="{{.VerifyURL}}" class="button">Verify Email</a></p>
+            <p>This link expires in {{.ExpiresIn}}. If you did not create an account, you can ignore this email.</p>
+        </div>
+        <div class="footer">
+            <p>© 2025 RentalFlow. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`))
+
+	// Password Reset Template
+	s.templates["password_reset"] = template.Must(template.New("password_reset").Parse(`
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: #4F46E5; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background: #f9f9f9; }
+        .button { background: #4F46E5; color: white; padding: 12px 24px; text-decoration: none; border-radius: 4px; display: inline-block; }
+        .footer { text-align: center; padding: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Reset Your Password</h1>
+        </div>
+        <div class="content">
+            <p>Hi {{.UserName}},</p>
+            <p>We received a request to reset the password for your RentalFlow account.</p>
+            <p><a href="{{.ResetURL}}" class="button">Reset Password</a></p>
+            <p>This link expires in {{.ExpiresIn}} and can only be used once. If you did not request a reset, you can ignore this email and your password will stay the same.</p>
+        </div>
+        <div class="footer">
+            <p>© 2025 RentalFlow. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`))
+
+	// Account Locked Template
+	s.templates["account_locked"] = template.Must(template.New("account_locked").Parse(`
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: #DC2626; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background: #f9f9f9; }
+        .button { background: #DC2626; color: white; padding: 12px 24px; text-decoration: none; border-radius: 4px; display: inline-block; }
+        .footer { text-align: center; padding: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Account Temporarily Locked</h1>
+        </div>
+        <div class="content">
+            <p>Hi {{.UserName}},</p>
+            <p>We locked your RentalFlow account after several failed sign-in attempts.</p>
+            <p>You can try again after <strong>{{.LockedUntil}}</strong>.</p>
+            <p>If this wasn't you, someone may be trying to guess your password. We recommend resetting it now.</p>
+            <p><a href="{{.ResetURL}}" class="button">Reset Password</a></p>
+        </div>
+        <div class="footer">
+            <p>© 2025 RentalFlow. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`))
+
+	// Identity Verification Approved Template
+	s.templates["kyc_approved"] = template.Must(template.New("kyc_approved").Parse(`
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: #4F46E5; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background: #f9f9f9; }
+        .button { background: #4F46E5; color: white; padding: 12px 24px; text-decoration: none; border-radius: 4px; display: inline-block; }
+        .footer { text-align: center; padding: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Identity Verified</h1>
+        </div>
+        <div class="content">
+            <p>Hi {{.UserName}},</p>
+            <p>Good news: we reviewed your documents and your identity is now verified.</p>
+            <p>You can list items and make high-value bookings on RentalFlow.</p>
+            <p><a href="{{.AccountURL}}" class="button">Go to Your Account</a></p>
+        </div>
+        <div class="footer">
+            <p>© 2025 RentalFlow. All rights reserved.</p>
+        </div>
+    </div>
+</body>
+</html>
+	`))
+
+	// Identity Verification Rejected Template
+	s.templates["kyc_rejected"] = template.Must(template.New("kyc_rejected").Parse(`
+<!DOCTYPE html>
+<html>
+<head>
+    <style>
+        body { font-family: Arial, sans-serif; line-height: 1.6; color: #333; }
+        .container { max-width: 600px; margin: 0 auto; padding: 20px; }
+        .header { background: #DC2626; color: white; padding: 20px; text-align: center; }
+        .content { padding: 20px; background: #f9f9f9; }
+        .button { background: #DC2626; color: white; padding: 12px 24px; text-decoration: none; border-radius: 4px; display: inline-block; }
+        .footer { text-align: center; padding: 20px; color: #666; font-size: 12px; }
+    </style>
+</head>
+<body>
+    <div class="container">
+        <div class="header">
+            <h1>Identity Verification Unsuccessful</h1>
+        </div>
+        <div class="content">
+            <p>Hi {{.UserName}},</p>
+            <p>We could not verify your identity with the documents you submitted.</p>
+            <p><strong>Reason:</strong> {{.Reason}}</p>
+            <p>Please submit new documents and we will review them again.</p>
+            <p><a href="{{.AccountURL}}" class="button">Resubmit Documents</a></p>
+        </div>
+        <div class="footer">
+            <p>© 2025 RentalFlow. All rights reserved.</p>
+        </div>
+    </div>
+</body>
 </html>
 	`))
 }
@@ -160,8 +336,60 @@ func (s *Service) SendReviewReceived(to string, data map[string]interface{}) err
 	return s.send(to, "New Review Received - RentalFlow", "review_received", data)
 }
 
+// SendEmailVerification sends a link that verifies the address with token
+func (s *Service) SendEmailVerification(to, token string, data map[string]interface{}) error {
+	data = withLink(data, "VerifyURL", s.link("/verify-email", token))
+	return s.send(to, "Verify Your Email - RentalFlow", "email_verification", data)
+}
+
+// SendPasswordReset sends a link that resets the password with token
+func (s *Service) SendPasswordReset(to, token string, data map[string]interface{}) error {
+	data = withLink(data, "ResetURL", s.link("/reset-password", token))
+	return s.send(to, "Reset Your Password - RentalFlow", "password_reset", data)
+}
+
+// SendAccountLocked warns a user that their account was locked
+func (s *Service) SendAccountLocked(to string, data map[string]interface{}) error {
+	data = withLink(data, "ResetURL", s.link("/forgot-password", ""))
+	return s.send(to, "Your Account Was Locked - RentalFlow", "account_locked", data)
+}
+
+// SendKYCApproved tells a user their identity verification was approved
+func (s *Service) SendKYCApproved(to string, data map[string]interface{}) error {
+	data = withLink(data, "AccountURL", s.link("/account/verification", ""))
+	return s.send(to, "Your Identity Is Verified - RentalFlow", "kyc_approved", data)
+}
+
+// SendKYCRejected tells a user their identity verification was rejected and why
+func (s *Service) SendKYCRejected(to string, data map[string]interface{}) error {
+	data = withLink(data, "AccountURL", s.link("/account/verification", ""))
+	return s.send(to, "Identity Verification Update - RentalFlow", "kyc_rejected", data)
+}
+
+// link builds a frontend link, carrying token if it is set
+func (s *Service) link(path, token string) string {
+	link := strings.TrimSuffix(s.config.AppBaseURL, "/") + path
+	if token != "" {
+		link += "?token=" + url.QueryEscape(token)
+	}
+	return link
+}
+
+// withLink copies data with a link added. Links are only ever built here, so
+// a link in the request data is replaced.
+func withLink(data map[string]interface{}, key, link string) map[string]interface{} {
+	out := make(map[string]interface{}, len(data)+1)
+	for k, v := range data {
+		out[k] = v
+	}
+	out[key] = link
+	return out
+}
+
 // send sends an email using the specified template
-func (s *Service) send(to, subject, templateName string, data map[string]interface{}) error {
+func (s *Service) send(to, subject, templateName string, data map[string]interface{}) (err error) {
+	defer func() { metrics.NotificationSent("email", err) }()
+
 	// Render template
 	tmpl, exists := s.templates[templateName]
 	if !exists {
@@ -187,10 +415,34 @@ func (s *Service) send(to, subject, templateName string, data map[string]interfa
 	auth := smtp.PlainAuth("", s.config.SMTPUsername, s.config.SMTPPassword, s.config.SMTPHost)
 	addr := fmt.Sprintf("%s:%s", s.config.SMTPHost, s.config.SMTPPort)
 
-	err := smtp.SendMail(addr, auth, s.config.FromEmail, []string{to}, msg)
+	err = smtp.SendMail(addr, auth, s.config.FromEmail, []string{to}, msg)
 	if err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
 
 	return nil
 }
+
+// Ping checks that the SMTP server accepts connections and greets us
+func (s *Service) Ping(ctx context.Context) error {
+	if s.config.SMTPHost == "" {
+		return fmt.Errorf("smtp is not configured")
+	}
+
+	addr := fmt.Sprintf("%s:%s", s.config.SMTPHost, s.config.SMTPPort)
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to reach smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.config.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake failed: %w", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/notification-service/internal/domain"
+	"github.com/rentalflow/notification-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	apperrors "github.com/rentalflow/rentalflow/pkg/errors"
+	pb "github.com/rentalflow/rentalflow/pkg/pb/notification"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// NotificationHandler implements the gRPC NotificationService
+type NotificationHandler struct {
+	pb.UnimplementedNotificationServiceServer
+	notificationService *service.NotificationService
+	checker             *auth.Checker
+}
+
+// NewNotificationHandler creates a new notification gRPC handler
+func NewNotificationHandler(notificationService *service.NotificationService, checker *auth.Checker) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		checker:             checker,
+	}
+}
+
+// SendNotification stores a notification for a user. It is called by other
+// services and is not exposed through the gateway. The title and message are
+// taken from the "title" and "message" data keys.
+func (h *NotificationHandler) SendNotification(ctx context.Context, req *pb.SendNotificationRequest) (*pb.Notification, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	if req.NotificationType == "" {
+		return nil, status.Error(codes.InvalidArgument, "notification_type is required")
+	}
+
+	channel := domain.NotificationChannel(req.Channel)
+	switch channel {
+	case "":
+		channel = domain.ChannelInApp
+	case domain.ChannelEmail, domain.ChannelSMS, domain.ChannelPush, domain.ChannelInApp:
+	default:
+		return nil, toGRPCError(domain.ErrInvalidChannel)
+	}
+
+	notification, err := h.notificationService.SendNotification(ctx, userID, req.NotificationType, req.Data["title"], req.Data["message"], channel)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoNotification(notification), nil
+}
+
+// GetUserNotifications lists the caller's notifications
+func (h *NotificationHandler) GetUserNotifications(ctx context.Context, req *pb.GetUserNotificationsRequest) (*pb.NotificationsResponse, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := resolveUser(principal, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications, total, err := h.notificationService.GetUserNotifications(ctx, userID, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	resp := &pb.NotificationsResponse{
+		Notifications: make([]*pb.Notification, len(notifications)),
+		Total:         int32(total),
+		Page:          req.Page,
+		PageSize:      req.PageSize,
+	}
+	for i, notification := range notifications {
+		resp.Notifications[i] = toProtoNotification(notification)
+	}
+	return resp, nil
+}
+
+// MarkAsRead marks one of the caller's notifications as read
+func (h *NotificationHandler) MarkAsRead(ctx context.Context, req *pb.MarkAsReadRequest) (*pb.Notification, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationID, err := uuid.Parse(req.NotificationId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid notification_id")
+	}
+
+	notification, err := h.notificationService.GetNotification(ctx, notificationID)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	if !principal.CanActFor(notification.UserID.String()) {
+		return nil, toGRPCError(domain.ErrUnauthorized)
+	}
+
+	if err := h.notificationService.MarkAsRead(ctx, notificationID); err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	notification, err = h.notificationService.GetNotification(ctx, notificationID)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	return toProtoNotification(notification), nil
+}
+
+// GetUnreadCount counts the caller's unread notifications
+func (h *NotificationHandler) GetUnreadCount(ctx context.Context, req *pb.GetUnreadCountRequest) (*pb.UnreadCountResponse, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := resolveUser(principal, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := h.notificationService.GetUnreadCount(ctx, userID)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.UnreadCountResponse{Count: int32(count)}, nil
+}
+
+// SendMessage sends a booking message from the caller
+func (h *NotificationHandler) SendMessage(ctx context.Context, req *pb.SendMessageRequest) (*pb.Message, error) {
+	principal, err := h.checker.RequireContext(ctx, auth.PermMessagesSend)
+	if err != nil {
+		return nil, err
+	}
+
+	senderID, err := resolveUser(principal, req.SenderId)
+	if err != nil {
+		return nil, err
+	}
+	bookingID, err := uuid.Parse(req.BookingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid booking_id")
+	}
+	receiverID, err := uuid.Parse(req.ReceiverId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid receiver_id")
+	}
+	if req.Content == "" {
+		return nil, status.Error(codes.InvalidArgument, "content is required")
+	}
+
+	message, err := h.notificationService.SendMessage(ctx, bookingID, senderID, receiverID, req.Content)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoMessage(message), nil
+}
+
+// GetBookingMessages lists the messages of a booking that the caller sent or
+// received. Holders of users:manage see every message.
+func (h *NotificationHandler) GetBookingMessages(ctx context.Context, req *pb.GetBookingMessagesRequest) (*pb.MessagesResponse, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := resolveUser(principal, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	bookingID, err := uuid.Parse(req.BookingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid booking_id")
+	}
+
+	messages, total, err := h.notificationService.GetBookingMessages(ctx, bookingID, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	resp := &pb.MessagesResponse{
+		Total:    int32(total),
+		Page:     req.Page,
+		PageSize: req.PageSize,
+	}
+	for _, message := range messages {
+		if message.SenderID != userID && message.ReceiverID != userID && !principal.Has(auth.PermUsersManage) {
+			resp.Total--
+			continue
+		}
+		resp.Messages = append(resp.Messages, toProtoMessage(message))
+	}
+	return resp, nil
+}
+
+// resolveUser defaults the user to the caller and checks the caller may act for it
+func resolveUser(principal *auth.Principal, userID string) (uuid.UUID, error) {
+	if !principal.CanActFor(userID) {
+		return uuid.Nil, toGRPCError(domain.ErrUnauthorized)
+	}
+	if userID == "" {
+		userID = principal.UserID
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	return id, nil
+}
+
+func toProtoNotification(notification *domain.Notification) *pb.Notification {
+	protoNotification := &pb.Notification{
+		Id:               notification.ID.String(),
+		UserId:           notification.UserID.String(),
+		NotificationType: notification.NotificationType,
+		Title:            notification.Title,
+		Message:          notification.Message,
+		Channel:          string(notification.Channel),
+		Priority:         notification.Priority,
+		Status:           string(notification.Status),
+		ActionUrl:        notification.ActionURL,
+		CreatedAt:        timestamppb.New(notification.CreatedAt),
+	}
+	if notification.SentAt != nil {
+		protoNotification.SentAt = timestamppb.New(*notification.SentAt)
+	}
+	if notification.ReadAt != nil {
+		protoNotification.ReadAt = timestamppb.New(*notification.ReadAt)
+	}
+	return protoNotification
+}
+
+func toProtoMessage(message *domain.Message) *pb.Message {
+	protoMessage := &pb.Message{
+		Id:          message.ID.String(),
+		BookingId:   message.BookingID.String(),
+		SenderId:    message.SenderID.String(),
+		ReceiverId:  message.ReceiverID.String(),
+		Content:     message.Content,
+		Attachments: message.Attachments,
+		IsRead:      message.IsRead,
+		CreatedAt:   timestamppb.New(message.CreatedAt),
+	}
+	if message.ReadAt != nil {
+		protoMessage.ReadAt = timestamppb.New(*message.ReadAt)
+	}
+	return protoMessage
+}
+
+// toGRPCError maps domain errors to gRPC status errors
+func toGRPCError(err error) error {
+	switch err {
+	case domain.ErrNotificationNotFound, domain.ErrMessageNotFound:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeNotFound, err))
+	case domain.ErrUnauthorized:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeForbidden, err))
+	case domain.ErrInvalidChannel:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeValidation, err))
+	default:
+		return apperrors.ToGRPCError(err)
+	}
+}
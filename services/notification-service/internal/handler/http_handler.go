@@ -9,17 +9,21 @@ import (
 	"github.com/google/uuid"
 	"github.com/rentalflow/notification-service/internal/email"
 	"github.com/rentalflow/notification-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/userdata"
 )
 
 type HTTPHandler struct {
 	notificationService *service.NotificationService
 	emailService        *email.Service
+	checker             *auth.Checker
 }
 
-func NewHTTPHandler(notificationService *service.NotificationService, emailService *email.Service) *HTTPHandler {
+func NewHTTPHandler(notificationService *service.NotificationService, emailService *email.Service, checker *auth.Checker) *HTTPHandler {
 	return &HTTPHandler{
 		notificationService: notificationService,
 		emailService:        emailService,
+		checker:             checker,
 	}
 }
 
@@ -28,10 +32,16 @@ func (h *HTTPHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/health", h.Health)
 	fmt.Println("Registering /api/notifications/booking-created")
 	mux.HandleFunc("/api/notifications/booking-created", h.SendBookingCreated)
-	fmt.Println("Registering /api/notifications/payment-success", h.SendPaymentSuccess)
+	fmt.Println("Registering /api/notifications/payment-success")
 	mux.HandleFunc("/api/notifications/payment-success", h.SendPaymentSuccess)
 	fmt.Println("Registering /api/notifications/review-received")
 	mux.HandleFunc("/api/notifications/review-received", h.SendReviewReceived)
+	// Account emails are sent by auth-service with a service token
+	mux.HandleFunc("/api/notifications/email-verification", h.SendEmailVerification)
+	mux.HandleFunc("/api/notifications/password-reset", h.SendPasswordReset)
+	mux.HandleFunc("/api/notifications/account-locked", h.SendAccountLocked)
+	mux.HandleFunc("/api/notifications/kyc-approved", h.SendKYCApproved)
+	mux.HandleFunc("/api/notifications/kyc-rejected", h.SendKYCRejected)
 
 	// New In-App Notification Routes
 	fmt.Println("Registering /api/notifications/user")
@@ -46,6 +56,9 @@ func (h *HTTPHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/messages/send", h.SendMessage)
 	fmt.Println("Registering /api/messages/booking")
 	mux.HandleFunc("/api/messages/booking", h.GetBookingMessages)
+
+	// Personal-data routes called by auth-service
+	userdata.NewHandler(h.checker, h.notificationService.ExportUserData, h.notificationService.EraseUserData, userdata.StatusErased, nil).Register(mux)
 }
 
 func (h *HTTPHandler) Health(w http.ResponseWriter, r *http.Request) {
@@ -127,6 +140,162 @@ func (h *HTTPHandler) SendReviewReceived(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]bool{"success": true})
 }
+
+func (h *HTTPHandler) SendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.checker.Require(w, r, auth.PermNotificationsSend); !ok {
+		return
+	}
+
+	var req struct {
+		To    string                 `json:"to"`
+		Token string                 `json:"token"`
+		Data  map[string]interface{} `json:"data"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Token == "" {
+		http.Error(w, "token is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.emailService.SendEmailVerification(req.To, req.Token, req.Data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func (h *HTTPHandler) SendPasswordReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.checker.Require(w, r, auth.PermNotificationsSend); !ok {
+		return
+	}
+
+	var req struct {
+		To    string                 `json:"to"`
+		Token string                 `json:"token"`
+		Data  map[string]interface{} `json:"data"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Token == "" {
+		http.Error(w, "token is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.emailService.SendPasswordReset(req.To, req.Token, req.Data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func (h *HTTPHandler) SendAccountLocked(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.checker.Require(w, r, auth.PermNotificationsSend); !ok {
+		return
+	}
+
+	var req struct {
+		To   string                 `json:"to"`
+		Data map[string]interface{} `json:"data"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.emailService.SendAccountLocked(req.To, req.Data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func (h *HTTPHandler) SendKYCApproved(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.checker.Require(w, r, auth.PermNotificationsSend); !ok {
+		return
+	}
+
+	var req struct {
+		To   string                 `json:"to"`
+		Data map[string]interface{} `json:"data"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.emailService.SendKYCApproved(req.To, req.Data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
+func (h *HTTPHandler) SendKYCRejected(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, ok := h.checker.Require(w, r, auth.PermNotificationsSend); !ok {
+		return
+	}
+
+	var req struct {
+		To   string                 `json:"to"`
+		Data map[string]interface{} `json:"data"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.emailService.SendKYCRejected(req.To, req.Data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]bool{"success": true})
+}
+
 func (h *HTTPHandler) GetUserNotifications(w http.ResponseWriter, r *http.Request) {
 	userIDStr := r.URL.Query().Get("user_id")
 	userID, err := uuid.Parse(userIDStr)
@@ -203,6 +372,11 @@ func (h *HTTPHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	principal, ok := h.checker.Require(w, r, auth.PermMessagesSend)
+	if !ok {
+		return
+	}
+
 	var req struct {
 		BookingID  string `json:"booking_id"`
 		SenderID   string `json:"sender_id"`
@@ -214,6 +388,14 @@ func (h *HTTPHandler) SendMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !principal.CanActFor(req.SenderID) {
+		http.Error(w, "Cannot send messages as another user", http.StatusForbidden)
+		return
+	}
+	if req.SenderID == "" {
+		req.SenderID = principal.UserID
+	}
+
 	bid, _ := uuid.Parse(req.BookingID)
 	sid, _ := uuid.Parse(req.SenderID)
 	rid, _ := uuid.Parse(req.ReceiverID)
@@ -0,0 +1,37 @@
+// Package migrations declares the notification service's indexes, and its
+// schema on PostgreSQL in the .sql files of postgres/. Released versions must
+// never change: add a new one instead.
+package migrations
+
+import (
+	"embed"
+
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// All lists the notification service's migrations
+var All = []database.Migration{
+	{
+		Version:     1,
+		Description: "Index notifications by user and messages by booking and participant",
+		Up: database.Steps(
+			database.CreateIndexes("notifications",
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}}},
+			),
+			database.CreateIndexes("messages",
+				mongo.IndexModel{Keys: bson.D{{Key: "booking_id", Value: 1}, {Key: "created_at", Value: -1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "sender_id", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "receiver_id", Value: 1}}},
+			),
+		),
+	},
+}
+
+//go:embed postgres/*.sql
+var postgresFiles embed.FS
+
+// Postgres lists the notification service's PostgreSQL migrations
+var Postgres = database.MustLoadSQLMigrations(postgresFiles, "postgres")
@@ -0,0 +1,33 @@
+CREATE TABLE notifications (
+    id                uuid PRIMARY KEY,
+    user_id           uuid NOT NULL,
+    notification_type text NOT NULL,
+    title             text NOT NULL,
+    message           text NOT NULL,
+    channel           text NOT NULL,
+    priority          text NOT NULL,
+    status            text NOT NULL,
+    action_url        text NOT NULL DEFAULT '',
+    sent_at           timestamptz,
+    read_at           timestamptz,
+    created_at        timestamptz NOT NULL
+);
+
+CREATE INDEX notifications_user_id_created_at_idx ON notifications (user_id, created_at DESC);
+CREATE INDEX notifications_user_id_status_idx ON notifications (user_id, status);
+
+CREATE TABLE messages (
+    id          uuid PRIMARY KEY,
+    booking_id  uuid NOT NULL,
+    sender_id   uuid NOT NULL,
+    receiver_id uuid NOT NULL,
+    content     text NOT NULL,
+    attachments text[],
+    is_read     boolean NOT NULL DEFAULT false,
+    read_at     timestamptz,
+    created_at  timestamptz NOT NULL
+);
+
+CREATE INDEX messages_booking_id_created_at_idx ON messages (booking_id, created_at DESC);
+CREATE INDEX messages_sender_id_idx ON messages (sender_id);
+CREATE INDEX messages_receiver_id_idx ON messages (receiver_id);
@@ -0,0 +1,49 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/rentalflow/notification-service/internal/migrations"
+	"github.com/rentalflow/notification-service/internal/repository"
+	"github.com/rentalflow/notification-service/internal/repository/repositorytest"
+	"github.com/rentalflow/rentalflow/pkg/database/databasetest"
+)
+
+func TestMongoRepositories(t *testing.T) {
+	t.Run("Notification", func(t *testing.T) {
+		repositorytest.NotificationRepository(t, func(t *testing.T) repository.NotificationRepository {
+			return repository.NewMongoNotificationRepository(databasetest.Mongo(t, migrations.All).DB)
+		})
+	})
+	t.Run("Message", func(t *testing.T) {
+		repositorytest.MessageRepository(t, func(t *testing.T) repository.MessageRepository {
+			return repository.NewMongoMessageRepository(databasetest.Mongo(t, migrations.All).DB)
+		})
+	})
+}
+
+func TestPostgresRepositories(t *testing.T) {
+	t.Run("Notification", func(t *testing.T) {
+		repositorytest.NotificationRepository(t, func(t *testing.T) repository.NotificationRepository {
+			return repository.NewPostgresNotificationRepository(databasetest.Postgres(t, migrations.Postgres))
+		})
+	})
+	t.Run("Message", func(t *testing.T) {
+		repositorytest.MessageRepository(t, func(t *testing.T) repository.MessageRepository {
+			return repository.NewPostgresMessageRepository(databasetest.Postgres(t, migrations.Postgres))
+		})
+	})
+}
+
+func TestMemoryRepositories(t *testing.T) {
+	t.Run("Notification", func(t *testing.T) {
+		repositorytest.NotificationRepository(t, func(t *testing.T) repository.NotificationRepository {
+			return repository.NewMemoryNotificationRepository()
+		})
+	})
+	t.Run("Message", func(t *testing.T) {
+		repositorytest.MessageRepository(t, func(t *testing.T) repository.MessageRepository {
+			return repository.NewMemoryMessageRepository()
+		})
+	})
+}
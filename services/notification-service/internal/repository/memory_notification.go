@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/notification-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// MemoryNotificationRepository keeps notifications in memory, for tests and
+// local runs
+type MemoryNotificationRepository struct {
+	mu            sync.Mutex
+	notifications map[uuid.UUID]*domain.Notification
+}
+
+func NewMemoryNotificationRepository() *MemoryNotificationRepository {
+	return &MemoryNotificationRepository{
+		notifications: make(map[uuid.UUID]*domain.Notification),
+	}
+}
+
+func (r *MemoryNotificationRepository) Create(ctx context.Context, notification *domain.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.notifications[notification.ID] = database.Clone(notification)
+	return nil
+}
+
+func (r *MemoryNotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.notifications[id]
+	if !ok {
+		return nil, domain.ErrNotificationNotFound
+	}
+	return database.Clone(n), nil
+}
+
+func (r *MemoryNotificationRepository) GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*domain.Notification, int, error) {
+	notifications := r.find(userID)
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.After(notifications[j].CreatedAt)
+	})
+	return database.Page(notifications, offset, limit), len(notifications), nil
+}
+
+func (r *MemoryNotificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n, ok := r.notifications[id]
+	if !ok {
+		return domain.ErrNotificationNotFound
+	}
+	now := time.Now()
+	n.Status = domain.StatusRead
+	n.ReadAt = &now
+	return nil
+}
+
+func (r *MemoryNotificationRepository) GetUnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	count := 0
+	for _, n := range r.find(userID) {
+		if n.Status != domain.StatusRead {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListAllByUser returns every notification sent to the user
+func (r *MemoryNotificationRepository) ListAllByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Notification, error) {
+	notifications := r.find(userID)
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.Before(notifications[j].CreatedAt)
+	})
+	return notifications, nil
+}
+
+// DeleteByUser removes every notification sent to the user
+func (r *MemoryNotificationRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, n := range r.notifications {
+		if n.UserID == userID {
+			delete(r.notifications, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// find returns copies of the notifications sent to the user
+func (r *MemoryNotificationRepository) find(userID uuid.UUID) []*domain.Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	notifications := []*domain.Notification{}
+	for _, n := range r.notifications {
+		if n.UserID == userID {
+			notifications = append(notifications, database.Clone(n))
+		}
+	}
+	return notifications
+}
+
+// MemoryMessageRepository keeps messages in memory, for tests and local runs
+type MemoryMessageRepository struct {
+	mu       sync.Mutex
+	messages map[uuid.UUID]*domain.Message
+}
+
+func NewMemoryMessageRepository() *MemoryMessageRepository {
+	return &MemoryMessageRepository{
+		messages: make(map[uuid.UUID]*domain.Message),
+	}
+}
+
+func (r *MemoryMessageRepository) Create(ctx context.Context, message *domain.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.messages[message.ID] = database.Clone(message)
+	return nil
+}
+
+func (r *MemoryMessageRepository) GetByBooking(ctx context.Context, bookingID uuid.UUID, offset, limit int) ([]*domain.Message, int, error) {
+	messages := r.find(func(m *domain.Message) bool { return m.BookingID == bookingID })
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].CreatedAt.After(messages[j].CreatedAt)
+	})
+	return database.Page(messages, offset, limit), len(messages), nil
+}
+
+// ListByUser returns every message the user sent or received
+func (r *MemoryMessageRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Message, error) {
+	messages := r.find(func(m *domain.Message) bool { return m.SenderID == userID || m.ReceiverID == userID })
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+	return messages, nil
+}
+
+// AnonymizeByUser blanks the messages the user sent and replaces the user's
+// ID with the nil UUID on both sides of each conversation. The other party
+// keeps the thread, without the deleted user's words or identity.
+func (r *MemoryMessageRepository) AnonymizeByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, m := range r.messages {
+		if m.SenderID == userID {
+			m.SenderID = uuid.Nil
+			m.Content = ""
+			m.Attachments = []string{}
+			count++
+		}
+	}
+	for _, m := range r.messages {
+		if m.ReceiverID == userID {
+			m.ReceiverID = uuid.Nil
+			count++
+		}
+	}
+	return count, nil
+}
+
+// find returns copies of the messages that match
+func (r *MemoryMessageRepository) find(match func(*domain.Message) bool) []*domain.Message {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	messages := []*domain.Message{}
+	for _, m := range r.messages {
+		if match(m) {
+			messages = append(messages, database.Clone(m))
+		}
+	}
+	return messages
+}
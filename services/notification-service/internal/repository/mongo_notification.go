@@ -91,6 +91,31 @@ func (r *MongoNotificationRepository) GetUnreadCount(ctx context.Context, userID
 	return int(count), err
 }
 
+// ListAllByUser returns every notification sent to the user
+func (r *MongoNotificationRepository) ListAllByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Notification, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+	cursor, err := r.coll.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var notifications []*domain.Notification
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// DeleteByUser removes every notification sent to the user
+func (r *MongoNotificationRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	result, err := r.coll.DeleteMany(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return 0, err
+	}
+	return int(result.DeletedCount), nil
+}
+
 type MongoMessageRepository struct {
 	coll *mongo.Collection
 }
@@ -131,3 +156,46 @@ func (r *MongoMessageRepository) GetByBooking(ctx context.Context, bookingID uui
 	}
 	return messages, int(total), nil
 }
+
+// ListByUser returns every message the user sent or received
+func (r *MongoMessageRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Message, error) {
+	filter := bson.M{"$or": []bson.M{{"sender_id": userID}, {"receiver_id": userID}}}
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var messages []*domain.Message
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// AnonymizeByUser blanks the messages the user sent and replaces the user's
+// ID with the nil UUID on both sides of each conversation. The other party
+// keeps the thread, without the deleted user's words or identity.
+func (r *MongoMessageRepository) AnonymizeByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	sent, err := r.coll.UpdateMany(ctx, bson.M{"sender_id": userID}, bson.M{
+		"$set": bson.M{
+			"sender_id":   uuid.Nil,
+			"content":     "",
+			"attachments": []string{},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	received, err := r.coll.UpdateMany(ctx, bson.M{"receiver_id": userID}, bson.M{
+		"$set": bson.M{"receiver_id": uuid.Nil},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(sent.ModifiedCount + received.ModifiedCount), nil
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/notification-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// notificationColumns lists the columns of the notifications table in
+// scanNotification order
+const notificationColumns = `id, user_id, notification_type, title, message, channel, priority, status,
+	action_url, sent_at, read_at, created_at`
+
+type PostgresNotificationRepository struct {
+	db *database.Postgres
+}
+
+func NewPostgresNotificationRepository(db *database.Postgres) *PostgresNotificationRepository {
+	return &PostgresNotificationRepository{db: db}
+}
+
+func (r *PostgresNotificationRepository) Create(ctx context.Context, n *domain.Notification) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO notifications (`+notificationColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		n.ID, n.UserID, n.NotificationType, n.Title, n.Message, n.Channel, n.Priority, n.Status,
+		n.ActionURL, n.SentAt, n.ReadAt, n.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresNotificationRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Notification, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+notificationColumns+` FROM notifications WHERE id = $1`, id)
+	n, err := scanNotification(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotificationNotFound
+		}
+		return nil, err
+	}
+	return n, nil
+}
+
+func (r *PostgresNotificationRepository) GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*domain.Notification, int, error) {
+	conn := r.db.Conn(ctx)
+
+	var total int
+	if err := conn.QueryRow(ctx, `SELECT count(*) FROM notifications WHERE user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := conn.Query(ctx, `SELECT `+notificationColumns+` FROM notifications
+		WHERE user_id = $1 ORDER BY created_at DESC OFFSET $2 LIMIT $3`,
+		userID, offset, limit,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	notifications, err := collectNotifications(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+func (r *PostgresNotificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE notifications SET status = $2, read_at = $3 WHERE id = $1`,
+		id, domain.StatusRead, time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNotificationRepository) GetUnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.Conn(ctx).QueryRow(ctx, `SELECT count(*) FROM notifications WHERE user_id = $1 AND status <> $2`,
+		userID, domain.StatusRead,
+	).Scan(&count)
+	return count, err
+}
+
+// ListAllByUser returns every notification sent to the user
+func (r *PostgresNotificationRepository) ListAllByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Notification, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, `SELECT `+notificationColumns+` FROM notifications
+		WHERE user_id = $1 ORDER BY created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectNotifications(rows)
+}
+
+// DeleteByUser removes every notification sent to the user
+func (r *PostgresNotificationRepository) DeleteByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `DELETE FROM notifications WHERE user_id = $1`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// scanNotification reads a row of notificationColumns
+func scanNotification(row pgx.Row) (*domain.Notification, error) {
+	var n domain.Notification
+	err := row.Scan(
+		&n.ID, &n.UserID, &n.NotificationType, &n.Title, &n.Message, &n.Channel, &n.Priority, &n.Status,
+		&n.ActionURL, &n.SentAt, &n.ReadAt, &n.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// collectNotifications reads every row of notificationColumns and closes rows
+func collectNotifications(rows pgx.Rows) ([]*domain.Notification, error) {
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.Notification, error) {
+		return scanNotification(row)
+	})
+}
+
+// messageColumns lists the columns of the messages table in scanMessage order
+const messageColumns = `id, booking_id, sender_id, receiver_id, content, attachments, is_read, read_at, created_at`
+
+type PostgresMessageRepository struct {
+	db *database.Postgres
+}
+
+func NewPostgresMessageRepository(db *database.Postgres) *PostgresMessageRepository {
+	return &PostgresMessageRepository{db: db}
+}
+
+func (r *PostgresMessageRepository) Create(ctx context.Context, m *domain.Message) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO messages (`+messageColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		m.ID, m.BookingID, m.SenderID, m.ReceiverID, m.Content, m.Attachments, m.IsRead, m.ReadAt, m.CreatedAt,
+	)
+	return err
+}
+
+func (r *PostgresMessageRepository) GetByBooking(ctx context.Context, bookingID uuid.UUID, offset, limit int) ([]*domain.Message, int, error) {
+	conn := r.db.Conn(ctx)
+
+	var total int
+	if err := conn.QueryRow(ctx, `SELECT count(*) FROM messages WHERE booking_id = $1`, bookingID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := conn.Query(ctx, `SELECT `+messageColumns+` FROM messages
+		WHERE booking_id = $1 ORDER BY created_at DESC OFFSET $2 LIMIT $3`,
+		bookingID, offset, limit,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	messages, err := collectMessages(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return messages, total, nil
+}
+
+// ListByUser returns every message the user sent or received
+func (r *PostgresMessageRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Message, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, `SELECT `+messageColumns+` FROM messages
+		WHERE sender_id = $1 OR receiver_id = $1 ORDER BY created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectMessages(rows)
+}
+
+// AnonymizeByUser blanks the messages the user sent and replaces the user's
+// ID with the nil UUID on both sides of each conversation. The other party
+// keeps the thread, without the deleted user's words or identity.
+func (r *PostgresMessageRepository) AnonymizeByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	var anonymized int
+	err := r.db.WithTransaction(ctx, func(ctx context.Context) error {
+		conn := r.db.Conn(ctx)
+
+		sent, err := conn.Exec(ctx, `UPDATE messages SET sender_id = $2, content = '', attachments = '{}'
+			WHERE sender_id = $1`,
+			userID, uuid.Nil,
+		)
+		if err != nil {
+			return err
+		}
+
+		received, err := conn.Exec(ctx, `UPDATE messages SET receiver_id = $2 WHERE receiver_id = $1`,
+			userID, uuid.Nil,
+		)
+		if err != nil {
+			return err
+		}
+
+		anonymized = int(sent.RowsAffected() + received.RowsAffected())
+		return nil
+	})
+	return anonymized, err
+}
+
+// scanMessage reads a row of messageColumns
+func scanMessage(row pgx.Row) (*domain.Message, error) {
+	var m domain.Message
+	err := row.Scan(
+		&m.ID, &m.BookingID, &m.SenderID, &m.ReceiverID, &m.Content, &m.Attachments, &m.IsRead, &m.ReadAt, &m.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// collectMessages reads every row of messageColumns and closes rows
+func collectMessages(rows pgx.Rows) ([]*domain.Message, error) {
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.Message, error) {
+		return scanMessage(row)
+	})
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rentalflow/notification-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
 )
 
 type NotificationRepository interface {
@@ -13,9 +14,35 @@ type NotificationRepository interface {
 	GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*domain.Notification, int, error)
 	MarkAsRead(ctx context.Context, id uuid.UUID) error
 	GetUnreadCount(ctx context.Context, userID uuid.UUID) (int, error)
+	ListAllByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Notification, error)
+	DeleteByUser(ctx context.Context, userID uuid.UUID) (int, error)
 }
 
 type MessageRepository interface {
 	Create(ctx context.Context, message *domain.Message) error
 	GetByBooking(ctx context.Context, bookingID uuid.UUID, offset, limit int) ([]*domain.Message, int, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Message, error)
+	AnonymizeByUser(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// NewNotificationRepository returns the NotificationRepository of the database
+func NewNotificationRepository(db *database.Backend) NotificationRepository {
+	if db.Memory {
+		return NewMemoryNotificationRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresNotificationRepository(db.Postgres)
+	}
+	return NewMongoNotificationRepository(db.Mongo.DB)
+}
+
+// NewMessageRepository returns the MessageRepository of the database
+func NewMessageRepository(db *database.Backend) MessageRepository {
+	if db.Memory {
+		return NewMemoryMessageRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresMessageRepository(db.Postgres)
+	}
+	return NewMongoMessageRepository(db.Mongo.DB)
 }
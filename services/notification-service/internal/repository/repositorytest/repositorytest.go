@@ -0,0 +1,244 @@
+// Package repositorytest is the conformance suite of the notification
+// service's repositories. Every implementation runs it, so the service
+// behaves the same whichever database it stores its data in.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/notification-service/internal/domain"
+	"github.com/rentalflow/notification-service/internal/repository"
+)
+
+// NotificationRepository runs the conformance tests of
+// NotificationRepository against the repositories newRepo opens, an empty
+// one for each test
+func NotificationRepository(t *testing.T, newRepo func(t *testing.T) repository.NotificationRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		n := newNotification(uuid.New(), time.Now())
+		sent := time.Now()
+		n.Status = domain.StatusSent
+		n.SentAt = &sent
+		n.ActionURL = "/bookings/1"
+		mustCreateNotification(t, repo, n)
+
+		got, err := repo.GetByID(ctx, n.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.UserID != n.UserID || got.NotificationType != n.NotificationType || got.Title != n.Title ||
+			got.Message != n.Message || got.Channel != domain.ChannelInApp || got.Priority != "medium" ||
+			got.Status != domain.StatusSent || got.ActionURL != n.ActionURL || got.ReadAt != nil {
+			t.Errorf("GetByID = %+v, want %+v", got, n)
+		}
+		if got.SentAt == nil {
+			t.Fatal("sent_at was not stored")
+		}
+		sameTime(t, "sent_at", *got.SentAt, sent)
+
+		if _, err := repo.GetByID(ctx, uuid.New()); err != domain.ErrNotificationNotFound {
+			t.Errorf("GetByID of a missing notification = %v, want ErrNotificationNotFound", err)
+		}
+	})
+
+	t.Run("ReadAndCount", func(t *testing.T) {
+		repo := newRepo(t)
+		user := uuid.New()
+		start := time.Now().Add(-time.Hour)
+		var created []*domain.Notification
+		for i := 0; i < 3; i++ {
+			n := newNotification(user, start.Add(time.Duration(i)*time.Minute))
+			mustCreateNotification(t, repo, n)
+			created = append(created, n)
+		}
+		mustCreateNotification(t, repo, newNotification(uuid.New(), start))
+
+		if err := repo.MarkAsRead(ctx, created[0].ID); err != nil {
+			t.Fatalf("MarkAsRead: %v", err)
+		}
+		if err := repo.MarkAsRead(ctx, uuid.New()); err != domain.ErrNotificationNotFound {
+			t.Errorf("MarkAsRead of a missing notification = %v, want ErrNotificationNotFound", err)
+		}
+		got, err := repo.GetByID(ctx, created[0].ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != domain.StatusRead || got.ReadAt == nil {
+			t.Errorf("GetByID after MarkAsRead = %+v", got)
+		}
+
+		unread, err := repo.GetUnreadCount(ctx, user)
+		if err != nil {
+			t.Fatalf("GetUnreadCount: %v", err)
+		}
+		if unread != 2 {
+			t.Errorf("GetUnreadCount = %d, want 2", unread)
+		}
+
+		page, total, err := repo.GetByUser(ctx, user, 1, 5)
+		if err != nil {
+			t.Fatalf("GetByUser: %v", err)
+		}
+		if total != 3 || len(page) != 2 || page[0].ID != created[1].ID || page[1].ID != created[0].ID {
+			t.Errorf("GetByUser past the newest = %d notifications (total %d), want the older 2 of 3", len(page), total)
+		}
+
+		all, err := repo.ListAllByUser(ctx, user)
+		if err != nil {
+			t.Fatalf("ListAllByUser: %v", err)
+		}
+		if len(all) != 3 || all[0].ID != created[0].ID || all[2].ID != created[2].ID {
+			t.Errorf("ListAllByUser = %d notifications, want all 3, oldest first", len(all))
+		}
+	})
+
+	t.Run("DeleteByUser", func(t *testing.T) {
+		repo := newRepo(t)
+		user := uuid.New()
+		mustCreateNotification(t, repo, newNotification(user, time.Now()))
+		mustCreateNotification(t, repo, newNotification(user, time.Now()))
+		other := newNotification(uuid.New(), time.Now())
+		mustCreateNotification(t, repo, other)
+
+		deleted, err := repo.DeleteByUser(ctx, user)
+		if err != nil {
+			t.Fatalf("DeleteByUser: %v", err)
+		}
+		if deleted != 2 {
+			t.Errorf("DeleteByUser = %d, want 2", deleted)
+		}
+		if all, err := repo.ListAllByUser(ctx, user); err != nil || len(all) != 0 {
+			t.Errorf("ListAllByUser after DeleteByUser = %d notifications, %v", len(all), err)
+		}
+		if _, err := repo.GetByID(ctx, other.ID); err != nil {
+			t.Errorf("another user's notification was deleted: %v", err)
+		}
+	})
+}
+
+// MessageRepository runs the conformance tests of MessageRepository against
+// the repositories newRepo opens, an empty one for each test
+func MessageRepository(t *testing.T, newRepo func(t *testing.T) repository.MessageRepository) {
+	ctx := context.Background()
+
+	t.Run("GetByBooking", func(t *testing.T) {
+		repo := newRepo(t)
+		booking, renter, owner := uuid.New(), uuid.New(), uuid.New()
+		start := time.Now().Add(-time.Hour)
+		question := newMessage(booking, renter, owner, "Is it available?", start)
+		question.Attachments = []string{"https://cdn.example.com/id.jpg"}
+		answer := newMessage(booking, owner, renter, "Yes", start.Add(time.Minute))
+		mustCreateMessage(t, repo, question)
+		mustCreateMessage(t, repo, answer)
+		mustCreateMessage(t, repo, newMessage(uuid.New(), renter, owner, "Hello", start))
+
+		messages, total, err := repo.GetByBooking(ctx, booking, 0, 10)
+		if err != nil {
+			t.Fatalf("GetByBooking: %v", err)
+		}
+		if total != 2 || len(messages) != 2 || messages[0].ID != answer.ID || messages[1].ID != question.ID {
+			t.Fatalf("GetByBooking = %d messages (total %d), want the booking's 2, newest first", len(messages), total)
+		}
+		got := messages[1]
+		if got.SenderID != renter || got.ReceiverID != owner || got.Content != question.Content ||
+			len(got.Attachments) != 1 || got.Attachments[0] != question.Attachments[0] || got.IsRead {
+			t.Errorf("stored message = %+v, want %+v", got, question)
+		}
+		sameTime(t, "created_at", got.CreatedAt, question.CreatedAt)
+	})
+
+	t.Run("AnonymizeByUser", func(t *testing.T) {
+		repo := newRepo(t)
+		booking, user, other := uuid.New(), uuid.New(), uuid.New()
+		start := time.Now().Add(-time.Hour)
+		sent := newMessage(booking, user, other, "My phone number is 0911", start)
+		sent.Attachments = []string{"https://cdn.example.com/id.jpg"}
+		received := newMessage(booking, other, user, "Thanks", start.Add(time.Minute))
+		unrelated := newMessage(uuid.New(), other, uuid.New(), "Hi", start)
+		for _, m := range []*domain.Message{sent, received, unrelated} {
+			mustCreateMessage(t, repo, m)
+		}
+
+		messages, err := repo.ListByUser(ctx, user)
+		if err != nil {
+			t.Fatalf("ListByUser: %v", err)
+		}
+		if len(messages) != 2 || messages[0].ID != sent.ID || messages[1].ID != received.ID {
+			t.Errorf("ListByUser = %d messages, want the user's 2, oldest first", len(messages))
+		}
+
+		anonymized, err := repo.AnonymizeByUser(ctx, user)
+		if err != nil {
+			t.Fatalf("AnonymizeByUser: %v", err)
+		}
+		if anonymized != 2 {
+			t.Errorf("AnonymizeByUser = %d, want 2", anonymized)
+		}
+		if messages, err := repo.ListByUser(ctx, user); err != nil || len(messages) != 0 {
+			t.Errorf("ListByUser after AnonymizeByUser = %d messages, %v", len(messages), err)
+		}
+
+		thread, _, err := repo.GetByBooking(ctx, booking, 0, 10)
+		if err != nil {
+			t.Fatalf("GetByBooking: %v", err)
+		}
+		if len(thread) != 2 {
+			t.Fatalf("GetByBooking = %d messages, the thread should survive", len(thread))
+		}
+		for _, m := range thread {
+			switch m.ID {
+			case sent.ID:
+				if m.SenderID != uuid.Nil || m.Content != "" || len(m.Attachments) != 0 || m.ReceiverID != other {
+					t.Errorf("sent message after AnonymizeByUser = %+v", m)
+				}
+			case received.ID:
+				if m.ReceiverID != uuid.Nil || m.Content != "Thanks" || m.SenderID != other {
+					t.Errorf("received message after AnonymizeByUser = %+v", m)
+				}
+			}
+		}
+	})
+}
+
+// newNotification returns a pending in-app notification created at createdAt
+func newNotification(userID uuid.UUID, createdAt time.Time) *domain.Notification {
+	n := domain.NewNotification(userID, "booking_confirmed", "Booking confirmed", "Your booking is confirmed", domain.ChannelInApp)
+	n.CreatedAt = createdAt
+	return n
+}
+
+// newMessage returns an unread message created at createdAt
+func newMessage(bookingID, senderID, receiverID uuid.UUID, content string, createdAt time.Time) *domain.Message {
+	m := domain.NewMessage(bookingID, senderID, receiverID, content)
+	m.CreatedAt = createdAt
+	return m
+}
+
+func mustCreateNotification(t *testing.T, repo repository.NotificationRepository, n *domain.Notification) {
+	t.Helper()
+	if err := repo.Create(context.Background(), n); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
+
+func mustCreateMessage(t *testing.T, repo repository.MessageRepository, m *domain.Message) {
+	t.Helper()
+	if err := repo.Create(context.Background(), m); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
+
+// sameTime fails the test unless got is want at the millisecond precision
+// every database keeps
+func sameTime(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if diff := got.Sub(want); diff > time.Millisecond || diff < -time.Millisecond {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
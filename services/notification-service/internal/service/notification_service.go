@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/rentalflow/notification-service/internal/domain"
 	"github.com/rentalflow/notification-service/internal/repository"
+	"github.com/rentalflow/rentalflow/pkg/metrics"
 )
 
 type NotificationService struct {
@@ -63,7 +64,9 @@ func (s *NotificationService) HandleBookingEvent(ctx context.Context, eventData
 
 func (s *NotificationService) SendNotification(ctx context.Context, userID uuid.UUID, notifType, title, message string, channel domain.NotificationChannel) (*domain.Notification, error) {
 	notification := domain.NewNotification(userID, notifType, title, message, channel)
-	if err := s.notificationRepo.Create(ctx, notification); err != nil {
+	err := s.notificationRepo.Create(ctx, notification)
+	metrics.NotificationSent(string(channel), err)
+	if err != nil {
 		return nil, err
 	}
 	return notification, nil
@@ -80,6 +83,10 @@ func (s *NotificationService) GetUserNotifications(ctx context.Context, userID u
 	return s.notificationRepo.GetByUser(ctx, userID, offset, pageSize)
 }
 
+func (s *NotificationService) GetNotification(ctx context.Context, notificationID uuid.UUID) (*domain.Notification, error) {
+	return s.notificationRepo.GetByID(ctx, notificationID)
+}
+
 func (s *NotificationService) MarkAsRead(ctx context.Context, notificationID uuid.UUID) error {
 	return s.notificationRepo.MarkAsRead(ctx, notificationID)
 }
@@ -106,3 +113,35 @@ func (s *NotificationService) GetBookingMessages(ctx context.Context, bookingID
 	offset := (page - 1) * pageSize
 	return s.messageRepo.GetByBooking(ctx, bookingID, offset, pageSize)
 }
+
+// ExportUserData returns the notifications and messages of a user
+func (s *NotificationService) ExportUserData(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+	notifications, err := s.notificationRepo.ListAllByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := s.messageRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"notifications": notifications,
+		"messages":      messages,
+	}, nil
+}
+
+// EraseUserData deletes a deleted user's notifications and anonymizes their messages
+func (s *NotificationService) EraseUserData(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+	notifications, err := s.notificationRepo.DeleteByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := s.messageRepo.AnonymizeByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"notifications_removed": notifications,
+		"messages_anonymized":   messages,
+	}, nil
+}
@@ -0,0 +1,154 @@
+// Package app assembles the payment service, so that cmd/server and the
+// system tests run the same wiring
+package app
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rentalflow/payment-service/internal/chapa"
+	"github.com/rentalflow/payment-service/internal/clients"
+	"github.com/rentalflow/payment-service/internal/handler"
+	"github.com/rentalflow/payment-service/internal/migrations"
+	"github.com/rentalflow/payment-service/internal/repository"
+	"github.com/rentalflow/payment-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/rentalflow/pkg/health"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/metrics"
+	pb "github.com/rentalflow/rentalflow/pkg/pb/payment"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// App is a payment service ready to serve
+type App struct {
+	log           zerolog.Logger
+	db            *database.Backend
+	bookingClient *clients.BookingClient
+	grpcServer    *grpc.Server
+	httpServer    *http.Server
+}
+
+// New connects to the database and builds the service
+func New(cfg *config.Config) (*App, error) {
+	// Built here rather than at package init, after main has set up logging
+	log := logger.NewLogger("app")
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	a := &App{log: log, db: db}
+
+	log.Info().Str("driver", db.Driver()).Str("uri", cfg.Database.GetURI()).Msg("Connected to database")
+
+	// Apply pending index and data migrations. Deployments that run the
+	// migrate command as a release step turn this off.
+	if cfg.Database.MigrateOnStart {
+		migrateCtx, cancelMigrate := context.WithTimeout(context.Background(), 5*time.Minute)
+		_, err := db.Migrator(migrations.All, migrations.Postgres).Up(migrateCtx)
+		cancelMigrate()
+		if err != nil {
+			a.close()
+			return nil, fmt.Errorf("failed to apply migrations: %w", err)
+		}
+	}
+
+	// Initialize Chapa client
+	chapaClient := chapa.NewClient(
+		cfg.Chapa.SecretKey,
+		cfg.Chapa.PublicKey,
+		cfg.Chapa.EncryptionKey,
+		true, // true for test mode
+	)
+	chapaClient.BaseURL = cfg.Chapa.BaseURL
+	log.Info().Str("url", chapaClient.BaseURL).Msg("Initialized Chapa payment client")
+
+	checker := auth.NewChecker(cfg.JWT.Secret)
+	bookingClient, err := clients.NewBookingClient(cfg.Services.BookingServiceAddr, checker)
+	if err != nil {
+		a.close()
+		return nil, fmt.Errorf("failed to create booking client: %w", err)
+	}
+	a.bookingClient = bookingClient
+
+	paymentRepo := repository.NewPaymentRepository(db)
+	paymentService := service.NewPaymentService(paymentRepo, db, chapaClient, bookingClient, service.CheckoutConfig{
+		CallbackURL: cfg.Chapa.CallbackURL,
+		ReturnURL:   cfg.Chapa.ReturnURL,
+	})
+
+	paymentHandler := handler.NewPaymentHandler(paymentService, checker)
+	httpHandler := handler.NewHTTPHandler(paymentService, checker)
+
+	a.grpcServer = grpc.NewServer(tracing.ServerOption(), metrics.ServerOption(), logger.ServerOption())
+	pb.RegisterPaymentServiceServer(a.grpcServer, paymentHandler)
+
+	healthServer := grpchealth.NewServer()
+	grpc_health_v1.RegisterHealthServer(a.grpcServer, healthServer)
+	healthServer.SetServingStatus("payment.PaymentService", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	if cfg.Environment == "development" {
+		reflection.Register(a.grpcServer)
+	}
+
+	// Dependency checks behind /ready
+	checks := health.NewRegistry(cfg.Health.Timeout, cfg.Health.CacheTTL)
+	checks.Register(db.Driver(), db.Health)
+	checks.RegisterOptional("chapa", chapaClient.Ping)
+
+	mux := http.NewServeMux()
+	httpHandler.RegisterRoutes(mux)
+	mux.Handle("/ready", checks.Handler())
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/debug/config", cfg.DebugHandler(checker))
+
+	a.httpServer = &http.Server{
+		Handler: tracing.Handler(logger.Handler(metrics.Instrument(mux)), cfg.ServiceName),
+	}
+
+	return a, nil
+}
+
+// Start serves gRPC on grpcLis and the HTTP API on httpLis until Shutdown
+func (a *App) Start(grpcLis, httpLis net.Listener) {
+	go func() {
+		a.log.Info().Str("addr", grpcLis.Addr().String()).Msg("gRPC server listening")
+		if err := a.grpcServer.Serve(grpcLis); err != nil {
+			a.log.Error().Err(err).Msg("gRPC server failed")
+		}
+	}()
+
+	go func() {
+		a.log.Info().Str("addr", httpLis.Addr().String()).Msg("HTTP API server listening")
+		if err := a.httpServer.Serve(httpLis); err != nil && err != http.ErrServerClosed {
+			a.log.Error().Err(err).Msg("HTTP server failed")
+		}
+	}()
+}
+
+// Shutdown stops the servers and closes the connections the service opened
+func (a *App) Shutdown(ctx context.Context) {
+	a.grpcServer.GracefulStop()
+	if err := a.httpServer.Shutdown(ctx); err != nil {
+		a.log.Error().Err(err).Msg("HTTP server shutdown failed")
+	}
+	a.close()
+}
+
+// close releases the clients and connections opened so far
+func (a *App) close() {
+	if a.bookingClient != nil {
+		a.bookingClient.Close()
+	}
+	a.db.Close(context.Background())
+}
@@ -3,84 +3,66 @@ package main
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
-	"github.com/rentalflow/payment-service/internal/chapa"
-	"github.com/rentalflow/payment-service/internal/config"
-	"github.com/rentalflow/payment-service/internal/handler"
-	"github.com/rentalflow/payment-service/internal/repository"
-	"github.com/rentalflow/payment-service/internal/service"
-	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/payment-service/app"
+	"github.com/rentalflow/rentalflow/pkg/config"
 	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
 )
 
 func main() {
-	cfg, err := config.Load()
+	cfg, err := config.Load("payment")
 	if err != nil {
 		fmt.Printf("Failed to load config: %v\n", err)
 		os.Exit(1)
 	}
 
-	logger.Init(cfg.ServiceName, cfg.LogLevel)
+	logger.Init(cfg.ServiceName, cfg.LogLevel, cfg.LogFormat)
 	log := logger.NewLogger("main")
 
 	log.Info().Msg("Starting Payment Service...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	client, err := database.New(cfg.Database.GetURI(), cfg.Database.Database)
+	shutdownTracing, err := tracing.Init(cfg.ServiceName, cfg.Tracing)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to connect to database")
+		log.Fatal().Err(err).Msg("Failed to initialize tracing")
 	}
-	defer client.Close(ctx)
-
-	log.Info().Str("uri", cfg.Database.GetURI()).Msg("Connected to database")
+	defer shutdownTracing(context.Background())
 
-	// Initialize Chapa client
-	chapaClient := chapa.NewClient(
-		cfg.ChapaSecretKey,
-		cfg.ChapaPublicKey,
-		cfg.ChapaEncryptionKey,
-		true, // true for test mode
-	)
-	log.Info().Msg("Initialized Chapa payment client")
+	// The log level can change in the config file without a restart
+	cfg.Watch(func(next *config.Config) {
+		logger.SetLevel(next.LogLevel)
+		log.Info().Str("log_level", next.LogLevel).Msg("Configuration reloaded")
+	})
 
-	paymentRepo := repository.NewMongoPaymentRepository(client.DB)
-	paymentService := service.NewPaymentService(paymentRepo, chapaClient)
-	httpHandler := handler.NewHTTPHandler(paymentService)
-
-	httpAddr := fmt.Sprintf(":%d", cfg.HTTPPort)
-	mux := http.NewServeMux()
-	httpHandler.RegisterRoutes(mux)
-
-	httpServer := &http.Server{
-		Addr:    httpAddr,
-		Handler: mux,
+	a, err := app.New(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to start payment service")
 	}
 
-	go func() {
-		log.Info().Str("addr", httpAddr).Msg("HTTP API server listening")
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Error().Err(err).Msg("HTTP server failed")
-		}
-	}()
+	grpcLis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to listen")
+	}
+	httpLis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.HTTPPort))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to listen")
+	}
+	a.Start(grpcLis, httpLis)
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Info().Msg("Shutting down server...")
+	log.Info().Msg("Shutting down servers...")
 
-	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	if err := httpServer.Shutdown(ctx); err != nil {
-		log.Error().Err(err).Msg("HTTP server shutdown failed")
-	}
+	a.Shutdown(ctx)
 
 	log.Info().Msg("Server stopped")
 }
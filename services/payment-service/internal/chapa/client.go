@@ -2,6 +2,7 @@ package chapa
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
 )
 
 const (
@@ -76,7 +78,8 @@ func NewClient(secretKey, publicKey, encryptionKey string, isTest bool) *Client
 		EncryptionKey: encryptionKey,
 		BaseURL:       baseURL,
 		HTTPClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: tracing.Transport(nil),
 		},
 	}
 }
@@ -162,3 +165,28 @@ func (c *Client) VerifyPayment(txRef string) (*VerifyPaymentResponse, error) {
 
 	return &chapaResp, nil
 }
+
+// Ping checks that Chapa is reachable and accepts the secret key, using the
+// lightweight bank list endpoint
+func (c *Client) Ping(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/banks", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.SecretKey)
+
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to reach chapa: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("chapa rejected the secret key (status %d)", resp.StatusCode)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return fmt.Errorf("chapa API error (status %d)", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package clients
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/payment-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	pb "github.com/rentalflow/rentalflow/pkg/pb/booking"
+	"github.com/rentalflow/rentalflow/pkg/tracing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// BookingClient calls booking-service over gRPC
+type BookingClient struct {
+	conn    *grpc.ClientConn
+	client  pb.BookingServiceClient
+	timeout time.Duration
+}
+
+// NewBookingClient creates a new booking-service client. The connection is
+// established lazily on the first call. Every call carries a service token
+// signed by checker, which booking-service requires for its internal calls.
+func NewBookingClient(addr string, checker *auth.Checker) (*BookingClient, error) {
+	conn, err := grpc.Dial(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		checker.ServiceDialOption("payment-service", auth.PermBookingsPayment),
+		tracing.DialOption(),
+		logger.DialOption(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial booking service: %w", err)
+	}
+
+	return &BookingClient{
+		conn:    conn,
+		client:  pb.NewBookingServiceClient(conn),
+		timeout: 5 * time.Second,
+	}, nil
+}
+
+// Close closes the underlying connection
+func (c *BookingClient) Close() error {
+	return c.conn.Close()
+}
+
+// GetBooking fetches a booking
+func (c *BookingClient) GetBooking(ctx context.Context, bookingID uuid.UUID) (*pb.Booking, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	booking, err := c.client.GetBookingById(ctx, &pb.GetBookingRequest{BookingId: bookingID.String()})
+	if err != nil {
+		return nil, bookingError(err)
+	}
+	return booking, nil
+}
+
+// UpdatePaymentStatus records a payment outcome on a booking
+func (c *BookingClient) UpdatePaymentStatus(ctx context.Context, bookingID uuid.UUID, paymentStatus string, paymentID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	_, err := c.client.UpdateBookingPaymentStatus(ctx, &pb.UpdatePaymentStatusRequest{
+		BookingId:     bookingID.String(),
+		PaymentStatus: paymentStatus,
+		PaymentId:     paymentID.String(),
+	})
+	if err != nil {
+		return bookingError(err)
+	}
+	return nil
+}
+
+// bookingError maps booking-service status codes to payment domain errors
+func bookingError(err error) error {
+	if status.Code(err) == codes.NotFound {
+		return domain.ErrBookingNotFound
+	}
+	return fmt.Errorf("booking service: %w", err)
+}
@@ -10,4 +10,7 @@ var (
 	ErrRefundNotAllowed     = errors.New("refund not allowed for this payment")
 	ErrInvalidPaymentMethod = errors.New("invalid payment method")
 	ErrUnauthorized         = errors.New("unauthorized to perform this action")
+	ErrBookingNotFound      = errors.New("booking not found")
+	ErrBookingNotPayable    = errors.New("booking cannot be paid")
+	ErrVersionConflict      = errors.New("payment was modified by another request")
 )
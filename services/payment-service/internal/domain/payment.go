@@ -47,6 +47,7 @@ type Payment struct {
 	ReceiptURL            string        `json:"receipt_url" bson:"receipt_url"`
 	CreatedAt             time.Time     `json:"created_at" bson:"created_at"`
 	UpdatedAt             time.Time     `json:"updated_at" bson:"updated_at"`
+	Version               int64         `json:"version" bson:"version"` // incremented by every update
 }
 
 func NewPayment(bookingID, userID uuid.UUID, amount float64, method PaymentMethod) *Payment {
@@ -60,5 +61,6 @@ func NewPayment(bookingID, userID uuid.UUID, amount float64, method PaymentMetho
 		Method:    method,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+		Version:   1,
 	}
 }
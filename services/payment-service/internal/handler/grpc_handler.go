@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/payment-service/internal/domain"
+	"github.com/rentalflow/payment-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	apperrors "github.com/rentalflow/rentalflow/pkg/errors"
+	"github.com/rentalflow/rentalflow/pkg/etag"
+	pb "github.com/rentalflow/rentalflow/pkg/pb/payment"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// PaymentHandler implements the gRPC PaymentService
+type PaymentHandler struct {
+	pb.UnimplementedPaymentServiceServer
+	paymentService *service.PaymentService
+	checker        *auth.Checker
+}
+
+// NewPaymentHandler creates a new payment gRPC handler
+func NewPaymentHandler(paymentService *service.PaymentService, checker *auth.Checker) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+		checker:        checker,
+	}
+}
+
+// InitializePayment starts a payment for the caller's booking
+func (h *PaymentHandler) InitializePayment(ctx context.Context, req *pb.InitializePaymentRequest) (*pb.InitializePaymentResponse, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := resolveUser(principal, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	bookingID, err := uuid.Parse(req.BookingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid booking_id")
+	}
+	if req.Method == "" {
+		return nil, status.Error(codes.InvalidArgument, "method is required")
+	}
+
+	payment, err := h.paymentService.InitializePayment(ctx, bookingID, userID, 0, domain.PaymentMethod(req.Method))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.InitializePaymentResponse{
+		PaymentId:     payment.ID.String(),
+		CheckoutUrl:   payment.CheckoutURL,
+		TransactionId: payment.ProviderTransactionID,
+		Status:        string(payment.Status),
+	}, nil
+}
+
+// GetPayment returns a payment to its payer
+func (h *PaymentHandler) GetPayment(ctx context.Context, req *pb.GetPaymentRequest) (*pb.Payment, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentID, err := uuid.Parse(req.PaymentId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid payment_id")
+	}
+
+	payment, err := h.paymentService.GetPayment(ctx, paymentID)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	if !principal.CanActFor(payment.UserID.String()) {
+		return nil, toGRPCError(domain.ErrUnauthorized)
+	}
+
+	etag.SetHeader(ctx, payment.Version)
+	return toProtoPayment(payment), nil
+}
+
+// GetBookingPayments lists the payments of a booking to its renter or owner
+func (h *PaymentHandler) GetBookingPayments(ctx context.Context, req *pb.GetBookingPaymentsRequest) (*pb.PaymentsResponse, error) {
+	principal, err := h.checker.RequireContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := resolveUser(principal, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	bookingID, err := uuid.Parse(req.BookingId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid booking_id")
+	}
+
+	if !principal.Has(auth.PermUsersManage) {
+		if err := h.paymentService.EnsureBookingParticipant(ctx, bookingID, userID); err != nil {
+			return nil, toGRPCError(err)
+		}
+	}
+
+	payments, err := h.paymentService.GetBookingPayments(ctx, bookingID)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	resp := &pb.PaymentsResponse{Payments: make([]*pb.Payment, len(payments))}
+	for i, payment := range payments {
+		resp.Payments[i] = toProtoPayment(payment)
+	}
+	return resp, nil
+}
+
+// ProcessRefund refunds a completed payment
+func (h *PaymentHandler) ProcessRefund(ctx context.Context, req *pb.ProcessRefundRequest) (*pb.Payment, error) {
+	if _, err := h.checker.RequireContext(ctx, auth.PermRefundsIssue); err != nil {
+		return nil, err
+	}
+
+	paymentID, err := uuid.Parse(req.PaymentId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid payment_id")
+	}
+
+	payment, err := h.paymentService.ProcessRefund(ctx, paymentID, req.Amount, etag.FromContext(ctx))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	etag.SetHeader(ctx, payment.Version)
+	return toProtoPayment(payment), nil
+}
+
+// UpdatePaymentStatus records a provider outcome. It is internal and is not
+// exposed through the gateway.
+func (h *PaymentHandler) UpdatePaymentStatus(ctx context.Context, req *pb.UpdatePaymentStatusRequest) (*pb.Payment, error) {
+	paymentID, err := uuid.Parse(req.PaymentId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid payment_id")
+	}
+	if req.Status == "" {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+
+	payment, err := h.paymentService.UpdatePaymentStatus(ctx, paymentID, domain.PaymentStatus(req.Status), req.ProviderTransactionId)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	etag.SetHeader(ctx, payment.Version)
+	return toProtoPayment(payment), nil
+}
+
+// resolveUser defaults the user to the caller and checks the caller may act for it
+func resolveUser(principal *auth.Principal, userID string) (uuid.UUID, error) {
+	if !principal.CanActFor(userID) {
+		return uuid.Nil, toGRPCError(domain.ErrUnauthorized)
+	}
+	if userID == "" {
+		userID = principal.UserID
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	return id, nil
+}
+
+func toProtoPayment(payment *domain.Payment) *pb.Payment {
+	return &pb.Payment{
+		Id:          payment.ID.String(),
+		BookingId:   payment.BookingID.String(),
+		PaymentType: payment.PaymentType,
+		Amount:      payment.Amount,
+		Currency:    payment.Currency,
+		Status:      string(payment.Status),
+		Method:      string(payment.Method),
+		Breakdown: &pb.PaymentBreakdown{
+			RentalFee:          payment.RentalFee,
+			SecurityDeposit:    payment.SecurityDeposit,
+			ServiceFee:         payment.ServiceFee,
+			AdditionalServices: payment.AdditionalServices,
+			Tax:                payment.Tax,
+		},
+		DepositHeld:           payment.DepositHeld,
+		DepositStatus:         payment.DepositStatus,
+		ProviderName:          payment.ProviderName,
+		ProviderTransactionId: payment.ProviderTransactionID,
+		ReceiptUrl:            payment.ReceiptURL,
+		CreatedAt:             timestamppb.New(payment.CreatedAt),
+		UpdatedAt:             timestamppb.New(payment.UpdatedAt),
+	}
+}
+
+// toGRPCError maps domain errors to gRPC status errors
+func toGRPCError(err error) error {
+	switch err {
+	case domain.ErrPaymentNotFound, domain.ErrInvoiceNotFound, domain.ErrBookingNotFound:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeNotFound, err))
+	case domain.ErrInvalidAmount, domain.ErrInvalidPaymentMethod:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeValidation, err))
+	case domain.ErrUnauthorized:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeForbidden, err))
+	case domain.ErrRefundNotAllowed, domain.ErrBookingNotPayable:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypePrecondition, err))
+	case domain.ErrVersionConflict:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeConflict, err))
+	case domain.ErrPaymentFailed:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeServiceUnavail, err))
+	default:
+		return apperrors.ToGRPCError(err)
+	}
+}
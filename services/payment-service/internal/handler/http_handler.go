@@ -7,24 +7,28 @@ import (
 	"github.com/google/uuid"
 	"github.com/rentalflow/payment-service/internal/domain"
 	"github.com/rentalflow/payment-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/etag"
+	"github.com/rentalflow/rentalflow/pkg/userdata"
 )
 
 type HTTPHandler struct {
 	paymentService *service.PaymentService
+	checker        *auth.Checker
 }
 
-func NewHTTPHandler(paymentService *service.PaymentService) *HTTPHandler {
-	return &HTTPHandler{paymentService: paymentService}
+func NewHTTPHandler(paymentService *service.PaymentService, checker *auth.Checker) *HTTPHandler {
+	return &HTTPHandler{paymentService: paymentService, checker: checker}
 }
 
 func (h *HTTPHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/health", h.Health)
-	mux.HandleFunc("/ready", h.Ready)
 	mux.HandleFunc("/api/payments/initialize", h.InitializePayment)
 	mux.HandleFunc("/api/payments", h.GetPayment)
 	mux.HandleFunc("/api/payments/booking", h.GetBookingPayments)
 	mux.HandleFunc("/api/payments/refund", h.ProcessRefund)
 	mux.HandleFunc("/api/payments/status", h.UpdateStatus)
+	userdata.NewHandler(h.checker, h.paymentService.ExportUserData, h.paymentService.EraseUserData, userdata.StatusRetained, h.handleError).Register(mux)
 	mux.HandleFunc("/api/payments/verify", h.VerifyPayment)
 }
 
@@ -33,11 +37,6 @@ func (h *HTTPHandler) Health(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]string{"status": "healthy"})
 }
 
-func (h *HTTPHandler) Ready(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "ready"})
-}
-
 func (h *HTTPHandler) InitializePayment(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -91,6 +90,7 @@ func (h *HTTPHandler) GetPayment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	etag.Set(w, payment.Version)
 	json.NewEncoder(w).Encode(payment)
 }
 
@@ -117,6 +117,10 @@ func (h *HTTPHandler) ProcessRefund(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if _, ok := h.checker.Require(w, r, auth.PermRefundsIssue); !ok {
+		return
+	}
+
 	var req struct {
 		PaymentID string  `json:"payment_id"`
 		Amount    float64 `json:"amount"`
@@ -128,16 +132,18 @@ func (h *HTTPHandler) ProcessRefund(w http.ResponseWriter, r *http.Request) {
 	}
 
 	paymentID, _ := uuid.Parse(req.PaymentID)
-	payment, err := h.paymentService.ProcessRefund(r.Context(), paymentID, req.Amount)
+	payment, err := h.paymentService.ProcessRefund(r.Context(), paymentID, req.Amount, etag.IfMatch(r))
 	if err != nil {
 		h.handleError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
+	etag.Set(w, payment.Version)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"id":     payment.ID.String(),
-		"status": payment.Status,
+		"id":      payment.ID.String(),
+		"status":  payment.Status,
+		"version": payment.Version,
 	})
 }
 
@@ -201,10 +207,12 @@ func (h *HTTPHandler) handleError(w http.ResponseWriter, err error) {
 	w.Header().Set("Content-Type", "application/json")
 
 	switch err {
-	case domain.ErrPaymentNotFound:
+	case domain.ErrPaymentNotFound, domain.ErrBookingNotFound:
 		w.WriteHeader(http.StatusNotFound)
 	case domain.ErrInvalidAmount, domain.ErrInvalidPaymentMethod:
 		w.WriteHeader(http.StatusBadRequest)
+	case domain.ErrBookingNotPayable, domain.ErrRefundNotAllowed, domain.ErrVersionConflict:
+		w.WriteHeader(http.StatusConflict)
 	case domain.ErrUnauthorized:
 		w.WriteHeader(http.StatusForbidden)
 	default:
@@ -0,0 +1,35 @@
+// Package migrations declares the payment service's indexes, and its schema
+// on PostgreSQL in the .sql files of postgres/. Released versions must never
+// change: add a new one instead.
+package migrations
+
+import (
+	"embed"
+
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// All lists the payment service's migrations
+var All = []database.Migration{
+	{
+		Version:     1,
+		Description: "Index payments by booking and user",
+		Up: database.CreateIndexes("payments",
+			mongo.IndexModel{Keys: bson.D{{Key: "booking_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		),
+	},
+	{
+		Version:     2,
+		Description: "Version payments",
+		Up:          database.BackfillVersion("payments"),
+	},
+}
+
+//go:embed postgres/*.sql
+var postgresFiles embed.FS
+
+// Postgres lists the payment service's PostgreSQL migrations
+var Postgres = database.MustLoadSQLMigrations(postgresFiles, "postgres")
@@ -0,0 +1,27 @@
+CREATE TABLE payments (
+    id                      uuid PRIMARY KEY,
+    booking_id              uuid NOT NULL,
+    user_id                 uuid NOT NULL,
+    payment_type            text NOT NULL DEFAULT '',
+    amount                  double precision NOT NULL,
+    currency                text NOT NULL,
+    status                  text NOT NULL,
+    method                  text NOT NULL,
+    rental_fee              double precision NOT NULL DEFAULT 0,
+    security_deposit        double precision NOT NULL DEFAULT 0,
+    service_fee             double precision NOT NULL DEFAULT 0,
+    additional_services     double precision NOT NULL DEFAULT 0,
+    tax                     double precision NOT NULL DEFAULT 0,
+    deposit_held            boolean NOT NULL DEFAULT false,
+    deposit_status          text NOT NULL DEFAULT '',
+    provider_name           text NOT NULL DEFAULT '',
+    provider_transaction_id text NOT NULL DEFAULT '',
+    checkout_url            text NOT NULL DEFAULT '',
+    receipt_url             text NOT NULL DEFAULT '',
+    created_at              timestamptz NOT NULL,
+    updated_at              timestamptz NOT NULL,
+    version                 bigint NOT NULL DEFAULT 1
+);
+
+CREATE INDEX payments_booking_id_created_at_idx ON payments (booking_id, created_at DESC);
+CREATE INDEX payments_user_id_created_at_idx ON payments (user_id, created_at);
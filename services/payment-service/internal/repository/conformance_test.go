@@ -0,0 +1,28 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/rentalflow/payment-service/internal/migrations"
+	"github.com/rentalflow/payment-service/internal/repository"
+	"github.com/rentalflow/payment-service/internal/repository/repositorytest"
+	"github.com/rentalflow/rentalflow/pkg/database/databasetest"
+)
+
+func TestMongoPaymentRepository(t *testing.T) {
+	repositorytest.PaymentRepository(t, func(t *testing.T) repository.PaymentRepository {
+		return repository.NewMongoPaymentRepository(databasetest.Mongo(t, migrations.All).DB)
+	})
+}
+
+func TestPostgresPaymentRepository(t *testing.T) {
+	repositorytest.PaymentRepository(t, func(t *testing.T) repository.PaymentRepository {
+		return repository.NewPostgresPaymentRepository(databasetest.Postgres(t, migrations.Postgres))
+	})
+}
+
+func TestMemoryPaymentRepository(t *testing.T) {
+	repositorytest.PaymentRepository(t, func(t *testing.T) repository.PaymentRepository {
+		return repository.NewMemoryPaymentRepository()
+	})
+}
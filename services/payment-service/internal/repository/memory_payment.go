@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/payment-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// MemoryPaymentRepository keeps payments in memory, for tests and local runs
+type MemoryPaymentRepository struct {
+	mu       sync.Mutex
+	payments map[uuid.UUID]*domain.Payment
+}
+
+func NewMemoryPaymentRepository() *MemoryPaymentRepository {
+	return &MemoryPaymentRepository{
+		payments: make(map[uuid.UUID]*domain.Payment),
+	}
+}
+
+func (r *MemoryPaymentRepository) Create(ctx context.Context, payment *domain.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.payments[payment.ID] = database.Clone(payment)
+	return nil
+}
+
+func (r *MemoryPaymentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payment, ok := r.payments[id]
+	if !ok {
+		return nil, domain.ErrPaymentNotFound
+	}
+	return database.Clone(payment), nil
+}
+
+func (r *MemoryPaymentRepository) GetByBooking(ctx context.Context, bookingID uuid.UUID) ([]*domain.Payment, error) {
+	payments := r.find(func(p *domain.Payment) bool { return p.BookingID == bookingID })
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].CreatedAt.After(payments[j].CreatedAt)
+	})
+	return payments, nil
+}
+
+func (r *MemoryPaymentRepository) Update(ctx context.Context, payment *domain.Payment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.payments[payment.ID]
+	if !ok {
+		return domain.ErrPaymentNotFound
+	}
+	if stored.Version != payment.Version {
+		return domain.ErrVersionConflict
+	}
+
+	now := time.Now()
+	stored.Status = payment.Status
+	stored.ProviderTransactionID = payment.ProviderTransactionID
+	stored.UpdatedAt = now
+	stored.Version++
+	r.payments[payment.ID] = database.Clone(stored)
+
+	payment.UpdatedAt = now
+	payment.Version++
+	return nil
+}
+
+// ListByUser returns every payment made by the user
+func (r *MemoryPaymentRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Payment, error) {
+	payments := r.find(func(p *domain.Payment) bool { return p.UserID == userID })
+	sort.SliceStable(payments, func(i, j int) bool {
+		return payments[i].CreatedAt.Before(payments[j].CreatedAt)
+	})
+	return payments, nil
+}
+
+// find returns copies of the payments that match
+func (r *MemoryPaymentRepository) find(match func(*domain.Payment) bool) []*domain.Payment {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	payments := []*domain.Payment{}
+	for _, payment := range r.payments {
+		if match(payment) {
+			payments = append(payments, database.Clone(payment))
+		}
+	}
+	return payments
+}
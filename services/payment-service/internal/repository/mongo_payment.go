@@ -55,19 +55,48 @@ func (r *MongoPaymentRepository) GetByBooking(ctx context.Context, bookingID uui
 }
 
 func (r *MongoPaymentRepository) Update(ctx context.Context, payment *domain.Payment) error {
+	now := time.Now()
 	update := bson.M{
 		"$set": bson.M{
 			"status":                  payment.Status,
 			"provider_transaction_id": payment.ProviderTransactionID,
-			"updated_at":              time.Now(),
+			"updated_at":              now,
 		},
+		"$inc": bson.M{"version": 1},
 	}
-	result, err := r.coll.UpdateOne(ctx, bson.M{"_id": payment.ID}, update)
+	filter := bson.M{"_id": payment.ID, "version": payment.Version}
+	result, err := r.coll.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
 	if result.MatchedCount == 0 {
+		// Tell a stale version from a missing payment
+		count, err := r.coll.CountDocuments(ctx, bson.M{"_id": payment.ID})
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return domain.ErrVersionConflict
+		}
 		return domain.ErrPaymentNotFound
 	}
+	payment.UpdatedAt = now
+	payment.Version++
 	return nil
 }
+
+// ListByUser returns every payment made by the user
+func (r *MongoPaymentRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Payment, error) {
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+	cursor, err := r.coll.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var payments []*domain.Payment
+	if err := cursor.All(ctx, &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
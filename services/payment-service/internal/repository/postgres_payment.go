@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/payment-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// paymentColumns lists the columns of the payments table in scanPayment order
+const paymentColumns = `id, booking_id, user_id, payment_type, amount, currency, status, method,
+	rental_fee, security_deposit, service_fee, additional_services, tax, deposit_held, deposit_status,
+	provider_name, provider_transaction_id, checkout_url, receipt_url, created_at, updated_at, version`
+
+type PostgresPaymentRepository struct {
+	db *database.Postgres
+}
+
+func NewPostgresPaymentRepository(db *database.Postgres) *PostgresPaymentRepository {
+	return &PostgresPaymentRepository{db: db}
+}
+
+func (r *PostgresPaymentRepository) Create(ctx context.Context, p *domain.Payment) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO payments (`+paymentColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)`,
+		p.ID, p.BookingID, p.UserID, p.PaymentType, p.Amount, p.Currency, p.Status, p.Method,
+		p.RentalFee, p.SecurityDeposit, p.ServiceFee, p.AdditionalServices, p.Tax, p.DepositHeld, p.DepositStatus,
+		p.ProviderName, p.ProviderTransactionID, p.CheckoutURL, p.ReceiptURL, p.CreatedAt, p.UpdatedAt, p.Version,
+	)
+	return err
+}
+
+func (r *PostgresPaymentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Payment, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+paymentColumns+` FROM payments WHERE id = $1`, id)
+	payment, err := scanPayment(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (r *PostgresPaymentRepository) GetByBooking(ctx context.Context, bookingID uuid.UUID) ([]*domain.Payment, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, `SELECT `+paymentColumns+` FROM payments
+		WHERE booking_id = $1 ORDER BY created_at DESC`,
+		bookingID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectPayments(rows)
+}
+
+func (r *PostgresPaymentRepository) Update(ctx context.Context, payment *domain.Payment) error {
+	now := time.Now()
+	conn := r.db.Conn(ctx)
+
+	tag, err := conn.Exec(ctx, `UPDATE payments SET
+			status = $3, provider_transaction_id = $4, updated_at = $5, version = version + 1
+		WHERE id = $1 AND version = $2`,
+		payment.ID, payment.Version, payment.Status, payment.ProviderTransactionID, now,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		// Tell a stale version from a missing payment
+		var exists bool
+		if err := conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM payments WHERE id = $1)`, payment.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return domain.ErrVersionConflict
+		}
+		return domain.ErrPaymentNotFound
+	}
+	payment.UpdatedAt = now
+	payment.Version++
+	return nil
+}
+
+// ListByUser returns every payment made by the user
+func (r *PostgresPaymentRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Payment, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, `SELECT `+paymentColumns+` FROM payments
+		WHERE user_id = $1 ORDER BY created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectPayments(rows)
+}
+
+// scanPayment reads a row of paymentColumns
+func scanPayment(row pgx.Row) (*domain.Payment, error) {
+	var p domain.Payment
+	err := row.Scan(
+		&p.ID, &p.BookingID, &p.UserID, &p.PaymentType, &p.Amount, &p.Currency, &p.Status, &p.Method,
+		&p.RentalFee, &p.SecurityDeposit, &p.ServiceFee, &p.AdditionalServices, &p.Tax, &p.DepositHeld, &p.DepositStatus,
+		&p.ProviderName, &p.ProviderTransactionID, &p.CheckoutURL, &p.ReceiptURL, &p.CreatedAt, &p.UpdatedAt, &p.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// collectPayments reads every row of paymentColumns and closes rows
+func collectPayments(rows pgx.Rows) ([]*domain.Payment, error) {
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.Payment, error) {
+		return scanPayment(row)
+	})
+}
@@ -5,11 +5,26 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rentalflow/payment-service/internal/domain"
+	"github.com/rentalflow/rentalflow/pkg/database"
 )
 
 type PaymentRepository interface {
 	Create(ctx context.Context, payment *domain.Payment) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Payment, error)
 	GetByBooking(ctx context.Context, bookingID uuid.UUID) ([]*domain.Payment, error)
+	// Update saves the payment if it is still at payment.Version, and
+	// returns domain.ErrVersionConflict if another update came first
 	Update(ctx context.Context, payment *domain.Payment) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.Payment, error)
+}
+
+// NewPaymentRepository returns the PaymentRepository of the database
+func NewPaymentRepository(db *database.Backend) PaymentRepository {
+	if db.Memory {
+		return NewMemoryPaymentRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresPaymentRepository(db.Postgres)
+	}
+	return NewMongoPaymentRepository(db.Mongo.DB)
 }
@@ -0,0 +1,142 @@
+// Package repositorytest is the conformance suite of the payment service's
+// repository. Every implementation runs it, so the service behaves the same
+// whichever database it stores its data in.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/payment-service/internal/domain"
+	"github.com/rentalflow/payment-service/internal/repository"
+)
+
+// PaymentRepository runs the conformance tests of PaymentRepository against
+// the repositories newRepo opens, an empty one for each test
+func PaymentRepository(t *testing.T, newRepo func(t *testing.T) repository.PaymentRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		payment := newPayment(uuid.New(), uuid.New(), time.Now())
+		payment.RentalFee = 3000
+		payment.SecurityDeposit = 1000
+		payment.DepositHeld = true
+		payment.CheckoutURL = "https://checkout.example.com/tx"
+		mustCreate(t, repo, payment)
+
+		got, err := repo.GetByID(ctx, payment.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.BookingID != payment.BookingID || got.UserID != payment.UserID || got.Amount != payment.Amount ||
+			got.Currency != "ETB" || got.Status != domain.StatusPending || got.Method != domain.MethodChapa ||
+			got.RentalFee != 3000 || got.SecurityDeposit != 1000 || !got.DepositHeld ||
+			got.CheckoutURL != payment.CheckoutURL || got.Version != 1 {
+			t.Errorf("GetByID = %+v, want %+v", got, payment)
+		}
+		sameTime(t, "created_at", got.CreatedAt, payment.CreatedAt)
+
+		if _, err := repo.GetByID(ctx, uuid.New()); err != domain.ErrPaymentNotFound {
+			t.Errorf("GetByID of a missing payment = %v, want ErrPaymentNotFound", err)
+		}
+	})
+
+	t.Run("GetByBooking", func(t *testing.T) {
+		repo := newRepo(t)
+		booking := uuid.New()
+		start := time.Now().Add(-time.Hour)
+		failed := newPayment(booking, uuid.New(), start)
+		retried := newPayment(booking, failed.UserID, start.Add(time.Minute))
+		mustCreate(t, repo, failed)
+		mustCreate(t, repo, retried)
+		mustCreate(t, repo, newPayment(uuid.New(), failed.UserID, start))
+
+		payments, err := repo.GetByBooking(ctx, booking)
+		if err != nil {
+			t.Fatalf("GetByBooking: %v", err)
+		}
+		if len(payments) != 2 || payments[0].ID != retried.ID || payments[1].ID != failed.ID {
+			t.Errorf("GetByBooking = %d payments, want the booking's two, newest first", len(payments))
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		payment := newPayment(uuid.New(), uuid.New(), time.Now())
+		mustCreate(t, repo, payment)
+
+		stale := *payment
+		payment.Status = domain.StatusCompleted
+		payment.ProviderTransactionID = "chapa-tx-1"
+		if err := repo.Update(ctx, payment); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if payment.Version != 2 {
+			t.Errorf("Version after Update = %d, want 2", payment.Version)
+		}
+		got, err := repo.GetByID(ctx, payment.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Status != domain.StatusCompleted || got.ProviderTransactionID != "chapa-tx-1" || got.Version != 2 {
+			t.Errorf("GetByID after Update = %+v", got)
+		}
+
+		stale.Status = domain.StatusFailed
+		if err := repo.Update(ctx, &stale); err != domain.ErrVersionConflict {
+			t.Errorf("Update of a stale version = %v, want ErrVersionConflict", err)
+		}
+		missing := newPayment(uuid.New(), uuid.New(), time.Now())
+		if err := repo.Update(ctx, missing); err != domain.ErrPaymentNotFound {
+			t.Errorf("Update of a missing payment = %v, want ErrPaymentNotFound", err)
+		}
+	})
+
+	t.Run("ListByUser", func(t *testing.T) {
+		repo := newRepo(t)
+		user := uuid.New()
+		start := time.Now().Add(-time.Hour)
+		first := newPayment(uuid.New(), user, start)
+		second := newPayment(uuid.New(), user, start.Add(time.Minute))
+		mustCreate(t, repo, second)
+		mustCreate(t, repo, first)
+		mustCreate(t, repo, newPayment(uuid.New(), uuid.New(), start))
+
+		payments, err := repo.ListByUser(ctx, user)
+		if err != nil {
+			t.Fatalf("ListByUser: %v", err)
+		}
+		if len(payments) != 2 || payments[0].ID != first.ID || payments[1].ID != second.ID {
+			t.Errorf("ListByUser = %d payments, want the user's two, oldest first", len(payments))
+		}
+	})
+}
+
+// newPayment returns a pending Chapa payment created at createdAt
+func newPayment(bookingID, userID uuid.UUID, createdAt time.Time) *domain.Payment {
+	payment := domain.NewPayment(bookingID, userID, 4200, domain.MethodChapa)
+	payment.PaymentType = "booking"
+	payment.ProviderName = "chapa"
+	payment.CreatedAt = createdAt
+	payment.UpdatedAt = createdAt
+	return payment
+}
+
+func mustCreate(t *testing.T, repo repository.PaymentRepository, payment *domain.Payment) {
+	t.Helper()
+	if err := repo.Create(context.Background(), payment); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
+
+// sameTime fails the test unless got is want at the millisecond precision
+// every database keeps
+func sameTime(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if diff := got.Sub(want); diff > time.Millisecond || diff < -time.Millisecond {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
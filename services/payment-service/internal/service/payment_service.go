@@ -3,26 +3,66 @@ package service
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	"github.com/google/uuid"
 	"github.com/rentalflow/payment-service/internal/chapa"
+	"github.com/rentalflow/payment-service/internal/clients"
 	"github.com/rentalflow/payment-service/internal/domain"
 	"github.com/rentalflow/payment-service/internal/repository"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/rentalflow/pkg/metrics"
+	bookingpb "github.com/rentalflow/rentalflow/pkg/pb/booking"
 )
 
 type PaymentService struct {
-	paymentRepo repository.PaymentRepository
-	chapaClient *chapa.Client
+	paymentRepo   repository.PaymentRepository
+	tx            database.Transactor
+	chapaClient   *chapa.Client
+	bookingClient *clients.BookingClient
+	checkout      CheckoutConfig
 }
 
-func NewPaymentService(paymentRepo repository.PaymentRepository, chapaClient *chapa.Client) *PaymentService {
+// CheckoutConfig holds where Chapa reports a payment and sends the payer
+// afterwards. The transaction reference, and on return the booking ID, are
+// added to the query.
+type CheckoutConfig struct {
+	CallbackURL string
+	ReturnURL   string
+}
+
+func NewPaymentService(paymentRepo repository.PaymentRepository, tx database.Transactor, chapaClient *chapa.Client, bookingClient *clients.BookingClient, checkout CheckoutConfig) *PaymentService {
 	return &PaymentService{
-		paymentRepo: paymentRepo,
-		chapaClient: chapaClient,
+		paymentRepo:   paymentRepo,
+		tx:            tx,
+		chapaClient:   chapaClient,
+		bookingClient: bookingClient,
+		checkout:      checkout,
 	}
 }
 
+// InitializePayment starts a payment for a booking. When booking-service is
+// configured the booking must belong to the payer and still be open, and a
+// zero amount means the booking total.
 func (s *PaymentService) InitializePayment(ctx context.Context, bookingID, userID uuid.UUID, amount float64, method domain.PaymentMethod) (*domain.Payment, error) {
+	var booking *bookingpb.Booking
+	if s.bookingClient != nil {
+		var err error
+		booking, err = s.bookingClient.GetBooking(ctx, bookingID)
+		if err != nil {
+			return nil, err
+		}
+		if booking.RenterId != userID.String() {
+			return nil, domain.ErrUnauthorized
+		}
+		if booking.Status == "cancelled" || booking.Status == "completed" {
+			return nil, domain.ErrBookingNotPayable
+		}
+		if amount == 0 {
+			amount = booking.TotalAmount
+		}
+	}
+
 	if amount <= 0 {
 		return nil, domain.ErrInvalidAmount
 	}
@@ -30,6 +70,11 @@ func (s *PaymentService) InitializePayment(ctx context.Context, bookingID, userI
 	payment := domain.NewPayment(bookingID, userID, amount, method)
 	payment.PaymentType = "booking"
 	payment.ProviderName = string(method)
+	if booking != nil {
+		payment.RentalFee = booking.Subtotal
+		payment.SecurityDeposit = booking.SecurityDeposit
+		payment.ServiceFee = booking.ServiceFee
+	}
 
 	// Handle Chapa payments
 	if method == domain.MethodChapa && s.chapaClient != nil {
@@ -42,8 +87,8 @@ func (s *PaymentService) InitializePayment(ctx context.Context, bookingID, userI
 			FirstName:   "RentalFlow",
 			LastName:    "Customer",
 			TxRef:       txRef,
-			CallbackURL: fmt.Sprintf("http://localhost:3001/payment/callback?tx_ref=%s", txRef),
-			ReturnURL:   fmt.Sprintf("http://localhost:3001/payment/callback?tx_ref=%s&booking_id=%s", txRef, bookingID.String()),
+			CallbackURL: withQuery(s.checkout.CallbackURL, "tx_ref", txRef),
+			ReturnURL:   withQuery(s.checkout.ReturnURL, "tx_ref", txRef, "booking_id", bookingID.String()),
 			CustomTitle: "RentalFlow Payment",
 			CustomDesc:  fmt.Sprintf("Payment for Booking #%s", bookingID.String()[:8]),
 			Metadata: map[string]string{
@@ -80,16 +125,30 @@ func (s *PaymentService) GetBookingPayments(ctx context.Context, bookingID uuid.
 	return s.paymentRepo.GetByBooking(ctx, bookingID)
 }
 
+// UpdatePaymentStatus records the status the payment provider reports
 func (s *PaymentService) UpdatePaymentStatus(ctx context.Context, paymentID uuid.UUID, status domain.PaymentStatus, transactionID string) (*domain.Payment, error) {
-	payment, err := s.paymentRepo.GetByID(ctx, paymentID)
+	var previous domain.PaymentStatus
+	payment, err := s.updatePayment(ctx, paymentID, 0, func(payment *domain.Payment) error {
+		previous = payment.Status
+		payment.Status = status
+		payment.ProviderTransactionID = transactionID
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	payment.Status = status
-	payment.ProviderTransactionID = transactionID
+	// Repeated reports of the same status count once
+	if status != previous {
+		switch status {
+		case domain.StatusCompleted:
+			metrics.PaymentCompleted()
+		case domain.StatusFailed:
+			metrics.PaymentFailed()
+		}
+	}
 
-	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+	if err := s.syncBooking(ctx, payment); err != nil {
 		return nil, err
 	}
 
@@ -103,24 +162,130 @@ func (s *PaymentService) VerifyPayment(ctx context.Context, txRef string) (*chap
 	return s.chapaClient.VerifyPayment(txRef)
 }
 
-func (s *PaymentService) ProcessRefund(ctx context.Context, paymentID uuid.UUID, amount float64) (*domain.Payment, error) {
-	payment, err := s.paymentRepo.GetByID(ctx, paymentID)
+// ProcessRefund refunds a completed payment. expectedVersion is the version
+// the caller last saw, as sent in If-Match, or 0 for any.
+func (s *PaymentService) ProcessRefund(ctx context.Context, paymentID uuid.UUID, amount float64, expectedVersion int64) (*domain.Payment, error) {
+	payment, err := s.updatePayment(ctx, paymentID, expectedVersion, func(payment *domain.Payment) error {
+		if payment.Status != domain.StatusCompleted {
+			return domain.ErrRefundNotAllowed
+		}
+		if amount > payment.Amount {
+			return domain.ErrInvalidAmount
+		}
+
+		payment.Status = domain.StatusRefunded
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if payment.Status != domain.StatusCompleted {
-		return nil, domain.ErrRefundNotAllowed
+	if err := s.syncBooking(ctx, payment); err != nil {
+		return nil, err
 	}
 
-	if amount > payment.Amount {
-		return nil, domain.ErrInvalidAmount
+	return payment, nil
+}
+
+// maxUpdateAttempts bounds how often an update that lost a race with
+// another update of the same payment is tried again
+const maxUpdateAttempts = 3
+
+// updatePayment loads a payment, applies change and saves it, reloading and
+// applying change again when another update saves first. A non-zero
+// expectedVersion is the version the caller based the update on: the update
+// then fails with ErrVersionConflict once the payment has moved past it.
+func (s *PaymentService) updatePayment(ctx context.Context, paymentID uuid.UUID, expectedVersion int64, change func(*domain.Payment) error) (*domain.Payment, error) {
+	for attempt := 1; ; attempt++ {
+		payment, err := s.paymentRepo.GetByID(ctx, paymentID)
+		if err != nil {
+			return nil, err
+		}
+		if expectedVersion != 0 && payment.Version != expectedVersion {
+			return nil, domain.ErrVersionConflict
+		}
+		if err := change(payment); err != nil {
+			return nil, err
+		}
+
+		err = s.paymentRepo.Update(ctx, payment)
+		if err == domain.ErrVersionConflict && expectedVersion == 0 && attempt < maxUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return payment, nil
+	}
+}
+
+// EnsureBookingParticipant checks that a user is the renter or owner of a booking
+func (s *PaymentService) EnsureBookingParticipant(ctx context.Context, bookingID, userID uuid.UUID) error {
+	if s.bookingClient == nil {
+		return nil
+	}
+
+	booking, err := s.bookingClient.GetBooking(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	if booking.RenterId != userID.String() && booking.OwnerId != userID.String() {
+		return domain.ErrUnauthorized
+	}
+	return nil
+}
+
+// syncBooking reports completed and refunded payments to booking-service.
+// The booking update is idempotent, so a failed sync can be retried by
+// repeating the status update.
+func (s *PaymentService) syncBooking(ctx context.Context, payment *domain.Payment) error {
+	if s.bookingClient == nil {
+		return nil
+	}
+
+	var bookingStatus string
+	switch payment.Status {
+	case domain.StatusCompleted:
+		bookingStatus = "paid"
+	case domain.StatusRefunded:
+		bookingStatus = "refunded"
+	default:
+		return nil
 	}
 
-	payment.Status = domain.StatusRefunded
-	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+	return s.bookingClient.UpdatePaymentStatus(ctx, payment.BookingID, bookingStatus, payment.ID)
+}
+
+// ExportUserData returns the payments made by a user
+func (s *PaymentService) ExportUserData(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+	payments, err := s.paymentRepo.ListByUser(ctx, userID)
+	if err != nil {
 		return nil, err
 	}
+	return map[string]interface{}{"payments": payments}, nil
+}
 
-	return payment, nil
+// EraseUserData leaves a deleted user's payments in place. Payment records
+// must be kept for accounting and tax purposes.
+func (s *PaymentService) EraseUserData(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+	payments, err := s.paymentRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"payments_retained": len(payments)}, nil
+}
+
+// withQuery adds name/value pairs to the query of rawURL, keeping any it
+// already has
+func withQuery(rawURL string, pairs ...string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := u.Query()
+	for i := 0; i+1 < len(pairs); i += 2 {
+		query.Set(pairs[i], pairs[i+1])
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
 }
@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/payment-service/internal/domain"
+	"github.com/rentalflow/payment-service/internal/repository"
+	"github.com/rentalflow/payment-service/internal/service"
+	"github.com/rentalflow/rentalflow/pkg/database"
+)
+
+// newService returns a payment service on the memory repository, without
+// Chapa or booking-service
+func newService(t *testing.T) (*service.PaymentService, repository.PaymentRepository) {
+	t.Helper()
+
+	repo := repository.NewMemoryPaymentRepository()
+	return service.NewPaymentService(repo, &database.Backend{Memory: true}, nil, nil, service.CheckoutConfig{}), repo
+}
+
+func TestInitializePayment(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  float64
+		method  domain.PaymentMethod
+		wantErr error
+	}{
+		{"BankTransfer", 250, domain.MethodBankTransfer, nil},
+		{"ChapaWithoutClient", 250, domain.MethodChapa, nil},
+		{"ZeroAmount", 0, domain.MethodCash, domain.ErrInvalidAmount},
+		{"NegativeAmount", -5, domain.MethodCash, domain.ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newService(t)
+
+			payment, err := svc.InitializePayment(context.Background(), uuid.New(), uuid.New(), tt.amount, tt.method)
+			if err != tt.wantErr {
+				t.Fatalf("InitializePayment error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if payment.Status != domain.StatusPending || payment.Amount != tt.amount {
+				t.Errorf("status, amount = %s, %v, want pending, %v", payment.Status, payment.Amount, tt.amount)
+			}
+			if payment.CheckoutURL == "" || payment.ProviderTransactionID == "" {
+				t.Errorf("checkout URL %q and transaction %q should be set", payment.CheckoutURL, payment.ProviderTransactionID)
+			}
+			if _, err := repo.GetByID(context.Background(), payment.ID); err != nil {
+				t.Errorf("stored payment: %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdatePaymentStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		from domain.PaymentStatus
+		to   domain.PaymentStatus
+	}{
+		{"PendingToProcessing", domain.StatusPending, domain.StatusProcessing},
+		{"PendingToCompleted", domain.StatusPending, domain.StatusCompleted},
+		{"ProcessingToFailed", domain.StatusProcessing, domain.StatusFailed},
+		{"CompletedAgain", domain.StatusCompleted, domain.StatusCompleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newService(t)
+			payment := paymentWithStatus(t, svc, repo, tt.from)
+
+			got, err := svc.UpdatePaymentStatus(context.Background(), payment.ID, tt.to, "TX-1")
+			if err != nil {
+				t.Fatalf("UpdatePaymentStatus: %v", err)
+			}
+			if got.Status != tt.to || got.ProviderTransactionID != "TX-1" {
+				t.Errorf("status, transaction = %s, %s, want %s, TX-1", got.Status, got.ProviderTransactionID, tt.to)
+			}
+
+			stored, err := repo.GetByID(context.Background(), payment.ID)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if stored.Status != tt.to || stored.Version != got.Version {
+				t.Errorf("stored status, version = %s, %d, want %s, %d", stored.Status, stored.Version, tt.to, got.Version)
+			}
+		})
+	}
+
+	t.Run("UnknownPayment", func(t *testing.T) {
+		svc, _ := newService(t)
+		if _, err := svc.UpdatePaymentStatus(context.Background(), uuid.New(), domain.StatusCompleted, ""); err != domain.ErrPaymentNotFound {
+			t.Errorf("UpdatePaymentStatus error = %v, want %v", err, domain.ErrPaymentNotFound)
+		}
+	})
+}
+
+func TestProcessRefund(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    domain.PaymentStatus
+		amount  float64
+		version func(payment *domain.Payment) int64
+		wantErr error
+	}{
+		{"Completed", domain.StatusCompleted, 100, anyVersion, nil},
+		{"Partial", domain.StatusCompleted, 40, anyVersion, nil},
+		{"AtCurrentVersion", domain.StatusCompleted, 100, currentVersion, nil},
+		{"AtStaleVersion", domain.StatusCompleted, 100, staleVersion, domain.ErrVersionConflict},
+		{"MoreThanPaid", domain.StatusCompleted, 150, anyVersion, domain.ErrInvalidAmount},
+		{"Pending", domain.StatusPending, 100, anyVersion, domain.ErrRefundNotAllowed},
+		{"Failed", domain.StatusFailed, 100, anyVersion, domain.ErrRefundNotAllowed},
+		{"AlreadyRefunded", domain.StatusRefunded, 100, anyVersion, domain.ErrRefundNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := newService(t)
+			payment := paymentWithStatus(t, svc, repo, tt.from)
+
+			got, err := svc.ProcessRefund(context.Background(), payment.ID, tt.amount, tt.version(payment))
+			if err != tt.wantErr {
+				t.Fatalf("ProcessRefund error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Status != domain.StatusRefunded {
+				t.Errorf("status = %s, want refunded", got.Status)
+			}
+		})
+	}
+}
+
+// paymentWithStatus starts a payment of 100 and moves it to status
+func paymentWithStatus(t *testing.T, svc *service.PaymentService, repo repository.PaymentRepository, status domain.PaymentStatus) *domain.Payment {
+	t.Helper()
+
+	payment, err := svc.InitializePayment(context.Background(), uuid.New(), uuid.New(), 100, domain.MethodBankTransfer)
+	if err != nil {
+		t.Fatalf("InitializePayment: %v", err)
+	}
+	if status == payment.Status {
+		return payment
+	}
+
+	payment.Status = status
+	if err := repo.Update(context.Background(), payment); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	return payment
+}
+
+func anyVersion(*domain.Payment) int64 { return 0 }
+
+func currentVersion(payment *domain.Payment) int64 { return payment.Version }
+
+func staleVersion(payment *domain.Payment) int64 { return payment.Version + 1 }
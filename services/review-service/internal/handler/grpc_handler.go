@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	apperrors "github.com/rentalflow/rentalflow/pkg/errors"
+	pb "github.com/rentalflow/rentalflow/pkg/pb/review"
+	"github.com/rentalflow/review-service/internal/domain"
+	"github.com/rentalflow/review-service/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ReviewHandler implements the gRPC ReviewService. CreateReview needs the
+// review target, which the request does not carry, so reviews are still
+// created over HTTP.
+type ReviewHandler struct {
+	pb.UnimplementedReviewServiceServer
+	reviewService *service.ReviewService
+	checker       *auth.Checker
+}
+
+// NewReviewHandler creates a new review gRPC handler
+func NewReviewHandler(reviewService *service.ReviewService, checker *auth.Checker) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+		checker:       checker,
+	}
+}
+
+// GetReview retrieves a review by ID
+func (h *ReviewHandler) GetReview(ctx context.Context, req *pb.GetReviewRequest) (*pb.Review, error) {
+	reviewID, err := uuid.Parse(req.ReviewId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid review_id")
+	}
+
+	review, err := h.reviewService.GetReview(ctx, reviewID)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoReview(review), nil
+}
+
+// UpdateReview changes the rating or comment of the caller's review
+func (h *ReviewHandler) UpdateReview(ctx context.Context, req *pb.UpdateReviewRequest) (*pb.Review, error) {
+	principal, err := h.checker.RequireContext(ctx, auth.PermReviewsWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := h.ownedReview(ctx, principal, req.ReviewId)
+	if err != nil {
+		return nil, err
+	}
+
+	var comment string
+	if req.Comment != nil {
+		comment = *req.Comment
+	}
+	var rating float64
+	if req.Rating != nil {
+		if *req.Rating < 1.0 || *req.Rating > 5.0 {
+			return nil, toGRPCError(domain.ErrInvalidRating)
+		}
+		rating = *req.Rating
+	}
+
+	review, err = h.reviewService.UpdateReview(ctx, review.ID, rating, comment)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoReview(review), nil
+}
+
+// DeleteReview deletes the caller's review
+func (h *ReviewHandler) DeleteReview(ctx context.Context, req *pb.DeleteReviewRequest) (*pb.DeleteReviewResponse, error) {
+	principal, err := h.checker.RequireContext(ctx, auth.PermReviewsWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := h.ownedReview(ctx, principal, req.ReviewId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.reviewService.DeleteReview(ctx, review.ID); err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.DeleteReviewResponse{Success: true}, nil
+}
+
+// GetUserReviews lists the reviews about a user
+func (h *ReviewHandler) GetUserReviews(ctx context.Context, req *pb.GetUserReviewsRequest) (*pb.ReviewsResponse, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	reviews, total, err := h.reviewService.GetUserReviews(ctx, userID, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoReviewList(reviews, total, req.Page, req.PageSize), nil
+}
+
+// GetItemReviews lists the reviews about an item
+func (h *ReviewHandler) GetItemReviews(ctx context.Context, req *pb.GetItemReviewsRequest) (*pb.ReviewsResponse, error) {
+	itemID, err := uuid.Parse(req.ItemId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid item_id")
+	}
+
+	reviews, total, err := h.reviewService.GetItemReviews(ctx, itemID, int(req.Page), int(req.PageSize))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoReviewList(reviews, total, req.Page, req.PageSize), nil
+}
+
+// ownedReview loads a review the caller wrote, or any review for users:manage
+func (h *ReviewHandler) ownedReview(ctx context.Context, principal *auth.Principal, reviewID string) (*domain.Review, error) {
+	id, err := uuid.Parse(reviewID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid review_id")
+	}
+
+	review, err := h.reviewService.GetReview(ctx, id)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+	if !principal.CanActFor(review.ReviewerID.String()) {
+		return nil, toGRPCError(domain.ErrUnauthorized)
+	}
+	return review, nil
+}
+
+func toProtoReview(review *domain.Review) *pb.Review {
+	protoReview := &pb.Review{
+		Id:         review.ID.String(),
+		BookingId:  review.BookingID.String(),
+		ReviewerId: review.ReviewerID.String(),
+		ReviewType: string(review.ReviewType),
+		Rating:     review.Rating,
+		Comment:    review.Comment,
+		IsVerified: review.IsVerified,
+		IsVisible:  review.IsVisible,
+		CreatedAt:  timestamppb.New(review.CreatedAt),
+		UpdatedAt:  timestamppb.New(review.UpdatedAt),
+	}
+	if review.TargetUserID != nil {
+		protoReview.TargetUserId = review.TargetUserID.String()
+	}
+	if review.TargetItemID != nil {
+		protoReview.TargetItemId = review.TargetItemID.String()
+	}
+	return protoReview
+}
+
+func toProtoReviewList(reviews []*domain.Review, total int, page, pageSize int32) *pb.ReviewsResponse {
+	resp := &pb.ReviewsResponse{
+		Reviews:  make([]*pb.Review, len(reviews)),
+		Total:    int32(total),
+		Page:     page,
+		PageSize: pageSize,
+	}
+	for i, review := range reviews {
+		resp.Reviews[i] = toProtoReview(review)
+	}
+	return resp
+}
+
+// toGRPCError maps domain errors to gRPC status errors
+func toGRPCError(err error) error {
+	switch err {
+	case domain.ErrReviewNotFound:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeNotFound, err))
+	case domain.ErrUnauthorized:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeForbidden, err))
+	case domain.ErrInvalidRating:
+		return apperrors.ToGRPCError(apperrors.Wrap(apperrors.ErrorTypeValidation, err))
+	default:
+		return apperrors.ToGRPCError(err)
+	}
+}
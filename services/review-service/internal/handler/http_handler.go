@@ -6,16 +6,19 @@ import (
 	"strconv"
 
 	"github.com/google/uuid"
+	"github.com/rentalflow/rentalflow/pkg/auth"
+	"github.com/rentalflow/rentalflow/pkg/userdata"
 	"github.com/rentalflow/review-service/internal/domain"
 	"github.com/rentalflow/review-service/internal/service"
 )
 
 type HTTPHandler struct {
 	reviewService *service.ReviewService
+	checker       *auth.Checker
 }
 
-func NewHTTPHandler(reviewService *service.ReviewService) *HTTPHandler {
-	return &HTTPHandler{reviewService: reviewService}
+func NewHTTPHandler(reviewService *service.ReviewService, checker *auth.Checker) *HTTPHandler {
+	return &HTTPHandler{reviewService: reviewService, checker: checker}
 }
 
 func (h *HTTPHandler) RegisterRoutes(mux *http.ServeMux) {
@@ -23,6 +26,7 @@ func (h *HTTPHandler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("/api/reviews", h.HandleReviews)
 	mux.HandleFunc("/api/reviews/item", h.GetItemReviews)
 	mux.HandleFunc("/api/reviews/user", h.GetUserReviews)
+	userdata.NewHandler(h.checker, h.reviewService.ExportUserData, h.reviewService.EraseUserData, userdata.StatusErased, nil).Register(mux)
 }
 
 func (h *HTTPHandler) Health(w http.ResponseWriter, r *http.Request) {
@@ -48,6 +52,11 @@ func (h *HTTPHandler) HandleReviews(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *HTTPHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	principal, ok := h.checker.Require(w, r, auth.PermReviewsWrite)
+	if !ok {
+		return
+	}
+
 	var req struct {
 		ItemID     string  `json:"item_id"`
 		BookingID  string  `json:"booking_id"`
@@ -68,6 +77,14 @@ func (h *HTTPHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !principal.CanActFor(req.ReviewerID) {
+		http.Error(w, domain.ErrUnauthorized.Error(), http.StatusForbidden)
+		return
+	}
+	if req.ReviewerID == "" {
+		req.ReviewerID = principal.UserID
+	}
+
 	reviewerID, err := uuid.Parse(req.ReviewerID)
 	if err != nil {
 		http.Error(w, "Invalid reviewer_id", http.StatusBadRequest)
@@ -0,0 +1,31 @@
+// Package migrations declares the review service's indexes, and its schema
+// on PostgreSQL in the .sql files of postgres/. Released versions must never
+// change: add a new one instead.
+package migrations
+
+import (
+	"embed"
+
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// All lists the review service's migrations
+var All = []database.Migration{
+	{
+		Version:     1,
+		Description: "Index visible reviews by item and user, and reviews by author",
+		Up: database.CreateIndexes("reviews",
+			mongo.IndexModel{Keys: bson.D{{Key: "target_item_id", Value: 1}, {Key: "is_visible", Value: 1}, {Key: "created_at", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "target_user_id", Value: 1}, {Key: "is_visible", Value: 1}, {Key: "created_at", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "reviewer_id", Value: 1}}},
+		),
+	},
+}
+
+//go:embed postgres/*.sql
+var postgresFiles embed.FS
+
+// Postgres lists the review service's PostgreSQL migrations
+var Postgres = database.MustLoadSQLMigrations(postgresFiles, "postgres")
@@ -0,0 +1,18 @@
+CREATE TABLE reviews (
+    id             uuid PRIMARY KEY,
+    booking_id     uuid NOT NULL,
+    reviewer_id    uuid NOT NULL,
+    target_user_id uuid,
+    target_item_id uuid,
+    review_type    text NOT NULL,
+    rating         double precision NOT NULL,
+    comment        text NOT NULL DEFAULT '',
+    is_verified    boolean NOT NULL DEFAULT false,
+    is_visible     boolean NOT NULL DEFAULT true,
+    created_at     timestamptz NOT NULL,
+    updated_at     timestamptz NOT NULL
+);
+
+CREATE INDEX reviews_target_item_id_is_visible_created_at_idx ON reviews (target_item_id, is_visible, created_at DESC);
+CREATE INDEX reviews_target_user_id_is_visible_created_at_idx ON reviews (target_user_id, is_visible, created_at DESC);
+CREATE INDEX reviews_reviewer_id_idx ON reviews (reviewer_id);
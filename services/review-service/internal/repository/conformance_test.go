@@ -0,0 +1,28 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/rentalflow/rentalflow/pkg/database/databasetest"
+	"github.com/rentalflow/review-service/internal/migrations"
+	"github.com/rentalflow/review-service/internal/repository"
+	"github.com/rentalflow/review-service/internal/repository/repositorytest"
+)
+
+func TestMongoReviewRepository(t *testing.T) {
+	repositorytest.ReviewRepository(t, func(t *testing.T) repository.ReviewRepository {
+		return repository.NewMongoReviewRepository(databasetest.Mongo(t, migrations.All).DB)
+	})
+}
+
+func TestPostgresReviewRepository(t *testing.T) {
+	repositorytest.ReviewRepository(t, func(t *testing.T) repository.ReviewRepository {
+		return repository.NewPostgresReviewRepository(databasetest.Postgres(t, migrations.Postgres))
+	})
+}
+
+func TestMemoryReviewRepository(t *testing.T) {
+	repositorytest.ReviewRepository(t, func(t *testing.T) repository.ReviewRepository {
+		return repository.NewMemoryReviewRepository()
+	})
+}
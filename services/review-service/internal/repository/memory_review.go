@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/review-service/internal/domain"
+)
+
+// MemoryReviewRepository keeps reviews in memory, for tests and local runs
+type MemoryReviewRepository struct {
+	mu      sync.Mutex
+	reviews map[uuid.UUID]*domain.Review
+}
+
+func NewMemoryReviewRepository() *MemoryReviewRepository {
+	return &MemoryReviewRepository{
+		reviews: make(map[uuid.UUID]*domain.Review),
+	}
+}
+
+func (r *MemoryReviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reviews[review.ID] = database.Clone(review)
+	return nil
+}
+
+func (r *MemoryReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Review, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	review, ok := r.reviews[id]
+	if !ok {
+		return nil, domain.ErrReviewNotFound
+	}
+	return database.Clone(review), nil
+}
+
+func (r *MemoryReviewRepository) GetByItem(ctx context.Context, itemID uuid.UUID, offset, limit int) ([]*domain.Review, int, error) {
+	return r.page(func(review *domain.Review) bool {
+		return review.TargetItemID != nil && *review.TargetItemID == itemID && review.IsVisible
+	}, offset, limit)
+}
+
+func (r *MemoryReviewRepository) GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*domain.Review, int, error) {
+	return r.page(func(review *domain.Review) bool {
+		return review.TargetUserID != nil && *review.TargetUserID == userID && review.IsVisible
+	}, offset, limit)
+}
+
+// page returns a page of the matching reviews, newest first
+func (r *MemoryReviewRepository) page(match func(*domain.Review) bool, offset, limit int) ([]*domain.Review, int, error) {
+	reviews := r.find(match)
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviews[i].CreatedAt.After(reviews[j].CreatedAt)
+	})
+	return database.Page(reviews, offset, limit), len(reviews), nil
+}
+
+func (r *MemoryReviewRepository) Update(ctx context.Context, review *domain.Review) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.reviews[review.ID]
+	if !ok {
+		return domain.ErrReviewNotFound
+	}
+	stored.Rating = review.Rating
+	stored.Comment = review.Comment
+	stored.IsVisible = review.IsVisible
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *MemoryReviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.reviews[id]; !ok {
+		return domain.ErrReviewNotFound
+	}
+	delete(r.reviews, id)
+	return nil
+}
+
+// ListInvolvingUser returns reviews written by the user or about the user,
+// including hidden ones
+func (r *MemoryReviewRepository) ListInvolvingUser(ctx context.Context, userID uuid.UUID) ([]*domain.Review, error) {
+	reviews := r.find(func(review *domain.Review) bool {
+		return review.ReviewerID == userID || (review.TargetUserID != nil && *review.TargetUserID == userID)
+	})
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviews[i].CreatedAt.Before(reviews[j].CreatedAt)
+	})
+	return reviews, nil
+}
+
+// AnonymizeReviewer detaches the user's reviews from them and clears the
+// comment text. Ratings stay so item scores do not change.
+func (r *MemoryReviewRepository) AnonymizeReviewer(ctx context.Context, reviewerID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, review := range r.reviews {
+		if review.ReviewerID == reviewerID {
+			review.ReviewerID = uuid.Nil
+			review.Comment = ""
+			review.UpdatedAt = time.Now()
+			count++
+		}
+	}
+	return count, nil
+}
+
+// HideByTargetUser hides the reviews written about a user
+func (r *MemoryReviewRepository) HideByTargetUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, review := range r.reviews {
+		if review.TargetUserID != nil && *review.TargetUserID == userID && review.IsVisible {
+			review.IsVisible = false
+			review.UpdatedAt = time.Now()
+			count++
+		}
+	}
+	return count, nil
+}
+
+// find returns copies of the reviews that match
+func (r *MemoryReviewRepository) find(match func(*domain.Review) bool) []*domain.Review {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reviews := []*domain.Review{}
+	for _, review := range r.reviews {
+		if match(review) {
+			reviews = append(reviews, database.Clone(review))
+		}
+	}
+	return reviews
+}
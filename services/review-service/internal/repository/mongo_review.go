@@ -126,3 +126,56 @@ func (r *MongoReviewRepository) Delete(ctx context.Context, id uuid.UUID) error
 	}
 	return nil
 }
+
+// ListInvolvingUser returns reviews written by the user or about the user,
+// including hidden ones
+func (r *MongoReviewRepository) ListInvolvingUser(ctx context.Context, userID uuid.UUID) ([]*domain.Review, error) {
+	filter := bson.M{"$or": []bson.M{{"reviewer_id": userID}, {"target_user_id": userID}}}
+	opts := options.Find().SetSort(bson.M{"created_at": 1})
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var reviews []*domain.Review
+	if err := cursor.All(ctx, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// AnonymizeReviewer detaches the user's reviews from them and clears the
+// comment text. Ratings stay so item scores do not change.
+func (r *MongoReviewRepository) AnonymizeReviewer(ctx context.Context, reviewerID uuid.UUID) (int, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"reviewer_id": uuid.Nil,
+			"comment":     "",
+			"updated_at":  time.Now(),
+		},
+	}
+
+	result, err := r.coll.UpdateMany(ctx, bson.M{"reviewer_id": reviewerID}, update)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
+
+// HideByTargetUser hides the reviews written about a user
+func (r *MongoReviewRepository) HideByTargetUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	update := bson.M{
+		"$set": bson.M{
+			"is_visible": false,
+			"updated_at": time.Now(),
+		},
+	}
+
+	result, err := r.coll.UpdateMany(ctx, bson.M{"target_user_id": userID, "is_visible": true}, update)
+	if err != nil {
+		return 0, err
+	}
+	return int(result.ModifiedCount), nil
+}
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/rentalflow/rentalflow/pkg/database"
+	"github.com/rentalflow/review-service/internal/domain"
+)
+
+// reviewColumns lists the columns of the reviews table in scanReview order
+const reviewColumns = `id, booking_id, reviewer_id, target_user_id, target_item_id, review_type,
+	rating, comment, is_verified, is_visible, created_at, updated_at`
+
+type PostgresReviewRepository struct {
+	db *database.Postgres
+}
+
+func NewPostgresReviewRepository(db *database.Postgres) *PostgresReviewRepository {
+	return &PostgresReviewRepository{db: db}
+}
+
+func (r *PostgresReviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	_, err := r.db.Conn(ctx).Exec(ctx, `INSERT INTO reviews (`+reviewColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		review.ID, review.BookingID, review.ReviewerID, review.TargetUserID, review.TargetItemID, review.ReviewType,
+		review.Rating, review.Comment, review.IsVerified, review.IsVisible, review.CreatedAt, review.UpdatedAt,
+	)
+	return err
+}
+
+func (r *PostgresReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Review, error) {
+	row := r.db.Conn(ctx).QueryRow(ctx, `SELECT `+reviewColumns+` FROM reviews WHERE id = $1`, id)
+	review, err := scanReview(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrReviewNotFound
+		}
+		return nil, err
+	}
+	return review, nil
+}
+
+func (r *PostgresReviewRepository) GetByItem(ctx context.Context, itemID uuid.UUID, offset, limit int) ([]*domain.Review, int, error) {
+	return r.visible(ctx, "target_item_id", itemID, offset, limit)
+}
+
+func (r *PostgresReviewRepository) GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*domain.Review, int, error) {
+	return r.visible(ctx, "target_user_id", userID, offset, limit)
+}
+
+// visible returns a page of the visible reviews whose column holds id,
+// newest first
+func (r *PostgresReviewRepository) visible(ctx context.Context, column string, id uuid.UUID, offset, limit int) ([]*domain.Review, int, error) {
+	conn := r.db.Conn(ctx)
+
+	var total int
+	if err := conn.QueryRow(ctx, `SELECT count(*) FROM reviews WHERE `+column+` = $1 AND is_visible`, id).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := conn.Query(ctx, `SELECT `+reviewColumns+` FROM reviews
+		WHERE `+column+` = $1 AND is_visible ORDER BY created_at DESC OFFSET $2 LIMIT $3`,
+		id, offset, limit,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	reviews, err := collectReviews(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+func (r *PostgresReviewRepository) Update(ctx context.Context, review *domain.Review) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE reviews SET
+			rating = $2, comment = $3, is_visible = $4, updated_at = $5
+		WHERE id = $1`,
+		review.ID, review.Rating, review.Comment, review.IsVisible, time.Now(),
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrReviewNotFound
+	}
+	return nil
+}
+
+func (r *PostgresReviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `DELETE FROM reviews WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrReviewNotFound
+	}
+	return nil
+}
+
+// ListInvolvingUser returns reviews written by the user or about the user,
+// including hidden ones
+func (r *PostgresReviewRepository) ListInvolvingUser(ctx context.Context, userID uuid.UUID) ([]*domain.Review, error) {
+	rows, err := r.db.Conn(ctx).Query(ctx, `SELECT `+reviewColumns+` FROM reviews
+		WHERE reviewer_id = $1 OR target_user_id = $1 ORDER BY created_at`,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return collectReviews(rows)
+}
+
+// AnonymizeReviewer detaches the user's reviews from them and clears the
+// comment text. Ratings stay so item scores do not change.
+func (r *PostgresReviewRepository) AnonymizeReviewer(ctx context.Context, reviewerID uuid.UUID) (int, error) {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE reviews SET reviewer_id = $2, comment = '', updated_at = $3
+		WHERE reviewer_id = $1`,
+		reviewerID, uuid.Nil, time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// HideByTargetUser hides the reviews written about a user
+func (r *PostgresReviewRepository) HideByTargetUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	tag, err := r.db.Conn(ctx).Exec(ctx, `UPDATE reviews SET is_visible = false, updated_at = $2
+		WHERE target_user_id = $1 AND is_visible`,
+		userID, time.Now(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// scanReview reads a row of reviewColumns
+func scanReview(row pgx.Row) (*domain.Review, error) {
+	var review domain.Review
+	err := row.Scan(
+		&review.ID, &review.BookingID, &review.ReviewerID, &review.TargetUserID, &review.TargetItemID, &review.ReviewType,
+		&review.Rating, &review.Comment, &review.IsVerified, &review.IsVisible, &review.CreatedAt, &review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// collectReviews reads every row of reviewColumns and closes rows
+func collectReviews(rows pgx.Rows) ([]*domain.Review, error) {
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*domain.Review, error) {
+		return scanReview(row)
+	})
+}
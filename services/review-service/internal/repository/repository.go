@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/rentalflow/rentalflow/pkg/database"
 	"github.com/rentalflow/review-service/internal/domain"
 )
 
@@ -14,4 +15,18 @@ type ReviewRepository interface {
 	GetByUser(ctx context.Context, userID uuid.UUID, offset, limit int) ([]*domain.Review, int, error)
 	Update(ctx context.Context, review *domain.Review) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	ListInvolvingUser(ctx context.Context, userID uuid.UUID) ([]*domain.Review, error)
+	AnonymizeReviewer(ctx context.Context, reviewerID uuid.UUID) (int, error)
+	HideByTargetUser(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// NewReviewRepository returns the ReviewRepository of the database
+func NewReviewRepository(db *database.Backend) ReviewRepository {
+	if db.Memory {
+		return NewMemoryReviewRepository()
+	}
+	if db.Postgres != nil {
+		return NewPostgresReviewRepository(db.Postgres)
+	}
+	return NewMongoReviewRepository(db.Mongo.DB)
 }
@@ -0,0 +1,189 @@
+// Package repositorytest is the conformance suite of the review service's
+// repository. Every implementation runs it, so the service behaves the same
+// whichever database it stores its data in.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rentalflow/review-service/internal/domain"
+	"github.com/rentalflow/review-service/internal/repository"
+)
+
+// ReviewRepository runs the conformance tests of ReviewRepository against
+// the repositories newRepo opens, an empty one for each test
+func ReviewRepository(t *testing.T, newRepo func(t *testing.T) repository.ReviewRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+		item := uuid.New()
+		review := newItemReview(uuid.New(), item, time.Now())
+		review.IsVerified = true
+		mustCreate(t, repo, review)
+
+		got, err := repo.GetByID(ctx, review.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.BookingID != review.BookingID || got.ReviewerID != review.ReviewerID ||
+			got.TargetItemID == nil || *got.TargetItemID != item || got.TargetUserID != nil ||
+			got.ReviewType != domain.TypeRenterToItem || got.Rating != 4.5 || got.Comment != review.Comment ||
+			!got.IsVerified || !got.IsVisible {
+			t.Errorf("GetByID = %+v, want %+v", got, review)
+		}
+		sameTime(t, "created_at", got.CreatedAt, review.CreatedAt)
+
+		if _, err := repo.GetByID(ctx, uuid.New()); err != domain.ErrReviewNotFound {
+			t.Errorf("GetByID of a missing review = %v, want ErrReviewNotFound", err)
+		}
+	})
+
+	t.Run("VisiblePages", func(t *testing.T) {
+		repo := newRepo(t)
+		item, owner := uuid.New(), uuid.New()
+		start := time.Now().Add(-time.Hour)
+		older := newItemReview(uuid.New(), item, start)
+		newer := newItemReview(uuid.New(), item, start.Add(time.Minute))
+		hidden := newItemReview(uuid.New(), item, start.Add(2*time.Minute))
+		hidden.IsVisible = false
+		aboutOwner := newUserReview(uuid.New(), owner, start)
+		for _, review := range []*domain.Review{older, newer, hidden, aboutOwner, newItemReview(uuid.New(), uuid.New(), start)} {
+			mustCreate(t, repo, review)
+		}
+
+		reviews, total, err := repo.GetByItem(ctx, item, 0, 1)
+		if err != nil {
+			t.Fatalf("GetByItem: %v", err)
+		}
+		if total != 2 || len(reviews) != 1 || reviews[0].ID != newer.ID {
+			t.Errorf("GetByItem = %d reviews (total %d), want the newest of 2 visible", len(reviews), total)
+		}
+
+		reviews, total, err = repo.GetByUser(ctx, owner, 0, 10)
+		if err != nil {
+			t.Fatalf("GetByUser: %v", err)
+		}
+		if total != 1 || len(reviews) != 1 || reviews[0].ID != aboutOwner.ID {
+			t.Errorf("GetByUser = %d reviews (total %d), want the one about the owner", len(reviews), total)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		review := newItemReview(uuid.New(), uuid.New(), time.Now())
+		mustCreate(t, repo, review)
+
+		review.Rating = 2
+		review.Comment = "Arrived late"
+		review.IsVisible = false
+		if err := repo.Update(ctx, review); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := repo.GetByID(ctx, review.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.Rating != 2 || got.Comment != "Arrived late" || got.IsVisible {
+			t.Errorf("GetByID after Update = %+v", got)
+		}
+
+		if err := repo.Delete(ctx, review.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if err := repo.Delete(ctx, review.ID); err != domain.ErrReviewNotFound {
+			t.Errorf("second Delete = %v, want ErrReviewNotFound", err)
+		}
+		if err := repo.Update(ctx, review); err != domain.ErrReviewNotFound {
+			t.Errorf("Update of a deleted review = %v, want ErrReviewNotFound", err)
+		}
+	})
+
+	t.Run("Erasure", func(t *testing.T) {
+		repo := newRepo(t)
+		user := uuid.New()
+		start := time.Now().Add(-time.Hour)
+		written := newItemReview(user, uuid.New(), start)
+		about := newUserReview(uuid.New(), user, start.Add(time.Minute))
+		alreadyHidden := newUserReview(uuid.New(), user, start.Add(2*time.Minute))
+		alreadyHidden.IsVisible = false
+		unrelated := newItemReview(uuid.New(), uuid.New(), start)
+		for _, review := range []*domain.Review{written, about, alreadyHidden, unrelated} {
+			mustCreate(t, repo, review)
+		}
+
+		reviews, err := repo.ListInvolvingUser(ctx, user)
+		if err != nil {
+			t.Fatalf("ListInvolvingUser: %v", err)
+		}
+		if len(reviews) != 3 || reviews[0].ID != written.ID || reviews[2].ID != alreadyHidden.ID {
+			t.Errorf("ListInvolvingUser = %d reviews, want the user's 3, hidden included, oldest first", len(reviews))
+		}
+
+		anonymized, err := repo.AnonymizeReviewer(ctx, user)
+		if err != nil {
+			t.Fatalf("AnonymizeReviewer: %v", err)
+		}
+		if anonymized != 1 {
+			t.Errorf("AnonymizeReviewer = %d, want 1", anonymized)
+		}
+		got, err := repo.GetByID(ctx, written.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if got.ReviewerID != uuid.Nil || got.Comment != "" || got.Rating != written.Rating {
+			t.Errorf("anonymized review = %+v, want no author or comment and the same rating", got)
+		}
+
+		hidden, err := repo.HideByTargetUser(ctx, user)
+		if err != nil {
+			t.Fatalf("HideByTargetUser: %v", err)
+		}
+		if hidden != 1 {
+			t.Errorf("HideByTargetUser = %d, want 1", hidden)
+		}
+		if _, total, err := repo.GetByUser(ctx, user, 0, 10); err != nil || total != 0 {
+			t.Errorf("GetByUser after HideByTargetUser = %d visible, %v", total, err)
+		}
+		if got, err := repo.GetByID(ctx, unrelated.ID); err != nil || got.Comment == "" {
+			t.Errorf("an unrelated review changed: %+v, %v", got, err)
+		}
+	})
+}
+
+// newItemReview returns a visible review of an item created at createdAt
+func newItemReview(reviewerID, itemID uuid.UUID, createdAt time.Time) *domain.Review {
+	review := domain.NewReview(uuid.New(), reviewerID, domain.TypeRenterToItem, 4.5, "Clean and on time")
+	review.TargetItemID = &itemID
+	review.CreatedAt = createdAt
+	review.UpdatedAt = createdAt
+	return review
+}
+
+// newUserReview returns a visible review of a user created at createdAt
+func newUserReview(reviewerID, userID uuid.UUID, createdAt time.Time) *domain.Review {
+	review := domain.NewReview(uuid.New(), reviewerID, domain.TypeRenterToOwner, 5, "Friendly owner")
+	review.TargetUserID = &userID
+	review.CreatedAt = createdAt
+	review.UpdatedAt = createdAt
+	return review
+}
+
+func mustCreate(t *testing.T, repo repository.ReviewRepository, review *domain.Review) {
+	t.Helper()
+	if err := repo.Create(context.Background(), review); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+}
+
+// sameTime fails the test unless got is want at the millisecond precision
+// every database keeps
+func sameTime(t *testing.T, name string, got, want time.Time) {
+	t.Helper()
+	if diff := got.Sub(want); diff > time.Millisecond || diff < -time.Millisecond {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
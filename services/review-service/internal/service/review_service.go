@@ -4,16 +4,34 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/messaging"
 	"github.com/rentalflow/review-service/internal/domain"
 	"github.com/rentalflow/review-service/internal/repository"
 )
 
+// ReviewEventsTopic is the topic review changes are published to, with keys
+// review.created, review.updated, review.deleted and review.erased
+const ReviewEventsTopic = "review_events"
+
 type ReviewService struct {
 	reviewRepo repository.ReviewRepository
+	publisher  messaging.Publisher
 }
 
-func NewReviewService(reviewRepo repository.ReviewRepository) *ReviewService {
-	return &ReviewService{reviewRepo: reviewRepo}
+func NewReviewService(reviewRepo repository.ReviewRepository, publisher messaging.Publisher) *ReviewService {
+	return &ReviewService{reviewRepo: reviewRepo, publisher: publisher}
+}
+
+// publish announces a review change so cached reviews can be dropped. A
+// failure is logged; the cached reviews then expire with their TTL.
+func (s *ReviewService) publish(ctx context.Context, key string, body interface{}) {
+	if s.publisher == nil {
+		return
+	}
+	if err := s.publisher.Publish(ctx, ReviewEventsTopic, key, body); err != nil {
+		logger.Ctx(ctx).Error().Err(err).Str("key", key).Msg("Failed to publish review event")
+	}
 }
 
 func (s *ReviewService) CreateReview(ctx context.Context, itemID uuid.UUID, bookingID *uuid.UUID, reviewerID uuid.UUID, reviewType domain.ReviewType, rating float64, comment string) (*domain.Review, error) {
@@ -41,6 +59,8 @@ func (s *ReviewService) CreateReview(ctx context.Context, itemID uuid.UUID, book
 	if err := s.reviewRepo.Create(ctx, review); err != nil {
 		return nil, err
 	}
+
+	s.publish(ctx, "review.created", review)
 	return review, nil
 }
 
@@ -86,9 +106,49 @@ func (s *ReviewService) UpdateReview(ctx context.Context, reviewID uuid.UUID, ra
 	if err := s.reviewRepo.Update(ctx, review); err != nil {
 		return nil, err
 	}
+
+	s.publish(ctx, "review.updated", review)
 	return review, nil
 }
 
 func (s *ReviewService) DeleteReview(ctx context.Context, reviewID uuid.UUID) error {
-	return s.reviewRepo.Delete(ctx, reviewID)
+	// Loaded first so the event can name the reviewed item
+	review, err := s.reviewRepo.GetByID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if err := s.reviewRepo.Delete(ctx, reviewID); err != nil {
+		return err
+	}
+
+	s.publish(ctx, "review.deleted", review)
+	return nil
+}
+
+// ExportUserData returns the reviews a user wrote or received
+func (s *ReviewService) ExportUserData(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+	reviews, err := s.reviewRepo.ListInvolvingUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"reviews": reviews}, nil
+}
+
+// EraseUserData anonymizes a deleted user's reviews and hides reviews about them
+func (s *ReviewService) EraseUserData(ctx context.Context, userID uuid.UUID) (map[string]interface{}, error) {
+	written, err := s.reviewRepo.AnonymizeReviewer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	received, err := s.reviewRepo.HideByTargetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if written > 0 || received > 0 {
+		s.publish(ctx, "review.erased", map[string]string{"user_id": userID.String()})
+	}
+	return map[string]interface{}{
+		"reviews_anonymized": written,
+		"reviews_hidden":     received,
+	}, nil
 }
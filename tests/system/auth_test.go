@@ -0,0 +1,102 @@
+package system
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	authpb "github.com/rentalflow/rentalflow/pkg/pb/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TestAuthGRPCChecksCaller calls auth-service's gRPC API directly, as anyone
+// who can reach its port could, and checks it acts only for the caller
+func TestAuthGRPCChecksCaller(t *testing.T) {
+	s := Start(t)
+
+	renter := register(t, s, "renter")
+	owner := register(t, s, "owner")
+	code := s.Do(t, http.MethodPost, "/api/auth/kyc", owner.Token, map[string]interface{}{
+		"documents": []map[string]interface{}{
+			{"type": "national_id", "url": "https://example.com/id.jpg"},
+		},
+	}, nil)
+	if code != http.StatusCreated {
+		t.Fatalf("POST /api/auth/kyc: %d", code)
+	}
+	s.CreateAdmin(t, "admin@example.com", password)
+	admin := login(t, s, "admin@example.com")
+
+	conn, err := grpc.Dial(s.GRPCAddrs["auth"], grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial auth: %v", err)
+	}
+	defer conn.Close()
+	client := authpb.NewAuthServiceClient(conn)
+
+	as := func(token string) context.Context {
+		if token == "" {
+			return context.Background()
+		}
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{"ListUsersAnonymously", func() error {
+			_, err := client.ListUsers(as(""), &authpb.ListUsersRequest{})
+			return err
+		}, codes.Unauthenticated},
+		{"ListUsersAsRenter", func() error {
+			_, err := client.ListUsers(as(renter.Token), &authpb.ListUsersRequest{})
+			return err
+		}, codes.PermissionDenied},
+		{"GetOtherProfile", func() error {
+			_, err := client.GetProfile(as(renter.Token), &authpb.GetProfileRequest{UserId: owner.ID})
+			return err
+		}, codes.PermissionDenied},
+		{"UpdateOtherProfile", func() error {
+			name := "Mallory"
+			_, err := client.UpdateProfile(as(renter.Token), &authpb.UpdateProfileRequest{UserId: owner.ID, FirstName: &name})
+			return err
+		}, codes.PermissionDenied},
+		{"LogoutOther", func() error {
+			_, err := client.Logout(as(renter.Token), &authpb.LogoutRequest{UserId: owner.ID})
+			return err
+		}, codes.PermissionDenied},
+		{"ApproveOwnKYCNamingAnAdmin", func() error {
+			_, err := client.VerifyUser(as(owner.Token), &authpb.VerifyUserRequest{UserId: owner.ID, Status: "verified", AdminId: renter.ID})
+			return err
+		}, codes.PermissionDenied},
+		{"ApproveKYCNamingAnotherAdmin", func() error {
+			_, err := client.VerifyUser(as(admin), &authpb.VerifyUserRequest{UserId: owner.ID, Status: "verified", AdminId: renter.ID})
+			return err
+		}, codes.PermissionDenied},
+		{"GetOwnProfile", func() error {
+			_, err := client.GetProfile(as(renter.Token), &authpb.GetProfileRequest{})
+			return err
+		}, codes.OK},
+		{"ListUsersAsAdmin", func() error {
+			_, err := client.ListUsers(as(admin), &authpb.ListUsersRequest{})
+			return err
+		}, codes.OK},
+		{"ApproveKYCAsAdmin", func() error {
+			_, err := client.VerifyUser(as(admin), &authpb.VerifyUserRequest{UserId: owner.ID, Status: "verified"})
+			return err
+		}, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Errorf("code = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package system
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// FakeChapa stands in for the Chapa API. Every transaction it initializes
+// verifies as paid.
+type FakeChapa struct {
+	URL string
+
+	mu           sync.Mutex
+	transactions map[string]map[string]interface{}
+}
+
+// NewFakeChapa starts a fake Chapa API that stops when the test ends
+func NewFakeChapa(t testing.TB) *FakeChapa {
+	c := &FakeChapa{transactions: make(map[string]map[string]interface{})}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/transaction/initialize", c.initialize)
+	mux.HandleFunc("/transaction/verify/", c.verify)
+	mux.HandleFunc("/banks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"status": "success", "data": []interface{}{}})
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	c.URL = server.URL
+	return c
+}
+
+// Transactions returns the number of transactions initialized so far
+func (c *FakeChapa) Transactions() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.transactions)
+}
+
+func (c *FakeChapa) initialize(w http.ResponseWriter, r *http.Request) {
+	var req map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "failed", "message": err.Error()})
+		return
+	}
+	txRef, _ := req["tx_ref"].(string)
+	if txRef == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]interface{}{"status": "failed", "message": "tx_ref is required"})
+		return
+	}
+
+	c.mu.Lock()
+	c.transactions[txRef] = req
+	c.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Hosted Link",
+		"data":    map[string]interface{}{"checkout_url": c.URL + "/checkout/" + txRef},
+	})
+}
+
+func (c *FakeChapa) verify(w http.ResponseWriter, r *http.Request) {
+	txRef := strings.TrimPrefix(r.URL.Path, "/transaction/verify/")
+
+	c.mu.Lock()
+	req, ok := c.transactions[txRef]
+	c.mu.Unlock()
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"status": "failed", "message": "Invalid transaction or Transaction not found"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "success",
+		"message": "Payment details",
+		"data": map[string]interface{}{
+			"amount":    req["amount"],
+			"currency":  req["currency"],
+			"email":     req["email"],
+			"tx_ref":    txRef,
+			"status":    "success",
+			"reference": "CH-" + txRef,
+		},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,248 @@
+package system
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+)
+
+// TestRentalFlow walks the journey of scripts/test_system_flow.sh: an owner
+// lists an item, a renter books and pays for it, the owner hears about the
+// booking and the renter reviews it
+func TestRentalFlow(t *testing.T) {
+	s := Start(t)
+
+	if status := s.Do(t, http.MethodGet, "/health", "", nil, nil); status != http.StatusOK {
+		t.Fatalf("GET /health: %d", status)
+	}
+
+	renter := register(t, s, "renter")
+	owner := register(t, s, "owner")
+
+	// Renters book once their email address is verified, with the link
+	// mailed to them at registration
+	var mailedToken string
+	Eventually(t, 5*time.Second, func() error {
+		for _, mail := range s.SMTP.MailTo(renter.Email) {
+			if m := verifyLink.FindStringSubmatch(mail.Data); m != nil {
+				mailedToken, _ = url.QueryUnescape(m[1])
+				return nil
+			}
+		}
+		return fmt.Errorf("no verification email for %s", renter.Email)
+	})
+	status := s.Do(t, http.MethodPost, "/api/auth/verify-email", "", map[string]interface{}{"token": mailedToken}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("POST /api/auth/verify-email: %d", status)
+	}
+
+	// New verification links go to the signed-in user, not a user named in the body
+	status = s.Do(t, http.MethodPost, "/api/auth/verify-email/resend", "", map[string]interface{}{"user_id": renter.ID}, nil)
+	if status != http.StatusUnauthorized {
+		t.Fatalf("POST /api/auth/verify-email/resend anonymously: %d, want %d", status, http.StatusUnauthorized)
+	}
+
+	// Account emails are sent by auth-service alone, not through the gateway
+	status = s.Do(t, http.MethodPost, "/api/notifications/password-reset", renter.Token, map[string]interface{}{
+		"to":    renter.Email,
+		"token": "chosen-by-the-caller",
+	}, nil)
+	if status != http.StatusForbidden {
+		t.Fatalf("POST /api/notifications/password-reset as a user: %d, want %d", status, http.StatusForbidden)
+	}
+
+	// Owners list their first item once an admin has verified their identity
+	var kycCase struct {
+		ID string `json:"id"`
+	}
+	status = s.Do(t, http.MethodPost, "/api/auth/kyc", owner.Token, map[string]interface{}{
+		"documents": []map[string]interface{}{
+			{"type": "national_id", "url": "https://example.com/id.jpg"},
+		},
+	}, &kycCase)
+	if status != http.StatusCreated || kycCase.ID == "" {
+		t.Fatalf("POST /api/auth/kyc: %d %+v", status, kycCase)
+	}
+	s.CreateAdmin(t, "admin@example.com", password)
+	adminToken := login(t, s, "admin@example.com")
+	status = s.Do(t, http.MethodPost, "/api/auth/admin/kyc/approve", adminToken, map[string]interface{}{"case_id": kycCase.ID}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("POST /api/auth/admin/kyc/approve: %d", status)
+	}
+
+	start := time.Now().AddDate(0, 0, 1).UTC().Truncate(24 * time.Hour).Format(time.RFC3339)
+	end := time.Now().AddDate(0, 0, 5).UTC().Truncate(24 * time.Hour).Format(time.RFC3339)
+
+	var item struct {
+		ID string `json:"id"`
+	}
+	status = s.Do(t, http.MethodPost, "/api/items", owner.Token, map[string]interface{}{
+		"owner_id":    owner.ID,
+		"title":       "Luxury Apartment",
+		"description": "Great view",
+		"category":    "property",
+		"daily_rate":  150,
+		"location": map[string]interface{}{
+			"city":      "Addis Ababa",
+			"country":   "Ethiopia",
+			"latitude":  9.0,
+			"longitude": 38.7,
+		},
+		"available_quantity": 1,
+	}, &item)
+	if status != http.StatusCreated && status != http.StatusOK || item.ID == "" {
+		t.Fatalf("POST /api/items: %d %+v", status, item)
+	}
+
+	var booking struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	status = s.Do(t, http.MethodPost, "/api/bookings", renter.Token, map[string]interface{}{
+		"renter_id":        renter.ID,
+		"owner_id":         owner.ID,
+		"rental_item_id":   item.ID,
+		"start_date":       start,
+		"end_date":         end,
+		"daily_rate":       150,
+		"total_amount":     600,
+		"security_deposit": 100,
+	}, &booking)
+	if status != http.StatusCreated && status != http.StatusOK || booking.ID == "" {
+		t.Fatalf("POST /api/bookings: %d %+v", status, booking)
+	}
+
+	// The booking event reaches notification-service through the broker
+	Eventually(t, 5*time.Second, func() error {
+		var inbox struct {
+			Notifications []struct {
+				Title string `json:"title"`
+			} `json:"notifications"`
+		}
+		status := s.Do(t, http.MethodGet, "/api/notifications/user?user_id="+owner.ID, owner.Token, nil, &inbox)
+		if status != http.StatusOK {
+			return fmt.Errorf("GET /api/notifications/user: %d", status)
+		}
+		for _, n := range inbox.Notifications {
+			if n.Title == "New Booking Request" {
+				return nil
+			}
+		}
+		return fmt.Errorf("owner was not notified of the booking: %+v", inbox.Notifications)
+	})
+
+	var payment struct {
+		PaymentID   string `json:"payment_id"`
+		CheckoutURL string `json:"checkout_url"`
+	}
+	status = s.Do(t, http.MethodPost, "/api/payments/initialize", renter.Token, map[string]interface{}{
+		"booking_id": booking.ID,
+		"user_id":    renter.ID,
+		"amount":     700,
+		"method":     "chapa",
+		"provider":   "chapa",
+	}, &payment)
+	if status != http.StatusOK && status != http.StatusCreated || payment.PaymentID == "" {
+		t.Fatalf("POST /api/payments/initialize: %d %+v", status, payment)
+	}
+	if s.Chapa.Transactions() != 1 {
+		t.Fatalf("Chapa saw %d transactions, want 1", s.Chapa.Transactions())
+	}
+
+	// The gateway caches the item's reviews until review-service reports a change
+	var itemReviews struct {
+		Total int `json:"total"`
+	}
+	if status = s.Do(t, http.MethodGet, "/api/reviews/item?item_id="+item.ID, "", nil, &itemReviews); status != http.StatusOK || itemReviews.Total != 0 {
+		t.Fatalf("GET /api/reviews/item: %d %+v", status, itemReviews)
+	}
+
+	var review struct {
+		ID     string  `json:"id"`
+		Rating float64 `json:"rating"`
+	}
+	status = s.Do(t, http.MethodPost, "/api/reviews", renter.Token, map[string]interface{}{
+		"item_id":     item.ID,
+		"booking_id":  booking.ID,
+		"reviewer_id": renter.ID,
+		"review_type": "renter_to_item",
+		"rating":      5.0,
+		"comment":     "Amazing stay! Highly recommended.",
+	}, &review)
+	if status != http.StatusCreated && status != http.StatusOK || review.ID == "" {
+		t.Fatalf("POST /api/reviews: %d %+v", status, review)
+	}
+
+	var stored struct {
+		Rating float64 `json:"rating"`
+	}
+	status = s.Do(t, http.MethodGet, "/api/reviews?id="+review.ID, "", nil, &stored)
+	if status != http.StatusOK || stored.Rating != 5 {
+		t.Fatalf("GET /api/reviews: %d %+v", status, stored)
+	}
+
+	Eventually(t, 5*time.Second, func() error {
+		if status := s.Do(t, http.MethodGet, "/api/reviews/item?item_id="+item.ID, "", nil, &itemReviews); status != http.StatusOK || itemReviews.Total != 1 {
+			return fmt.Errorf("GET /api/reviews/item: %d %+v", status, itemReviews)
+		}
+		return nil
+	})
+}
+
+// password is the password of every test account
+const password = "Password123!"
+
+// verifyLink finds the token in the link of an email verification email
+var verifyLink = regexp.MustCompile(`/verify-email\?token=([^"&<\s]+)`)
+
+// user is a registered, logged in test account
+type user struct {
+	ID    string
+	Email string
+	Token string
+}
+
+// register signs up a user with a role and logs them in
+func register(t *testing.T, s *System, role string) user {
+	t.Helper()
+
+	email := fmt.Sprintf("%s_%d@example.com", role, time.Now().UnixNano())
+
+	var registered struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	status := s.Do(t, http.MethodPost, "/api/auth/register", "", map[string]interface{}{
+		"email":      email,
+		"password":   password,
+		"first_name": "Test",
+		"last_name":  role,
+		"role":       role,
+	}, &registered)
+	if status != http.StatusCreated && status != http.StatusOK || registered.User.ID == "" {
+		t.Fatalf("register %s: %d %+v", role, status, registered)
+	}
+
+	return user{ID: registered.User.ID, Email: email, Token: login(t, s, email)}
+}
+
+// login logs a user in and returns their access token
+func login(t *testing.T, s *System, email string) string {
+	t.Helper()
+
+	var login struct {
+		AccessToken string `json:"access_token"`
+	}
+	status := s.Do(t, http.MethodPost, "/api/auth/login", "", map[string]interface{}{
+		"email":    email,
+		"password": password,
+	}, &login)
+	if status != http.StatusOK || login.AccessToken == "" {
+		t.Fatalf("log in %s: %d %+v", email, status, login)
+	}
+	return login.AccessToken
+}
@@ -0,0 +1,134 @@
+package system
+
+import (
+	"bufio"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Mail is a message the fake SMTP server accepted
+type Mail struct {
+	From string
+	To   []string
+	Data string
+}
+
+// FakeSMTP is an SMTP server that keeps the mail it is sent instead of
+// delivering it. It accepts any credentials and offers no TLS.
+type FakeSMTP struct {
+	Host string
+	Port int
+
+	mu   sync.Mutex
+	mail []Mail
+}
+
+// NewFakeSMTP starts a fake SMTP server that stops when the test ends
+func NewFakeSMTP(t testing.TB) *FakeSMTP {
+	lis := listen(t)
+	s := &FakeSMTP{Host: "127.0.0.1", Port: port(lis)}
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// MailTo returns the messages sent to addr so far, oldest first
+func (s *FakeSMTP) MailTo(addr string) []Mail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var mail []Mail
+	for _, m := range s.mail {
+		for _, to := range m.To {
+			if strings.EqualFold(to, addr) {
+				mail = append(mail, m)
+				break
+			}
+		}
+	}
+	return mail
+}
+
+// serve speaks just enough SMTP for net/smtp.SendMail and a health check
+func (s *FakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(lines ...string) {
+		for _, line := range lines {
+			conn.Write([]byte(line + "\r\n"))
+		}
+	}
+
+	reply("220 " + s.Host + " fake ESMTP")
+	var mail Mail
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch verb {
+		case "EHLO":
+			reply("250-"+s.Host, "250-SIZE "+strconv.Itoa(10<<20), "250 AUTH PLAIN")
+		case "HELO", "NOOP", "RSET":
+			mail = Mail{}
+			reply("250 OK")
+		case "AUTH":
+			reply("235 Authentication succeeded")
+		case "MAIL":
+			mail.From = address(line)
+			reply("250 OK")
+		case "RCPT":
+			mail.To = append(mail.To, address(line))
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(line, "."))
+			}
+			mail.Data = data.String()
+			s.mu.Lock()
+			s.mail = append(s.mail, mail)
+			s.mu.Unlock()
+			mail = Mail{}
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// address returns the address in the angle brackets of a MAIL or RCPT command
+func address(line string) string {
+	start := strings.Index(line, "<")
+	end := strings.LastIndex(line, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
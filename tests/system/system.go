@@ -0,0 +1,317 @@
+// Package system boots every RentalFlow service and the API gateway in one
+// process, so end-to-end flows like scripts/test_system_flow.sh run with
+// go test. The services keep their data in memory, exchange events through
+// the in-process message bus, take payments through a fake Chapa and send
+// mail to a fake SMTP server; everything else, the gRPC and HTTP hops
+// between them included, is what runs in production.
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	gatewayapp "github.com/rentalflow/api-gateway/app"
+	authapp "github.com/rentalflow/auth-service/app"
+	bookingapp "github.com/rentalflow/booking-service/app"
+	inventoryapp "github.com/rentalflow/inventory-service/app"
+	notificationapp "github.com/rentalflow/notification-service/app"
+	paymentapp "github.com/rentalflow/payment-service/app"
+	"github.com/rentalflow/rentalflow/pkg/config"
+	"github.com/rentalflow/rentalflow/pkg/logger"
+	"github.com/rentalflow/rentalflow/pkg/messaging"
+	reviewapp "github.com/rentalflow/review-service/app"
+	"github.com/rs/zerolog/log"
+)
+
+// services are the services behind the gateway, by config name
+var services = []string{"auth", "inventory", "booking", "payment", "notification", "review"}
+
+// System is a running set of services behind the gateway
+type System struct {
+	// GatewayURL is the base URL of the API gateway
+	GatewayURL string
+
+	// ServiceURLs are the HTTP base URLs of the services, by config name
+	ServiceURLs map[string]string
+
+	// GRPCAddrs are the gRPC addresses of the services, by config name
+	GRPCAddrs map[string]string
+
+	// Broker carries the events between the services
+	Broker *messaging.Bus
+
+	// Chapa stands in for the payment provider
+	Chapa *FakeChapa
+
+	// SMTP receives the mail the notification service sends
+	SMTP *FakeSMTP
+
+	auth   *authapp.App
+	client *http.Client
+}
+
+// server is an app that serves until it is shut down
+type server interface {
+	Shutdown(ctx context.Context)
+}
+
+var initLogger sync.Once
+
+// Start boots the services and the gateway and waits until the gateway
+// reports them healthy. Everything stops when the test ends. Logs go to
+// stdout at the level in SYSTEM_TEST_LOG_LEVEL, by default only errors.
+func Start(t testing.TB) *System {
+	t.Helper()
+
+	initLogger.Do(func() {
+		level := os.Getenv("SYSTEM_TEST_LOG_LEVEL")
+		if level == "" {
+			level = "error"
+		}
+		logger.Init("system-test", level, "console")
+		log.Logger = logger.Logger
+	})
+
+	s := &System{
+		ServiceURLs: make(map[string]string),
+		GRPCAddrs:   make(map[string]string),
+		Broker:      messaging.NewBus(),
+		Chapa:       NewFakeChapa(t),
+		SMTP:        NewFakeSMTP(t),
+		client:      &http.Client{Timeout: 30 * time.Second},
+	}
+	t.Cleanup(s.Broker.Close)
+
+	// Every address is taken before any service starts, so each one can be
+	// told where the others are
+	grpcListeners := make(map[string]net.Listener)
+	httpListeners := make(map[string]net.Listener)
+	for _, name := range services {
+		grpcListeners[name] = listen(t)
+		httpListeners[name] = listen(t)
+		s.ServiceURLs[name] = "http://" + httpListeners[name].Addr().String()
+		s.GRPCAddrs[name] = grpcListeners[name].Addr().String()
+	}
+	gatewayListener := listen(t)
+	s.GatewayURL = "http://" + gatewayListener.Addr().String()
+
+	load := func(name string) *config.Config {
+		cfg, err := config.Load(name)
+		if err != nil {
+			t.Fatalf("load %s config: %v", name, err)
+		}
+		s.configure(cfg, grpcListeners, httpListeners)
+		if name != "gateway" {
+			cfg.GRPCPort = port(grpcListeners[name])
+			cfg.HTTPPort = port(httpListeners[name])
+		}
+		return cfg
+	}
+	start := func(name string, app server, err error) {
+		if err != nil {
+			t.Fatalf("start %s: %v", name, err)
+		}
+		t.Cleanup(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			app.Shutdown(ctx)
+		})
+	}
+
+	auth, err := authapp.New(load("auth"))
+	start("auth", auth, err)
+	s.auth = auth
+	auth.Start(grpcListeners["auth"], httpListeners["auth"])
+
+	inventory, err := inventoryapp.New(load("inventory"), s.Broker)
+	start("inventory", inventory, err)
+	inventory.Start(grpcListeners["inventory"], httpListeners["inventory"])
+
+	booking, err := bookingapp.New(load("booking"), s.Broker)
+	start("booking", booking, err)
+	booking.Start(grpcListeners["booking"], httpListeners["booking"])
+
+	payment, err := paymentapp.New(load("payment"))
+	start("payment", payment, err)
+	payment.Start(grpcListeners["payment"], httpListeners["payment"])
+
+	notification, err := notificationapp.New(load("notification"), s.Broker)
+	start("notification", notification, err)
+	notification.Start(grpcListeners["notification"], httpListeners["notification"])
+
+	review, err := reviewapp.New(load("review"), s.Broker)
+	start("review", review, err)
+	review.Start(grpcListeners["review"], httpListeners["review"])
+
+	gatewayCfg := load("gateway")
+	gatewayCfg.Gateway.Port = port(gatewayListener)
+	gateway, err := gatewayapp.New(gatewayCfg, s.Broker)
+	start("gateway", gateway, err)
+	gateway.Start(gatewayListener)
+
+	s.waitHealthy(t)
+	return s
+}
+
+// configure points a service at the in-memory database, the other
+// services and the fake Chapa
+func (s *System) configure(cfg *config.Config, grpcListeners, httpListeners map[string]net.Listener) {
+	grpcAddr := func(name string) string { return grpcListeners[name].Addr().String() }
+
+	cfg.Database.Driver = config.DriverMemory
+	cfg.Chapa.BaseURL = s.Chapa.URL
+	cfg.SMTP.Host = s.SMTP.Host
+	cfg.SMTP.Port = s.SMTP.Port
+
+	cfg.Services.AuthServiceAddr = grpcAddr("auth")
+	cfg.Services.InventoryServiceAddr = grpcAddr("inventory")
+	cfg.Services.BookingServiceAddr = grpcAddr("booking")
+	cfg.Services.PaymentServiceAddr = grpcAddr("payment")
+	cfg.Services.ReviewServiceAddr = grpcAddr("review")
+	// Auth reaches notification over HTTP
+	cfg.Services.NotificationServiceAddr = httpListeners["notification"].Addr().String()
+
+	cfg.ServiceURLs.Auth = s.ServiceURLs["auth"]
+	cfg.ServiceURLs.Inventory = s.ServiceURLs["inventory"]
+	cfg.ServiceURLs.Booking = s.ServiceURLs["booking"]
+	cfg.ServiceURLs.Payment = s.ServiceURLs["payment"]
+	cfg.ServiceURLs.Notification = s.ServiceURLs["notification"]
+	cfg.ServiceURLs.Review = s.ServiceURLs["review"]
+
+	upstreams := map[string]*config.UpstreamConfig{
+		"auth":         &cfg.Gateway.Upstreams.Auth,
+		"inventory":    &cfg.Gateway.Upstreams.Inventory,
+		"booking":      &cfg.Gateway.Upstreams.Booking,
+		"payment":      &cfg.Gateway.Upstreams.Payment,
+		"notification": &cfg.Gateway.Upstreams.Notification,
+		"review":       &cfg.Gateway.Upstreams.Review,
+	}
+	for name, upstream := range upstreams {
+		upstream.URLs = []string{s.ServiceURLs[name]}
+		upstream.GRPCAddr = grpcAddr(name)
+	}
+}
+
+// CreateAdmin creates an administrator account, which no one can register
+func (s *System) CreateAdmin(t testing.TB, email, password string) {
+	t.Helper()
+
+	if err := s.auth.CreateAdmin(context.Background(), email, password, "Test", "Admin"); err != nil {
+		t.Fatalf("create admin %s: %v", email, err)
+	}
+}
+
+// waitHealthy waits until the gateway reports every service healthy
+func (s *System) waitHealthy(t testing.TB) {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		status, body := s.raw(t, http.MethodGet, "/health", "", nil)
+		if status == http.StatusOK {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("services did not become healthy: %d %s", status, body)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// Do sends a request with a JSON body to the gateway, with token as the
+// bearer token if it is set, and decodes the JSON response into out if it
+// is set. It returns the response status; error responses are logged.
+func (s *System) Do(t testing.TB, method, path, token string, body, out interface{}) int {
+	t.Helper()
+
+	status, data := s.raw(t, method, path, token, body)
+	if status >= http.StatusBadRequest {
+		t.Logf("%s %s: %d %s", method, path, status, bytes.TrimSpace(data))
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("%s %s: decode %d response %q: %v", method, path, status, data, err)
+		}
+	}
+	return status
+}
+
+// raw sends a request to the gateway and returns the status and body
+func (s *System) raw(t testing.TB, method, path, token string, body interface{}) (int, []byte) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("%s %s: encode request: %v", method, path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, s.GatewayURL+path, reader)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("%s %s: read response: %v", method, path, err)
+	}
+	return resp.StatusCode, data
+}
+
+// Eventually retries check until it returns nil or timeout passes, then
+// fails the test with the last error
+func Eventually(t testing.TB, timeout time.Duration, check func() error) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := check()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal(err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// listen reserves a local port for a server
+func listen(t testing.TB) net.Listener {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { lis.Close() })
+	return lis
+}
+
+// port returns the port a listener is bound to
+func port(lis net.Listener) int {
+	return lis.Addr().(*net.TCPAddr).Port
+}